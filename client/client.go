@@ -10,12 +10,15 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/rpc"
+	"github.com/RTradeLtd/Nexus/tracing"
 )
 
 // IPFSOrchestratorClient is a lighweight container for the orchestrator's
 // gRPC API client
 type IPFSOrchestratorClient struct {
 	nexus.ServiceClient
+	rpc.ControlClient
 	grpc *grpc.ClientConn
 }
 
@@ -23,13 +26,15 @@ type IPFSOrchestratorClient struct {
 func New(opts config.API, devMode bool) (*IPFSOrchestratorClient, error) {
 	var (
 		c        = &IPFSOrchestratorClient{}
-		dialOpts []grpc.DialOption
+		dialOpts = []grpc.DialOption{
+			// propagate trace context of callers to the orchestrator
+			grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor()),
+		}
 	)
 
 	if opts.Key != "" {
-		dialOpts = []grpc.DialOption{
-			grpc.WithPerRPCCredentials(dialer.NewCredentials(opts.Key, !devMode)),
-		}
+		dialOpts = append(dialOpts,
+			grpc.WithPerRPCCredentials(dialer.NewCredentials(opts.Key, !devMode)))
 	}
 
 	if opts.TLS.CertPath != "" {
@@ -49,6 +54,7 @@ func New(opts config.API, devMode bool) (*IPFSOrchestratorClient, error) {
 		return nil, fmt.Errorf("failed to connect to core service: %s", err.Error())
 	}
 	c.ServiceClient = nexus.NewServiceClient(c.grpc)
+	c.ControlClient = rpc.NewControlClient(c.grpc)
 	return c, nil
 }
 
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/RTradeLtd/Nexus/rpc"
+)
+
+// isControlCommand checks if the given command is provided by the Control
+// service client
+func isControlCommand(c rpc.ControlClient, command string) bool {
+	_, found := reflect.TypeOf(c).MethodByName(command)
+	return found
+}
+
+// controlExec maps command line args of the form "<FIELD>=<VALUE>" to a call
+// to the Control service client. Unlike the generic ctl package, which only
+// sets string fields, this supports all scalar field types as well as
+// comma-separated lists for repeated fields.
+func controlExec(ctx context.Context, c rpc.ControlClient, args []string, out io.Writer) (interface{}, error) {
+	if len(args) < 1 {
+		return nil, errors.New("insufficient arguments provided")
+	}
+	method, found := reflect.TypeOf(c).MethodByName(args[0])
+	if !found {
+		return nil, fmt.Errorf("unknown command '%s'", args[0])
+	}
+	fmt.Fprintf(out, "function %s found\n", args[0])
+
+	// instantiate request and set fields from args
+	var req = reflect.New(method.Type.In(2).Elem())
+	for _, arg := range args[1:] {
+		var kv = strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid argument '%s' - must be of the form <FIELD>=<VALUE>", arg)
+		}
+		if err := setField(req.Elem(), kv[0], kv[1]); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Fprintf(out, "generated function call: \n%s(ctx, { %v})\n", args[0], req.Interface())
+
+	// execute and get results of call: [response, error]
+	var result = reflect.ValueOf(c).MethodByName(args[0]).Call(
+		[]reflect.Value{reflect.ValueOf(ctx), req})
+	if err, ok := result[1].Interface().(error); ok && err != nil {
+		return result[0].Interface(), err
+	}
+
+	// print responses of streaming calls until the stream ends
+	if recv := result[0].MethodByName("Recv"); recv.IsValid() {
+		for {
+			var msg = recv.Call(nil)
+			if err, ok := msg[1].Interface().(error); ok && err != nil {
+				if err == io.EOF {
+					return nil, nil
+				}
+				return nil, err
+			}
+			fmt.Fprintf(out, "%v\n", msg[0].Interface())
+		}
+	}
+	return result[0].Interface(), nil
+}
+
+// controlHelp lists functions and arguments of the Control service client
+func controlHelp(c rpc.ControlClient, out io.Writer) {
+	var t = reflect.TypeOf(c)
+	for i := 0; i < t.NumMethod(); i++ {
+		var method = t.Method(i)
+		fmt.Fprintf(out, "%s:\n", method.Name)
+		var arg = method.Type.In(2)
+		fmt.Fprintf(out, "  %s\n", arg.String())
+		fmt.Fprintln(out, "  arguments:")
+		var elem, hasArgs = arg.Elem(), false
+		for j := 0; j < elem.NumField(); j++ {
+			if !strings.HasPrefix(elem.Field(j).Name, "XXX") {
+				hasArgs = true
+				fmt.Fprintf(out, "    %s=%s\n", elem.Field(j).Name, elem.Field(j).Type.String())
+			}
+		}
+		if !hasArgs {
+			fmt.Fprintln(out, "    none")
+		}
+		fmt.Fprintln(out, "")
+	}
+}
+
+// setField assigns given value to the struct field with given name, matched
+// case-insensitively
+func setField(obj reflect.Value, name, value string) error {
+	var field reflect.Value
+	for i := 0; i < obj.NumField(); i++ {
+		if strings.EqualFold(obj.Type().Field(i).Name, name) {
+			field = obj.Field(i)
+			break
+		}
+	}
+	if !field.IsValid() || !field.CanSet() {
+		return fmt.Errorf("unknown argument '%s'", name)
+	}
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		var (
+			values = strings.Split(value, ",")
+			slice  = reflect.MakeSlice(field.Type(), len(values), len(values))
+		)
+		for i, v := range values {
+			if err := setScalar(slice.Index(i), name, v); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setScalar(field, name, value)
+}
+
+func setScalar(field reflect.Value, name, value string) error {
+	var err error
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(value)
+		field.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(value, 10, field.Type().Bits())
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(value, 10, field.Type().Bits())
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(value, field.Type().Bits())
+		field.SetFloat(f)
+	case reflect.Slice:
+		field.SetBytes([]byte(value))
+	default:
+		return fmt.Errorf("argument '%s' cannot be set from the command line", name)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for argument '%s': %s", name, err.Error())
+	}
+	return nil
+}
@@ -35,12 +35,21 @@ func runCTL(configPath string, devMode, prettyPrint bool, args []string) {
 	// show help if needed
 	if args != nil && len(args) == 1 && args[0] == "help" {
 		controller.Help(os.Stdout)
+		controlHelp(c.ControlClient, os.Stdout)
 		return
 	}
 
-	// execute command
-	start := time.Now()
-	out, err := controller.Exec(context.Background(), args, os.Stdout)
+	// execute command - Control service commands are executed separately, since
+	// their arguments are not limited to strings
+	var (
+		start = time.Now()
+		out   interface{}
+	)
+	if len(args) > 0 && isControlCommand(c.ControlClient, args[0]) {
+		out, err = controlExec(context.Background(), c.ControlClient, args, os.Stdout)
+	} else {
+		out, err = controller.Exec(context.Background(), args, os.Stdout)
+	}
 	if err != nil {
 		fatal(err.Error())
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -17,6 +18,8 @@ import (
 	"github.com/RTradeLtd/Nexus/ipfs"
 	"github.com/RTradeLtd/Nexus/log"
 	"github.com/RTradeLtd/Nexus/orchestrator"
+	"github.com/RTradeLtd/Nexus/store"
+	"github.com/RTradeLtd/Nexus/tracing"
 )
 
 func runDaemon(configPath string, devMode bool, args []string) {
@@ -25,6 +28,9 @@ func runDaemon(configPath string, devMode bool, args []string) {
 	if err != nil {
 		fatal(err.Error())
 	}
+	if !store.ValidRole(cfg.Delegator.DefaultRole) {
+		fatal(fmt.Sprintf("invalid default role '%s'", cfg.Delegator.DefaultRole))
+	}
 
 	println("preparing to start daemon")
 
@@ -40,6 +46,20 @@ func runDaemon(configPath string, devMode bool, args []string) {
 		println("logger initialized - output will be written to", cfg.LogPath)
 	}
 
+	// initialize tracing
+	println("initializing tracing")
+	shutdownTracing, err := tracing.New(l, cfg.Tracing, "nexus", Version)
+	if err != nil {
+		fatal(err.Error())
+	}
+	defer func() {
+		flush, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flush); err != nil {
+			l.Warnw("error occurred flushing traces", "error", err)
+		}
+	}()
+
 	// initialize node client
 	println("initializing node client")
 	c, err := ipfs.NewClient(l, cfg.IPFS)
@@ -72,10 +92,20 @@ func runDaemon(configPath string, devMode bool, args []string) {
 		}
 	}()
 
+	// set up tables owned by Nexus
+	l.Info("migrating nexus tables")
+	if err := store.Migrate(dbm.DB); err != nil {
+		l.Errorw("failed to migrate nexus tables", "error", err)
+		fatal(err.Error())
+	}
+
 	// initialize orchestrator
 	println("initializing orchestrator")
-	o, err := orchestrator.New(l, cfg.Address, cfg.IPFS.Ports, devMode,
-		c, models.NewHostedNetworkManager(dbm.DB))
+	o, err := orchestrator.New(l,
+		[]string{cfg.Address, cfg.AddressIPv6}, cfg.IPFS.Ports, cfg.IPFS.Bind, devMode,
+		c, models.NewHostedNetworkManager(dbm.DB), store.NewSettingsManager(dbm.DB),
+		store.NewTokenManager(dbm.DB), store.NewDenylistManager(dbm.DB),
+		store.NewDomainManager(dbm.DB), store.NewUsageManager(dbm.DB))
 	if err != nil {
 		fatal(err.Error())
 	}
@@ -86,23 +116,43 @@ func runDaemon(configPath string, devMode bool, args []string) {
 
 	// initialize delegator
 	println("initializing delegator")
-	dl := delegator.New(l, delegator.EngineOpts{
-		Version:        Version,
-		DevMode:        devMode,
-		RequestTimeout: 30 * time.Second,
-		Domain:         cfg.Delegator.Domain,
-		JWTKey:         []byte(cfg.Delegator.JWTKey),
-	}, o.Registry, models.NewHostedNetworkManager(dbm.DB))
+	dl, err := delegator.New(l, delegatorOpts(cfg, devMode), o.Registry,
+		models.NewHostedNetworkManager(dbm.DB), delegator.Stores{
+			Settings: store.NewSettingsManager(dbm.DB),
+			Tokens:   store.NewTokenManager(dbm.DB),
+			Denylist: store.NewDenylistManager(dbm.DB),
+			Domains:  store.NewDomainManager(dbm.DB),
+			Usage:    store.NewUsageManager(dbm.DB),
+		})
+	if err != nil {
+		fatal(err.Error())
+	}
+	o.OnNetworkChange(dl.InvalidateNetwork)
+	o.OnDenylistChange(dl.ReloadDenylist)
+	o.OnDomainChange(dl.ReloadDomain)
 
 	// catch interrupts
 	ctx, cancel := context.WithCancel(context.Background())
-	var signals = make(chan os.Signal)
+	var signals = make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	go func() {
 		<-signals
 		cancel()
 	}()
 
+	// reload certificates on hangup
+	var hangups = make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		for range hangups {
+			l.Info("reloading delegator certificate")
+			dl.ReloadCertificate()
+		}
+	}()
+
+	// meter swarm traffic of nodes
+	go o.MeterTraffic(ctx, time.Duration(cfg.Usage.SampleSeconds)*time.Second)
+
 	// serve gRPC endpoints
 	println("spinning up gRPC server...")
 	go func() {
@@ -125,3 +175,30 @@ func runDaemon(configPath string, devMode bool, args []string) {
 	<-ctx.Done()
 	println("orchestrator shut down")
 }
+
+// delegatorOpts creates delegator options from configuration
+func delegatorOpts(cfg config.IPFSOrchestratorConfig, devMode bool) delegator.EngineOpts {
+	return delegator.EngineOpts{
+		Version:        Version,
+		DevMode:        devMode,
+		RequestTimeout: 30 * time.Second,
+		Domain:         cfg.Delegator.Domain,
+		JWTKey:         []byte(cfg.Delegator.JWTKey),
+		JWT:            cfg.Delegator.JWT,
+		Bind:           cfg.IPFS.Bind,
+		RateLimits:     cfg.Delegator.RateLimits,
+		Commands:       cfg.Delegator.Commands,
+		DefaultRole:    cfg.Delegator.DefaultRole,
+		NetworkCache:   cfg.Delegator.NetworkCache,
+		GatewayCache:   cfg.Delegator.GatewayCache,
+
+		SubdomainGateway: cfg.Delegator.SubdomainGateway,
+		DNSLink:          cfg.Delegator.DNSLink,
+		Breaker:          cfg.Delegator.Breaker,
+		BodyLimits:       cfg.Delegator.BodyLimits,
+		Timeouts:         cfg.Delegator.Timeouts,
+		CORS:             cfg.Delegator.CORS,
+		Egress:           cfg.Delegator.Egress,
+		Usage:            cfg.Usage,
+	}
+}
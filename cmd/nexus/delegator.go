@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tcfg "github.com/RTradeLtd/config/v2"
+	"github.com/RTradeLtd/database/v2"
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/client"
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/delegator"
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/network"
+	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/store"
+	"github.com/RTradeLtd/Nexus/tracing"
+)
+
+// runDelegator spins up a delegator apart from the daemon, which mirrors the
+// daemon's node registry and proxies requests to nodes on the daemon's host
+func runDelegator(configPath string, devMode bool, args []string) {
+	// load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fatal(err.Error())
+	}
+	if !store.ValidRole(cfg.Delegator.DefaultRole) {
+		fatal(fmt.Sprintf("invalid default role '%s'", cfg.Delegator.DefaultRole))
+	}
+	var standalone = cfg.Delegator.Standalone
+
+	println("preparing to start delegator")
+
+	// initialize logger
+	println("initializing logger")
+	l, err := log.NewLogger(cfg.LogPath, devMode)
+	if err != nil {
+		fatal(err.Error())
+	}
+	defer l.Sync()
+	l = l.With("version", Version)
+	if cfg.LogPath != "" {
+		println("logger initialized - output will be written to", cfg.LogPath)
+	}
+
+	// initialize tracing
+	println("initializing tracing")
+	shutdownTracing, err := tracing.New(l, cfg.Tracing, "nexus-delegator", Version)
+	if err != nil {
+		fatal(err.Error())
+	}
+	defer func() {
+		flush, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flush); err != nil {
+			l.Warnw("error occurred flushing traces", "error", err)
+		}
+	}()
+
+	// set up database connection - tables are migrated by the daemon
+	l.Infow("intializing database connection",
+		"db.host", standalone.Database.URL,
+		"db.port", standalone.Database.Port,
+		"db.name", standalone.Database.Name,
+		"db.with_ssl", !devMode)
+	dbm, err := database.New(&tcfg.TemporalConfig{
+		Database: standalone.Database,
+	}, database.Options{
+		SSLModeDisable: devMode,
+	})
+	if err != nil {
+		l.Errorw("failed to connect to database", "error", err)
+		fatalf("unable to connect to database: %s", err.Error())
+	}
+	l.Info("successfully connected to database")
+	defer func() {
+		if err := dbm.DB.Close(); err != nil {
+			l.Warnw("error occurred closing database connection",
+				"error", err)
+		}
+	}()
+
+	// connect to daemon
+	println("connecting to daemon")
+	c, err := client.New(standalone.Daemon, devMode)
+	if err != nil {
+		fatal(err.Error())
+	}
+	defer c.Close()
+	var mirror = registry.NewMirror(l,
+		time.Duration(standalone.ReconnectSeconds)*time.Second)
+
+	// proxy requests to nodes on the daemon's host
+	var nodeAddress = standalone.NodeAddress
+	if nodeAddress == "" {
+		nodeAddress = standalone.Daemon.Host
+	}
+	var opts = delegatorOpts(cfg, devMode)
+	opts.Bind = config.Bind{
+		Swarm:   []string{nodeAddress},
+		SwarmWS: []string{nodeAddress},
+		API:     []string{nodeAddress},
+		Gateway: []string{nodeAddress},
+	}
+	l.Infow("routing requests to daemon host",
+		"daemon", standalone.Daemon.Host+":"+standalone.Daemon.Port,
+		"nodes", nodeAddress)
+	// node ports serve the IPFS API without any of the delegator's access
+	// controls, so they should only be reachable from delegators
+	var nodeIPs = []string{nodeAddress}
+	if net.ParseIP(nodeAddress) == nil {
+		if ips, err := net.LookupHost(nodeAddress); err == nil {
+			nodeIPs = ips
+		}
+	}
+	for _, ip := range nodeIPs {
+		if !network.IsPrivate(ip) {
+			l.Warnw("node ports are published on an address that is not private - "+
+				"firewall them so that only delegators can reach them",
+				"nodes", nodeAddress,
+				"address", ip)
+			break
+		}
+	}
+
+	// initialize delegator
+	println("initializing delegator")
+	dl, err := delegator.New(l, opts, mirror,
+		models.NewHostedNetworkManager(dbm.DB), delegator.Stores{
+			Settings: store.NewSettingsManager(dbm.DB),
+			Tokens:   store.NewTokenManager(dbm.DB),
+			Denylist: store.NewDenylistManager(dbm.DB),
+			Domains:  store.NewDomainManager(dbm.DB),
+			Usage:    store.NewUsageManager(dbm.DB),
+		})
+	if err != nil {
+		fatal(err.Error())
+	}
+	mirror.OnNetworkChange(dl.InvalidateNetwork)
+	mirror.OnDenylistChange(dl.ReloadDenylist)
+	mirror.OnDomainChange(dl.ReloadDomain)
+	mirror.OnResync(dl.Reload)
+
+	// catch interrupts
+	ctx, cancel := context.WithCancel(context.Background())
+	var signals = make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	// reload certificates on hangup
+	var hangups = make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		for range hangups {
+			l.Info("reloading delegator certificate")
+			dl.ReloadCertificate()
+		}
+	}()
+
+	// mirror registry
+	println("watching daemon registry...")
+	go mirror.Sync(ctx, c.ControlClient)
+
+	// serve delegator
+	println("spinning up delegator...")
+	go func() {
+		if err := dl.Run(ctx, cfg.Delegator); err != nil {
+			println(err.Error())
+		}
+		cancel()
+	}()
+
+	// block
+	<-ctx.Done()
+	println("delegator shut down")
+}
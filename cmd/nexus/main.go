@@ -28,6 +28,8 @@ COMMANDS:
 
   init        initialize configuration
 	daemon      spin up the Nexus daemon and related processes
+	delegator   spin up a delegator apart from the daemon, such as on an edge machine
+	usage       export the bandwidth usage of networks as CSV or JSON
 	version     display program version
 
 	dev         [DEV] utilities for development purposes
@@ -67,6 +69,14 @@ func main() {
 		case "daemon":
 			runDaemon(*configPath, *devMode, args[1:])
 			return
+		// run delegator apart from daemon
+		case "delegator":
+			runDelegator(*configPath, *devMode, args[1:])
+			return
+		// export usage
+		case "usage":
+			runUsage(*configPath, *devMode, args[1:])
+			return
 		// run ctl
 		case "ctl":
 			if len(args) > 1 && (args[1] == "-pretty" || args[1] == "--pretty") {
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/client"
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/rpc"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// runUsage exports the hourly bandwidth usage of networks recorded by the
+// daemon as CSV or JSON
+func runUsage(configPath string, devMode bool, args []string) {
+	var (
+		flags   = flag.NewFlagSet("usage", flag.ExitOnError)
+		network = flags.String("network", "",
+			"network to export usage of - all networks if not provided")
+		feature = flags.String("feature", "",
+			"feature to export usage of, one of 'api', 'gateway', or 'swarm' - all features if not provided")
+		since = flags.String("since", "",
+			"start of time range, as a date, RFC3339 time, or duration before now - start of the current month if not provided")
+		until = flags.String("until", "",
+			"end of time range, as a date, RFC3339 time, or duration before now - now if not provided")
+		format = flags.String("format", "csv",
+			"output format, one of 'csv' or 'json'")
+		output = flags.String("out", "",
+			"file to write usage to - stdout if not provided")
+	)
+	flags.Parse(args)
+
+	// parse time range
+	var now = time.Now().UTC()
+	var req = &rpc.UsageRequest{Network: *network, Feature: *feature}
+	if *since == "" {
+		req.Since = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Unix()
+	} else if t, err := parseUsageTime(*since, now); err != nil {
+		fatalf("invalid -since: %s", err.Error())
+	} else {
+		req.Since = t.Unix()
+	}
+	if *until != "" {
+		t, err := parseUsageTime(*until, now)
+		if err != nil {
+			fatalf("invalid -until: %s", err.Error())
+		}
+		req.Until = t.Unix()
+	}
+	var write func(io.Writer, []*rpc.UsageRecord) error
+	switch *format {
+	case "csv":
+		write = writeUsageCSV
+	case "json":
+		write = writeUsageJSON
+	default:
+		fatalf("unknown format '%s'", *format)
+	}
+
+	// load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fatal(err.Error())
+	}
+
+	// retrieve usage from daemon
+	c, err := client.New(cfg.API, devMode)
+	if err != nil {
+		fatal(err.Error())
+	}
+	defer c.Close()
+	resp, err := c.GetUsage(context.Background(), req)
+	if err != nil {
+		fatal(err.Error())
+	}
+
+	// write usage
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fatal(err.Error())
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := write(out, resp.GetRecords()); err != nil {
+		fatal(err.Error())
+	}
+}
+
+// parseUsageTime reads a date, an RFC3339 time, or a duration before now
+func parseUsageTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// writeUsageCSV writes usage records as CSV with a header row
+func writeUsageCSV(w io.Writer, records []*rpc.UsageRecord) error {
+	var out = csv.NewWriter(w)
+	out.Write([]string{"hour", "network", "feature", "user", "bytes_in", "bytes_out"})
+	for _, r := range records {
+		out.Write([]string{
+			time.Unix(r.GetHour(), 0).UTC().Format(time.RFC3339),
+			r.GetNetwork(),
+			r.GetFeature(),
+			r.GetUser(),
+			strconv.FormatInt(r.GetBytesIn(), 10),
+			strconv.FormatInt(r.GetBytesOut(), 10),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeUsageJSON writes usage records as a JSON array
+func writeUsageJSON(w io.Writer, records []*rpc.UsageRecord) error {
+	var usage = make([]store.UsageRecord, len(records))
+	for i, r := range records {
+		usage[i] = store.UsageRecord{
+			Hour:     time.Unix(r.GetHour(), 0).UTC(),
+			Network:  r.GetNetwork(),
+			Feature:  r.GetFeature(),
+			User:     r.GetUser(),
+			BytesIn:  r.GetBytesIn(),
+			BytesOut: r.GetBytesOut(),
+		}
+	}
+	var enc = json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(usage); err != nil {
+		return fmt.Errorf("failed to write usage: %s", err.Error())
+	}
+	return nil
+}
@@ -15,10 +15,19 @@ const DefaultIPFSVersion = "v0.4.20"
 type IPFSOrchestratorConfig struct {
 	// Address is the address through which external clients connect to this host
 	Address string `json:"address"`
+	// AddressIPv6, if given, is the IPv6 address through which external clients
+	// connect to this host
+	AddressIPv6 string `json:"address_ipv6"`
 
 	// LogPath, if given, will be where logs are written
 	LogPath string `json:"log_path"`
 
+	// Tracing declares export of request traces
+	Tracing Tracing `json:"tracing"`
+
+	// Usage declares metering of the bandwidth used by networks
+	Usage Usage `json:"usage"`
+
 	IPFS          `json:"ipfs"`
 	API           `json:"api"`
 	Delegator     `json:"delegator"`
@@ -31,16 +40,89 @@ type IPFS struct {
 	DataDirectory string `json:"data_dir"`
 	ModePerm      string `json:"perm_mode"`
 	Ports         `json:"ports"`
+	Bind          `json:"bind"`
 }
 
 // Ports declares port-range configuration for IPFS nodes. Elements of each
 // array can be of the form "<PORT>" or "<LOWER>-<UPPER>"
 type Ports struct {
 	Swarm   []string `json:"swarm"`
+	SwarmWS []string `json:"swarm_ws"`
+	API     []string `json:"api"`
+	Gateway []string `json:"gateway"`
+}
+
+// Bind declares the host addresses that IPFS node ports are published on.
+// Elements of each array can be IPv4 or IPv6 addresses - providing both, for
+// example "0.0.0.0" and "::", publishes the port on both families
+type Bind struct {
+	Swarm   []string `json:"swarm"`
+	SwarmWS []string `json:"swarm_ws"`
 	API     []string `json:"api"`
 	Gateway []string `json:"gateway"`
 }
 
+// WithDefaults returns a copy of this configuration with empty address lists
+// set to the defaults - swarm ports are public, while WebSocket swarm, API,
+// and gateway ports are only reachable from the host, and should be accessed
+// via the delegator
+func (b Bind) WithDefaults() Bind {
+	if len(b.Swarm) == 0 {
+		b.Swarm = []string{"0.0.0.0"}
+	}
+	if len(b.SwarmWS) == 0 {
+		b.SwarmWS = []string{"127.0.0.1"}
+	}
+	if len(b.API) == 0 {
+		b.API = []string{"127.0.0.1"}
+	}
+	if len(b.Gateway) == 0 {
+		b.Gateway = []string{"127.0.0.1"}
+	}
+	return b
+}
+
+// Trace exporters
+const (
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// Tracing declares export of OpenTelemetry traces of delegator requests and
+// orchestrator operations. Tracing is enabled if an exporter is set.
+type Tracing struct {
+	// Exporter is where spans are sent - "otlp" for an OTLP/HTTP collector, or
+	// "file" to write them to Path as JSON
+	Exporter string `json:"exporter"`
+
+	// Endpoint is the address of the collector, such as "127.0.0.1:4318"
+	Endpoint string `json:"endpoint"`
+	// Insecure disables TLS for connections to the collector
+	Insecure bool `json:"insecure"`
+
+	// Path is the file spans are appended to
+	Path string `json:"path"`
+
+	// SampleRatio is the fraction of traces that are recorded, unless the
+	// caller has already decided whether a trace is sampled
+	SampleRatio float64 `json:"sample_ratio"`
+}
+
+// Enabled checks if traces should be exported
+func (t Tracing) Enabled() bool { return t.Exporter != "" }
+
+// Usage declares how the bandwidth used by networks is metered. Bytes
+// transferred are aggregated hourly per network, feature, and user in the
+// database.
+type Usage struct {
+	// SampleSeconds is how often the network counters of node containers are
+	// sampled to meter swarm traffic
+	SampleSeconds int `json:"sample_seconds"`
+	// FlushSeconds is how often bytes counted by the delegator are written to
+	// the database
+	FlushSeconds int `json:"flush_seconds"`
+}
+
 // API declares configuration for the orchestrator daemon's gRPC API
 type API struct {
 	Host string `json:"host"`
@@ -56,6 +138,339 @@ type Delegator struct {
 	Port   string `json:"port"`
 	JWTKey string `json:"jwt_key"`
 	TLS    `json:"tls"`
+
+	// ACME declares automatic issuance of certificates for network hosts,
+	// which takes precedence over TLS
+	ACME ACME `json:"acme"`
+
+	// JWT declares how tokens signed by external identity providers are
+	// verified, in addition to tokens signed with JWTKey
+	JWT JWT `json:"jwt"`
+
+	// Admin declares the listener for delegator administration endpoints, such
+	// as metrics, which should not be publicly exposed
+	Admin Admin `json:"admin"`
+
+	// RateLimits declares default request rate limits for network features,
+	// which can be overridden per network
+	RateLimits RateLimits `json:"rate_limits"`
+
+	// Commands declares the default IPFS API command policy, which can be
+	// overridden per network
+	Commands CommandPolicy `json:"commands"`
+
+	// DefaultRole is the role of network users who have not been assigned one,
+	// one of "viewer", "writer", or "admin"
+	DefaultRole string `json:"default_role"`
+
+	// NetworkCache declares caching of network access settings
+	NetworkCache NetworkCache `json:"network_cache"`
+
+	// GatewayCache declares caching of immutable gateway content
+	GatewayCache GatewayCache `json:"gateway_cache"`
+
+	// SubdomainGateway enables serving gateway content from hosts of the form
+	// "<cid>.ipfs.<network>.gateway.<domain>" and
+	// "<name>.ipns.<network>.gateway.<domain>", so that each CID or IPNS name
+	// has its own origin. Path-style gateway requests are redirected to these
+	// hosts. With ACME, certificates are issued for CID hosts using "http-01"
+	// challenges, and "dns-01" challenges cannot be used.
+	SubdomainGateway bool `json:"subdomain_gateway"`
+
+	// DNSLink declares how DNSLink records of custom domains are resolved
+	DNSLink DNSLink `json:"dnslink"`
+
+	// Breaker declares how requests to nodes that cannot be reached, such as
+	// while they restart, are rejected without waiting on the node
+	Breaker Breaker `json:"breaker"`
+
+	// BodyLimits declares default request body size limits for network
+	// features, which can be overridden per network
+	BodyLimits BodyLimits `json:"body_limits"`
+
+	// Timeouts declares how long proxied requests may take
+	Timeouts Timeouts `json:"timeouts"`
+
+	// Egress declares default limits on the bytes served by network gateways,
+	// which can be overridden per network
+	Egress Egress `json:"egress"`
+
+	// TrustedProxies lists the IPs or CIDR ranges of proxies in front of the
+	// delegator. Client addresses are only taken from the X-Forwarded-For and
+	// X-Real-IP headers of requests from these proxies, so that clients
+	// cannot spoof their address to bypass access rules.
+	TrustedProxies []string `json:"trusted_proxies"`
+
+	// CORS declares the default cross-origin resource sharing policies of
+	// network features, which can be overridden per network
+	CORS CORS `json:"cors"`
+
+	// Standalone declares how a delegator run with 'nexus delegator', apart
+	// from the daemon, connects to the daemon and the database
+	Standalone Standalone `json:"standalone"`
+}
+
+// Standalone declares a delegator that runs apart from the daemon, such as on
+// an edge machine. It keeps a mirror of the daemon's node registry, and
+// continues to route requests to known nodes while the daemon is unreachable.
+type Standalone struct {
+	// Daemon is the gRPC API of the daemon the node registry is mirrored from
+	Daemon API `json:"daemon"`
+
+	// NodeAddress is the address that the daemon's host publishes node API,
+	// gateway, and WebSocket swarm ports on, which must be reachable from the
+	// delegator - the daemon's host is used if not set. Node ports are only
+	// published on the addresses set in the daemon's ipfs.bind configuration,
+	// and bypass all access controls of the delegator, so they should only be
+	// reachable from delegators.
+	NodeAddress string `json:"node_address"`
+
+	// ReconnectSeconds is the longest wait between attempts to reconnect to
+	// the daemon
+	ReconnectSeconds int `json:"reconnect_seconds"`
+
+	// Database is the delegator's own database connection, so that it does
+	// not share the daemon's credentials
+	Database tcfg.Database `json:"postgres"`
+}
+
+// CORS declares the cross-origin resource sharing policy of each proxied
+// network feature. Swarm connections are not subject to CORS.
+type CORS struct {
+	API     CORSPolicy `json:"api"`
+	Gateway CORSPolicy `json:"gateway"`
+	// Status applies to the delegator's status endpoint, and to the status,
+	// diagnostics, and swarm key endpoints of each network
+	Status CORSPolicy `json:"status"`
+}
+
+// Feature retrieves the policy of given feature, or an empty policy, which
+// permits no cross-origin requests, if the feature has none
+func (c CORS) Feature(feature string) CORSPolicy {
+	switch feature {
+	case "api":
+		return c.API
+	case "gateway":
+		return c.Gateway
+	case "status":
+		return c.Status
+	default:
+		return CORSPolicy{}
+	}
+}
+
+// CORSPolicy declares which cross-origin requests browsers may make
+type CORSPolicy struct {
+	// AllowedOrigins are the origins that may make requests, such as
+	// "https://example.com". Origins may match all subdomains of a host, such
+	// as "https://*.example.com", or "*" may be used to allow all origins.
+	AllowedOrigins []string `json:"allowed_origins"`
+	// AllowedMethods are the methods that may be used in requests
+	AllowedMethods []string `json:"allowed_methods"`
+	// AllowedHeaders are the headers that may be set in requests - "*"
+	// allows all headers
+	AllowedHeaders []string `json:"allowed_headers"`
+	// ExposedHeaders are the response headers that browsers may read
+	ExposedHeaders []string `json:"exposed_headers"`
+	// MaxAgeSeconds is how long browsers may cache the results of preflight
+	// requests
+	MaxAgeSeconds int `json:"max_age_seconds"`
+	// AllowCredentials allows requests to include cookies and other
+	// credentials managed by the browser
+	AllowCredentials bool `json:"allow_credentials"`
+}
+
+// BodyLimits declares the maximum size of request bodies of each proxied
+// network feature, in megabytes
+type BodyLimits struct {
+	API     int64 `json:"api"`
+	Gateway int64 `json:"gateway"`
+}
+
+// Egress declares limits on the bytes sent in gateway response bodies of a
+// network. Limits set to 0 are disabled.
+type Egress struct {
+	// BytesPerSecond is the rate at which a network's gateway responses are
+	// sent, shared by all of its requests
+	BytesPerSecond int64 `json:"bytes_per_second"`
+	// BurstBytes is how many bytes can be sent at once before responses are
+	// throttled, such as after the gateway has been idle - it is at least
+	// BytesPerSecond
+	BurstBytes int64 `json:"burst_bytes"`
+	// MonthlyCapGB is how many gigabytes a network's gateway can serve each
+	// calendar month (UTC), after which gateway requests are rejected
+	MonthlyCapGB int64 `json:"monthly_cap_gb"`
+}
+
+// Feature retrieves the body size limit for given feature in megabytes, or 0
+// if request bodies of the feature are not limited
+func (b BodyLimits) Feature(feature string) int64 {
+	switch feature {
+	case "api":
+		return b.API
+	case "gateway":
+		return b.Gateway
+	default:
+		return 0
+	}
+}
+
+// Timeouts declares how long proxied requests may take. Streaming uploads are
+// bounded by how long they go without transferring data rather than by how
+// long they take in total, so that large uploads are not cut off.
+type Timeouts struct {
+	// GatewaySeconds bounds how long gateway requests may take
+	GatewaySeconds int `json:"gateway_seconds"`
+	// StreamIdleSeconds bounds how long streaming uploads, such as the "add"
+	// and "dag/import" API commands, may go without transferring data
+	StreamIdleSeconds int `json:"stream_idle_seconds"`
+}
+
+// Breaker declares a circuit breaker for each network's node. Once a number of
+// consecutive requests fail, requests are rejected for a while, after which a
+// single request is let through to probe if the node has recovered.
+type Breaker struct {
+	// FailureThreshold is the number of consecutive failed requests after
+	// which requests to a node are rejected
+	FailureThreshold int `json:"failure_threshold"`
+	// OpenSeconds is how long requests are rejected for before the node is
+	// probed
+	OpenSeconds int `json:"open_seconds"`
+	// DialTimeoutSeconds bounds how long connecting to a node may take
+	DialTimeoutSeconds int `json:"dial_timeout_seconds"`
+	// GatewayRetries is the number of times idempotent gateway requests are
+	// retried if a node cannot be reached - requests are not retried if 0
+	GatewayRetries int `json:"gateway_retries"`
+}
+
+// DNSLink declares resolution of the DNSLink records that custom domains
+// without a root path serve content from
+type DNSLink struct {
+	// Resolver is the address of the DNS server that records are queried
+	// from, such as "1.1.1.1:53" - the system resolver is used if not set
+	Resolver string `json:"resolver"`
+	// TTLSeconds is how long resolved records are cached
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+// NetworkCache declares how long network access settings, such as users and
+// policies, are cached by the delegator
+type NetworkCache struct {
+	// TTLSeconds is how long settings are used before they are refreshed
+	TTLSeconds int `json:"ttl_seconds"`
+	// NegativeTTLSeconds is how long networks that do not exist are remembered
+	NegativeTTLSeconds int `json:"negative_ttl_seconds"`
+	// StaleSeconds is how long expired settings can continue to be used if
+	// they cannot be refreshed, such as during a database outage
+	StaleSeconds int `json:"stale_seconds"`
+}
+
+// GatewayCache declares an on-disk cache of immutable gateway content, such as
+// responses to requests for /ipfs/ paths. Least recently used content is
+// evicted when a size limit is reached.
+type GatewayCache struct {
+	// Path is the directory cached content is stored in - caching is disabled
+	// if no path is provided
+	Path string `json:"path"`
+	// MaxSizeMB limits the total size of cached content
+	MaxSizeMB int64 `json:"max_size_mb"`
+	// NetworkMaxSizeMB limits the size of each network's cached content
+	NetworkMaxSizeMB int64 `json:"network_max_size_mb"`
+	// MaxObjectSizeMB limits the size of individual responses that are cached
+	MaxObjectSizeMB int64 `json:"max_object_size_mb"`
+}
+
+// DefaultDeniedCommands are node management commands that are blocked by
+// default, since they can disrupt or reconfigure a hosted node
+var DefaultDeniedCommands = []string{
+	"bootstrap/add",
+	"bootstrap/rm",
+	"config",
+	"log/level",
+	"p2p",
+	"repo/fsck",
+	"repo/gc",
+	"shutdown",
+	"swarm/filters/add",
+	"swarm/filters/rm",
+	"update",
+}
+
+// CommandPolicy declares which IPFS API commands may be called through the
+// delegator. Entries match a command and all of its subcommands, so
+// "bootstrap" also matches "bootstrap/rm". Deny takes precedence over Allow,
+// and if Allow is set, only commands that match it are permitted.
+type CommandPolicy struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// JWT declares verification of JSON web tokens presented to the delegator
+type JWT struct {
+	// JWKSPath is a JSON Web Key Set file containing the public keys used to
+	// verify RS256, ES256, and EdDSA tokens. Keys are selected by each token's
+	// "kid" header, and the file is reloaded when it changes.
+	JWKSPath string `json:"jwks_path"`
+
+	// Issuer and Audience, if set, must match each token's "iss" and "aud"
+	// claims respectively
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+
+	// LeewaySeconds is the clock skew tolerated when validating the "exp",
+	// "nbf", and "iat" claims
+	LeewaySeconds int `json:"leeway_seconds"`
+}
+
+// RateLimits declares request rate limits for each proxied network feature
+type RateLimits struct {
+	API     FeatureRateLimits `json:"api"`
+	Gateway FeatureRateLimits `json:"gateway"`
+
+	// MaxBuckets bounds the number of rate limit states tracked at once - the
+	// least recently used are evicted first
+	MaxBuckets int `json:"max_buckets"`
+}
+
+// Feature retrieves the rate limits for given feature
+func (r RateLimits) Feature(feature string) FeatureRateLimits {
+	switch feature {
+	case "api":
+		return r.API
+	case "gateway":
+		return r.Gateway
+	default:
+		return FeatureRateLimits{}
+	}
+}
+
+// FeatureRateLimits declares request rate limits for a network feature
+type FeatureRateLimits struct {
+	// Network is shared by all requests to a network's feature
+	Network RateLimit `json:"network"`
+	// User is applied to each authenticated user of a network's feature
+	User RateLimit `json:"user"`
+}
+
+// RateLimit declares a token bucket that refills at Rate requests per second,
+// holding at most Burst requests (minimum 1). A Rate of 0 disables the limit.
+type RateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Enabled checks if the rate limit should be enforced
+func (r RateLimit) Enabled() bool { return r.Rate > 0 }
+
+// Admin declares configuration for an administrative HTTP listener. The
+// listener is only started if a port is set.
+type Admin struct {
+	Host string `json:"host"`
+	Port string `json:"port"`
+
+	// Token must be provided as a bearer token to use administration
+	// endpoints other than metrics, which are disabled if no token is set
+	Token string `json:"token"`
 }
 
 // TLS declares HTTPS configuration
@@ -64,6 +479,50 @@ type TLS struct {
 	KeyPath  string `json:"key"`
 }
 
+// ACME challenge types
+const (
+	ChallengeHTTP = "http-01"
+	ChallengeDNS  = "dns-01"
+)
+
+// ACME declares issuance of certificates for the delegator's network hosts by
+// an ACME certificate authority, such as Let's Encrypt. Issuance is enabled if
+// a challenge type is set.
+type ACME struct {
+	// Challenge is the type of challenge used to prove control of hosts. With
+	// "dns-01", wildcard certificates are issued for all network hosts. With
+	// "http-01", a certificate is issued for each network host and custom
+	// domain when it is first requested, which requires HTTPPort to be
+	// reachable. Custom domains and subdomain gateway hosts are not covered
+	// by "dns-01" certificates.
+	Challenge string `json:"challenge"`
+
+	// DirectoryURL is the ACME directory of the certificate authority
+	DirectoryURL string `json:"directory_url"`
+	// CARootsPath, if set, is a PEM bundle of the roots trusted when
+	// connecting to the directory, such as those of a test CA
+	CARootsPath string `json:"ca_roots"`
+	// Email is the account contact for expiry and revocation notices
+	Email string `json:"email"`
+	// CachePath is the directory the account key and certificates are kept in
+	CachePath string `json:"cache_path"`
+	// RenewBeforeDays is how long before expiry certificates are renewed
+	RenewBeforeDays int `json:"renew_before_days"`
+
+	// HTTPPort is the port "http-01" challenges are served on - other requests
+	// to it are redirected to HTTPS
+	HTTPPort string `json:"http_port"`
+
+	// DNSHook is an executable that publishes "dns-01" challenge records. It
+	// is invoked with "present" or "cleanup", the record's fully qualified
+	// name, and its value, and must only exit once the change is visible to
+	// the certificate authority.
+	DNSHook string `json:"dns_hook"`
+}
+
+// Enabled checks if certificates should be issued
+func (a ACME) Enabled() bool { return a.Challenge != "" }
+
 // New creates a new, default configuration
 func New() IPFSOrchestratorConfig {
 	var cfg IPFSOrchestratorConfig
@@ -104,6 +563,14 @@ func (c *IPFSOrchestratorConfig) SetDefaults(dev bool) {
 		c.API.Key = "DO_NOT_LEAVE_ME_AS_DEFAULT"
 	}
 
+	// Usage metering settings
+	if c.Usage.SampleSeconds == 0 {
+		c.Usage.SampleSeconds = 300
+	}
+	if c.Usage.FlushSeconds == 0 {
+		c.Usage.FlushSeconds = 60
+	}
+
 	// Proxy (delegator) settings
 	if c.Delegator.Host == "" {
 		c.Delegator.Host = "127.0.0.1"
@@ -125,6 +592,155 @@ func (c *IPFSOrchestratorConfig) SetDefaults(dev bool) {
 	if c.Delegator.Domain == "" && !dev {
 		c.Delegator.Domain = "nexus.temporal.cloud"
 	}
+	if c.Delegator.Admin.Host == "" {
+		c.Delegator.Admin.Host = "127.0.0.1"
+	}
+	if c.Delegator.Admin.Port == "" {
+		c.Delegator.Admin.Port = "9112"
+	}
+	if c.Delegator.RateLimits.MaxBuckets == 0 {
+		c.Delegator.RateLimits.MaxBuckets = 10000
+	}
+	if c.Delegator.NetworkCache.TTLSeconds == 0 {
+		c.Delegator.NetworkCache.TTLSeconds = 30
+	}
+	if c.Delegator.NetworkCache.NegativeTTLSeconds == 0 {
+		c.Delegator.NetworkCache.NegativeTTLSeconds = 5
+	}
+	if c.Delegator.NetworkCache.StaleSeconds == 0 {
+		c.Delegator.NetworkCache.StaleSeconds = 300
+	}
+	if c.Delegator.GatewayCache.MaxSizeMB == 0 {
+		c.Delegator.GatewayCache.MaxSizeMB = 10240
+	}
+	if c.Delegator.GatewayCache.NetworkMaxSizeMB == 0 {
+		c.Delegator.GatewayCache.NetworkMaxSizeMB = 1024
+	}
+	if c.Delegator.GatewayCache.MaxObjectSizeMB == 0 {
+		c.Delegator.GatewayCache.MaxObjectSizeMB = 64
+	}
+	if c.Delegator.ACME.DirectoryURL == "" {
+		c.Delegator.ACME.DirectoryURL = "https://acme-v02.api.letsencrypt.org/directory"
+	}
+	if c.Delegator.ACME.RenewBeforeDays == 0 {
+		c.Delegator.ACME.RenewBeforeDays = 30
+	}
+	if c.Delegator.ACME.HTTPPort == "" {
+		c.Delegator.ACME.HTTPPort = "80"
+	}
+	if c.Delegator.DNSLink.TTLSeconds == 0 {
+		c.Delegator.DNSLink.TTLSeconds = 60
+	}
+	if c.Delegator.Breaker.FailureThreshold == 0 {
+		c.Delegator.Breaker.FailureThreshold = 5
+	}
+	if c.Delegator.Breaker.OpenSeconds == 0 {
+		c.Delegator.Breaker.OpenSeconds = 10
+	}
+	if c.Delegator.Breaker.DialTimeoutSeconds == 0 {
+		c.Delegator.Breaker.DialTimeoutSeconds = 5
+	}
+	if c.Delegator.BodyLimits.API == 0 {
+		c.Delegator.BodyLimits.API = 1024
+	}
+	if c.Delegator.BodyLimits.Gateway == 0 {
+		c.Delegator.BodyLimits.Gateway = 1
+	}
+	if c.Delegator.Timeouts.GatewaySeconds == 0 {
+		c.Delegator.Timeouts.GatewaySeconds = 15
+	}
+	if c.Delegator.Timeouts.StreamIdleSeconds == 0 {
+		c.Delegator.Timeouts.StreamIdleSeconds = 60
+	}
+	if c.Delegator.TrustedProxies == nil {
+		c.Delegator.TrustedProxies = []string{"127.0.0.1", "::1"}
+	}
+	if c.Delegator.DefaultRole == "" {
+		c.Delegator.DefaultRole = "writer"
+	}
+	if c.Delegator.Commands.Allow == nil && c.Delegator.Commands.Deny == nil {
+		c.Delegator.Commands.Deny = append([]string(nil), DefaultDeniedCommands...)
+	}
+	if c.Delegator.CORS.API.AllowedOrigins == nil {
+		c.Delegator.CORS.API.AllowedOrigins = []string{"*"}
+	}
+	if c.Delegator.CORS.API.AllowedMethods == nil {
+		c.Delegator.CORS.API.AllowedMethods = []string{"GET", "POST"}
+	}
+	if c.Delegator.CORS.API.AllowedHeaders == nil {
+		c.Delegator.CORS.API.AllowedHeaders = []string{"*"}
+	}
+	if c.Delegator.CORS.API.ExposedHeaders == nil {
+		c.Delegator.CORS.API.ExposedHeaders = []string{"X-Stream-Output", "X-Chunked-Output", "X-Content-Length"}
+	}
+	if c.Delegator.CORS.API.MaxAgeSeconds == 0 {
+		c.Delegator.CORS.API.MaxAgeSeconds = 600
+	}
+	if c.Delegator.CORS.Gateway.AllowedOrigins == nil {
+		c.Delegator.CORS.Gateway.AllowedOrigins = []string{"*"}
+	}
+	if c.Delegator.CORS.Gateway.AllowedMethods == nil {
+		c.Delegator.CORS.Gateway.AllowedMethods = []string{"GET", "HEAD"}
+	}
+	if c.Delegator.CORS.Gateway.AllowedHeaders == nil {
+		c.Delegator.CORS.Gateway.AllowedHeaders = []string{"Content-Type", "Range", "User-Agent", "X-Requested-With"}
+	}
+	if c.Delegator.CORS.Gateway.ExposedHeaders == nil {
+		c.Delegator.CORS.Gateway.ExposedHeaders = []string{"Content-Length", "Content-Range", "X-Ipfs-Path", "X-Ipfs-Roots"}
+	}
+	if c.Delegator.CORS.Gateway.MaxAgeSeconds == 0 {
+		c.Delegator.CORS.Gateway.MaxAgeSeconds = 600
+	}
+	if c.Delegator.CORS.Status.AllowedOrigins == nil {
+		c.Delegator.CORS.Status.AllowedOrigins = []string{"*"}
+	}
+	if c.Delegator.CORS.Status.AllowedMethods == nil {
+		c.Delegator.CORS.Status.AllowedMethods = []string{"GET", "HEAD"}
+	}
+	if c.Delegator.CORS.Status.AllowedHeaders == nil {
+		c.Delegator.CORS.Status.AllowedHeaders = []string{"Authorization"}
+	}
+	if c.Delegator.CORS.Status.MaxAgeSeconds == 0 {
+		c.Delegator.CORS.Status.MaxAgeSeconds = 600
+	}
+	if c.Delegator.Standalone.Daemon.Host == "" {
+		c.Delegator.Standalone.Daemon.Host = "127.0.0.1"
+	}
+	if c.Delegator.Standalone.Daemon.Port == "" {
+		c.Delegator.Standalone.Daemon.Port = "9111"
+	}
+	if c.Delegator.Standalone.Daemon.Key == "" {
+		c.Delegator.Standalone.Daemon.Key = "DO_NOT_LEAVE_ME_AS_DEFAULT"
+	}
+	if c.Delegator.Standalone.ReconnectSeconds == 0 {
+		c.Delegator.Standalone.ReconnectSeconds = 30
+	}
+	if c.Delegator.Standalone.Database.URL == "" {
+		c.Delegator.Standalone.Database.URL = "127.0.0.1"
+	}
+	if c.Delegator.Standalone.Database.Port == "" {
+		if dev {
+			c.Delegator.Standalone.Database.Port = "5433"
+		} else {
+			c.Delegator.Standalone.Database.Port = "5432"
+		}
+	}
+	if dev {
+		if c.Delegator.Standalone.Database.Username == "" {
+			c.Delegator.Standalone.Database.Username = "postgres"
+		}
+		if c.Delegator.Standalone.Database.Password == "" {
+			c.Delegator.Standalone.Database.Password = "password123"
+		}
+	}
+
+	// Tracing settings
+	if c.Tracing.Endpoint == "" {
+		c.Tracing.Endpoint = "127.0.0.1:4318"
+	}
+	if c.Tracing.SampleRatio == 0 {
+		c.Tracing.SampleRatio = 1
+	}
 
 	// Database settings
 	if c.Database.URL == "" {
@@ -163,10 +779,14 @@ func (c *IPFSOrchestratorConfig) SetDefaults(dev bool) {
 	if c.IPFS.Ports.Swarm == nil {
 		c.IPFS.Ports.Swarm = []string{"4001-5000"}
 	}
+	if c.IPFS.Ports.SwarmWS == nil {
+		c.IPFS.Ports.SwarmWS = []string{"9001-10000"}
+	}
 	if c.IPFS.Ports.API == nil {
 		c.IPFS.Ports.API = []string{"5001-6000"}
 	}
 	if c.IPFS.Ports.Gateway == nil {
 		c.IPFS.Ports.Gateway = []string{"8001-9000"}
 	}
+	c.IPFS.Bind = c.IPFS.Bind.WithDefaults()
 }
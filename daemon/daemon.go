@@ -8,6 +8,8 @@ import (
 
 	"github.com/RTradeLtd/Nexus/config"
 	"github.com/RTradeLtd/Nexus/orchestrator"
+	"github.com/RTradeLtd/Nexus/rpc"
+	"github.com/RTradeLtd/Nexus/tracing"
 	"github.com/RTradeLtd/grpc/middleware"
 	"github.com/RTradeLtd/grpc/nexus"
 	"go.uber.org/zap"
@@ -25,14 +27,29 @@ import (
 type Daemon struct {
 	o *orchestrator.Orchestrator
 	l *zap.SugaredLogger
+
+	watchers *watchers
 }
 
 // New initializes a new Daemon
 func New(logger *zap.SugaredLogger, o *orchestrator.Orchestrator) *Daemon {
 	d := &Daemon{
-		o: o,
-		l: logger.Named("daemon"),
+		o:        o,
+		l:        logger.Named("daemon"),
+		watchers: newWatchers(),
 	}
+
+	// forward changes to registry watchers
+	o.Registry.OnChange(d.nodeChanged)
+	o.OnNetworkChange(func(network string) {
+		d.watchers.publish(&rpc.RegistryEvent{Type: rpc.RegistryEvent_NETWORK_CHANGED, Network: network})
+	})
+	o.OnDenylistChange(func(network string) {
+		d.watchers.publish(&rpc.RegistryEvent{Type: rpc.RegistryEvent_DENYLIST_CHANGED, Network: network})
+	})
+	o.OnDomainChange(func(host string) {
+		d.watchers.publish(&rpc.RegistryEvent{Type: rpc.RegistryEvent_DOMAIN_CHANGED, Host: host})
+	})
 	return d
 }
 
@@ -56,6 +73,7 @@ func (d *Daemon) Run(ctx context.Context, cfg config.API) error {
 	}
 	serverOpts := []grpc.ServerOption{
 		grpc_middleware.WithUnaryServerChain(
+			tracing.UnaryServerInterceptor(),
 			unaryInterceptor,
 			grpc_ctxtags.UnaryServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
 			grpc_zap.UnaryServerInterceptor(grpcLogger, zapOpts...)),
@@ -86,6 +104,7 @@ func (d *Daemon) Run(ctx context.Context, cfg config.API) error {
 	// initialize server
 	server := grpc.NewServer(serverOpts...)
 	nexus.RegisterServiceServer(server, d)
+	rpc.RegisterControlServer(server, d)
 
 	// interrupt server gracefully if context is cancelled
 	go func() {
@@ -3,11 +3,17 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"strings"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 
 	"github.com/RTradeLtd/grpc/nexus"
+
+	"github.com/RTradeLtd/Nexus/orchestrator"
+	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/rpc"
 )
 
 // Ping is useful for checking client-server connection
@@ -107,3 +113,273 @@ func (d *Daemon) NetworkDiagnostics(
 		Stats:    sb,
 	}, nil
 }
+
+// ListNetworks retrieves a filtered, paginated listing of the networks
+// registered on this host
+func (d *Daemon) ListNetworks(
+	ctx context.Context,
+	req *rpc.ListNetworksRequest,
+) (*rpc.ListNetworksResponse, error) {
+	q, err := newRegistryQuery(req)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	entries, next, err := d.o.Registry.Query(q)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	var networks = make([]*rpc.NetworkInfo, len(entries))
+	for i, e := range entries {
+		networks[i] = newNetworkInfo(e)
+	}
+	return &rpc.ListNetworksResponse{
+		Networks:   networks,
+		NextCursor: next,
+	}, nil
+}
+
+// GetNetworkSettings retrieves the Nexus-specific settings of a network
+func (d *Daemon) GetNetworkSettings(
+	ctx context.Context,
+	req *rpc.NetworkSettingsRequest,
+) (*rpc.NetworkSettingsResponse, error) {
+	s, err := d.o.NetworkSettings(req.GetNetwork())
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, err.Error())
+	}
+	return newNetworkSettingsResponse(s)
+}
+
+// UpdateNetworkSettings updates the Nexus-specific settings of a network
+func (d *Daemon) UpdateNetworkSettings(
+	ctx context.Context,
+	req *rpc.UpdateNetworkSettingsRequest,
+) (*rpc.NetworkSettingsResponse, error) {
+	s, err := d.o.UpdateNetworkSettings(req.GetNetwork(), []byte(req.GetSettings()))
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, err.Error())
+	}
+	return newNetworkSettingsResponse(s)
+}
+
+// BulkNetworkAction applies an action to all networks matching a selector
+func (d *Daemon) BulkNetworkAction(
+	ctx context.Context,
+	req *rpc.BulkNetworkActionRequest,
+) (*rpc.BulkNetworkActionResponse, error) {
+	selector, err := registry.ParseSelector(req.GetSelector())
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	results, err := d.o.NetworksBulk(ctx, selector, orchestrator.BulkAction(req.GetAction()))
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	var resp = &rpc.BulkNetworkActionResponse{
+		Results: make([]*rpc.BulkNetworkActionResult, len(results)),
+	}
+	for i, r := range results {
+		resp.Results[i] = &rpc.BulkNetworkActionResult{Network: r.Network}
+		if r.Err != nil {
+			resp.Results[i].Error = r.Err.Error()
+		}
+	}
+	return resp, nil
+}
+
+// CreateAPIToken issues a scoped API token for a network
+func (d *Daemon) CreateAPIToken(
+	ctx context.Context,
+	req *rpc.CreateAPITokenRequest,
+) (*rpc.CreateAPITokenResponse, error) {
+	var expiry time.Duration
+	if req.GetExpiresIn() != "" {
+		var err error
+		if expiry, err = time.ParseDuration(req.GetExpiresIn()); err != nil {
+			return nil, grpc.Errorf(codes.InvalidArgument, "invalid expiry: %s", err.Error())
+		}
+	}
+	secret, t, err := d.o.CreateAPIToken(req.GetNetwork(), req.GetName(), req.GetScopes(), expiry)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return &rpc.CreateAPITokenResponse{
+		Token:  newAPIToken(t),
+		Secret: secret,
+	}, nil
+}
+
+// RevokeAPIToken revokes a network's API token
+func (d *Daemon) RevokeAPIToken(
+	ctx context.Context,
+	req *rpc.RevokeAPITokenRequest,
+) (*rpc.RevokeAPITokenResponse, error) {
+	if err := d.o.RevokeAPIToken(req.GetNetwork(), req.GetTokenId()); err != nil {
+		return nil, grpc.Errorf(codes.NotFound, err.Error())
+	}
+	return &rpc.RevokeAPITokenResponse{}, nil
+}
+
+// ListAPITokens lists the API tokens issued for a network
+func (d *Daemon) ListAPITokens(
+	ctx context.Context,
+	req *rpc.ListAPITokensRequest,
+) (*rpc.ListAPITokensResponse, error) {
+	tokens, err := d.o.APITokens(req.GetNetwork())
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	var resp = &rpc.ListAPITokensResponse{
+		Tokens: make([]*rpc.APIToken, len(tokens)),
+	}
+	for i, t := range tokens {
+		resp.Tokens[i] = newAPIToken(t)
+	}
+	return resp, nil
+}
+
+// ListNetworkUsers lists the members of a network and their roles
+func (d *Daemon) ListNetworkUsers(
+	ctx context.Context,
+	req *rpc.NetworkUsersRequest,
+) (*rpc.NetworkUsersResponse, error) {
+	users, err := d.o.NetworkUsers(req.GetNetwork())
+	if err != nil {
+		return nil, grpc.Errorf(codes.NotFound, err.Error())
+	}
+	return newNetworkUsersResponse(users), nil
+}
+
+// SetUserRole assigns a role to a network user
+func (d *Daemon) SetUserRole(
+	ctx context.Context,
+	req *rpc.SetUserRoleRequest,
+) (*rpc.NetworkUsersResponse, error) {
+	if err := d.o.SetUserRole(req.GetNetwork(), req.GetUser(), req.GetRole()); err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	users, err := d.o.NetworkUsers(req.GetNetwork())
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, err.Error())
+	}
+	return newNetworkUsersResponse(users), nil
+}
+
+// AddDenylistEntries blocks access to content on a network, or on all networks
+// if no network is provided
+func (d *Daemon) AddDenylistEntries(
+	ctx context.Context,
+	req *rpc.AddDenylistEntriesRequest,
+) (*rpc.UpdateDenylistResponse, error) {
+	count, err := d.o.AddDenylistEntries(req.GetNetwork(), req.GetReason(),
+		strings.Join(req.GetEntries(), "\n"))
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return &rpc.UpdateDenylistResponse{Count: int64(count)}, nil
+}
+
+// RemoveDenylistEntries unblocks content on a network, or on all networks if
+// no network is provided
+func (d *Daemon) RemoveDenylistEntries(
+	ctx context.Context,
+	req *rpc.RemoveDenylistEntriesRequest,
+) (*rpc.UpdateDenylistResponse, error) {
+	count, err := d.o.RemoveDenylistEntries(req.GetNetwork(), req.GetEntries())
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return &rpc.UpdateDenylistResponse{Count: count}, nil
+}
+
+// ListDenylist lists the denylist entries of a network, or the entries that
+// apply to all networks if no network is provided
+func (d *Daemon) ListDenylist(
+	ctx context.Context,
+	req *rpc.ListDenylistRequest,
+) (*rpc.ListDenylistResponse, error) {
+	entries, err := d.o.Denylist(req.GetNetwork())
+	if err != nil {
+		return nil, grpc.Errorf(codes.NotFound, err.Error())
+	}
+	var resp = &rpc.ListDenylistResponse{
+		Entries: make([]*rpc.DenylistEntry, len(entries)),
+	}
+	for i, e := range entries {
+		resp.Entries[i] = newDenylistEntry(e)
+	}
+	return resp, nil
+}
+
+// AddDomain registers a custom domain for a network's gateway, which is served
+// once it is verified
+func (d *Daemon) AddDomain(
+	ctx context.Context,
+	req *rpc.AddDomainRequest,
+) (*rpc.Domain, error) {
+	domain, err := d.o.AddDomain(req.GetNetwork(), req.GetHost(), req.GetRootPath())
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return newDomain(domain), nil
+}
+
+// VerifyDomain checks a custom domain's challenge record, and starts serving
+// the domain if it is correct
+func (d *Daemon) VerifyDomain(
+	ctx context.Context,
+	req *rpc.VerifyDomainRequest,
+) (*rpc.Domain, error) {
+	domain, err := d.o.VerifyDomain(req.GetHost())
+	if err != nil {
+		return nil, grpc.Errorf(codes.FailedPrecondition, err.Error())
+	}
+	return newDomain(domain), nil
+}
+
+// RemoveDomain stops serving a network's custom domain
+func (d *Daemon) RemoveDomain(
+	ctx context.Context,
+	req *rpc.RemoveDomainRequest,
+) (*rpc.RemoveDomainResponse, error) {
+	if err := d.o.RemoveDomain(req.GetNetwork(), req.GetHost()); err != nil {
+		return nil, grpc.Errorf(codes.NotFound, err.Error())
+	}
+	return &rpc.RemoveDomainResponse{}, nil
+}
+
+// ListDomains lists the custom domains of a network
+func (d *Daemon) ListDomains(
+	ctx context.Context,
+	req *rpc.ListDomainsRequest,
+) (*rpc.ListDomainsResponse, error) {
+	domains, err := d.o.Domains(req.GetNetwork())
+	if err != nil {
+		return nil, grpc.Errorf(codes.NotFound, err.Error())
+	}
+	var resp = &rpc.ListDomainsResponse{
+		Domains: make([]*rpc.Domain, len(domains)),
+	}
+	for i, domain := range domains {
+		resp.Domains[i] = newDomain(domain)
+	}
+	return resp, nil
+}
+
+// GetUsage retrieves the hourly bandwidth usage of networks within a time range
+func (d *Daemon) GetUsage(
+	ctx context.Context,
+	req *rpc.UsageRequest,
+) (*rpc.UsageResponse, error) {
+	records, err := d.o.Usage(newUsageQuery(req))
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	var resp = &rpc.UsageResponse{
+		Records: make([]*rpc.UsageRecord, len(records)),
+	}
+	for i, r := range records {
+		resp.Records[i] = newUsageRecord(r)
+	}
+	return resp, nil
+}
@@ -0,0 +1,190 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/orchestrator"
+	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/rpc"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// newRegistryQuery converts a ListNetworks request into a registry query
+func newRegistryQuery(req *rpc.ListNetworksRequest) (registry.Query, error) {
+	var (
+		q = registry.Query{
+			Pattern: req.GetPattern(),
+			State:   registry.State(req.GetState()),
+			Cursor:  req.GetCursor(),
+			Limit:   int(req.GetLimit()),
+		}
+		err error
+	)
+
+	// parse filters
+	if q.MinUptime, err = parseDuration(req.GetMinUptime()); err != nil {
+		return q, fmt.Errorf("invalid min_uptime: %s", err.Error())
+	}
+	if q.MaxUptime, err = parseDuration(req.GetMaxUptime()); err != nil {
+		return q, fmt.Errorf("invalid max_uptime: %s", err.Error())
+	}
+	if q.Disk, err = registry.ParseRange(req.GetDisk()); err != nil {
+		return q, err
+	}
+	if q.Memory, err = registry.ParseRange(req.GetMemory()); err != nil {
+		return q, err
+	}
+	if q.CPUs, err = registry.ParseRange(req.GetCpus()); err != nil {
+		return q, err
+	}
+	if q.Labels, err = registry.ParseSelector(req.GetLabels()); err != nil {
+		return q, err
+	}
+
+	// parse ordering
+	q.Sort = req.GetSort()
+	if strings.HasPrefix(q.Sort, "-") {
+		q.Sort = strings.TrimPrefix(q.Sort, "-")
+		q.Descending = true
+	}
+
+	return q, nil
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// newNetworkInfo converts a registry entry into its gRPC representation
+func newNetworkInfo(e registry.Entry) *rpc.NetworkInfo {
+	return &rpc.NetworkInfo{
+		Network:     e.NetworkID,
+		State:       string(e.State),
+		Uptime:      int64(e.Uptime),
+		SwarmPort:   e.Ports.Swarm,
+		ApiPort:     e.Ports.API,
+		GatewayPort: e.Ports.Gateway,
+		DiskGb:      int32(e.Resources.DiskGB),
+		MemoryGb:    int32(e.Resources.MemoryGB),
+		Cpus:        int32(e.Resources.CPUs),
+		Labels:      e.Labels,
+	}
+}
+
+// newRegistryNode converts a node into its gRPC representation
+func newRegistryNode(n ipfs.NodeInfo) *rpc.RegistryNode {
+	return &rpc.RegistryNode{
+		Network:     n.NetworkID,
+		SwarmPort:   n.Ports.Swarm,
+		SwarmWsPort: n.Ports.SwarmWS,
+		ApiPort:     n.Ports.API,
+		GatewayPort: n.Ports.Gateway,
+		Labels:      n.Labels,
+	}
+}
+
+// newNetworkSettingsResponse converts network settings into their gRPC
+// representation
+func newNetworkSettingsResponse(s *store.NetworkSettings) (*rpc.NetworkSettingsResponse, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "failed to read settings: %s", err.Error())
+	}
+	return &rpc.NetworkSettingsResponse{
+		Network:  s.Network,
+		Settings: string(b),
+	}, nil
+}
+
+// newAPIToken converts an API token into its gRPC representation
+func newAPIToken(t *store.APIToken) *rpc.APIToken {
+	var token = &rpc.APIToken{
+		TokenId:   t.TokenID,
+		Network:   t.Network,
+		Name:      t.Name,
+		Scopes:    t.Scopes,
+		CreatedAt: t.CreatedAt.Unix(),
+	}
+	if t.ExpiresAt != nil {
+		token.ExpiresAt = t.ExpiresAt.Unix()
+	}
+	if t.RevokedAt != nil {
+		token.RevokedAt = t.RevokedAt.Unix()
+	}
+	return token
+}
+
+// newDenylistEntry converts a denylist entry into its gRPC representation
+func newDenylistEntry(e *store.DenylistEntry) *rpc.DenylistEntry {
+	return &rpc.DenylistEntry{
+		Rule:      e.Rule,
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt.Unix(),
+	}
+}
+
+// newDomain converts a custom domain into its gRPC representation
+func newDomain(d *store.CustomDomain) *rpc.Domain {
+	var domain = &rpc.Domain{
+		Host:            d.Host,
+		Network:         d.Network,
+		RootPath:        d.RootPath,
+		Verified:        d.Verified(),
+		ChallengeRecord: d.ChallengeRecord(),
+		ChallengeToken:  d.Token,
+		CreatedAt:       d.CreatedAt.Unix(),
+	}
+	if d.VerifiedAt != nil {
+		domain.VerifiedAt = d.VerifiedAt.Unix()
+	}
+	return domain
+}
+
+// newNetworkUsersResponse converts network users into their gRPC
+// representation
+func newNetworkUsersResponse(users []orchestrator.NetworkUser) *rpc.NetworkUsersResponse {
+	var resp = &rpc.NetworkUsersResponse{
+		Users: make([]*rpc.NetworkUser, len(users)),
+	}
+	for i, u := range users {
+		resp.Users[i] = &rpc.NetworkUser{User: u.User, Role: u.Role}
+	}
+	return resp
+}
+
+// newUsageQuery converts a GetUsage request into a usage query
+func newUsageQuery(req *rpc.UsageRequest) store.UsageQuery {
+	var q = store.UsageQuery{
+		Network: req.GetNetwork(),
+		Feature: req.GetFeature(),
+	}
+	if req.GetSince() != 0 {
+		q.Since = time.Unix(req.GetSince(), 0)
+	}
+	if req.GetUntil() != 0 {
+		q.Until = time.Unix(req.GetUntil(), 0)
+	}
+	return q
+}
+
+// newUsageRecord converts a usage record into its gRPC representation
+func newUsageRecord(r *store.UsageRecord) *rpc.UsageRecord {
+	return &rpc.UsageRecord{
+		Hour:     r.Hour.Unix(),
+		Network:  r.Network,
+		Feature:  r.Feature,
+		User:     r.User,
+		BytesIn:  r.BytesIn,
+		BytesOut: r.BytesOut,
+	}
+}
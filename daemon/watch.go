@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/rpc"
+)
+
+// heartbeatInterval is how often registry watchers are sent heartbeats
+const heartbeatInterval = 30 * time.Second
+
+// watcherBuffer is the number of events a registry watcher may fall behind by
+// before it is disconnected
+const watcherBuffer = 256
+
+// watchers distributes registry events to each WatchRegistry stream
+type watchers struct {
+	mux  sync.Mutex
+	subs map[chan *rpc.RegistryEvent]struct{}
+}
+
+func newWatchers() *watchers {
+	return &watchers{subs: make(map[chan *rpc.RegistryEvent]struct{})}
+}
+
+// subscribe registers a new watcher. Its channel is closed if it falls behind,
+// and the returned function must be called once it is no longer watching.
+func (w *watchers) subscribe() (<-chan *rpc.RegistryEvent, func()) {
+	var events = make(chan *rpc.RegistryEvent, watcherBuffer)
+	w.mux.Lock()
+	w.subs[events] = struct{}{}
+	w.mux.Unlock()
+	return events, func() {
+		w.mux.Lock()
+		if _, found := w.subs[events]; found {
+			delete(w.subs, events)
+			close(events)
+		}
+		w.mux.Unlock()
+	}
+}
+
+// publish sends an event to all watchers, disconnecting those that have
+// fallen behind, since they can no longer be kept consistent
+func (w *watchers) publish(event *rpc.RegistryEvent) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	for events := range w.subs {
+		select {
+		case events <- event:
+		default:
+			delete(w.subs, events)
+			close(events)
+		}
+	}
+}
+
+// nodeChanged publishes the state of the node of given network as of a change
+// to it, or its removal if node is nil. It is called while the registry
+// applies the change, so events are published in the order changes are made.
+func (d *Daemon) nodeChanged(network string, node *ipfs.NodeInfo) {
+	if node == nil {
+		d.watchers.publish(&rpc.RegistryEvent{
+			Type:    rpc.RegistryEvent_NODE_REMOVED,
+			Network: network,
+		})
+		return
+	}
+	d.watchers.publish(&rpc.RegistryEvent{
+		Type:  rpc.RegistryEvent_NODE_UPDATED,
+		Nodes: []*rpc.RegistryNode{newRegistryNode(*node)},
+	})
+}
+
+// WatchRegistry streams a snapshot of the node registry, followed by changes to
+// it and to network configuration, so that delegators running apart from the
+// daemon can keep a mirror of the registry
+func (d *Daemon) WatchRegistry(
+	req *rpc.WatchRegistryRequest,
+	stream rpc.Control_WatchRegistryServer,
+) error {
+	// subscribe before taking the snapshot so that no changes are missed
+	events, unsubscribe := d.watchers.subscribe()
+	defer unsubscribe()
+
+	var (
+		nodes    = d.o.Registry.List()
+		snapshot = &rpc.RegistryEvent{
+			Type:  rpc.RegistryEvent_SNAPSHOT,
+			Nodes: make([]*rpc.RegistryNode, len(nodes)),
+		}
+	)
+	for i, n := range nodes {
+		snapshot.Nodes[i] = newRegistryNode(n)
+	}
+	if err := stream.Send(snapshot); err != nil {
+		return err
+	}
+	d.l.Infow("registry watcher connected", "nodes", len(nodes))
+
+	var heartbeat = time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			d.l.Info("registry watcher disconnected")
+			return nil
+		case <-heartbeat.C:
+			if err := stream.Send(&rpc.RegistryEvent{Type: rpc.RegistryEvent_HEARTBEAT}); err != nil {
+				return err
+			}
+		case e, ok := <-events:
+			if !ok {
+				d.l.Warn("registry watcher fell behind - disconnecting")
+				return grpc.Errorf(codes.ResourceExhausted, "watcher fell behind")
+			}
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/RTradeLtd/Nexus/rpc"
+)
+
+func Test_watchers(t *testing.T) {
+	var w = newWatchers()
+	fast, unsubscribeFast := w.subscribe()
+	defer unsubscribeFast()
+	slow, unsubscribeSlow := w.subscribe()
+
+	// watchers that fall behind are disconnected
+	for i := 0; i < watcherBuffer+1; i++ {
+		w.publish(&rpc.RegistryEvent{Type: rpc.RegistryEvent_NETWORK_CHANGED})
+		<-fast
+	}
+	var received = 0
+	for range slow {
+		received++
+	}
+	if received != watcherBuffer {
+		t.Errorf("expected %d buffered events, got %d", watcherBuffer, received)
+	}
+	unsubscribeSlow()
+
+	// remaining watchers continue to receive events
+	w.publish(&rpc.RegistryEvent{Type: rpc.RegistryEvent_HEARTBEAT})
+	if e := <-fast; e.GetType() != rpc.RegistryEvent_HEARTBEAT {
+		t.Errorf("expected heartbeat, got %v", e.GetType())
+	}
+}
@@ -0,0 +1,123 @@
+package delegator
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/bobheadxi/res"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// parseTrustedProxies parses the addresses of trusted proxies
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	var trusted = make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		n, err := store.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", err.Error())
+		}
+		trusted = append(trusted, n)
+	}
+	return trusted, nil
+}
+
+// withRealIP creates a handler that sets each request's RemoteAddr to the
+// address of the client. Requests from trusted proxies are attributed to the
+// last address in X-Forwarded-For that is not a trusted proxy, or to X-Real-IP
+// if it is not set. Headers of requests from other addresses are ignored,
+// since they can be spoofed.
+func withRealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	var isTrusted = func(ip net.IP) bool {
+		for _, n := range trusted {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ip = remoteIP(r)
+			if ip != nil && isTrusted(ip) {
+				var forwarded []net.IP
+				for _, v := range r.Header.Values("X-Forwarded-For") {
+					for _, addr := range strings.Split(v, ",") {
+						if hop := net.ParseIP(strings.TrimSpace(addr)); hop != nil {
+							forwarded = append(forwarded, hop)
+						}
+					}
+				}
+				if len(forwarded) == 0 {
+					if real := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); real != nil {
+						forwarded = append(forwarded, real)
+					}
+				}
+				for i := len(forwarded) - 1; i >= 0; i-- {
+					ip = forwarded[i]
+					if !isTrusted(ip) {
+						break
+					}
+				}
+			}
+			if ip != nil {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// remoteIP parses the address of the request's client, which may or may not
+// include a port
+func remoteIP(r *http.Request) net.IP {
+	var host = r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return net.ParseIP(host)
+}
+
+// enforceAccessRules rejects requests from addresses that may not access the
+// network's feature, and returns false if the request was rejected. Rejected
+// requests are recorded in the audit log.
+func (e *Engine) enforceAccessRules(w http.ResponseWriter, r *http.Request, network, feature string) bool {
+	s, err := e.networks.GetNetworkSettings(network)
+	if err != nil {
+		e.l.Warnw("failed to retrieve network settings - rejecting request",
+			"network", network,
+			"error", err)
+		res.R(w, r, res.Err("failed to retrieve network access rules", http.StatusServiceUnavailable))
+		return false
+	}
+	if s == nil {
+		return true
+	}
+	rule, found := s.Access[feature]
+	if !found {
+		return true
+	}
+
+	// requests without a valid address cannot be matched against rules
+	var permitted bool
+	var match string
+	if ip := remoteIP(r); ip != nil {
+		if permitted, match = rule.Permits(ip); permitted {
+			return true
+		}
+	}
+	e.metrics.authFailure(network, reasonAddressNotPermitted)
+	e.audit.Warnw("request rejected by access rules",
+		"network", network,
+		"feature", feature,
+		"address", r.RemoteAddr,
+		"rule", match,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"forwarded-for", r.Header.Values("X-Forwarded-For"),
+		"user-agent", r.UserAgent())
+	res.R(w, r, res.ErrForbidden("access from this address is not permitted"))
+	return false
+}
@@ -0,0 +1,124 @@
+package delegator
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+)
+
+func Test_parseTrustedProxies(t *testing.T) {
+	if _, err := parseTrustedProxies([]string{"127.0.0.1", "10.0.0.0/8", "::1"}); err != nil {
+		t.Errorf("expected valid proxies, got %v", err)
+	}
+	if _, err := parseTrustedProxies([]string{"localhost"}); err == nil {
+		t.Error("expected error for invalid proxy")
+	}
+}
+
+func Test_withRealIP(t *testing.T) {
+	trusted, _ := parseTrustedProxies([]string{"127.0.0.1", "10.0.0.0/8"})
+	type args struct {
+		remote    string
+		forwarded []string
+		realIP    string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"direct", args{"203.0.113.1:1234", nil, ""}, "203.0.113.1"},
+		{"untrusted forwarded", args{"203.0.113.1:1234", []string{"198.51.100.7"}, ""}, "203.0.113.1"},
+		{"untrusted real ip", args{"203.0.113.1:1234", nil, "198.51.100.7"}, "203.0.113.1"},
+		{"trusted forwarded", args{"127.0.0.1:1234", []string{"198.51.100.7"}, ""}, "198.51.100.7"},
+		{"trusted real ip", args{"127.0.0.1:1234", nil, "198.51.100.7"}, "198.51.100.7"},
+		{"spoofed hop", args{"127.0.0.1:1234", []string{"192.0.2.1, 198.51.100.7"}, ""}, "198.51.100.7"},
+		{"trusted hops", args{"127.0.0.1:1234", []string{"198.51.100.7, 10.0.0.2", "10.0.0.1"}, ""}, "198.51.100.7"},
+		{"only trusted hops", args{"127.0.0.1:1234", []string{"10.0.0.2"}, ""}, "10.0.0.2"},
+		{"invalid forwarded", args{"127.0.0.1:1234", []string{"unknown"}, ""}, "127.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req = httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.args.remote
+			for _, f := range tt.args.forwarded {
+				req.Header.Add("X-Forwarded-For", f)
+			}
+			if tt.args.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.args.realIP)
+			}
+			var got string
+			withRealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("expected address '%s', got '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEngine_enforceAccessRules(t *testing.T) {
+	var rules = store.AccessRules{
+		"api":     {Allow: []string{"203.0.113.0/24"}},
+		"gateway": {Deny: []string{"198.51.100.7"}},
+	}
+	type fields struct {
+		settings    *store.NetworkSettings
+		settingsErr error
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		feature   string
+		remote    string
+		wantCode  int
+		wantAudit bool
+	}{
+		{"no settings", fields{nil, nil}, "api", "198.51.100.7", 0, false},
+		{"settings error", fields{nil, errors.New("oh no")}, "api", "203.0.113.1",
+			http.StatusServiceUnavailable, false},
+		{"no rule for feature", fields{&store.NetworkSettings{Access: rules}, nil}, "swarm", "198.51.100.7", 0, false},
+		{"allowed", fields{&store.NetworkSettings{Access: rules}, nil}, "api", "203.0.113.1", 0, false},
+		{"not allowed", fields{&store.NetworkSettings{Access: rules}, nil}, "api", "198.51.100.7",
+			http.StatusForbidden, true},
+		{"denied", fields{&store.NetworkSettings{Access: rules}, nil}, "gateway", "198.51.100.7",
+			http.StatusForbidden, true},
+		{"invalid address", fields{&store.NetworkSettings{Access: rules}, nil}, "gateway", "unknown",
+			http.StatusForbidden, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				settings = &smock.FakeSettings{}
+				audit    bytes.Buffer
+				e        = newTestEngine(t, EngineOpts{}, nil, Stores{Settings: settings})
+				rec      = httptest.NewRecorder()
+			)
+			settings.GetNetworkSettingsReturns(tt.fields.settings, tt.fields.settingsErr)
+			e.audit = zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.AddSync(&audit), zap.InfoLevel)).Sugar()
+
+			var req = httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			var ok = e.enforceAccessRules(rec, req, "bobheadxi", tt.feature)
+			if ok != (tt.wantCode == 0) {
+				t.Errorf("Engine.enforceAccessRules() = %v, want %v", ok, tt.wantCode == 0)
+			}
+			if tt.wantCode != 0 && rec.Code != tt.wantCode {
+				t.Errorf("expected status %d, found %d", tt.wantCode, rec.Code)
+			}
+			if logged := bytes.Contains(audit.Bytes(), []byte(tt.remote)); logged != tt.wantAudit {
+				t.Errorf("expected audit log entry %v, got %s", tt.wantAudit, audit.String())
+			}
+		})
+	}
+}
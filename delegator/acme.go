@@ -0,0 +1,424 @@
+package delegator
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+const (
+	// acmeRenewCheckInterval is how often certificates issued using dns-01
+	// challenges are checked for renewal
+	acmeRenewCheckInterval = 12 * time.Hour
+
+	// acmeIssueTimeout bounds the time taken to issue a certificate
+	acmeIssueTimeout = 10 * time.Minute
+)
+
+// hostFeatures are the network features served on subdomains of the
+// delegator's domain, such as "<network>.api.<domain>"
+var hostFeatures = []string{"api", "gateway", "swarm", "status"}
+
+// acmeHosts returns the wildcard hosts that cover all network hosts
+func acmeHosts(domain string) []string {
+	var hosts = make([]string, len(hostFeatures))
+	for i, feature := range hostFeatures {
+		hosts[i] = "*." + feature + "." + domain
+	}
+	return hosts
+}
+
+// acmeHostPolicy only permits issuance of certificates for hosts of registered
+// networks, verified custom domains, and subdomain gateway hosts of CIDs, so
+// that requests for arbitrary hosts do not exhaust the certificate
+// authority's rate limits
+func (e *Engine) acmeHostPolicy(ctx context.Context, host string) error {
+	if _, ok := e.domains.get(host); ok {
+		return nil
+	}
+	if label, _, network, ok := e.contentHost(host); ok && e.subdomainGateway {
+		// inlined DNSLink names cannot be validated, so only hosts of CIDs
+		// receive certificates
+		if _, err := cid.Decode(label); err != nil {
+			return fmt.Errorf("acme: host '%s' is not a CID host", host)
+		}
+		if _, err := e.reg.Get(network); err != nil {
+			return fmt.Errorf("acme: no network '%s' registered", network)
+		}
+		return nil
+	}
+	var parts = strings.SplitN(host, ".", 3)
+	if len(parts) != 3 || parts[2] != e.domain {
+		return fmt.Errorf("acme: host '%s' is not a network host", host)
+	}
+	var known bool
+	for _, feature := range hostFeatures {
+		known = known || parts[1] == feature
+	}
+	if !known {
+		return fmt.Errorf("acme: host '%s' is not a network host", host)
+	}
+	if _, err := e.reg.Get(parts[0]); err != nil {
+		return fmt.Errorf("acme: no network '%s' registered", parts[0])
+	}
+	return nil
+}
+
+// newACMEClient creates a client for the configured certificate authority
+func newACMEClient(opts config.ACME) (*acme.Client, error) {
+	var client = &acme.Client{DirectoryURL: opts.DirectoryURL}
+	if opts.CARootsPath != "" {
+		pem, err := ioutil.ReadFile(opts.CARootsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA roots: %s", err.Error())
+		}
+		var roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in '%s'", opts.CARootsPath)
+		}
+		client.HTTPClient = &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: roots},
+		}}
+	}
+	return client, nil
+}
+
+// acmeTLSConfig sets up issuance of certificates for network hosts. With
+// http-01 challenges, it also returns a handler that must be served on the
+// configured HTTP port.
+func (e *Engine) acmeTLSConfig(ctx context.Context, opts config.ACME) (*tls.Config, http.Handler, error) {
+	if e.domain == "" {
+		return nil, nil, errors.New("a domain is required to issue certificates")
+	}
+	if opts.CachePath == "" {
+		return nil, nil, errors.New("a cache path is required to issue certificates")
+	}
+	client, err := newACMEClient(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var renewBefore = time.Duration(opts.RenewBeforeDays) * 24 * time.Hour
+
+	switch opts.Challenge {
+	case config.ChallengeHTTP:
+		var m = &autocert.Manager{
+			Prompt:      autocert.AcceptTOS,
+			Email:       opts.Email,
+			Cache:       autocert.DirCache(opts.CachePath),
+			HostPolicy:  e.acmeHostPolicy,
+			RenewBefore: renewBefore,
+			Client:      client,
+		}
+		return m.TLSConfig(), m.HTTPHandler(nil), nil
+
+	case config.ChallengeDNS:
+		if opts.DNSHook == "" {
+			return nil, nil, errors.New("a DNS hook is required for dns-01 challenges")
+		}
+		if e.subdomainGateway {
+			// content hosts are two labels below the wildcard hosts
+			return nil, nil, errors.New("subdomain gateways require http-01 challenges, since " +
+				"wildcard certificates do not cover content hosts")
+		}
+		var issuer = newDNSIssuer(e.l.Named("acme"), client, opts, acmeHosts(e.domain), renewBefore)
+		if err := issuer.ensure(ctx); err != nil {
+			// continue with a previously issued certificate if there is one,
+			// and try again later
+			if _, certErr := issuer.certs.GetCertificate(nil); certErr != nil {
+				return nil, nil, err
+			}
+			e.l.Errorw("failed to renew certificate - continuing with previous certificate",
+				"error", err)
+		}
+		e.certs = issuer.certs
+		go issuer.run(ctx, acmeRenewCheckInterval)
+		return &tls.Config{GetCertificate: issuer.certs.GetCertificate}, nil, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unsupported challenge type '%s'", opts.Challenge)
+	}
+}
+
+// dnsIssuer obtains a wildcard certificate for network hosts using dns-01
+// challenges, and renews it before it expires. The certificate is kept in the
+// cache directory, and served from there by a certReloader.
+type dnsIssuer struct {
+	l           *zap.SugaredLogger
+	client      *acme.Client
+	email       string
+	hook        string
+	names       []string
+	renewBefore time.Duration
+
+	cachePath string
+	certs     *certReloader
+}
+
+func newDNSIssuer(l *zap.SugaredLogger, client *acme.Client, opts config.ACME,
+	names []string, renewBefore time.Duration) *dnsIssuer {
+	var (
+		certPath = filepath.Join(opts.CachePath, "certificate.pem")
+		keyPath  = filepath.Join(opts.CachePath, "certificate.key")
+	)
+	return &dnsIssuer{
+		l:           l,
+		client:      client,
+		email:       opts.Email,
+		hook:        opts.DNSHook,
+		names:       names,
+		renewBefore: renewBefore,
+
+		cachePath: opts.CachePath,
+		certs:     newCertReloader(l, certPath, keyPath),
+	}
+}
+
+// run periodically renews the certificate until the context is cancelled
+func (d *dnsIssuer) run(ctx context.Context, interval time.Duration) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.ensure(ctx); err != nil {
+				d.l.Errorw("failed to renew certificate - continuing with previous certificate",
+					"error", err)
+			}
+		}
+	}
+}
+
+// ensure issues a certificate if there is no valid certificate for all names
+// in the cache, or if it is due for renewal
+func (d *dnsIssuer) ensure(ctx context.Context) error {
+	if err := d.certs.load(false); err == nil {
+		cert, _ := d.certs.GetCertificate(nil)
+		if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil &&
+			time.Now().Add(d.renewBefore).Before(leaf.NotAfter) && coversNames(leaf, d.names) {
+			return nil
+		}
+	}
+
+	d.l.Infow("issuing certificate", "names", d.names)
+	ctx, cancel := context.WithTimeout(ctx, acmeIssueTimeout)
+	defer cancel()
+	if err := d.issue(ctx); err != nil {
+		return fmt.Errorf("failed to issue certificate: %s", err.Error())
+	}
+	if err := d.certs.load(true); err != nil {
+		return err
+	}
+	d.l.Infow("certificate issued", "names", d.names)
+	return nil
+}
+
+// issue obtains a new certificate and writes it to the cache
+func (d *dnsIssuer) issue(ctx context.Context) error {
+	if err := d.register(ctx); err != nil {
+		return err
+	}
+
+	order, err := d.client.AuthorizeOrder(ctx, acme.DomainIDs(d.names...))
+	if err != nil {
+		return err
+	}
+	// order responses do not necessarily repeat the order's location
+	var orderURL = order.URI
+	for _, u := range order.AuthzURLs {
+		if err := d.authorize(ctx, u); err != nil {
+			return err
+		}
+	}
+	if order, err = d.client.WaitOrder(ctx, orderURL); err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		DNSNames: d.names,
+	}, key)
+	if err != nil {
+		return err
+	}
+	chain, _, err := d.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		// some CAs finalize orders asynchronously without providing the
+		// order's location, which the client needs to wait for issuance - in
+		// that case, wait for the order ourselves
+		o, waitErr := d.client.WaitOrder(ctx, orderURL)
+		if waitErr != nil || o.Status != acme.StatusValid {
+			return err
+		}
+		if chain, err = d.client.FetchCert(ctx, o.CertURL, true); err != nil {
+			return err
+		}
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	// write key first, so that the certificate is never served with an
+	// outdated key
+	if err := writeFileAtomic(d.certs.keyPath,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})); err != nil {
+		return err
+	}
+	return writeFileAtomic(d.certs.certPath, certPEM)
+}
+
+// authorize completes the dns-01 challenge of an authorization
+func (d *dnsIssuer) authorize(ctx context.Context, url string) error {
+	z, err := d.client.GetAuthorization(ctx, url)
+	if err != nil {
+		return err
+	}
+	if z.Status == acme.StatusValid {
+		return nil
+	}
+	var chal *acme.Challenge
+	for _, c := range z.Challenges {
+		if c.Type == config.ChallengeDNS {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("no dns-01 challenge offered for '%s'", z.Identifier.Value)
+	}
+	value, err := d.client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return err
+	}
+
+	var name = "_acme-challenge." + z.Identifier.Value
+	if err := d.runHook(ctx, "present", name, value); err != nil {
+		return err
+	}
+	defer func() {
+		if err := d.runHook(context.Background(), "cleanup", name, value); err != nil {
+			d.l.Warnw("failed to clean up challenge record",
+				"name", name,
+				"error", err)
+		}
+	}()
+	if _, err := d.client.Accept(ctx, chal); err != nil {
+		return err
+	}
+	_, err = d.client.WaitAuthorization(ctx, z.URI)
+	return err
+}
+
+// register creates an account with the certificate authority if one does not
+// exist for the account key yet
+func (d *dnsIssuer) register(ctx context.Context) error {
+	if d.client.Key == nil {
+		key, err := loadOrCreateAccountKey(filepath.Join(d.cachePath, "acme_account.key"))
+		if err != nil {
+			return err
+		}
+		d.client.Key = key
+	}
+	var account = &acme.Account{}
+	if d.email != "" {
+		account.Contact = []string{"mailto:" + d.email}
+	}
+	if _, err := d.client.Register(ctx, account, acme.AcceptTOS); err != nil &&
+		err != acme.ErrAccountAlreadyExists {
+		return fmt.Errorf("failed to register account: %s", err.Error())
+	}
+	return nil
+}
+
+// runHook invokes the DNS hook to present or clean up a challenge record
+func (d *dnsIssuer) runHook(ctx context.Context, action, name, value string) error {
+	/* #nosec */
+	out, err := exec.CommandContext(ctx, d.hook, action, name, value).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("dns hook failed to %s record '%s': %s: %s",
+			action, name, err.Error(), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// coversNames checks if a certificate is valid for all given names
+func coversNames(cert *x509.Certificate, names []string) bool {
+	for _, name := range names {
+		if err := cert.VerifyHostname(strings.Replace(name, "*", "host", 1)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// loadOrCreateAccountKey reads an ACME account key, generating one if it does
+// not exist yet
+func loadOrCreateAccountKey(path string) (crypto.Signer, error) {
+	if b, err := ioutil.ReadFile(path); err == nil {
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("invalid account key '%s'", path)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read account key: %s", err.Error())
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// writeFileAtomic writes a file readable only by the owner, replacing any
+// existing file only once the write is complete
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	var tmp = path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,233 @@
+package delegator
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+func newTestACMEEngine(t *testing.T) *Engine {
+	return newTestEngine(t, EngineOpts{Domain: "example.com"}, nil, Stores{}, &ipfs.NodeInfo{NetworkID: "test"})
+}
+
+func TestEngine_acmeHostPolicy(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{"test.api.example.com", false},
+		{"test.gateway.example.com", false},
+		{"test.swarm.example.com", false},
+		{"test.status.example.com", false},
+		{"other.api.example.com", true},
+		{"test.admin.example.com", true},
+		{"test.api.example.org", true},
+		{"a.test.api.example.com", true},
+		{"example.com", true},
+		{"docs.example.org", false},
+		{"www.example.org", true},
+	}
+	var e = newTestACMEEngine(t)
+	e.domains.hosts["docs.example.org"] = &store.CustomDomain{Host: "docs.example.org", Network: "test"}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if err := e.acmeHostPolicy(context.Background(), tt.host); (err != nil) != tt.wantErr {
+				t.Errorf("Engine.acmeHostPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEngine_acmeHostPolicy_subdomainGateway(t *testing.T) {
+	var label, _ = labelFromRoot("ipfs", testCID)
+	tests := []struct {
+		name      string
+		subdomain bool
+		host      string
+		wantErr   bool
+	}{
+		{"CID host", true, label + ".ipfs.test.gateway.example.com", false},
+		{"IPNS key host", true, label + ".ipns.test.gateway.example.com", false},
+		{"disabled", false, label + ".ipfs.test.gateway.example.com", true},
+		{"unknown network", true, label + ".ipfs.other.gateway.example.com", true},
+		{"invalid CID", true, "hello.ipfs.test.gateway.example.com", true},
+		{"DNSLink host", true, "docs-example-org.ipns.test.gateway.example.com", true},
+		{"unknown namespace", true, label + ".ipld.test.gateway.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e = newTestEngine(t, EngineOpts{Domain: "example.com", SubdomainGateway: tt.subdomain},
+				nil, Stores{}, &ipfs.NodeInfo{NetworkID: "test"})
+			if err := e.acmeHostPolicy(context.Background(), tt.host); (err != nil) != tt.wantErr {
+				t.Errorf("Engine.acmeHostPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEngine_acmeTLSConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		subdomain bool
+		opts      config.ACME
+	}{
+		{"no cache path", false, config.ACME{Challenge: config.ChallengeHTTP}},
+		{"no DNS hook", false, config.ACME{Challenge: config.ChallengeDNS, CachePath: "tmp"}},
+		{"unknown challenge", false, config.ACME{Challenge: "tls-alpn-01", CachePath: "tmp"}},
+		{"invalid CA roots", false, config.ACME{Challenge: config.ChallengeHTTP, CachePath: "tmp", CARootsPath: "acme.go"}},
+		{"dns-01 with subdomain gateway", true, config.ACME{Challenge: config.ChallengeDNS, CachePath: "tmp", DNSHook: "hook.sh"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e = newTestACMEEngine(t)
+			e.subdomainGateway = tt.subdomain
+			if _, _, err := e.acmeTLSConfig(context.Background(), tt.opts); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestDNSIssuer_runHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		hook = filepath.Join(dir, "hook.sh")
+		out  = filepath.Join(dir, "out")
+	)
+	if err := ioutil.WriteFile(hook, []byte("#!/bin/sh\necho \"$@\" > "+out+"\n[ \"$1\" = present ] || exit 1\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	var d = newDNSIssuer(zaptest.NewLogger(t).Sugar(), nil, config.ACME{DNSHook: hook, CachePath: dir}, nil, 0)
+
+	if err := d.runHook(context.Background(), "present", "_acme-challenge.api.example.com", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(out); strings.TrimSpace(string(b)) != "present _acme-challenge.api.example.com value" {
+		t.Errorf("unexpected hook arguments '%s'", b)
+	}
+	if err := d.runHook(context.Background(), "cleanup", "_acme-challenge.api.example.com", "value"); err == nil {
+		t.Error("expected error for failed hook")
+	}
+}
+
+func TestLoadOrCreateAccountKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var path = filepath.Join(dir, "account.key")
+
+	created, err := loadOrCreateAccountKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadOrCreateAccountKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(created.Public(), loaded.Public()) {
+		t.Error("expected account key to be reused")
+	}
+}
+
+func TestCoversNames(t *testing.T) {
+	var cert = &x509.Certificate{DNSNames: []string{"*.api.example.com", "*.gateway.example.com"}}
+	if !coversNames(cert, []string{"*.api.example.com"}) {
+		t.Error("expected names to be covered")
+	}
+	if coversNames(cert, acmeHosts("example.com")) {
+		t.Error("expected missing names to not be covered")
+	}
+}
+
+// TestACME_pebble issues certificates from an ACME test server, such as one
+// started with 'make testenv-acme'. Challenges must be configured to always
+// pass.
+func TestACME_pebble(t *testing.T) {
+	var directory = os.Getenv("NEXUS_TEST_ACME_DIRECTORY")
+	if directory == "" {
+		t.Skip("NEXUS_TEST_ACME_DIRECTORY not set")
+	}
+	var opts = config.ACME{
+		DirectoryURL:    directory,
+		CARootsPath:     os.Getenv("NEXUS_TEST_ACME_CA"),
+		Email:           "test@example.com",
+		DNSHook:         "true",
+		RenewBeforeDays: 30,
+	}
+
+	t.Run(config.ChallengeDNS, func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "nexus-acme")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		opts.Challenge, opts.CachePath = config.ChallengeDNS, dir
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var e = newTestACMEEngine(t)
+		tlsConfig, _, err := e.acmeTLSConfig(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "test.gateway.example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !coversNames(leaf, acmeHosts("example.com")) {
+			t.Errorf("expected certificate for network hosts, got %v", leaf.DNSNames)
+		}
+
+		// issued certificates should be reused
+		var issuer = newDNSIssuer(e.l, nil, opts, acmeHosts("example.com"), 24*time.Hour)
+		if err := issuer.ensure(ctx); err != nil {
+			t.Errorf("expected cached certificate to be used, got %v", err)
+		}
+	})
+
+	t.Run(config.ChallengeHTTP, func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "nexus-acme")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		opts.Challenge, opts.CachePath = config.ChallengeHTTP, dir
+
+		tlsConfig, challenges, err := newTestACMEEngine(t).acmeTLSConfig(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if challenges == nil {
+			t.Error("expected challenge handler")
+		}
+		// issuance itself is not checked, since the ACME client cannot wait
+		// for orders that Pebble finalizes without providing their location
+		if _, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.api.example.com"}); err == nil ||
+			!strings.Contains(err.Error(), "no network") {
+			t.Errorf("expected no certificate for unknown network, got %v", err)
+		}
+	})
+}
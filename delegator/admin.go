@@ -0,0 +1,184 @@
+package delegator
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bobheadxi/res"
+	"github.com/go-chi/chi"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+)
+
+// proxyFeatures lists the features proxies are cached for
+var proxyFeatures = []string{"api", "gateway", "swarm"}
+
+// proxyKey is the key of the cached proxy for given network's feature
+func proxyKey(network, feature string) string {
+	return fmt.Sprintf("%s-%s", network, feature)
+}
+
+// inflight counts the requests being proxied to each network
+type inflight struct {
+	mux    sync.Mutex
+	counts map[string]int64
+}
+
+func newInflight() *inflight {
+	return &inflight{counts: make(map[string]int64)}
+}
+
+// track records the start of a request to given network, and returns a
+// function that must be called once the request completes
+func (i *inflight) track(network string) func() {
+	i.mux.Lock()
+	i.counts[network]++
+	i.mux.Unlock()
+	return func() {
+		i.mux.Lock()
+		if i.counts[network]--; i.counts[network] <= 0 {
+			delete(i.counts, network)
+		}
+		i.mux.Unlock()
+	}
+}
+
+// get retrieves the number of requests being proxied to given network
+func (i *inflight) get(network string) int64 {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	return i.counts[network]
+}
+
+// maintenance tracks networks whose requests are rejected while an operator
+// works on them
+type maintenance struct {
+	mux      sync.RWMutex
+	networks map[string]time.Time
+}
+
+func newMaintenance() *maintenance {
+	return &maintenance{networks: make(map[string]time.Time)}
+}
+
+// set enables or disables maintenance mode for given network
+func (m *maintenance) set(network string, enabled bool) {
+	m.mux.Lock()
+	if enabled {
+		if _, found := m.networks[network]; !found {
+			m.networks[network] = time.Now()
+		}
+	} else {
+		delete(m.networks, network)
+	}
+	m.mux.Unlock()
+}
+
+// get checks if given network is in maintenance mode, and since when
+func (m *maintenance) get(network string) (time.Time, bool) {
+	m.mux.RLock()
+	since, found := m.networks[network]
+	m.mux.RUnlock()
+	return since, found
+}
+
+// requireAdminToken creates a handler that rejects requests without given
+// bearer token
+func requireAdminToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(getBearerToken(r)), []byte(token)) != 1 {
+				res.R(w, r, res.ErrUnauthorized("invalid admin token"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// adminNetwork describes a network known to the delegator
+type adminNetwork struct {
+	Network     string         `json:"network"`
+	Ports       ipfs.NodePorts `json:"ports"`
+	InFlight    int64          `json:"in_flight"`
+	Maintenance *time.Time     `json:"maintenance_since,omitempty"`
+}
+
+// adminProxy describes a cached proxy
+type adminProxy struct {
+	proxyEntry
+	AgeSeconds int64 `json:"age_seconds"`
+}
+
+// AdminNetworks lists registered networks, their ports, and their requests
+func (e *Engine) AdminNetworks(w http.ResponseWriter, r *http.Request) {
+	var nodes = e.reg.List()
+	var networks = make([]adminNetwork, 0, len(nodes))
+	for _, n := range nodes {
+		var network = adminNetwork{
+			Network:  n.NetworkID,
+			Ports:    n.Ports,
+			InFlight: e.inflight.get(n.NetworkID),
+		}
+		if since, ok := e.maintenance.get(n.NetworkID); ok {
+			network.Maintenance = &since
+		}
+		networks = append(networks, network)
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Network < networks[j].Network })
+	res.R(w, r, res.MsgOK(fmt.Sprintf("found %d networks", len(networks)),
+		"networks", networks))
+}
+
+// AdminProxies lists cached proxies and their ages
+func (e *Engine) AdminProxies(w http.ResponseWriter, r *http.Request) {
+	var (
+		now     = time.Now()
+		entries = e.cache.Entries()
+		proxies = make([]adminProxy, 0, len(entries))
+	)
+	for _, entry := range entries {
+		proxies = append(proxies, adminProxy{
+			proxyEntry: entry,
+			AgeSeconds: int64(now.Sub(entry.CreatedAt).Seconds()),
+		})
+	}
+	res.R(w, r, res.MsgOK(fmt.Sprintf("found %d cached proxies", len(proxies)),
+		"proxies", proxies))
+}
+
+// AdminPurgeProxies discards the cached proxies of a network
+func (e *Engine) AdminPurgeProxies(w http.ResponseWriter, r *http.Request) {
+	var network = chi.URLParam(r, string(keyNetwork))
+	var keys = make([]string, len(proxyFeatures))
+	for i, feature := range proxyFeatures {
+		keys[i] = proxyKey(network, feature)
+	}
+	e.cache.Delete(keys...)
+	e.l.Infow("proxy cache purged", "network", network)
+	res.R(w, r, res.MsgOK("proxy cache purged",
+		"network", network))
+}
+
+// AdminSetMaintenance enables maintenance mode for a network on PUT requests,
+// and disables it on DELETE requests. Requests to networks in maintenance
+// mode are rejected.
+func (e *Engine) AdminSetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var network = chi.URLParam(r, string(keyNetwork))
+	if _, err := e.reg.Get(network); err != nil {
+		res.R(w, r, res.ErrNotFound(err.Error()))
+		return
+	}
+	var enabled = r.Method == http.MethodPut
+	e.maintenance.set(network, enabled)
+	e.l.Infow("maintenance mode updated",
+		"network", network,
+		"enabled", enabled)
+	res.R(w, r, res.MsgOK("maintenance mode updated",
+		"network", network,
+		"maintenance", enabled))
+}
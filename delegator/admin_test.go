@@ -0,0 +1,136 @@
+package delegator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"testing"
+	"time"
+
+	"github.com/bobheadxi/res"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+)
+
+func newTestAdminEngine(t *testing.T) *Engine {
+	return newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: defaultTestKey}, nil, Stores{}, &ipfs.NodeInfo{
+		NetworkID: "test",
+		Ports:     ipfs.NodePorts{API: "5001", Gateway: "8080"},
+	})
+}
+
+func TestEngine_adminRouter(t *testing.T) {
+	type args struct {
+		token  string
+		bearer string
+		method string
+		path   string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantCode int
+	}{
+		{"metrics without token", args{"", "", "GET", "/metrics"}, http.StatusOK},
+		{"metrics do not require token", args{"secret", "", "GET", "/metrics"}, http.StatusOK},
+		{"admin disabled without token", args{"", "", "GET", "/networks"}, http.StatusNotFound},
+		{"no bearer", args{"secret", "", "GET", "/networks"}, http.StatusUnauthorized},
+		{"wrong bearer", args{"secret", "public", "GET", "/networks"}, http.StatusUnauthorized},
+		{"list networks", args{"secret", "secret", "GET", "/networks"}, http.StatusOK},
+		{"list proxies", args{"secret", "secret", "GET", "/proxies"}, http.StatusOK},
+		{"purge proxies", args{"secret", "secret", "DELETE", "/proxies/test"}, http.StatusOK},
+		{"maintenance on", args{"secret", "secret", "PUT", "/networks/test/maintenance"}, http.StatusOK},
+		{"maintenance off", args{"secret", "secret", "DELETE", "/networks/test/maintenance"}, http.StatusOK},
+		{"maintenance of unknown network", args{"secret", "secret", "PUT", "/networks/bye/maintenance"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e = newTestAdminEngine(t)
+			var req = httptest.NewRequest(tt.args.method, tt.args.path, nil)
+			if tt.args.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.args.bearer)
+			}
+			var rec = httptest.NewRecorder()
+			e.adminRouter(tt.args.token).ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status '%d', found '%d'", tt.wantCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestEngine_AdminNetworks(t *testing.T) {
+	var e = newTestAdminEngine(t)
+	var done = e.inflight.track("test")
+	e.inflight.track("test")
+	done()
+	e.maintenance.set("test", true)
+
+	var rec = httptest.NewRecorder()
+	e.AdminNetworks(rec, httptest.NewRequest("GET", "/networks", nil))
+	var networks []adminNetwork
+	if _, err := res.Unmarshal(rec.Body, res.KV{Key: "networks", Value: &networks}); err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 || networks[0].Network != "test" {
+		t.Fatalf("expected network 'test', got %+v", networks)
+	}
+	if networks[0].Ports.API != "5001" || networks[0].Ports.Gateway != "8080" {
+		t.Errorf("unexpected ports %+v", networks[0].Ports)
+	}
+	if networks[0].InFlight != 1 {
+		t.Errorf("expected 1 request in flight, got %d", networks[0].InFlight)
+	}
+	if networks[0].Maintenance == nil {
+		t.Error("expected network to be in maintenance mode")
+	}
+}
+
+func TestEngine_AdminProxies(t *testing.T) {
+	var e = newTestAdminEngine(t)
+	e.cache.Cache(proxyKey("test", "api"), &httputil.ReverseProxy{})
+	e.cache.Cache(proxyKey("test", "gateway"), &httputil.ReverseProxy{})
+	e.cache.Cache(proxyKey("other", "gateway"), &httputil.ReverseProxy{})
+
+	var list = func() []adminProxy {
+		var rec = httptest.NewRecorder()
+		e.AdminProxies(rec, httptest.NewRequest("GET", "/proxies", nil))
+		var proxies []adminProxy
+		if _, err := res.Unmarshal(rec.Body, res.KV{Key: "proxies", Value: &proxies}); err != nil {
+			t.Fatal(err)
+		}
+		return proxies
+	}
+	if proxies := list(); len(proxies) != 3 || proxies[0].Key != "other-gateway" {
+		t.Fatalf("expected 3 sorted proxies, got %+v", proxies)
+	}
+
+	var rec = httptest.NewRecorder()
+	e.adminRouter("secret").ServeHTTP(rec, func() *http.Request {
+		var req = httptest.NewRequest("DELETE", "/proxies/test", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		return req
+	}())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status '%d', found '%d'", http.StatusOK, rec.Code)
+	}
+	if proxies := list(); len(proxies) != 1 || proxies[0].Key != "other-gateway" {
+		t.Errorf("expected only proxies of 'test' to be purged, got %+v", proxies)
+	}
+}
+
+func TestEngine_Redirect_maintenance(t *testing.T) {
+	var e = newTestAdminEngine(t)
+	n, _ := e.reg.Get("test")
+	e.maintenance.set("test", true)
+
+	var rec = httptest.NewRecorder()
+	e.Redirect(rec, httptest.NewRequest("GET", "/", nil).WithContext(
+		context.WithValue(context.WithValue(context.Background(),
+			keyNetwork, &n),
+			keyFeature, "gateway")))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status '%d', found '%d'", http.StatusServiceUnavailable, rec.Code)
+	}
+}
@@ -1,12 +1,16 @@
 package delegator
 
 import (
+	"crypto/ed25519"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/RTradeLtd/Nexus/store"
 )
 
 var (
@@ -15,37 +19,202 @@ var (
 	errExpiredAuth = errors.New("authentication is expired")
 )
 
-func getUserFromJWT(
-	r *http.Request,
-	keyLookup jwt.Keyfunc,
-	timeFunc func() time.Time,
-) (user string, err error) {
-	jwt.TimeFunc = timeFunc
-
-	// Collect the token from the header.
-	bearerString := r.Header.Get("Authorization")
-
+// getBearerToken retrieves the bearer credential from the request, if any
+func getBearerToken(r *http.Request) string {
 	// Split out the actual token from the header.
-	splitToken := strings.Split(bearerString, "Bearer ")
+	splitToken := strings.Split(r.Header.Get("Authorization"), "Bearer ")
 	if len(splitToken) < 2 {
-		return "", errNoAuth
+		return ""
 	}
-	tokenString := splitToken[1]
+	return splitToken[1]
+}
 
-	// Parse takes the token string and a function for looking up the key.
-	token, err := jwt.Parse(tokenString, keyLookup)
+// getAPIToken looks up a Nexus-issued API token and checks that it is valid
+// for use against the given network
+func getAPIToken(
+	tokens store.Tokens,
+	secret string,
+	network string,
+	now time.Time,
+) (*store.APIToken, error) {
+	token, err := tokens.FindToken(store.HashToken(secret))
 	if err != nil {
-		return "", errInvalidAuth
+		return nil, err
+	}
+	if token == nil || token.Network != network {
+		return nil, errInvalidAuth
+	}
+	if err := token.Valid(now); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// signingMethods declares the algorithms accepted for JWTs. Methods are looked
+// up here rather than through jwt-go's global registry, so that EdDSA can be
+// supported without registering it package-wide.
+var signingMethods = map[string]jwt.SigningMethod{
+	jwt.SigningMethodHS256.Alg(): jwt.SigningMethodHS256,
+	jwt.SigningMethodHS384.Alg(): jwt.SigningMethodHS384,
+	jwt.SigningMethodHS512.Alg(): jwt.SigningMethodHS512,
+	jwt.SigningMethodRS256.Alg(): jwt.SigningMethodRS256,
+	jwt.SigningMethodRS384.Alg(): jwt.SigningMethodRS384,
+	jwt.SigningMethodRS512.Alg(): jwt.SigningMethodRS512,
+	jwt.SigningMethodES256.Alg(): jwt.SigningMethodES256,
+	jwt.SigningMethodES384.Alg(): jwt.SigningMethodES384,
+	jwt.SigningMethodES512.Alg(): jwt.SigningMethodES512,
+	signingMethodEdDSA{}.Alg():   signingMethodEdDSA{},
+}
+
+// signingMethodEdDSA implements EdDSA signatures using Ed25519 keys, which
+// jwt-go does not provide
+type signingMethodEdDSA struct{}
+
+func (signingMethodEdDSA) Alg() string { return "EdDSA" }
+
+func (signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pub, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
+
+func (signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	return jwt.EncodeSegment(ed25519.Sign(priv, []byte(signingString))), nil
+}
+
+// verifier validates JWTs and their claims. HMAC-signed tokens are verified
+// with a shared secret, and asymmetrically signed tokens with keys from a key
+// set.
+type verifier struct {
+	secret []byte
+	keys   *keySet
+
+	issuer   string
+	audience string
+	leeway   time.Duration
+	timeFunc func() time.Time
+}
+
+// key retrieves the key used to verify tokens with given header
+func (v *verifier) key(alg, kid string) (interface{}, error) {
+	if strings.HasPrefix(alg, "HS") {
+		if len(v.secret) == 0 {
+			return nil, errors.New("HMAC-signed tokens are not accepted")
+		}
+		return v.secret, nil
+	}
+	if v.keys == nil {
+		return nil, errors.New("no key set configured")
+	}
+	return v.keys.lookup(kid, alg)
+}
+
+// verify checks a token's signature and claims, and returns its claims
+func (v *verifier) verify(tokenString string) (jwt.MapClaims, error) {
+	var parts = strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return nil, errInvalidAuth
 	}
 
-	// Verify the claims - this checks expiry as well
+	// read header to select verification method and key
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if b, err := jwt.DecodeSegment(parts[0]); err != nil {
+		return nil, errInvalidAuth
+	} else if err := json.Unmarshal(b, &header); err != nil {
+		return nil, errInvalidAuth
+	}
+	method, ok := signingMethods[header.Alg]
+	if !ok {
+		return nil, errInvalidAuth
+	}
+	key, err := v.key(header.Alg, header.Kid)
+	if err != nil {
+		return nil, errInvalidAuth
+	}
+	if err := method.Verify(parts[0]+"."+parts[1], parts[2], key); err != nil {
+		return nil, errInvalidAuth
+	}
+
+	// read claims
 	var claims jwt.MapClaims
-	var ok bool
-	if claims, ok = token.Claims.(jwt.MapClaims); !ok || !token.Valid {
-		return "", errInvalidAuth
+	if b, err := jwt.DecodeSegment(parts[1]); err != nil {
+		return nil, errInvalidAuth
+	} else if err := json.Unmarshal(b, &claims); err != nil {
+		return nil, errInvalidAuth
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// validate checks time-based claims, allowing for clock skew, as well as the
+// issuer and audience if configured
+func (v *verifier) validate(claims jwt.MapClaims) error {
+	var now = v.timeFunc()
+	if !claims.VerifyExpiresAt(now.Add(-v.leeway).Unix(), false) {
+		return errExpiredAuth
+	}
+	if !claims.VerifyNotBefore(now.Add(v.leeway).Unix(), false) ||
+		!claims.VerifyIssuedAt(now.Add(v.leeway).Unix(), false) {
+		return errInvalidAuth
+	}
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return errInvalidAuth
+	}
+	if v.audience != "" && !hasAudience(claims["aud"], v.audience) {
+		return errInvalidAuth
+	}
+	return nil
+}
+
+// hasAudience checks if an "aud" claim, which may be a string or an array of
+// strings, contains the given audience
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getUserFromJWT(r *http.Request, v *verifier) (user string, err error) {
+	// Collect the token from the header.
+	tokenString := getBearerToken(r)
+	if tokenString == "" {
+		return "", errNoAuth
+	}
+
+	// Verify the token and its claims
+	claims, err := v.verify(tokenString)
+	if err != nil {
+		return "", err
 	}
 
 	// Retrieve ID
+	var ok bool
 	if user, ok = claims["id"].(string); !ok || user == "" {
 		return "", errInvalidAuth
 	}
@@ -0,0 +1,278 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bobheadxi/res"
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/tracing"
+)
+
+// Reasons requests could not be proxied to a node
+const (
+	upstreamNodeDown = "node_down"
+	upstreamTimeout  = "timeout"
+	upstreamRefused  = "refused"
+	upstreamTooLarge = "body_too_large"
+)
+
+// retryBackoff is how long to wait before each retry of a gateway request,
+// multiplied by the number of attempts made
+const retryBackoff = 100 * time.Millisecond
+
+// errBreakerOpen is returned for requests that were rejected because their
+// node's breaker is open
+var errBreakerOpen = errors.New("node is not accepting requests")
+
+type breakerState int
+
+const (
+	// breakerClosed lets all requests through
+	breakerClosed breakerState = iota
+	// breakerOpen rejects all requests
+	breakerOpen
+	// breakerHalfOpen lets a single probe request through
+	breakerHalfOpen
+)
+
+// breaker tracks failed requests to a network's node, and rejects requests
+// while the node appears to be down
+type breaker struct {
+	l         *zap.SugaredLogger
+	network   string
+	threshold int
+	cooldown  time.Duration
+	timeFunc  func() time.Time
+
+	// transport is used to send requests that are let through
+	transport http.RoundTripper
+
+	mux      sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow checks if a request may be sent to the node. Once the breaker has
+// been open for its cooldown, a single probe request is allowed, which decides
+// whether the breaker closes or opens again.
+func (b *breaker) allow() bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.timeFunc().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a request that reached the node
+func (b *breaker) success() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.state != breakerClosed {
+		b.l.Infow("node recovered - closing breaker", "network", b.network)
+	}
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure records a request that could not reach the node
+func (b *breaker) failure() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state != breakerOpen {
+			b.l.Warnw("node unreachable - opening breaker",
+				"network", b.network,
+				"failures", b.failures)
+		}
+		b.state = breakerOpen
+		b.openedAt = b.timeFunc()
+		b.probing = false
+	}
+}
+
+// release records a request that was abandoned before its outcome was known,
+// such as when the client disconnects
+func (b *breaker) release() {
+	b.mux.Lock()
+	b.probing = false
+	b.mux.Unlock()
+}
+
+// retryAfter is how long clients should wait before retrying requests to
+// the node
+func (b *breaker) retryAfter() time.Duration {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.state == breakerClosed {
+		return time.Second
+	}
+	if remaining := b.cooldown - b.timeFunc().Sub(b.openedAt); remaining > time.Second {
+		return remaining
+	}
+	return time.Second
+}
+
+// breakers holds the breaker of each network's node
+type breakers struct {
+	l         *zap.SugaredLogger
+	threshold int
+	cooldown  time.Duration
+	timeFunc  func() time.Time
+	transport http.RoundTripper
+
+	mux   sync.Mutex
+	nodes map[string]*breaker
+}
+
+func newBreakers(l *zap.SugaredLogger, opts config.Breaker) *breakers {
+	var transport = http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   time.Duration(opts.DialTimeoutSeconds) * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	return &breakers{
+		l:         l,
+		threshold: opts.FailureThreshold,
+		cooldown:  time.Duration(opts.OpenSeconds) * time.Second,
+		timeFunc:  time.Now,
+		transport: transport,
+		nodes:     make(map[string]*breaker),
+	}
+}
+
+// get retrieves the breaker of given network's node
+func (bs *breakers) get(network string) *breaker {
+	bs.mux.Lock()
+	defer bs.mux.Unlock()
+	b, found := bs.nodes[network]
+	if !found {
+		b = &breaker{
+			l:         bs.l,
+			network:   network,
+			threshold: bs.threshold,
+			cooldown:  bs.cooldown,
+			timeFunc:  bs.timeFunc,
+			transport: bs.transport,
+		}
+		bs.nodes[network] = b
+	}
+	return b
+}
+
+// breakerTransport sends requests to a node if its breaker allows it, and
+// retries requests that are safe to retry
+type breakerTransport struct {
+	breaker *breaker
+	retries int
+	base    http.RoundTripper
+}
+
+func newBreakerTransport(b *breaker, retries int) *breakerTransport {
+	return &breakerTransport{
+		breaker: b,
+		retries: retries,
+		base:    &tracing.Transport{Name: "delegator.proxy", Base: b.transport},
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if !t.breaker.allow() {
+			return nil, errBreakerOpen
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			t.breaker.success()
+			return resp, nil
+		}
+		// requests cancelled by the client or rejected for their size say
+		// nothing about the node
+		var tooLarge *http.MaxBytesError
+		if req.Context().Err() != nil || errors.As(err, &tooLarge) {
+			t.breaker.release()
+			return nil, err
+		}
+		t.breaker.failure()
+		if attempt > t.retries || !isRetryable(req) {
+			return nil, err
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, err
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		}
+	}
+}
+
+// isRetryable checks if sending the request again has no side effects
+func isRetryable(r *http.Request) bool {
+	return (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+		(r.Body == nil || r.Body == http.NoBody) &&
+		r.Header.Get("Upgrade") == ""
+}
+
+// upstreamErrorHandler responds to requests that could not be proxied to the
+// breaker's node
+func upstreamErrorHandler(l *zap.SugaredLogger, m *metrics, b *breaker) func(http.ResponseWriter, *http.Request, error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		var reason, message, status = upstreamError(err)
+		if reason != upstreamTooLarge && err != errBreakerOpen {
+			l.Warnw("failed to proxy request",
+				"network", b.network,
+				"reason", reason,
+				"error", err)
+		}
+		m.upstreamError(b.network, reason)
+		if reason != upstreamTooLarge {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(b.retryAfter())))
+		}
+		res.R(w, r, res.Err(message, status,
+			"network", b.network,
+			"reason", reason))
+	}
+}
+
+// upstreamError describes why a request could not be proxied to a node
+func upstreamError(err error) (reason, message string, status int) {
+	var netErr net.Error
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return upstreamTooLarge, "request body is too large", http.StatusRequestEntityTooLarge
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return upstreamRefused, "network node refused the connection", http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return upstreamTimeout, "network node timed out", http.StatusGatewayTimeout
+	default:
+		return upstreamNodeDown, "network node is unavailable", http.StatusServiceUnavailable
+	}
+}
@@ -0,0 +1,222 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bobheadxi/res"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+func newTestBreaker(t *testing.T, now *time.Time) *breaker {
+	var bs = newBreakers(zaptest.NewLogger(t).Sugar(), config.Breaker{
+		FailureThreshold:   2,
+		OpenSeconds:        10,
+		DialTimeoutSeconds: 1,
+	})
+	bs.timeFunc = func() time.Time { return *now }
+	return bs.get("test")
+}
+
+func TestBreaker(t *testing.T) {
+	var now = time.Now()
+	var b = newTestBreaker(t, &now)
+
+	// failures below the threshold do not open the breaker
+	b.failure()
+	if !b.allow() {
+		t.Fatal("expected breaker to be closed")
+	}
+	b.success()
+	b.failure()
+	if !b.allow() {
+		t.Fatal("expected success to reset failures")
+	}
+
+	// consecutive failures open the breaker
+	b.failure()
+	if b.allow() {
+		t.Fatal("expected breaker to be open")
+	}
+	if got := b.retryAfter(); got != 10*time.Second {
+		t.Errorf("expected retry after 10s, got %s", got)
+	}
+
+	// a single probe is let through after the cooldown
+	now = now.Add(10 * time.Second)
+	if !b.allow() {
+		t.Fatal("expected probe to be allowed")
+	}
+	if b.allow() {
+		t.Fatal("expected only one probe to be allowed")
+	}
+
+	// abandoned probes let another probe through
+	b.release()
+	if !b.allow() {
+		t.Fatal("expected probe to be allowed after release")
+	}
+
+	// failed probes open the breaker again
+	b.failure()
+	if b.allow() {
+		t.Fatal("expected breaker to be open after failed probe")
+	}
+
+	// successful probes close the breaker
+	now = now.Add(10 * time.Second)
+	if !b.allow() {
+		t.Fatal("expected probe to be allowed")
+	}
+	b.success()
+	if !b.allow() || !b.allow() {
+		t.Fatal("expected breaker to be closed after successful probe")
+	}
+	if got := b.retryAfter(); got != time.Second {
+		t.Errorf("expected retry after 1s, got %s", got)
+	}
+}
+
+type testRoundTripper struct {
+	calls int
+	err   error
+}
+
+func (rt *testRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.calls++
+	if rt.err != nil {
+		return nil, rt.err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func Test_breakerTransport(t *testing.T) {
+	var refused = &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	type args struct {
+		method  string
+		body    string
+		retries int
+		err     error
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success", args{"GET", "", 2, nil}, 1, false},
+		// retries stop once the breaker opens
+		{"GET retried", args{"GET", "", 2, refused}, 2, true},
+		{"HEAD retried", args{"HEAD", "", 1, refused}, 2, true},
+		{"no retries", args{"GET", "", 0, refused}, 1, true},
+		{"POST not retried", args{"POST", "hello", 2, refused}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var now = time.Now()
+			var rt = &testRoundTripper{err: tt.args.err}
+			var transport = &breakerTransport{
+				breaker: newTestBreaker(t, &now),
+				retries: tt.args.retries,
+				base:    rt,
+			}
+
+			var body io.Reader
+			if tt.args.body != "" {
+				body = strings.NewReader(tt.args.body)
+			}
+			_, err := transport.RoundTrip(httptest.NewRequest(tt.args.method, "/ipfs/"+testCID, body))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if rt.calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, rt.calls)
+			}
+		})
+	}
+}
+
+func Test_newProxy_upstreamErrors(t *testing.T) {
+	// reserve an address that refuses connections
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var target = &url.URL{Scheme: "http", Host: listener.Addr().String()}
+	listener.Close()
+
+	var now = time.Now()
+	var l = zaptest.NewLogger(t).Sugar()
+	var b = newTestBreaker(t, &now)
+	var proxy = newProxy("gateway", target, l, newMetrics(), true, b, 0)
+
+	tests := []struct {
+		name       string
+		wantStatus int
+		wantReason string
+		wantRetry  string
+	}{
+		{"refused", http.StatusBadGateway, upstreamRefused, "1"},
+		{"refused and breaker opened", http.StatusBadGateway, upstreamRefused, "10"},
+		{"breaker open", http.StatusServiceUnavailable, upstreamNodeDown, "10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			proxy.ServeHTTP(w, httptest.NewRequest("GET", "/ipfs/"+testCID, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Errorf("expected Retry-After '%s', got '%s'", tt.wantRetry, got)
+			}
+			var network, reason string
+			if _, err := res.Unmarshal(w.Body,
+				res.KV{Key: "network", Value: &network},
+				res.KV{Key: "reason", Value: &reason}); err != nil {
+				t.Fatal(err)
+			}
+			if network != "test" || reason != tt.wantReason {
+				t.Errorf("expected network 'test' and reason '%s', got '%s' and '%s'",
+					tt.wantReason, network, reason)
+			}
+		})
+	}
+}
+
+func Test_upstreamError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+		wantStatus int
+	}{
+		{"breaker open", errBreakerOpen, upstreamNodeDown, http.StatusServiceUnavailable},
+		{"refused",
+			&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			upstreamRefused, http.StatusBadGateway},
+		{"deadline", context.DeadlineExceeded, upstreamTimeout, http.StatusGatewayTimeout},
+		{"dial timeout", &net.DNSError{IsTimeout: true}, upstreamTimeout, http.StatusGatewayTimeout},
+		{"reset", errors.New("connection reset by peer"), upstreamNodeDown, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, _, status := upstreamError(tt.err)
+			if reason != tt.wantReason || status != tt.wantStatus {
+				t.Errorf("expected (%s, %d), got (%s, %d)", tt.wantReason, tt.wantStatus, reason, status)
+			}
+		})
+	}
+}
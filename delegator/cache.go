@@ -2,15 +2,24 @@ package delegator
 
 import (
 	"net/http/httputil"
+	"sort"
 	"sync"
 	"time"
 )
 
 type proxy struct {
+	created int64
 	expire  int64
 	handler *httputil.ReverseProxy
 }
 
+// proxyEntry describes a cached proxy
+type proxyEntry struct {
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 type cache struct {
 	dur   time.Duration
 	store map[string]proxy
@@ -36,7 +45,8 @@ func newCache(expire, cleanInterval time.Duration) *cache {
 // Cache stores given key
 func (c *cache) Cache(key string, handler *httputil.ReverseProxy) {
 	c.mux.Lock()
-	c.store[key] = proxy{time.Now().Add(c.dur).UnixNano(), handler}
+	var now = time.Now()
+	c.store[key] = proxy{now.UnixNano(), now.Add(c.dur).UnixNano(), handler}
 	c.mux.Unlock()
 }
 
@@ -65,6 +75,31 @@ func (c *cache) Size() int {
 	return n
 }
 
+// Delete removes given keys
+func (c *cache) Delete(keys ...string) {
+	c.mux.Lock()
+	for _, k := range keys {
+		delete(c.store, k)
+	}
+	c.mux.Unlock()
+}
+
+// Entries describes all cached items, sorted by key
+func (c *cache) Entries() []proxyEntry {
+	c.mux.RLock()
+	var entries = make([]proxyEntry, 0, len(c.store))
+	for k, v := range c.store {
+		entries = append(entries, proxyEntry{
+			Key:       k,
+			CreatedAt: time.Unix(0, v.created),
+			ExpiresAt: time.Unix(0, v.expire),
+		})
+	}
+	c.mux.RUnlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
 // prune removes all expired items
 func (c *cache) prune() {
 	c.mux.Lock()
@@ -0,0 +1,96 @@
+package delegator
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certReloadInterval is how often certificate files are checked for changes
+const certReloadInterval = 10 * time.Second
+
+// certReloader serves a certificate from files, and reloads it when the files
+// change, so that renewed certificates are picked up without a restart
+type certReloader struct {
+	l        *zap.SugaredLogger
+	certPath string
+	keyPath  string
+
+	mux     sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func newCertReloader(l *zap.SugaredLogger, certPath, keyPath string) *certReloader {
+	return &certReloader{l: l, certPath: certPath, keyPath: keyPath}
+}
+
+// load reads the certificate if either file has changed since it was last
+// read, or unconditionally if force is set. The previous certificate continues
+// to be served if the files cannot be read.
+func (c *certReloader) load(force bool) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var modTime time.Time
+	for _, p := range []string{c.certPath, c.keyPath} {
+		info, err := os.Stat(p)
+		if err != nil {
+			return fmt.Errorf("failed to read certificate: %s", err.Error())
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	if !force && c.cert != nil && modTime.Equal(c.modTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %s", err.Error())
+	}
+	c.cert = &cert
+	c.modTime = modTime
+	c.l.Infow("certificate loaded",
+		"cert", c.certPath)
+	return nil
+}
+
+// watch reloads the certificate when its files change, or whenever a reload
+// is requested, until the context is cancelled
+func (c *certReloader) watch(ctx context.Context, interval time.Duration, reload <-chan struct{}) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var force bool
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-reload:
+			force = true
+		}
+		if err := c.load(force); err != nil {
+			c.l.Errorw("failed to reload certificate - continuing with previous certificate",
+				"cert", c.certPath,
+				"error", err)
+		}
+	}
+}
+
+// GetCertificate implements tls.Config's GetCertificate
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	if c.cert == nil {
+		return nil, errors.New("no certificate loaded")
+	}
+	return c.cert, nil
+}
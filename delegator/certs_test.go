@@ -0,0 +1,141 @@
+package delegator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// writeTestCert writes a self-signed certificate for given name
+func writeTestCert(t *testing.T, dir, name string, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}, &x509.Certificate{SerialNumber: big.NewInt(1)}, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		certPath = filepath.Join(dir, "cert.pem")
+		keyPath  = filepath.Join(dir, "key.pem")
+	)
+	if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+func servedName(t *testing.T, c *certReloader) string {
+	cert, err := c.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var c = newCertReloader(zaptest.NewLogger(t).Sugar(),
+		filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"))
+	if _, err := c.GetCertificate(nil); err == nil {
+		t.Error("expected error before certificate is loaded")
+	}
+	if err := c.load(false); err == nil {
+		t.Error("expected error for missing files")
+	}
+
+	certPath, keyPath := writeTestCert(t, dir, "a.example.com", time.Now().Add(time.Hour))
+	if err := c.load(false); err != nil {
+		t.Fatal(err)
+	}
+	if name := servedName(t, c); name != "a.example.com" {
+		t.Errorf("expected certificate for a.example.com, got %s", name)
+	}
+
+	// changed files should be picked up
+	writeTestCert(t, dir, "b.example.com", time.Now().Add(time.Hour))
+	var later = time.Now().Add(time.Minute)
+	os.Chtimes(certPath, later, later)
+	os.Chtimes(keyPath, later, later)
+	if err := c.load(false); err != nil {
+		t.Fatal(err)
+	}
+	if name := servedName(t, c); name != "b.example.com" {
+		t.Errorf("expected certificate for b.example.com, got %s", name)
+	}
+
+	// invalid files should not replace the served certificate
+	if err := ioutil.WriteFile(certPath, []byte("asdf"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.load(true); err == nil {
+		t.Error("expected error for invalid certificate")
+	}
+	if name := servedName(t, c); name != "b.example.com" {
+		t.Errorf("expected previous certificate to be served, got %s", name)
+	}
+}
+
+func TestCertReloader_watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certPath, keyPath := writeTestCert(t, dir, "a.example.com", time.Now().Add(time.Hour))
+	var c = newCertReloader(zaptest.NewLogger(t).Sugar(), certPath, keyPath)
+	if err := c.load(false); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reload = make(chan struct{})
+	go c.watch(ctx, time.Hour, reload)
+
+	// requested reloads should apply even if modification times are unchanged
+	info, _ := os.Stat(certPath)
+	writeTestCert(t, dir, "b.example.com", time.Now().Add(time.Hour))
+	os.Chtimes(certPath, info.ModTime(), info.ModTime())
+	os.Chtimes(keyPath, info.ModTime(), info.ModTime())
+	reload <- struct{}{}
+	reload <- struct{}{} // wait for first reload to complete
+	if name := servedName(t, c); name != "b.example.com" {
+		t.Errorf("expected certificate for b.example.com, got %s", name)
+	}
+}
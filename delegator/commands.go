@@ -0,0 +1,111 @@
+package delegator
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/bobheadxi/res"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// scopeCommands declares the IPFS API commands each API token scope permits.
+// Commands are matched by prefix, so "pin" permits "pin/add" as well.
+var scopeCommands = map[string][]string{
+	store.ScopeReadOnly: {
+		"bitswap/stat", "bitswap/wantlist", "block/get", "block/stat", "cat",
+		"commands", "dag/get", "dag/resolve", "dht/findpeer", "dht/findprovs",
+		"dht/get", "dns", "file/ls", "files/ls", "files/read", "files/stat", "get",
+		"id", "key/list", "ls", "name/resolve", "object/data", "object/get",
+		"object/links", "object/stat", "pin/ls", "refs", "repo/stat",
+		"repo/version", "resolve", "stats", "swarm/addrs", "swarm/peers",
+		"tar/cat", "version",
+	},
+	store.ScopePinOnly: {
+		"pin",
+	},
+}
+
+// apiCommand extracts the IPFS API command from a request path, such as
+// "pin/add" from "/api/v0/pin/add". The path is cleaned first, so that
+// commands cannot be obscured with redundant separators or dot segments.
+func apiCommand(p string) string {
+	const prefix = "/api/v0/"
+	p = path.Clean("/" + p)
+	var i = strings.Index(p+"/", prefix)
+	if i < 0 {
+		return ""
+	}
+	return strings.Trim(p[i+len(prefix)-1:], "/")
+}
+
+// matchesCommand checks if command is the given command or one of its
+// subcommands
+func matchesCommand(command, match string) bool {
+	return command == match || strings.HasPrefix(command, match+"/")
+}
+
+// scopesAllow checks if any of the given scopes permit the command
+func scopesAllow(scopes store.Scopes, command string) bool {
+	if scopes.Has(store.ScopeAdmin) {
+		return true
+	}
+	if command == "" {
+		return false
+	}
+	for _, scope := range scopes {
+		for _, match := range scopeCommands[scope] {
+			if matchesCommand(command, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesAnyCommand checks if command matches any of the given commands
+func matchesAnyCommand(command string, matches []string) bool {
+	for _, match := range matches {
+		if matchesCommand(command, match) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandPermitted checks if the policy allows the command
+func commandPermitted(policy config.CommandPolicy, command string) bool {
+	if matchesAnyCommand(command, policy.Deny) {
+		return false
+	}
+	if len(policy.Allow) > 0 && !matchesAnyCommand(command, policy.Allow) {
+		return false
+	}
+	return true
+}
+
+// enforceCommandPolicy rejects requests for IPFS API commands that are not
+// permitted on the network, and returns false if the request was rejected
+func (e *Engine) enforceCommandPolicy(w http.ResponseWriter, r *http.Request, network string) bool {
+	var policy = e.commands
+	if s, err := e.networks.GetNetworkSettings(network); err != nil {
+		e.l.Warnw("failed to retrieve network settings - using default command policy",
+			"network", network,
+			"error", err)
+	} else if s != nil {
+		policy = s.CommandPolicy(e.commands)
+	}
+
+	var command = apiCommand(r.URL.Path)
+	if commandPermitted(policy, command) {
+		return true
+	}
+	e.metrics.authFailure(network, reasonCommandNotPermitted)
+	res.R(w, r, res.ErrForbidden(
+		fmt.Sprintf("command '%s' is not permitted on this network", command),
+		"command", command))
+	return false
+}
@@ -0,0 +1,139 @@
+package delegator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+)
+
+func Test_apiCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"no command", "/", ""},
+		{"command", "/api/v0/cat", "cat"},
+		{"subcommand", "/api/v0/pin/add/", "pin/add"},
+		{"network path", "/network/test/api/api/v0/pin/ls", "pin/ls"},
+		{"root", "/api/v0", ""},
+		{"similar prefix", "/api/v0x/shutdown", ""},
+		{"redundant separators", "/api/v0//shutdown", "shutdown"},
+		{"dot segments", "/api/v0/./pin/../shutdown", "shutdown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiCommand(tt.path); got != tt.want {
+				t.Errorf("apiCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scopesAllow(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  store.Scopes
+		command string
+		want    bool
+	}{
+		{"admin", store.Scopes{store.ScopeAdmin}, "repo/gc", true},
+		{"no command", store.Scopes{store.ScopeReadOnly}, "", false},
+		{"read-only read", store.Scopes{store.ScopeReadOnly}, "cat", true},
+		{"read-only write", store.Scopes{store.ScopeReadOnly}, "add", false},
+		{"read-only prefix", store.Scopes{store.ScopeReadOnly}, "catalog", false},
+		{"read-only pin list", store.Scopes{store.ScopeReadOnly}, "pin/ls", true},
+		{"read-only pin add", store.Scopes{store.ScopeReadOnly}, "pin/add", false},
+		{"pin-only pin add", store.Scopes{store.ScopePinOnly}, "pin/add", true},
+		{"pin-only cat", store.Scopes{store.ScopePinOnly}, "cat", false},
+		{"combined", store.Scopes{store.ScopePinOnly, store.ScopeReadOnly}, "cat", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scopesAllow(tt.scopes, tt.command); got != tt.want {
+				t.Errorf("scopesAllow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commandPermitted(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  config.CommandPolicy
+		command string
+		want    bool
+	}{
+		{"empty policy", config.CommandPolicy{}, "shutdown", true},
+		{"denied", config.CommandPolicy{Deny: []string{"shutdown"}}, "shutdown", false},
+		{"denied subcommand", config.CommandPolicy{Deny: []string{"config"}}, "config/replace", false},
+		{"not denied", config.CommandPolicy{Deny: []string{"config"}}, "configure", true},
+		{"allowed", config.CommandPolicy{Allow: []string{"pin"}}, "pin/add", true},
+		{"not allowed", config.CommandPolicy{Allow: []string{"pin"}}, "cat", false},
+		{"deny takes precedence",
+			config.CommandPolicy{Allow: []string{"repo"}, Deny: []string{"repo/gc"}}, "repo/gc", false},
+		{"defaults", config.New().Delegator.Commands, "bootstrap/rm", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commandPermitted(tt.policy, tt.command); got != tt.want {
+				t.Errorf("commandPermitted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_enforceCommandPolicy(t *testing.T) {
+	var defaults = config.CommandPolicy{Deny: []string{"shutdown"}}
+	type fields struct {
+		settings    *store.NetworkSettings
+		settingsErr error
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		path     string
+		wantCode int
+	}{
+		{"default allows", fields{nil, nil}, "/api/v0/cat", 0},
+		{"default denies", fields{nil, nil}, "/api/v0/shutdown", http.StatusForbidden},
+		{"settings error uses defaults",
+			fields{nil, errors.New("oh no")}, "/api/v0/shutdown", http.StatusForbidden},
+		{"override allows",
+			fields{&store.NetworkSettings{Commands: &store.CommandPolicy{}}, nil},
+			"/api/v0/shutdown", 0},
+		{"override denies",
+			fields{&store.NetworkSettings{Commands: &store.CommandPolicy{Allow: []string{"pin"}}}, nil},
+			"/api/v0/cat", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				settings = &smock.FakeSettings{}
+				e        = newTestEngine(t, EngineOpts{Commands: defaults}, nil, Stores{Settings: settings})
+				rec      = httptest.NewRecorder()
+			)
+			settings.GetNetworkSettingsReturns(tt.fields.settings, tt.fields.settingsErr)
+
+			var ok = e.enforceCommandPolicy(rec, httptest.NewRequest("POST", tt.path, nil), "bobheadxi")
+			if ok != (tt.wantCode == 0) {
+				t.Errorf("Engine.enforceCommandPolicy() = %v, want %v", ok, tt.wantCode == 0)
+			}
+			if tt.wantCode != 0 {
+				if rec.Code != tt.wantCode {
+					t.Errorf("expected status %d, found %d", tt.wantCode, rec.Code)
+				}
+				var command = apiCommand(tt.path)
+				if !strings.Contains(rec.Body.String(), "'"+command+"'") {
+					t.Errorf("expected blocked command '%s' in response, got %s", command, rec.Body.String())
+				}
+			}
+		})
+	}
+}
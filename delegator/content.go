@@ -0,0 +1,458 @@
+package delegator
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+// Results of gateway content cache lookups
+const (
+	contentHit    = "hit"
+	contentMiss   = "miss"
+	contentBypass = "bypass"
+)
+
+// contentHeaders are the response headers that are stored with cached content
+var contentHeaders = []string{
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Type",
+	"Etag",
+	"X-Content-Type-Options",
+	"X-Ipfs-Path",
+}
+
+// contentEntry describes a cached gateway response
+type contentEntry struct {
+	Key     string      `json:"key"`
+	Network string      `json:"network"`
+	Size    int64       `json:"size"`
+	Header  http.Header `json:"header"`
+	Stored  time.Time   `json:"stored"`
+}
+
+// contentCache is an on-disk cache of immutable gateway content. Since
+// content under /ipfs/ paths is addressed by its hash, cached responses never
+// need to be revalidated - they are only evicted, least recently used first,
+// once the total size of cached content or the size of a network's cached
+// content exceeds its limit.
+type contentCache struct {
+	l       *zap.SugaredLogger
+	metrics *metrics
+
+	path           string
+	maxSize        int64
+	networkMaxSize int64
+	maxObjectSize  int64
+
+	mux     sync.Mutex
+	lru     *list.List // most recently used entries are at the front
+	entries map[string]*list.Element
+	sizes   map[string]int64
+	size    int64
+}
+
+func newContentCache(l *zap.SugaredLogger, m *metrics, opts config.GatewayCache) *contentCache {
+	var c = &contentCache{
+		l:       l,
+		metrics: m,
+
+		path:           opts.Path,
+		maxSize:        opts.MaxSizeMB << 20,
+		networkMaxSize: opts.NetworkMaxSizeMB << 20,
+		maxObjectSize:  opts.MaxObjectSizeMB << 20,
+
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		sizes:   make(map[string]int64),
+	}
+	m.registerGatewayCache(c.Size)
+	return c
+}
+
+// load indexes content cached on disk, discarding incomplete entries
+func (c *contentCache) load() error {
+	if err := os.MkdirAll(c.path, 0700); err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(c.path, "*", "*"))
+	if err != nil {
+		return err
+	}
+
+	var loaded = make([]*contentEntry, 0)
+	for _, f := range files {
+		if filepath.Ext(f) != ".json" {
+			// clean up incomplete writes and content without metadata
+			if _, err := os.Stat(f + ".json"); err != nil {
+				os.Remove(f)
+			}
+			continue
+		}
+		var entry contentEntry
+		b, err := ioutil.ReadFile(f)
+		if err == nil {
+			err = json.Unmarshal(b, &entry)
+		}
+		if err != nil || c.file(entry.Network, entry.Key)+".json" != f {
+			c.l.Warnw("discarding invalid gateway cache entry",
+				"file", f,
+				"error", err)
+			os.Remove(f)
+			os.Remove(strings.TrimSuffix(f, ".json"))
+			continue
+		}
+		if info, err := os.Stat(strings.TrimSuffix(f, ".json")); err != nil || info.Size() != entry.Size {
+			os.Remove(f)
+			continue
+		}
+		loaded = append(loaded, &entry)
+	}
+
+	// restore entries in order of age, so that the oldest are evicted first
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Stored.Before(loaded[j].Stored) })
+	c.mux.Lock()
+	for _, entry := range loaded {
+		c.entries[entry.Key] = c.lru.PushFront(entry)
+		c.sizes[entry.Network] += entry.Size
+		c.size += entry.Size
+	}
+	for network := range c.sizes {
+		c.evict(network, 0)
+	}
+	var entries, size = c.lru.Len(), c.size
+	c.mux.Unlock()
+
+	c.l.Infow("gateway cache loaded",
+		"path", c.path,
+		"entries", entries,
+		"size", size)
+	return nil
+}
+
+// Size returns the total size of cached content in bytes
+func (c *contentCache) Size() int64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.size
+}
+
+// Purge discards all cached content of given network
+func (c *contentCache) Purge(network string) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for el := c.lru.Front(); el != nil; {
+		var next = el.Next()
+		if el.Value.(*contentEntry).Network == network {
+			c.remove(el)
+		}
+		el = next
+	}
+	delete(c.sizes, network)
+	return os.RemoveAll(filepath.Join(c.path, hashName(network)))
+}
+
+// serve responds to gateway requests for immutable content from the cache,
+// and caches responses from next if possible. Other requests, such as those
+// for /ipns/ paths, are passed directly to next.
+func (c *contentCache) serve(w http.ResponseWriter, r *http.Request, network, p string, next http.Handler) {
+	var (
+		cacheControl = r.Header.Get("Cache-Control")
+		noStore      = hasDirective(cacheControl, "no-store")
+		noCache      = hasDirective(cacheControl, "no-cache") || r.Header.Get("Pragma") == "no-cache"
+	)
+
+	// only content addressed by hash is immutable
+	var key string
+	if cleaned, ok := immutablePath(p); ok && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		key = network + cleaned
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+	}
+	if key == "" || noStore {
+		c.metrics.gatewayCacheLookup(contentBypass)
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	if !noCache && c.serveCached(w, r, key) {
+		c.metrics.gatewayCacheLookup(contentHit)
+		return
+	}
+	c.metrics.gatewayCacheLookup(contentMiss)
+	w.Header().Set("X-Cache", "MISS")
+
+	// only complete responses are cached - partial content is served from the
+	// cache once the complete response has been cached
+	if r.Method != http.MethodGet || r.Header.Get("Range") != "" {
+		next.ServeHTTP(w, r)
+		return
+	}
+	var rec = &contentRecorder{
+		ResponseWriter: w,
+		dir:            filepath.Join(c.path, hashName(network)),
+		max:            c.maxObjectSize,
+	}
+	defer rec.discard()
+	next.ServeHTTP(rec, r)
+	if tmp, size, ok := rec.complete(); ok {
+		c.add(&contentEntry{
+			Key:     key,
+			Network: network,
+			Size:    size,
+			Header:  rec.header,
+			Stored:  time.Now(),
+		}, tmp)
+	}
+}
+
+// serveCached responds with the cached content for given key, if available
+func (c *contentCache) serveCached(w http.ResponseWriter, r *http.Request, key string) bool {
+	c.mux.Lock()
+	el, found := c.entries[key]
+	if !found {
+		c.mux.Unlock()
+		return false
+	}
+	c.lru.MoveToFront(el)
+	var entry = el.Value.(*contentEntry)
+	c.mux.Unlock()
+
+	f, err := os.Open(c.file(entry.Network, entry.Key))
+	if err != nil {
+		c.l.Warnw("failed to open cached content - discarding entry",
+			"key", key,
+			"error", err)
+		c.mux.Lock()
+		if el, found := c.entries[key]; found {
+			c.remove(el)
+		}
+		c.mux.Unlock()
+		return false
+	}
+	defer f.Close()
+
+	for k, v := range entry.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("X-Cache", "HIT")
+	http.ServeContent(w, r, "", entry.Stored, f)
+	return true
+}
+
+// add moves the content at given temporary path into the cache, evicting
+// least recently used content as required
+func (c *contentCache) add(entry *contentEntry, tmp string) {
+	if entry.Size > c.networkMaxSize || entry.Size > c.maxSize {
+		os.Remove(tmp)
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if el, found := c.entries[entry.Key]; found {
+		c.remove(el)
+	}
+	c.evict(entry.Network, entry.Size)
+
+	var file = c.file(entry.Network, entry.Key)
+	b, err := json.Marshal(entry)
+	if err == nil {
+		err = os.Rename(tmp, file)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(file+".json", b, 0600)
+	}
+	if err != nil {
+		c.l.Errorw("failed to cache gateway content",
+			"key", entry.Key,
+			"error", err)
+		os.Remove(tmp)
+		os.Remove(file)
+		return
+	}
+	c.entries[entry.Key] = c.lru.PushFront(entry)
+	c.sizes[entry.Network] += entry.Size
+	c.size += entry.Size
+}
+
+// evict discards least recently used content until content of given size can
+// be added for given network. It must be called with the lock held.
+func (c *contentCache) evict(network string, incoming int64) {
+	for c.sizes[network]+incoming > c.networkMaxSize {
+		var el = c.lru.Back()
+		for el != nil && el.Value.(*contentEntry).Network != network {
+			el = el.Prev()
+		}
+		if el == nil {
+			break
+		}
+		c.remove(el)
+		c.metrics.gatewayCacheEvictions.Inc()
+	}
+	for c.size+incoming > c.maxSize && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+		c.metrics.gatewayCacheEvictions.Inc()
+	}
+}
+
+// remove discards the given entry. It must be called with the lock held.
+func (c *contentCache) remove(el *list.Element) {
+	var entry = c.lru.Remove(el).(*contentEntry)
+	delete(c.entries, entry.Key)
+	c.sizes[entry.Network] -= entry.Size
+	c.size -= entry.Size
+	var file = c.file(entry.Network, entry.Key)
+	os.Remove(file + ".json")
+	os.Remove(file)
+}
+
+// file returns the path of the cached content for given key
+func (c *contentCache) file(network, key string) string {
+	return filepath.Join(c.path, hashName(network), hashName(key))
+}
+
+// contentRecorder forwards a response while copying it to a temporary file,
+// if the response can be cached
+type contentRecorder struct {
+	http.ResponseWriter
+	dir string
+	max int64
+
+	status  int
+	header  http.Header
+	file    *os.File
+	written int64
+}
+
+func (rr *contentRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+		rr.start()
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *contentRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.WriteHeader(http.StatusOK)
+	}
+	n, err := rr.ResponseWriter.Write(b)
+	if rr.file != nil {
+		if rr.written+int64(n) > rr.max {
+			rr.discard()
+		} else if _, err := rr.file.Write(b[:n]); err != nil {
+			rr.discard()
+		} else {
+			rr.written += int64(n)
+		}
+	}
+	return n, err
+}
+
+// Flush allows streamed responses to be flushed to the client
+func (rr *contentRecorder) Flush() {
+	if f, ok := rr.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// start begins copying the response if it can be cached
+func (rr *contentRecorder) start() {
+	var h = rr.Header()
+	if rr.status != http.StatusOK ||
+		h.Get("Content-Range") != "" ||
+		h.Get("Content-Encoding") != "" ||
+		hasDirective(h.Get("Cache-Control"), "no-store") ||
+		hasDirective(h.Get("Cache-Control"), "private") {
+		return
+	}
+	if length, err := strconv.ParseInt(h.Get("Content-Length"), 10, 64); err == nil && length > rr.max {
+		return
+	}
+	if err := os.MkdirAll(rr.dir, 0700); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(rr.dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	rr.file = f
+	rr.header = make(http.Header)
+	for _, k := range contentHeaders {
+		if v, ok := h[k]; ok {
+			rr.header[k] = v
+		}
+	}
+}
+
+// complete returns the temporary file the response was copied to, if the
+// entire response was copied
+func (rr *contentRecorder) complete() (string, int64, bool) {
+	if rr.file == nil {
+		return "", 0, false
+	}
+	if length, err := strconv.ParseInt(rr.Header().Get("Content-Length"), 10, 64); err == nil && length != rr.written {
+		return "", 0, false
+	}
+	var name = rr.file.Name()
+	if err := rr.file.Close(); err != nil {
+		return "", 0, false
+	}
+	rr.file = nil
+	return name, rr.written, true
+}
+
+// discard stops copying the response and removes the temporary file
+func (rr *contentRecorder) discard() {
+	if rr.file != nil {
+		rr.file.Close()
+		os.Remove(rr.file.Name())
+		rr.file = nil
+	}
+}
+
+// immutablePath cleans the given gateway path and checks if it refers to
+// content addressed by hash
+func immutablePath(p string) (string, bool) {
+	var cleaned = path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned, strings.HasPrefix(cleaned, "/ipfs/") && len(cleaned) > len("/ipfs/")
+}
+
+// hasDirective checks if a Cache-Control header contains given directive
+func hasDirective(header, directive string) bool {
+	for _, d := range strings.Split(header, ",") {
+		if i := strings.Index(d, "="); i >= 0 {
+			d = d[:i]
+		}
+		if strings.EqualFold(strings.TrimSpace(d), directive) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashName(s string) string {
+	var sum = sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,234 @@
+package delegator
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+// testGateway mocks a node's gateway, which serves the requested path
+type testGateway struct {
+	requests     int
+	cacheControl string
+}
+
+func (g *testGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.requests++
+	var body = "content of " + r.URL.Path
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("Etag", `"`+r.URL.Path+`"`)
+	if g.cacheControl != "" {
+		w.Header().Set("Cache-Control", g.cacheControl)
+	}
+	w.Write([]byte(body))
+}
+
+func newTestContentCache(t *testing.T, opts config.GatewayCache) (*contentCache, func()) {
+	dir, err := ioutil.TempDir("", "nexus-content")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Path = dir
+	if opts.MaxSizeMB == 0 {
+		opts.MaxSizeMB = 1
+	}
+	if opts.NetworkMaxSizeMB == 0 {
+		opts.NetworkMaxSizeMB = 1
+	}
+	if opts.MaxObjectSizeMB == 0 {
+		opts.MaxObjectSizeMB = 1
+	}
+	var c = newContentCache(zaptest.NewLogger(t).Sugar(), newMetrics(), opts)
+	if err := c.load(); err != nil {
+		t.Fatal(err)
+	}
+	return c, func() { os.RemoveAll(dir) }
+}
+
+func serveContent(c *contentCache, next http.Handler, network, path string, headers map[string]string) *httptest.ResponseRecorder {
+	var (
+		req = httptest.NewRequest("GET", path, nil)
+		rec = httptest.NewRecorder()
+	)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	c.serve(rec, req, network, req.URL.Path, next)
+	return rec
+}
+
+func TestContentCache_serve(t *testing.T) {
+	var path = "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/readme"
+	tests := []struct {
+		name         string
+		path         string
+		headers      map[string]string
+		cacheControl string
+		wantRequests int
+		wantCache    string
+	}{
+		{"immutable content is cached", path, nil, "", 1, "HIT"},
+		{"ipns paths bypass cache", "/ipns/temporal.cloud", nil, "", 2, ""},
+		{"no-store requests bypass cache", path, map[string]string{"Cache-Control": "no-store"}, "", 2, ""},
+		{"no-cache requests are revalidated", path, map[string]string{"Cache-Control": "no-cache"}, "", 2, "MISS"},
+		{"private responses are not cached", path, nil, "private", 2, "MISS"},
+		{"uncacheable paths are not cached", "/ipfs/../ipns/temporal.cloud", nil, "", 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, cleanup := newTestContentCache(t, config.GatewayCache{})
+			defer cleanup()
+			var gateway = &testGateway{cacheControl: tt.cacheControl}
+
+			var first = serveContent(c, gateway, "test", tt.path, tt.headers)
+			var second = serveContent(c, gateway, "test", tt.path, tt.headers)
+			if gateway.requests != tt.wantRequests {
+				t.Errorf("expected %d requests to node, found %d", tt.wantRequests, gateway.requests)
+			}
+			if second.Header().Get("X-Cache") != tt.wantCache {
+				t.Errorf("expected X-Cache '%s', found '%s'", tt.wantCache, second.Header().Get("X-Cache"))
+			}
+			if first.Body.String() != second.Body.String() {
+				t.Errorf("expected identical responses, found '%s' and '%s'", first.Body, second.Body)
+			}
+		})
+	}
+}
+
+func TestContentCache_serveRange(t *testing.T) {
+	c, cleanup := newTestContentCache(t, config.GatewayCache{})
+	defer cleanup()
+	var (
+		gateway = &testGateway{}
+		path    = "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/readme"
+	)
+
+	// ranges are not cached on miss
+	if rec := serveContent(c, gateway, "test", path, map[string]string{"Range": "bytes=0-6"}); c.Size() != 0 {
+		t.Errorf("expected partial response not to be cached, found %d bytes cached (status %d)", c.Size(), rec.Code)
+	}
+
+	// ranges are served from cached content
+	serveContent(c, gateway, "test", path, nil)
+	var rec = serveContent(c, gateway, "test", path, map[string]string{"Range": "bytes=0-6"})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "content" {
+		t.Errorf("expected partial content 'content', found %d '%s'", rec.Code, rec.Body)
+	}
+	if gateway.requests != 2 {
+		t.Errorf("expected 2 requests to node, found %d", gateway.requests)
+	}
+
+	// conditional requests are served from cached content
+	rec = serveContent(c, gateway, "test", path, map[string]string{"If-None-Match": `"` + path + `"`})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected status '%d', found '%d'", http.StatusNotModified, rec.Code)
+	}
+}
+
+func TestContentCache_evict(t *testing.T) {
+	c, cleanup := newTestContentCache(t, config.GatewayCache{})
+	defer cleanup()
+	c.networkMaxSize = 120
+	c.maxSize = 200
+	var gateway = &testGateway{}
+
+	// each response is 65 bytes - only one fits per network
+	serveContent(c, gateway, "a", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil)
+	serveContent(c, gateway, "a", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/2", nil)
+	if c.sizes["a"] != 65 || c.lru.Len() != 1 {
+		t.Errorf("expected network limit to be enforced, found %d bytes in %d entries", c.sizes["a"], c.lru.Len())
+	}
+
+	// only three fit in total - least recently used content is evicted
+	serveContent(c, gateway, "b", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil)
+	serveContent(c, gateway, "a", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/2", nil)
+	serveContent(c, gateway, "c", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil)
+	serveContent(c, gateway, "d", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil)
+	if c.Size() > 200 {
+		t.Errorf("expected global limit to be enforced, found %d bytes", c.Size())
+	}
+	if _, found := c.entries["b/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1"]; found {
+		t.Error("expected least recently used content to be evicted")
+	}
+	if _, found := c.entries["a/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/2"]; !found {
+		t.Error("expected recently used content to be retained")
+	}
+	if v := testutil.ToFloat64(c.metrics.gatewayCacheEvictions); v != 2 {
+		t.Errorf("expected 2 evictions, found %v", v)
+	}
+}
+
+func TestContentCache_loadAndPurge(t *testing.T) {
+	c, cleanup := newTestContentCache(t, config.GatewayCache{})
+	defer cleanup()
+	var gateway = &testGateway{}
+	serveContent(c, gateway, "a", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil)
+	serveContent(c, gateway, "b", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil)
+
+	// leave an incomplete write behind
+	if err := ioutil.WriteFile(c.file("a", "incomplete")+".tmp", []byte("asdf"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// cached content should be restored
+	var restored = newContentCache(c.l, newMetrics(), config.GatewayCache{
+		Path: c.path, MaxSizeMB: 1, NetworkMaxSizeMB: 1, MaxObjectSizeMB: 1})
+	if err := restored.load(); err != nil {
+		t.Fatal(err)
+	}
+	if restored.lru.Len() != 2 || restored.Size() != c.Size() {
+		t.Errorf("expected 2 entries of %d bytes, found %d of %d bytes", c.Size(), restored.lru.Len(), restored.Size())
+	}
+	if _, err := os.Stat(c.file("a", "incomplete") + ".tmp"); !os.IsNotExist(err) {
+		t.Error("expected incomplete write to be removed")
+	}
+	if rec := serveContent(restored, gateway, "a", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil); rec.Header().Get("X-Cache") != "HIT" ||
+		!strings.HasPrefix(rec.Body.String(), "content of") {
+		t.Errorf("expected restored content to be served, found '%s'", rec.Body)
+	}
+
+	// purged content should no longer be served
+	if err := restored.Purge("a"); err != nil {
+		t.Fatal(err)
+	}
+	if restored.lru.Len() != 1 || restored.sizes["a"] != 0 {
+		t.Errorf("expected network content to be purged, found %d entries", restored.lru.Len())
+	}
+	if rec := serveContent(restored, gateway, "a", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/1", nil); rec.Header().Get("X-Cache") != "MISS" {
+		t.Error("expected purged content to be fetched")
+	}
+}
+
+func Test_immutablePath(t *testing.T) {
+	tests := []struct {
+		path          string
+		wantPath      string
+		wantImmutable bool
+	}{
+		{"/ipfs/QmHash", "/ipfs/QmHash", true},
+		{"/ipfs/QmHash/dir/", "/ipfs/QmHash/dir/", true},
+		{"/ipfs//QmHash/./a", "/ipfs/QmHash/a", true},
+		{"/ipfs/", "/ipfs/", false},
+		{"/ipns/temporal.cloud", "/ipns/temporal.cloud", false},
+		{"/ipfs/../ipns/temporal.cloud", "/ipns/temporal.cloud", false},
+		{"/api/v0/cat", "/api/v0/cat", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, immutable := immutablePath(tt.path)
+			if got != tt.wantPath || immutable != tt.wantImmutable {
+				t.Errorf("immutablePath() = (%s, %v), want (%s, %v)", got, immutable, tt.wantPath, tt.wantImmutable)
+			}
+		})
+	}
+}
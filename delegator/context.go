@@ -8,4 +8,11 @@ func (c contextKey) String() string { return string(c) }
 const (
 	keyNetwork contextKey = "network_id"
 	keyFeature contextKey = "feature"
+	keyLabels  contextKey = "labels"
+
+	// keyContentRoot denotes the content path root, such as "/ipfs/<cid>", of
+	// requests to subdomain gateway hosts and custom domains
+	keyContentRoot contextKey = "content_root"
+	// keyDomain denotes the custom domain a request was made to
+	keyDomain contextKey = "domain"
 )
@@ -0,0 +1,178 @@
+package delegator
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bobheadxi/res"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// corsHeaders are response headers that are set according to the network's
+// CORS policy, and are removed from node responses so that nodes' own
+// policies do not conflict with it
+var corsHeaders = []string{
+	"Access-Control-Allow-Origin",
+	"Access-Control-Allow-Credentials",
+	"Access-Control-Allow-Methods",
+	"Access-Control-Allow-Headers",
+	"Access-Control-Expose-Headers",
+	"Access-Control-Max-Age",
+}
+
+// isPreflight checks if the request is a CORS preflight request
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// corsPolicy retrieves the network's CORS policy for given feature. Networks
+// without a policy for the API may still restrict API origins with their
+// allowed origin.
+func (e *Engine) corsPolicy(ctx context.Context, network, feature string) config.CORSPolicy {
+	s, err := e.networks.GetNetworkSettings(network)
+	if err != nil {
+		e.l.Warnw("failed to retrieve network settings - using default CORS policy",
+			"network", network,
+			"error", err)
+		return e.cors.Feature(feature)
+	}
+	if s != nil {
+		if _, found := s.CORS[feature]; found {
+			return s.CORS.Feature(feature, e.cors)
+		}
+	}
+	if feature == "api" {
+		if entry, err := e.lookupNetwork(ctx, network); err == nil && entry.APIAllowedOrigin != "" {
+			var policy = e.cors.API
+			policy.AllowedOrigins = []string{entry.APIAllowedOrigin}
+			return policy
+		}
+	}
+	return e.cors.Feature(feature)
+}
+
+// handleCORS sets CORS headers on the response according to the network's
+// policy, and answers preflight requests. Preflights are answered before
+// requests are authenticated, since browsers do not send credentials with
+// them. Returns false if the request has been answered.
+func (e *Engine) handleCORS(w http.ResponseWriter, r *http.Request, network, feature string) bool {
+	return serveCORS(w, r, func() config.CORSPolicy {
+		return e.corsPolicy(r.Context(), network, feature)
+	})
+}
+
+// serveCORS sets CORS headers on the response according to given policy, which
+// is only retrieved for cross-origin requests, and answers preflight requests.
+// Returns false if the request has been answered.
+func serveCORS(w http.ResponseWriter, r *http.Request, getPolicy func() config.CORSPolicy) bool {
+	var h = w.Header()
+	h.Add("Vary", "Origin")
+	var origin = r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	var preflight = isPreflight(r)
+	if preflight {
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+	}
+
+	// requests from other origins are still served, but browsers will not
+	// allow the response to be read
+	var policy = getPolicy()
+	allowed, wildcard := matchAllowedOrigin(policy.AllowedOrigins, origin)
+	if !allowed && !preflight {
+		return true
+	}
+
+	// check the method and headers the browser intends to use
+	var method = r.Header.Get("Access-Control-Request-Method")
+	var headers = parseHeaderList(r.Header.Values("Access-Control-Request-Headers"))
+	if preflight {
+		if !allowed {
+			res.R(w, r, res.ErrForbidden("origin is not permitted",
+				"origin", origin))
+			return false
+		}
+		if !containsToken(policy.AllowedMethods, method) {
+			res.R(w, r, res.ErrForbidden("method is not permitted",
+				"method", method))
+			return false
+		}
+		for _, header := range headers {
+			if !containsToken(policy.AllowedHeaders, header) {
+				res.R(w, r, res.ErrForbidden("header is not permitted",
+					"header", header))
+				return false
+			}
+		}
+	}
+
+	if wildcard && !policy.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if policy.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if !preflight {
+		if len(policy.ExposedHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+		}
+		return true
+	}
+	h.Set("Access-Control-Allow-Methods", method)
+	if len(headers) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	if policy.MaxAgeSeconds > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAgeSeconds))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return false
+}
+
+// matchAllowedOrigin checks if the origin is allowed, and whether it was only
+// allowed by the "*" wildcard
+func matchAllowedOrigin(allowed []string, origin string) (bool, bool) {
+	var wildcard = false
+	for _, a := range allowed {
+		if a == "*" {
+			wildcard = true
+		} else if store.MatchOrigin(a, origin) {
+			return true, false
+		}
+	}
+	return wildcard, wildcard
+}
+
+// containsToken checks if a method or header is in the list, ignoring case -
+// "*" matches everything
+func containsToken(list []string, token string) bool {
+	for _, t := range list {
+		if t == "*" || strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHeaderList splits comma-separated header values into their elements
+func parseHeaderList(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,243 @@
+package delegator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestEngine_handleCORS(t *testing.T) {
+	var overrides = &store.NetworkSettings{CORS: store.CORSPolicies{
+		"api": {
+			AllowedOrigins:   []string{"https://*.example.com"},
+			AllowedHeaders:   []string{"Authorization"},
+			AllowCredentials: true,
+		},
+	}}
+	type fields struct {
+		settings *store.NetworkSettings
+		network  *models.HostedNetwork
+	}
+	type args struct {
+		feature string
+		method  string
+		origin  string
+		request string
+		headers string
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantHandled bool
+		wantCode    int
+		wantOrigin  string
+		wantHeaders map[string]string
+	}{
+		{"no origin",
+			fields{nil, nil},
+			args{"api", "POST", "", "", ""},
+			false, 0, "", nil},
+		{"default policy",
+			fields{nil, nil},
+			args{"api", "POST", "https://app.example.com", "", ""},
+			false, 0, "*",
+			map[string]string{"Access-Control-Expose-Headers": "X-Stream-Output, X-Chunked-Output, X-Content-Length"}},
+		{"default preflight",
+			fields{nil, nil},
+			args{"api", "OPTIONS", "https://app.example.com", "POST", "Authorization, Content-Type"},
+			true, http.StatusNoContent, "*",
+			map[string]string{
+				"Access-Control-Allow-Methods": "POST",
+				"Access-Control-Allow-Headers": "Authorization, Content-Type",
+				"Access-Control-Max-Age":       "600",
+			}},
+		{"default preflight with disallowed method",
+			fields{nil, nil},
+			args{"gateway", "OPTIONS", "https://app.example.com", "DELETE", ""},
+			true, http.StatusForbidden, "", nil},
+		{"default preflight with disallowed header",
+			fields{nil, nil},
+			args{"gateway", "OPTIONS", "https://app.example.com", "GET", "Authorization"},
+			true, http.StatusForbidden, "", nil},
+		{"override allows subdomain with credentials",
+			fields{overrides, nil},
+			args{"api", "POST", "https://app.example.com", "", ""},
+			false, 0, "https://app.example.com",
+			map[string]string{"Access-Control-Allow-Credentials": "true"}},
+		{"override preflight inherits default methods",
+			fields{overrides, nil},
+			args{"api", "OPTIONS", "https://app.example.com", "POST", "authorization"},
+			true, http.StatusNoContent, "https://app.example.com",
+			map[string]string{"Access-Control-Allow-Credentials": "true"}},
+		{"override rejects origin",
+			fields{overrides, nil},
+			args{"api", "POST", "https://example.org", "", ""},
+			false, 0, "", nil},
+		{"override rejects preflight",
+			fields{overrides, nil},
+			args{"api", "OPTIONS", "https://example.org", "POST", ""},
+			true, http.StatusForbidden, "", nil},
+		{"override only applies to feature",
+			fields{overrides, nil},
+			args{"gateway", "GET", "https://example.org", "", ""},
+			false, 0, "*", nil},
+		{"network allowed origin",
+			fields{nil, &models.HostedNetwork{APIAllowedOrigin: "https://www.google.com"}},
+			args{"api", "POST", "https://www.google.com", "", ""},
+			false, 0, "https://www.google.com", nil},
+		{"network allowed origin rejects others",
+			fields{nil, &models.HostedNetwork{APIAllowedOrigin: "https://www.google.com"}},
+			args{"api", "POST", "https://app.example.com", "", ""},
+			false, 0, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				settings = &smock.FakeSettings{}
+				networks = &mock.FakePrivateNetworks{}
+				e        = newTestEngine(t, EngineOpts{}, networks, Stores{Settings: settings})
+				rec      = httptest.NewRecorder()
+			)
+			settings.GetNetworkSettingsReturns(tt.fields.settings, nil)
+			if tt.fields.network != nil {
+				networks.GetNetworkByNameReturns(tt.fields.network, nil)
+			} else {
+				networks.GetNetworkByNameReturns(&models.HostedNetwork{}, nil)
+			}
+
+			var req = httptest.NewRequest(tt.args.method, "/", nil)
+			if tt.args.origin != "" {
+				req.Header.Set("Origin", tt.args.origin)
+			}
+			if tt.args.request != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.args.request)
+			}
+			if tt.args.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.args.headers)
+			}
+
+			var handled = !e.handleCORS(rec, req, "bobheadxi", tt.args.feature)
+			if handled != tt.wantHandled {
+				t.Fatalf("expected handled %v, got %v", tt.wantHandled, handled)
+			}
+			if handled && rec.Code != tt.wantCode {
+				t.Errorf("expected status %d, found %d", tt.wantCode, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("expected allowed origin '%s', found '%s'", tt.wantOrigin, got)
+			}
+			for k, v := range tt.wantHeaders {
+				if got := rec.Header().Get(k); got != v {
+					t.Errorf("expected %s '%s', found '%s'", k, v, got)
+				}
+			}
+			if !strings.Contains(strings.Join(rec.Header().Values("Vary"), ","), "Origin") {
+				t.Error("expected responses to vary by origin")
+			}
+		})
+	}
+}
+
+func TestEngine_Redirect_preflight(t *testing.T) {
+	// node responses carry their own CORS headers, which should be replaced
+	var upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "https://node.example.com")
+		w.Write([]byte("hello"))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	var (
+		networks = &mock.FakePrivateNetworks{}
+		e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, JWTKey: defaultTestKey}, networks, Stores{})
+		node     = &ipfs.NodeInfo{NetworkID: "bobheadxi", Ports: ipfs.NodePorts{API: target.Port(), Gateway: target.Port()}}
+	)
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{GatewayPublic: true}, nil)
+	var serve = func(feature, method string) *httptest.ResponseRecorder {
+		var ctx = context.WithValue(context.WithValue(context.Background(),
+			keyNetwork, node), keyFeature, feature)
+		var req = httptest.NewRequest(method, "/ipfs/"+testCID, nil).WithContext(ctx)
+		req.Header.Set("Origin", "https://app.example.com")
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		}
+		var rec = httptest.NewRecorder()
+		e.Redirect(rec, req)
+		return rec
+	}
+
+	// preflights are answered without credentials
+	if rec := serve("api", http.MethodOptions); rec.Code != http.StatusNoContent {
+		t.Errorf("expected preflight to be answered, found status %d", rec.Code)
+	}
+
+	// proxied responses follow the network's policy
+	var rec = serve("gateway", http.MethodGet)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected gateway response, found status %d", rec.Code)
+	}
+	if got := rec.Header().Values("Access-Control-Allow-Origin"); len(got) != 1 || got[0] != "*" {
+		t.Errorf("expected only the network's allowed origin, found %v", got)
+	}
+}
+
+func TestEngine_router_statusCORS(t *testing.T) {
+	var (
+		settings = &smock.FakeSettings{}
+		e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, JWTKey: defaultTestKey}, nil, Stores{Settings: settings},
+			&ipfs.NodeInfo{NetworkID: "bobheadxi"}, &ipfs.NodeInfo{NetworkID: "restricted"})
+		router = e.router(nil)
+	)
+	settings.GetNetworkSettingsStub = func(network string) (*store.NetworkSettings, error) {
+		if network == "restricted" {
+			return &store.NetworkSettings{CORS: store.CORSPolicies{
+				"status": {AllowedOrigins: []string{"https://dashboard.example.com"}},
+			}}, nil
+		}
+		return nil, nil
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantCode   int
+		wantOrigin string
+	}{
+		{"delegator status", "GET", "/status", http.StatusOK, "*"},
+		{"network status preflight", "OPTIONS", "/network/bobheadxi/status", http.StatusNoContent, "*"},
+		{"unauthorized diagnostics are readable", "GET", "/network/bobheadxi/diagnostics", http.StatusUnauthorized, "*"},
+		{"swarm key preflight", "OPTIONS", "/network/bobheadxi/swarm.key", http.StatusNoContent, "*"},
+		{"network policy", "GET", "/network/restricted/diagnostics", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req = httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", "GET")
+				req.Header.Set("Access-Control-Request-Headers", "Authorization")
+			}
+			var rec = httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status %d, found %d", tt.wantCode, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("expected allowed origin '%s', found '%s'", tt.wantOrigin, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package delegator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bobheadxi/res"
+	cid "github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// denylistReloadInterval is how often the denylist is reloaded from the
+// database, in case changes were made by another Nexus instance
+const denylistReloadInterval = 5 * time.Minute
+
+// denylist is an in-memory copy of the denylist entries of all networks.
+// Entries with no network apply to all networks.
+type denylist struct {
+	l     *zap.SugaredLogger
+	store store.Denylist
+
+	mux   sync.RWMutex
+	rules map[string]map[string]struct{}
+}
+
+func newDenylist(l *zap.SugaredLogger, s store.Denylist) *denylist {
+	return &denylist{
+		l:     l,
+		store: s,
+		rules: make(map[string]map[string]struct{}),
+	}
+}
+
+// load replaces all rules with the entries in the database
+func (d *denylist) load() error {
+	entries, err := d.store.AllDenylistEntries()
+	if err != nil {
+		return err
+	}
+	var rules = make(map[string]map[string]struct{})
+	for _, e := range entries {
+		if rules[e.Network] == nil {
+			rules[e.Network] = make(map[string]struct{})
+		}
+		rules[e.Network][e.Rule] = struct{}{}
+	}
+	d.mux.Lock()
+	d.rules = rules
+	d.mux.Unlock()
+	return nil
+}
+
+// reload replaces the rules of given network with its entries in the database
+func (d *denylist) reload(network string) error {
+	entries, err := d.store.ListDenylistEntries(network)
+	if err != nil {
+		return err
+	}
+	var rules = make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		rules[e.Rule] = struct{}{}
+	}
+	d.mux.Lock()
+	if len(rules) == 0 {
+		delete(d.rules, network)
+	} else {
+		d.rules[network] = rules
+	}
+	d.mux.Unlock()
+	return nil
+}
+
+// watch periodically reloads all rules until the context is cancelled
+func (d *denylist) watch(ctx context.Context, interval time.Duration) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.load(); err != nil {
+				d.l.Errorw("failed to reload denylist - continuing with previous rules",
+					"error", err)
+			}
+		}
+	}
+}
+
+// blocked checks if the content path is blocked on given network, and returns
+// the matching rule if it is
+func (d *denylist) blocked(network, contentPath string) (string, bool) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	var (
+		networkRules = d.rules[network]
+		globalRules  = d.rules[""]
+	)
+	if len(networkRules) == 0 && len(globalRules) == 0 {
+		return "", false
+	}
+	for _, candidate := range denylistCandidates(contentPath) {
+		if _, found := networkRules[candidate]; found {
+			return candidate, true
+		}
+		if _, found := globalRules[candidate]; found {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// denylistCandidates generates the rules that would block given content path -
+// the path itself, each of its parents up to the root CID or IPNS name, and
+// their double hashes
+func denylistCandidates(contentPath string) []string {
+	contentPath = path.Clean("/" + contentPath)
+
+	var (
+		namespace string
+		root      string
+		rest      string
+	)
+	switch {
+	case strings.HasPrefix(contentPath, "/ipfs/"):
+		namespace = "/ipfs/"
+		c, p := splitPath(strings.TrimPrefix(contentPath, namespace))
+		id, err := cid.Decode(c)
+		if err != nil {
+			return nil
+		}
+		root, rest = cid.NewCidV1(id.Type(), id.Hash()).String(), p
+	case strings.HasPrefix(contentPath, "/ipns/"):
+		namespace = "/ipns/"
+		root, rest = splitPath(strings.TrimPrefix(contentPath, namespace))
+	default:
+		return nil
+	}
+	if root == "" {
+		return nil
+	}
+
+	var prefixes = []string{""}
+	if rest != "" {
+		var segments = strings.Split(strings.TrimPrefix(rest, "/"), "/")
+		for i := range segments {
+			prefixes = append(prefixes, "/"+strings.Join(segments[:i+1], "/"))
+		}
+	}
+
+	var candidates = make([]string, 0, 3*len(prefixes))
+	for _, p := range prefixes {
+		candidates = append(candidates, namespace+root+p)
+		if namespace == "/ipns/" {
+			candidates = append(candidates, "//"+doubleHash(root+p))
+			continue
+		}
+		// legacy badbits anchors hash the CIDv1 and path, while compact
+		// denylist anchors hash the CID's multihash and path
+		var legacy = sha256.Sum256([]byte(root + "/" + strings.TrimPrefix(p, "/")))
+		candidates = append(candidates, "//"+hex.EncodeToString(legacy[:]))
+		if id, err := cid.Decode(root); err == nil {
+			candidates = append(candidates, "//"+doubleHash(id.Hash().B58String()+p))
+		}
+	}
+	return candidates
+}
+
+// doubleHash returns the base58-encoded sha2-256 multihash of s
+func doubleHash(s string) string {
+	sum, _ := mh.Sum([]byte(s), mh.SHA2_256, -1)
+	return sum.B58String()
+}
+
+// splitPath separates the root of a content path from the cleaned remainder
+// of the path
+func splitPath(p string) (string, string) {
+	var parts = strings.SplitN(p, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	var rest = path.Clean("/" + parts[1])
+	if rest == "/" {
+		rest = ""
+	}
+	return parts[0], rest
+}
+
+// apiContentPaths retrieves the content paths requested by IPFS API commands
+// that retrieve content
+func apiContentPaths(r *http.Request) []string {
+	switch apiCommand(r.URL.Path) {
+	case "cat", "get":
+	default:
+		return nil
+	}
+	var paths []string
+	for _, arg := range r.URL.Query()["arg"] {
+		if !strings.HasPrefix(arg, "/") {
+			arg = "/ipfs/" + arg
+		}
+		paths = append(paths, arg)
+	}
+	return paths
+}
+
+// enforceDenylist rejects requests for denylisted content, and returns false
+// if the request was rejected
+func (e *Engine) enforceDenylist(w http.ResponseWriter, r *http.Request, network, feature string, paths ...string) bool {
+	for _, p := range paths {
+		if rule, blocked := e.denylist.blocked(network, p); blocked {
+			e.metrics.denylistBlock(network, feature)
+			e.l.Infow("blocked request for denylisted content",
+				"network", network,
+				"path", p,
+				"rule", rule)
+			res.R(w, r, res.Err(
+				fmt.Sprintf("content '%s' is unavailable", p), http.StatusGone))
+			return false
+		}
+	}
+	return true
+}
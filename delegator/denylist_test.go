@@ -0,0 +1,145 @@
+package delegator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+)
+
+const (
+	testCID   = "QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D"
+	testCIDv1 = "bafybeiccfclkdtucu6y4yc5cpr6y3yuinr67svmii46v5cfcrkp47ihehy"
+)
+
+func TestDenylist_blocked(t *testing.T) {
+	tests := []struct {
+		name        string
+		network     string
+		rule        string
+		path        string
+		wantBlocked bool
+	}{
+		{"CID", "test", "/ipfs/" + testCIDv1, "/ipfs/" + testCID + "/readme", true},
+		{"CID on other network", "other", "/ipfs/" + testCIDv1, "/ipfs/" + testCID, false},
+		{"CID on all networks", "", "/ipfs/" + testCIDv1, "/ipfs/" + testCIDv1, true},
+		{"CID path", "test", "/ipfs/" + testCIDv1 + "/secret", "/ipfs/" + testCID + "//secret/a", true},
+		{"CID path sibling", "test", "/ipfs/" + testCIDv1 + "/secret", "/ipfs/" + testCID + "/readme", false},
+		{"IPNS name", "test", "/ipns/example.com", "/ipns/example.com/a", true},
+		{"legacy double hash", "test",
+			"//4fcf8f4836b2f39d00c53164d930ca89a96602d64e59de93f1da550f68ff4a70", "/ipfs/" + testCID, true},
+		{"legacy double hash path", "test",
+			"//19081091950bbe8ab6f27b65f0d63fd46495ff30cd299be0368c919c6b1755d5", "/ipfs/" + testCID + "/secret", true},
+		{"multihash double hash", "test",
+			"//QmVUgA7CedKJVWDgupQwu1ZeppTwUebG1WFDYZ14AFpbUk", "/ipfs/" + testCIDv1 + "/secret/a", true},
+		{"IPNS double hash", "test",
+			"//QmZLnkh3cpUYmBUASVwskient4NnT3edQ5EkUXZSVA2Zrv", "/ipns/example.com", true},
+		{"invalid CID", "test", "/ipfs/" + testCIDv1, "/ipfs/asdf", false},
+		{"not content", "test", "/ipfs/" + testCIDv1, "/api/v0/cat", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s = &smock.FakeDenylist{}
+			s.AllDenylistEntriesReturns([]*store.DenylistEntry{
+				{Network: tt.network, Rule: tt.rule},
+			}, nil)
+			var d = newDenylist(zaptest.NewLogger(t).Sugar(), s)
+			if err := d.load(); err != nil {
+				t.Fatal(err)
+			}
+			if _, blocked := d.blocked("test", tt.path); blocked != tt.wantBlocked {
+				t.Errorf("denylist.blocked() = %v, want %v", blocked, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestDenylist_reload(t *testing.T) {
+	var (
+		s = &smock.FakeDenylist{}
+		d = newDenylist(zaptest.NewLogger(t).Sugar(), s)
+	)
+	s.AllDenylistEntriesReturns([]*store.DenylistEntry{
+		{Network: "test", Rule: "/ipns/example.com"},
+		{Rule: "/ipns/temporal.cloud"},
+	}, nil)
+	if err := d.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// reloading a network should not affect other rules
+	s.ListDenylistEntriesReturns(nil, nil)
+	if err := d.reload("test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, blocked := d.blocked("test", "/ipns/example.com"); blocked {
+		t.Error("expected removed rule to no longer apply")
+	}
+	if _, blocked := d.blocked("test", "/ipns/temporal.cloud"); !blocked {
+		t.Error("expected rules of all networks to still apply")
+	}
+
+	// failed reloads should retain previous rules
+	s.ListDenylistEntriesReturns(nil, errors.New("oh no"))
+	if err := d.reload(""); err == nil {
+		t.Error("expected error")
+	}
+	if _, blocked := d.blocked("test", "/ipns/temporal.cloud"); !blocked {
+		t.Error("expected previous rules to be retained")
+	}
+}
+
+func TestEngine_enforceDenylist(t *testing.T) {
+	tests := []struct {
+		name        string
+		feature     string
+		path        string
+		wantBlocked bool
+	}{
+		{"gateway", "gateway", "/ipfs/" + testCID, true},
+		{"cat", "api", "/api/v0/cat?arg=" + testCID, true},
+		{"get path", "api", "/api/v0/get?arg=/ipfs/" + testCIDv1 + "/readme", true},
+		{"cat other", "api", "/api/v0/cat?arg=/ipns/example.com", false},
+		{"other command", "api", "/api/v0/pin/add?arg=" + testCID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				denylist = &smock.FakeDenylist{}
+				e        = newTestEngine(t, EngineOpts{}, nil, Stores{Denylist: denylist})
+				req      = httptest.NewRequest("GET", tt.path, nil)
+				rec      = httptest.NewRecorder()
+			)
+			denylist.AllDenylistEntriesReturns([]*store.DenylistEntry{
+				{Rule: "/ipfs/" + testCIDv1},
+			}, nil)
+			if err := e.denylist.load(); err != nil {
+				t.Fatal(err)
+			}
+
+			var paths = apiContentPaths(req)
+			if tt.feature == "gateway" {
+				paths = []string{req.URL.Path}
+			}
+			if ok := e.enforceDenylist(rec, req, "test", tt.feature, paths...); ok == tt.wantBlocked {
+				t.Errorf("Engine.enforceDenylist() = %v, want %v", ok, !tt.wantBlocked)
+			}
+			if tt.wantBlocked && rec.Code != http.StatusGone {
+				t.Errorf("expected status %d, found %d", http.StatusGone, rec.Code)
+			}
+			var want float64
+			if tt.wantBlocked {
+				want = 1
+			}
+			if v := testutil.ToFloat64(e.metrics.denylistBlocked.WithLabelValues("test", tt.feature)); v != want {
+				t.Errorf("expected %v blocked requests, found %v", want, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// dnslinkPrefix denotes the value of a DNSLink TXT record
+const dnslinkPrefix = "dnslink="
+
+// errNoDNSLink indicates that a host has no valid DNSLink record
+var errNoDNSLink = errors.New("no DNSLink record found")
+
+// dnslinkResolver resolves the content paths that hosts' DNSLink records point
+// to, caching results for a fixed duration
+type dnslinkResolver struct {
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+	ttl       time.Duration
+	timeFunc  func() time.Time
+
+	mux     sync.RWMutex
+	records map[string]dnslinkRecord
+}
+
+type dnslinkRecord struct {
+	root    string
+	err     error
+	expires time.Time
+}
+
+// newDNSLinkResolver creates a resolver that queries the DNS server at given
+// address, or the system resolver if no address is provided
+func newDNSLinkResolver(addr string, ttl time.Duration) *dnslinkResolver {
+	var resolver = net.DefaultResolver
+	if addr != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		}
+	}
+	return &dnslinkResolver{
+		lookupTXT: resolver.LookupTXT,
+		ttl:       ttl,
+		timeFunc:  time.Now,
+		records:   make(map[string]dnslinkRecord),
+	}
+}
+
+// resolve retrieves the content path that the DNSLink record of given host
+// points to. The record is looked up at "_dnslink.<host>", and then at the
+// host itself. Hosts without a record are remembered, but lookup failures are
+// not.
+func (d *dnslinkResolver) resolve(ctx context.Context, host string) (string, error) {
+	var now = d.timeFunc()
+	d.mux.RLock()
+	record, found := d.records[host]
+	d.mux.RUnlock()
+	if found && now.Before(record.expires) {
+		return record.root, record.err
+	}
+
+	var root string
+	var err = errNoDNSLink
+	for _, name := range []string{"_dnslink." + host, host} {
+		txts, lookupErr := d.lookupTXT(ctx, name)
+		if lookupErr != nil {
+			if dnsErr, ok := lookupErr.(*net.DNSError); ok && dnsErr.IsNotFound {
+				continue
+			}
+			return "", fmt.Errorf("failed to look up DNSLink record: %s", lookupErr.Error())
+		}
+		if root, err = parseDNSLink(txts); err == nil {
+			break
+		}
+	}
+
+	d.mux.Lock()
+	d.records[host] = dnslinkRecord{root: root, err: err, expires: now.Add(d.ttl)}
+	d.mux.Unlock()
+	return root, err
+}
+
+// parseDNSLink retrieves the content path from the first valid DNSLink value
+// among given TXT records
+func parseDNSLink(txts []string) (string, error) {
+	for _, txt := range txts {
+		txt = strings.TrimSpace(txt)
+		if !strings.HasPrefix(txt, dnslinkPrefix) {
+			continue
+		}
+		if root, err := store.ParseRootPath(strings.TrimPrefix(txt, dnslinkPrefix)); err == nil {
+			return root, nil
+		}
+	}
+	return "", errNoDNSLink
+}
@@ -0,0 +1,102 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParseDNSLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		txts    []string
+		want    string
+		wantErr bool
+	}{
+		{"ipfs", []string{"dnslink=/ipfs/" + testCIDv1}, "/ipfs/" + testCIDv1, false},
+		{"ipns", []string{"v=spf1 -all", " dnslink=/ipns/example.com/docs "}, "/ipns/example.com/docs", false},
+		{"invalid value skipped", []string{"dnslink=/ipfs/asdf", "dnslink=/ipns/example.com"}, "/ipns/example.com", false},
+		{"no record", []string{"v=spf1 -all"}, "", true},
+		{"invalid record", []string{"dnslink=example.com"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDNSLink(tt.txts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDNSLink() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseDNSLink() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDNSLinkResolver_resolve(t *testing.T) {
+	var notFound = &net.DNSError{Err: "no such host", IsNotFound: true}
+	tests := []struct {
+		name    string
+		records map[string][]string
+		errs    map[string]error
+		want    string
+		wantErr error
+		cached  bool
+	}{
+		{"dnslink subdomain",
+			map[string][]string{"_dnslink.docs.example.com": {"dnslink=/ipns/example.com"}},
+			map[string]error{}, "/ipns/example.com", nil, true},
+		{"host fallback",
+			map[string][]string{"docs.example.com": {"dnslink=/ipfs/" + testCIDv1}},
+			map[string]error{"_dnslink.docs.example.com": notFound}, "/ipfs/" + testCIDv1, nil, true},
+		{"no record",
+			map[string][]string{"_dnslink.docs.example.com": {"v=spf1 -all"}},
+			map[string]error{"docs.example.com": notFound}, "", errNoDNSLink, true},
+		{"lookup failure",
+			map[string][]string{},
+			map[string]error{"_dnslink.docs.example.com": errors.New("oh no")}, "", errors.New("oh no"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				d       = newDNSLinkResolver("", time.Minute)
+				now     = time.Now()
+				lookups int
+			)
+			d.timeFunc = func() time.Time { return now }
+			d.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+				lookups++
+				if err := tt.errs[name]; err != nil {
+					return nil, err
+				}
+				return tt.records[name], nil
+			}
+
+			for i := 0; i < 2; i++ {
+				got, err := d.resolve(context.Background(), "docs.example.com")
+				if (err != nil) != (tt.wantErr != nil) || (tt.wantErr == errNoDNSLink && err != errNoDNSLink) {
+					t.Errorf("dnslinkResolver.resolve() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Errorf("dnslinkResolver.resolve() = %v, want %v", got, tt.want)
+				}
+			}
+			var firstLookups = lookups
+			if tt.cached && firstLookups > 2 {
+				t.Errorf("expected result to be cached, but made %d lookups", lookups)
+			}
+			if !tt.cached && firstLookups != 2 {
+				t.Errorf("expected failures not to be cached, but made %d lookups", lookups)
+			}
+
+			// results expire
+			now = now.Add(2 * time.Minute)
+			d.resolve(context.Background(), "docs.example.com")
+			if lookups == firstLookups {
+				t.Error("expected expired result to be looked up again")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package delegator
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bobheadxi/res"
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// domainsReloadInterval is how often custom domains are reloaded from the
+// database, in case changes were made by another Nexus instance
+const domainsReloadInterval = 5 * time.Minute
+
+// domains is an in-memory copy of all verified custom domains, indexed by host
+type domains struct {
+	l     *zap.SugaredLogger
+	store store.Domains
+
+	mux   sync.RWMutex
+	hosts map[string]*store.CustomDomain
+}
+
+func newDomains(l *zap.SugaredLogger, s store.Domains) *domains {
+	return &domains{
+		l:     l,
+		store: s,
+		hosts: make(map[string]*store.CustomDomain),
+	}
+}
+
+// load replaces all domains with the verified domains in the database
+func (d *domains) load() error {
+	entries, err := d.store.AllDomains()
+	if err != nil {
+		return err
+	}
+	var hosts = make(map[string]*store.CustomDomain, len(entries))
+	for _, e := range entries {
+		if e.Verified() {
+			hosts[e.Host] = e
+		}
+	}
+	d.mux.Lock()
+	d.hosts = hosts
+	d.mux.Unlock()
+	return nil
+}
+
+// reload replaces the domain with given host with its entry in the database
+func (d *domains) reload(host string) error {
+	entry, err := d.store.GetDomain(host)
+	if err != nil {
+		return err
+	}
+	d.mux.Lock()
+	if entry == nil || !entry.Verified() {
+		delete(d.hosts, host)
+	} else {
+		d.hosts[host] = entry
+	}
+	d.mux.Unlock()
+	return nil
+}
+
+// watch periodically reloads all domains until the context is cancelled
+func (d *domains) watch(ctx context.Context, interval time.Duration) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.load(); err != nil {
+				d.l.Errorw("failed to reload domains - continuing with previous domains",
+					"error", err)
+			}
+		}
+	}
+}
+
+// get retrieves the verified domain with given host, if there is one
+func (d *domains) get(host string) (*store.CustomDomain, bool) {
+	d.mux.RLock()
+	entry, found := d.hosts[host]
+	d.mux.RUnlock()
+	return entry, found
+}
+
+// CustomDomainContext creates a handler that injects relevant network and
+// content context into requests to verified custom domains. Content is served
+// from the domain's root path, or from the path its DNSLink record points to.
+// Requests to other hosts are treated as subdomain gateway requests if the
+// subdomain gateway is enabled.
+func (e *Engine) CustomDomainContext(next http.Handler) http.Handler {
+	var fallback http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res.R(w, r, res.ErrNotFound(http.StatusText(http.StatusNotFound)))
+	})
+	if e.subdomainGateway {
+		fallback = e.ContentSubdomainContext(next)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var host = r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		domain, ok := e.domains.get(host)
+		if !ok {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		n, err := e.reg.Get(domain.Network)
+		if err != nil {
+			res.R(w, r, res.ErrNotFound(err.Error()))
+			return
+		}
+
+		var root = domain.RootPath
+		if root == "" {
+			if root, err = e.dnslink.resolve(r.Context(), host); err != nil {
+				if err == errNoDNSLink {
+					res.R(w, r, res.ErrNotFound("no DNSLink record found for "+host))
+					return
+				}
+				e.l.Errorw("failed to resolve DNSLink record",
+					"host", host,
+					"error", err)
+				res.R(w, r, res.Err("failed to resolve DNSLink record", http.StatusBadGateway))
+				return
+			}
+		}
+
+		var ctx = context.WithValue(r.Context(), keyFeature, "gateway")
+		ctx = context.WithValue(ctx, keyNetwork, &n)
+		ctx = context.WithValue(ctx, keyContentRoot, root)
+		ctx = context.WithValue(ctx, keyDomain, host)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,141 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestDomains_reload(t *testing.T) {
+	var (
+		s          = &smock.FakeDomains{}
+		d          = newDomains(zaptest.NewLogger(t).Sugar(), s)
+		verifiedAt = time.Now()
+	)
+	s.AllDomainsReturns([]*store.CustomDomain{
+		{Host: "docs.example.com", Network: "test", VerifiedAt: &verifiedAt},
+		{Host: "unverified.example.com", Network: "test"},
+	}, nil)
+	if err := d.load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := d.get("docs.example.com"); !found {
+		t.Error("expected verified domain to be loaded")
+	}
+	if _, found := d.get("unverified.example.com"); found {
+		t.Error("expected unverified domain not to be loaded")
+	}
+
+	// newly verified domains should be added
+	s.GetDomainReturns(&store.CustomDomain{Host: "www.example.com", Network: "test", VerifiedAt: &verifiedAt}, nil)
+	if err := d.reload("www.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := d.get("www.example.com"); !found {
+		t.Error("expected verified domain to be added")
+	}
+
+	// removed domains should be dropped
+	s.GetDomainReturns(nil, nil)
+	if err := d.reload("docs.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := d.get("docs.example.com"); found {
+		t.Error("expected removed domain to be dropped")
+	}
+
+	// failed reloads should retain previous domains
+	s.GetDomainReturns(nil, errors.New("oh no"))
+	if err := d.reload("www.example.com"); err == nil {
+		t.Error("expected error")
+	}
+	if _, found := d.get("www.example.com"); !found {
+		t.Error("expected previous domains to be retained")
+	}
+}
+
+func TestEngine_CustomDomainContext(t *testing.T) {
+	var verifiedAt = time.Now()
+	tests := []struct {
+		name      string
+		host      string
+		path      string
+		subdomain bool
+		dnslink   []string
+		wantCode  int
+		wantPath  string
+	}{
+		{"root path", "docs.example.com", "/readme",
+			false, nil, http.StatusOK, "/ipfs/" + testCIDv1 + "/docs/readme"},
+		{"root path with port", "DOCS.example.com:8080", "/",
+			false, nil, http.StatusOK, "/ipfs/" + testCIDv1 + "/docs/"},
+		{"path-style request not redirected", "docs.example.com", "/ipfs/" + testCID,
+			true, nil, http.StatusOK, "/ipfs/" + testCIDv1 + "/docs/ipfs/" + testCID},
+		{"dnslink", "www.example.com", "/index.html",
+			false, []string{"dnslink=/ipns/example.com"}, http.StatusOK, "/ipns/example.com/index.html"},
+		{"no dnslink", "www.example.com", "/",
+			false, []string{"v=spf1 -all"}, http.StatusNotFound, ""},
+		{"unknown network", "other.example.com", "/",
+			false, nil, http.StatusNotFound, ""},
+		{"unknown host", "blah.example.com", "/",
+			false, nil, http.StatusNotFound, ""},
+		{"subdomain gateway fallback", testCIDv1 + ".ipfs.test.gateway.domain.com", "/readme",
+			true, nil, http.StatusOK, "/ipfs/" + testCIDv1 + "/readme"},
+		{"subdomain gateway disabled", testCIDv1 + ".ipfs.test.gateway.domain.com", "/readme",
+			false, nil, http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gateway = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(r.URL.Path))
+				}))
+				port     = gateway.URL[strings.LastIndex(gateway.URL, ":")+1:]
+				networks = &mock.FakePrivateNetworks{}
+				domains  = &smock.FakeDomains{}
+				e        = newTestEngine(t, EngineOpts{Domain: "domain.com", SubdomainGateway: tt.subdomain}, networks, Stores{Domains: domains}, &ipfs.NodeInfo{
+					NetworkID: "test",
+					Ports:     ipfs.NodePorts{Gateway: port},
+				})
+				req = httptest.NewRequest("GET", tt.path, nil)
+				rec = httptest.NewRecorder()
+			)
+			defer gateway.Close()
+			e.direct = true
+			networks.GetNetworkByNameReturns(&models.HostedNetwork{GatewayPublic: true}, nil)
+			domains.AllDomainsReturns([]*store.CustomDomain{
+				{Host: "docs.example.com", Network: "test", RootPath: "/ipfs/" + testCIDv1 + "/docs", VerifiedAt: &verifiedAt},
+				{Host: "www.example.com", Network: "test", VerifiedAt: &verifiedAt},
+				{Host: "other.example.com", Network: "other", VerifiedAt: &verifiedAt},
+			}, nil)
+			if err := e.domains.load(); err != nil {
+				t.Fatal(err)
+			}
+			e.dnslink.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+				return tt.dnslink, nil
+			}
+			req.Host = tt.host
+
+			e.CustomDomainContext(http.HandlerFunc(e.Redirect)).ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d (%s)", tt.wantCode, rec.Code, rec.Body)
+			}
+			if tt.wantPath != "" && rec.Body.String() != tt.wantPath {
+				t.Errorf("expected node to be requested at '%s', got '%s'", tt.wantPath, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package delegator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bobheadxi/res"
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+const (
+	// egressChunkSize is the most bytes of a response written at once, so
+	// that throttled responses are sent steadily rather than in bursts
+	egressChunkSize = 16 << 10
+
+	// egressSampleInterval is how often the gateway throughput of each
+	// network is reported
+	egressSampleInterval = 10 * time.Second
+
+	// egressReloadInterval is how often the monthly gateway transfer of
+	// networks with a transfer cap is reloaded from recorded usage
+	egressReloadInterval = time.Minute
+)
+
+// egressBucket is a token bucket that tracks the bytes a network's gateway
+// may send. Bytes can be reserved before they are available, in which case
+// the sender waits until the bucket has refilled.
+type egressBucket struct {
+	mux    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes n bytes from the bucket, and returns how long to wait before
+// sending them. If the limits have changed since the last reservation, the
+// bucket is adjusted to the new limits.
+func (b *egressBucket) reserve(now time.Time, n int64, limits config.Egress) time.Duration {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	var (
+		rate     = float64(limits.BytesPerSecond)
+		capacity = float64(egressBurst(limits))
+	)
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * rate
+		b.last = now
+	}
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return seconds(-b.tokens / rate)
+}
+
+// transfer is the number of bytes a network's gateway has served in a month
+type transfer struct {
+	month time.Time
+	// recorded is the gateway usage recorded in the database, and pending is
+	// the bytes sent that have not been recorded yet
+	recorded int64
+	pending  int64
+	// loaded is set once recorded usage has been loaded, and capped is set if
+	// the network's transfer is checked against a cap
+	loaded bool
+	capped bool
+}
+
+// egress throttles the response bodies of network gateways, and tracks how
+// much each gateway has served for monthly transfer caps and throughput
+// metrics
+type egress struct {
+	l       *zap.SugaredLogger
+	usage   store.Usage
+	metrics *metrics
+	now     func() time.Time
+	// recording is held while recorded usage is loaded, so that usage being
+	// recorded is not counted as both recorded and pending
+	recording sync.Locker
+
+	mux       sync.Mutex
+	buckets   map[string]*egressBucket
+	transfers map[string]transfer
+	// window is the bytes sent by each network since throughput was last
+	// sampled
+	window map[string]int64
+}
+
+func newEgress(l *zap.SugaredLogger, usage store.Usage, m *metrics) *egress {
+	return &egress{
+		l:         l,
+		usage:     usage,
+		metrics:   m,
+		now:       time.Now,
+		recording: new(sync.RWMutex).RLocker(),
+		buckets:   make(map[string]*egressBucket),
+		transfers: make(map[string]transfer),
+		window:    make(map[string]int64),
+	}
+}
+
+// wait blocks until n bytes may be sent by given network's gateway, or until
+// the context is cancelled
+func (e *egress) wait(ctx context.Context, network string, n int64, limits config.Egress) error {
+	var now = e.now()
+	e.mux.Lock()
+	var b, found = e.buckets[network]
+	if !found {
+		b = &egressBucket{tokens: float64(egressBurst(limits)), last: now}
+		e.buckets[network] = b
+	}
+	e.mux.Unlock()
+
+	var d = b.reserve(now, n, limits)
+	if d <= 0 {
+		return nil
+	}
+	e.metrics.gatewayThrottled.WithLabelValues(network).Add(d.Seconds())
+	var timer = time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sent counts bytes served by given network's gateway
+func (e *egress) sent(network string, n int64) {
+	if n == 0 {
+		return
+	}
+	e.metrics.gatewayEgress.WithLabelValues(network).Add(float64(n))
+	var month = startOfMonth(e.now())
+	e.mux.Lock()
+	var t = e.transfers[network]
+	if !t.month.Equal(month) {
+		t = transfer{month: month, capped: t.capped}
+	}
+	t.pending += n
+	e.transfers[network] = t
+	e.window[network] += n
+	e.mux.Unlock()
+}
+
+// transferred retrieves the bytes served by given network's gateway this
+// month, loading its recorded usage if it has not been loaded yet. Bytes sent
+// by other delegators are only counted once they are recorded and reloaded,
+// so the transfer is approximate.
+func (e *egress) transferred(network string) int64 {
+	var month = startOfMonth(e.now())
+	e.mux.Lock()
+	var t = e.transfers[network]
+	e.mux.Unlock()
+	if !t.loaded || !t.month.Equal(month) {
+		if err := e.load(network); err != nil {
+			e.l.Warnw("failed to load gateway transfer - counting bytes sent since startup",
+				"network", network,
+				"error", err)
+		}
+	}
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	t = e.transfers[network]
+	if !t.month.Equal(month) {
+		t = transfer{month: month}
+	}
+	// retry failed loads with the next reload rather than on every request
+	t.loaded, t.capped = true, true
+	e.transfers[network] = t
+	return t.recorded + t.pending
+}
+
+// load retrieves the gateway usage of given network recorded this month.
+// Bytes sent that have not been recorded yet are kept.
+func (e *egress) load(network string) error {
+	e.recording.Lock()
+	defer e.recording.Unlock()
+
+	var month = startOfMonth(e.now())
+	records, err := e.usage.QueryUsage(store.UsageQuery{
+		Network: network,
+		Feature: "gateway",
+		Since:   month,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve gateway usage: %s", err.Error())
+	}
+	var recorded int64
+	for _, r := range records {
+		recorded += r.BytesOut
+	}
+
+	e.mux.Lock()
+	var t = e.transfers[network]
+	if !t.month.Equal(month) {
+		t = transfer{month: month, capped: t.capped}
+	}
+	t.recorded, t.loaded = recorded, true
+	e.transfers[network] = t
+	e.mux.Unlock()
+	return nil
+}
+
+// recorded moves gateway bytes in given records, which have been added to the
+// recorded usage, from the pending bytes of their network's transfer
+func (e *egress) recorded(records []*store.UsageRecord) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	for _, r := range records {
+		var t, found = e.transfers[r.Network]
+		if !found || r.Feature != "gateway" || !t.month.Equal(startOfMonth(r.Hour)) {
+			continue
+		}
+		var n = r.BytesOut
+		if n > t.pending {
+			n = t.pending
+		}
+		t.recorded += n
+		t.pending -= n
+		e.transfers[r.Network] = t
+	}
+}
+
+// reload refreshes the monthly transfer of networks with a transfer cap from
+// recorded usage, which includes bytes served by other delegators
+func (e *egress) reload() {
+	var networks []string
+	e.mux.Lock()
+	for network, t := range e.transfers {
+		if t.capped {
+			networks = append(networks, network)
+		}
+	}
+	e.mux.Unlock()
+	for _, network := range networks {
+		if err := e.load(network); err != nil {
+			e.l.Warnw("failed to reload gateway transfer - retrying on next reload",
+				"network", network,
+				"error", err)
+		}
+	}
+}
+
+// sample reports the throughput of each network's gateway since the previous
+// sample
+func (e *egress) sample(elapsed time.Duration) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	for network, n := range e.window {
+		e.metrics.gatewayThroughput.WithLabelValues(network).Set(float64(n) / elapsed.Seconds())
+		e.window[network] = 0
+	}
+}
+
+// run reports gateway throughput and reloads monthly transfers at their
+// respective intervals until the given context is cancelled
+func (e *egress) run(ctx context.Context, sampleInterval, reloadInterval time.Duration) {
+	var (
+		sampler  = time.NewTicker(sampleInterval)
+		reloader = time.NewTicker(reloadInterval)
+		last     = e.now()
+	)
+	defer sampler.Stop()
+	defer reloader.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sampler.C:
+			var now = e.now()
+			e.sample(now.Sub(last))
+			last = now
+		case <-reloader.C:
+			e.reload()
+		}
+	}
+}
+
+// egressWriter throttles a gateway response body to its network's egress
+// rate, and counts the bytes sent towards the network's transfer
+type egressWriter struct {
+	http.ResponseWriter
+	ctx     context.Context
+	egress  *egress
+	network string
+	limits  config.Egress
+}
+
+func (w *egressWriter) Write(b []byte) (int, error) {
+	var written int
+	for len(b) > 0 {
+		var chunk = b
+		if len(chunk) > egressChunkSize {
+			chunk = chunk[:egressChunkSize]
+		}
+		if w.limits.BytesPerSecond > 0 {
+			if err := w.egress.wait(w.ctx, w.network, int64(len(chunk)), w.limits); err != nil {
+				return written, err
+			}
+		}
+		n, err := w.ResponseWriter.Write(chunk)
+		written += n
+		w.egress.sent(w.network, int64(n))
+		if err != nil {
+			return written, err
+		}
+		b = b[len(chunk):]
+	}
+	return written, nil
+}
+
+// Flush allows streamed responses to be flushed to the client
+func (w *egressWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// limitEgress retrieves the egress limits of given network's gateway. If the
+// network has served its monthly transfer cap, a response is written and
+// false is returned.
+func (e *Engine) limitEgress(w http.ResponseWriter, r *http.Request, network string) (config.Egress, bool) {
+	var limits = e.egressLimits
+	if s, err := e.networks.GetNetworkSettings(network); err != nil {
+		e.l.Warnw("failed to retrieve network settings - using default egress limits",
+			"network", network,
+			"error", err)
+	} else if s != nil {
+		limits = s.EgressLimits(e.egressLimits)
+	}
+	if limits.MonthlyCapGB <= 0 {
+		return limits, true
+	}
+	if e.egress.transferred(network) < limits.MonthlyCapGB<<30 {
+		return limits, true
+	}
+
+	e.metrics.gatewayCapped.WithLabelValues(network).Inc()
+	var now = e.egress.now()
+	w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(
+		startOfMonth(now).AddDate(0, 1, 0).Sub(now))))
+	res.R(w, r, res.Err(fmt.Sprintf("gateway has served its monthly transfer cap of %dGB", limits.MonthlyCapGB),
+		http.StatusTooManyRequests, "network", network))
+	return limits, false
+}
+
+// egressBurst retrieves the burst allowance of given limits, which is at
+// least a second's worth of bytes
+func egressBurst(limits config.Egress) int64 {
+	if limits.BurstBytes < limits.BytesPerSecond {
+		return limits.BytesPerSecond
+	}
+	return limits.BurstBytes
+}
+
+// startOfMonth retrieves the start of the calendar month of given time in UTC
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,339 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/database/v2/models"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func Test_egressBucket_reserve(t *testing.T) {
+	var (
+		limits = config.Egress{BytesPerSecond: 100, BurstBytes: 200}
+		now    = time.Now()
+		b      = &egressBucket{tokens: float64(egressBurst(limits)), last: now}
+	)
+
+	// bursts are sent immediately
+	if d := b.reserve(now, 200, limits); d != 0 {
+		t.Errorf("expected burst to be sent immediately, got wait %v", d)
+	}
+	// bytes beyond the burst wait for the bucket to refill
+	if d := b.reserve(now, 50, limits); d != 500*time.Millisecond {
+		t.Errorf("expected wait of 500ms, got %v", d)
+	}
+	// reserved bytes are paid off before further bytes can be sent
+	if d := b.reserve(now.Add(time.Second), 100, limits); d != 500*time.Millisecond {
+		t.Errorf("expected wait of 500ms, got %v", d)
+	}
+	// idle buckets refill up to the burst
+	if d := b.reserve(now.Add(time.Minute), 200, limits); d != 0 {
+		t.Errorf("expected refilled burst to be sent immediately, got wait %v", d)
+	}
+}
+
+func Test_egressBurst(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits config.Egress
+		want   int64
+	}{
+		{"unset", config.Egress{BytesPerSecond: 100}, 100},
+		{"below rate", config.Egress{BytesPerSecond: 100, BurstBytes: 10}, 100},
+		{"above rate", config.Egress{BytesPerSecond: 100, BurstBytes: 1000}, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := egressBurst(tt.limits); got != tt.want {
+				t.Errorf("egressBurst() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_egress_transferred(t *testing.T) {
+	var (
+		usage = &smock.FakeUsage{}
+		e     = newEgress(zaptest.NewLogger(t).Sugar(), usage, newMetrics())
+		now   = time.Date(2019, 4, 30, 23, 0, 0, 0, time.UTC)
+	)
+	e.now = func() time.Time { return now }
+	usage.QueryUsageReturns([]*store.UsageRecord{
+		{Network: "bobheadxi", Feature: "gateway", User: "", BytesOut: 1000},
+		{Network: "bobheadxi", Feature: "gateway", User: "bob", BytesOut: 500},
+	}, nil)
+
+	// recorded usage is loaded once, and bytes sent since are added
+	if n := e.transferred("bobheadxi"); n != 1500 {
+		t.Errorf("expected 1500 bytes transferred, got %d", n)
+	}
+	if q := usage.QueryUsageArgsForCall(0); q.Network != "bobheadxi" || q.Feature != "gateway" ||
+		!q.Since.Equal(time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected query %+v", q)
+	}
+	e.sent("bobheadxi", 100)
+	if n := e.transferred("bobheadxi"); n != 1600 || usage.QueryUsageCallCount() != 1 {
+		t.Errorf("expected 1600 bytes transferred without reloading, got %d (%d queries)",
+			n, usage.QueryUsageCallCount())
+	}
+
+	// reloads replace recorded usage, and keep bytes that are not recorded yet
+	usage.QueryUsageReturns([]*store.UsageRecord{{BytesOut: 2000}}, nil)
+	e.reload()
+	if n := e.transferred("bobheadxi"); n != 2100 {
+		t.Errorf("expected 2100 bytes transferred after reload, got %d", n)
+	}
+
+	// recorded bytes are no longer pending, and are not counted twice once
+	// reloaded
+	e.recorded([]*store.UsageRecord{
+		{Hour: now, Network: "bobheadxi", Feature: "gateway", BytesOut: 100},
+		{Hour: now, Network: "bobheadxi", Feature: "api", BytesOut: 100},
+	})
+	if p := e.transfers["bobheadxi"].pending; p != 0 {
+		t.Errorf("expected no pending bytes, got %d", p)
+	}
+	usage.QueryUsageReturns([]*store.UsageRecord{{BytesOut: 2100}}, nil)
+	e.reload()
+	if n := e.transferred("bobheadxi"); n != 2100 {
+		t.Errorf("expected 2100 bytes transferred after recording, got %d", n)
+	}
+
+	// failed loads count bytes sent since startup, and are not retried until
+	// the next reload
+	now = now.Add(2 * time.Hour)
+	usage.QueryUsageReturns(nil, errors.New("oh no"))
+	e.sent("bobheadxi", 10)
+	if n := e.transferred("bobheadxi"); n != 10 {
+		t.Errorf("expected 10 bytes transferred in new month, got %d", n)
+	}
+	var queries = usage.QueryUsageCallCount()
+	if e.transferred("bobheadxi"); usage.QueryUsageCallCount() != queries {
+		t.Error("expected failed load not to be retried on every request")
+	}
+}
+
+func TestEngine_egressLoadWhileRecording(t *testing.T) {
+	var (
+		usage = &smock.FakeUsage{}
+		e     = newTestEngine(t, EngineOpts{}, nil, Stores{Usage: usage})
+
+		mux     sync.Mutex
+		stored  int64
+		adding  = make(chan struct{})
+		queried = make(chan struct{})
+	)
+	// usage is stored and reported as recorded in separate steps - give each
+	// of the load and flush below a chance to run between the other's steps
+	usage.AddUsageStub = func(records []*store.UsageRecord) error {
+		mux.Lock()
+		for _, r := range records {
+			stored += r.BytesOut
+		}
+		mux.Unlock()
+		close(adding)
+		select {
+		case <-queried:
+		case <-time.After(100 * time.Millisecond):
+		}
+		return nil
+	}
+	usage.QueryUsageStub = func(store.UsageQuery) ([]*store.UsageRecord, error) {
+		select {
+		case <-adding:
+		case <-time.After(100 * time.Millisecond):
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		return []*store.UsageRecord{{BytesOut: stored}}, nil
+	}
+
+	// bytes being recorded while usage is loaded are only counted once
+	e.egress.sent("bobheadxi", 100)
+	e.meter.add("bobheadxi", "gateway", "", 0, 100)
+	var flushed = make(chan error)
+	go func() { flushed <- e.meter.flush() }()
+	if err := e.egress.load("bobheadxi"); err != nil {
+		t.Fatal(err)
+	}
+	close(queried)
+	if err := <-flushed; err != nil {
+		t.Fatal(err)
+	}
+	if n := e.egress.transferred("bobheadxi"); n != 100 {
+		t.Errorf("expected 100 bytes transferred, got %d", n)
+	}
+}
+
+func Test_egress_sample(t *testing.T) {
+	var e = newEgress(zaptest.NewLogger(t).Sugar(), &smock.FakeUsage{}, newMetrics())
+	e.sent("bobheadxi", 1000)
+	e.sample(10 * time.Second)
+	if v := testutil.ToFloat64(e.metrics.gatewayThroughput.WithLabelValues("bobheadxi")); v != 100 {
+		t.Errorf("expected throughput of 100 bytes per second, got %v", v)
+	}
+	e.sample(10 * time.Second)
+	if v := testutil.ToFloat64(e.metrics.gatewayThroughput.WithLabelValues("bobheadxi")); v != 0 {
+		t.Errorf("expected idle throughput to be reported, got %v", v)
+	}
+	if v := testutil.ToFloat64(e.metrics.gatewayEgress.WithLabelValues("bobheadxi")); v != 1000 {
+		t.Errorf("expected 1000 bytes sent, got %v", v)
+	}
+}
+
+func Test_egressWriter(t *testing.T) {
+	var (
+		e      = newEgress(zaptest.NewLogger(t).Sugar(), &smock.FakeUsage{}, newMetrics())
+		limits = config.Egress{BytesPerSecond: egressChunkSize * 10}
+		body   = strings.Repeat("a", egressChunkSize*11)
+	)
+
+	// bytes beyond the burst are throttled
+	var rec = httptest.NewRecorder()
+	var w = &egressWriter{ResponseWriter: rec, ctx: context.Background(),
+		egress: e, network: "bobheadxi", limits: limits}
+	var start = time.Now()
+	if n, err := w.Write([]byte(body)); err != nil || n != len(body) {
+		t.Fatalf("unexpected write of %d bytes (%v)", n, err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("expected write to be throttled, took %v", elapsed)
+	}
+	if rec.Body.String() != body {
+		t.Error("expected full body to be written")
+	}
+	if n := e.transfers["bobheadxi"].pending; n != int64(len(body)) {
+		t.Errorf("expected %d bytes to be counted, got %d", len(body), n)
+	}
+
+	// cancelled requests stop waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = &egressWriter{ResponseWriter: httptest.NewRecorder(), ctx: ctx,
+		egress: e, network: "bobheadxi", limits: limits}
+	if _, err := w.Write([]byte(body)); err != context.Canceled {
+		t.Errorf("expected cancellation error, got %v", err)
+	}
+
+	// unthrottled writes are only counted
+	w = &egressWriter{ResponseWriter: httptest.NewRecorder(), ctx: ctx,
+		egress: e, network: "other", limits: config.Egress{}}
+	if n, err := w.Write([]byte(body)); err != nil || n != len(body) {
+		t.Errorf("unexpected write of %d bytes (%v)", n, err)
+	}
+}
+
+func TestEngine_limitEgress(t *testing.T) {
+	var defaults = config.Egress{MonthlyCapGB: 1}
+	type fields struct {
+		settings    *store.NetworkSettings
+		settingsErr error
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		recorded int64
+		wantOK   bool
+	}{
+		{"within cap", fields{nil, nil}, 1<<30 - 1, true},
+		{"exceeds cap", fields{nil, nil}, 1 << 30, false},
+		{"settings error uses defaults", fields{nil, errors.New("oh no")}, 1 << 30, false},
+		{"override raises cap",
+			fields{&store.NetworkSettings{Egress: &store.EgressLimits{MonthlyCapGB: 2}}, nil},
+			1 << 30, true},
+		{"override disables cap",
+			fields{&store.NetworkSettings{Egress: &store.EgressLimits{BytesPerSecond: 1 << 20}}, nil},
+			1 << 40, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				settings = &smock.FakeSettings{}
+				usage    = &smock.FakeUsage{}
+				e        = newTestEngine(t, EngineOpts{Egress: defaults}, nil, Stores{Settings: settings, Usage: usage})
+			)
+			settings.GetNetworkSettingsReturns(tt.fields.settings, tt.fields.settingsErr)
+			usage.QueryUsageReturns([]*store.UsageRecord{{BytesOut: tt.recorded}}, nil)
+
+			var rec = httptest.NewRecorder()
+			_, ok := e.limitEgress(rec, httptest.NewRequest("GET", "/ipfs/"+testCID, nil), "bobheadxi")
+			if ok != tt.wantOK {
+				t.Fatalf("Engine.limitEgress() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				return
+			}
+			if rec.Code != http.StatusTooManyRequests {
+				t.Errorf("expected status %d, found %d", http.StatusTooManyRequests, rec.Code)
+			}
+			if rec.Header().Get("Retry-After") == "" {
+				t.Error("expected Retry-After header")
+			}
+			if v := testutil.ToFloat64(e.metrics.gatewayCapped.WithLabelValues("bobheadxi")); v != 1 {
+				t.Errorf("expected rejection to be recorded, found %v", v)
+			}
+		})
+	}
+}
+
+func TestEngine_Redirect_egressCapReload(t *testing.T) {
+	var upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	var (
+		networks = &mock.FakePrivateNetworks{}
+		usage    = &smock.FakeUsage{}
+		e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, JWTKey: defaultTestKey,
+			Egress: config.Egress{MonthlyCapGB: 1}}, networks, Stores{Usage: usage})
+		node     = &ipfs.NodeInfo{NetworkID: "bobheadxi", Ports: ipfs.NodePorts{Gateway: target.Port()}}
+		recorded = int64(1<<30 - 10)
+	)
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{GatewayPublic: true}, nil)
+	usage.QueryUsageReturns([]*store.UsageRecord{{BytesOut: recorded}}, nil)
+	var serve = func() int {
+		var ctx = context.WithValue(context.WithValue(context.Background(),
+			keyNetwork, node), keyFeature, "gateway")
+		var rec = httptest.NewRecorder()
+		e.Redirect(rec, httptest.NewRequest("GET", "/ipfs/"+testCID, nil).WithContext(ctx))
+		return rec.Code
+	}
+
+	// the cap is reached by a response within it
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("expected gateway response, found status %d", code)
+	}
+
+	// reloads before the response is recorded still count it
+	e.egress.reload()
+	if code := serve(); code != http.StatusTooManyRequests {
+		t.Errorf("expected cap to be enforced after reload, found status %d", code)
+	}
+
+	// once recorded, the response is counted once
+	if err := e.meter.flush(); err != nil {
+		t.Fatal(err)
+	}
+	usage.QueryUsageReturns([]*store.UsageRecord{{BytesOut: recorded + int64(len("hello world"))}}, nil)
+	e.egress.reload()
+	if n := e.egress.transferred("bobheadxi"); n != recorded+int64(len("hello world")) {
+		t.Errorf("expected %d bytes transferred, got %d", recorded+int64(len("hello world")), n)
+	}
+}
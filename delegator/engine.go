@@ -2,45 +2,99 @@ package delegator
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/bobheadxi/res"
-	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
-	"github.com/go-chi/cors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	// fork of github.com/go-chi/hostrouter with subdomain wildcard support
 	"github.com/RTradeLtd/hostrouter"
 
+	"github.com/RTradeLtd/database/v2/models"
+
 	"github.com/RTradeLtd/Nexus/config"
 	"github.com/RTradeLtd/Nexus/ipfs"
 	"github.com/RTradeLtd/Nexus/log"
 	"github.com/RTradeLtd/Nexus/network"
-	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/store"
 	"github.com/RTradeLtd/Nexus/temporal"
+	"github.com/RTradeLtd/Nexus/tracing"
 )
 
+// Nodes provides the nodes that requests to each network are proxied to, such
+// as the orchestrator's node registry or a mirror of it
+type Nodes interface {
+	Get(network string) (ipfs.NodeInfo, error)
+	List() []ipfs.NodeInfo
+}
+
 // Engine manages request delegation
 type Engine struct {
-	l     *zap.SugaredLogger
-	reg   *registry.NodeRegistry
-	cache *cache
-
-	networks temporal.PrivateNetworks
-
-	timeout   time.Duration
-	keyLookup jwt.Keyfunc
-	timeFunc  func() time.Time
-	version   string
-	domain    string
-	direct    bool
+	l       *zap.SugaredLogger
+	audit   *zap.SugaredLogger
+	reg     Nodes
+	cache   *cache
+	metrics *metrics
+
+	networks *networkCache
+	tokens   store.Tokens
+	content  *contentCache
+	denylist *denylist
+	domains  *domains
+	dnslink  *dnslinkResolver
+	breakers *breakers
+
+	gatewayRetries int
+
+	// maintenance and inflight are managed and reported by the admin API
+	maintenance *maintenance
+	inflight    *inflight
+
+	bodyLimits config.BodyLimits
+	timeouts   timeouts
+
+	// meter counts bytes transferred for billing
+	meter      *meter
+	usageFlush time.Duration
+
+	egress       *egress
+	egressLimits config.Egress
+
+	cors config.CORS
+
+	limits   config.RateLimits
+	limiter  *limiter
+	commands config.CommandPolicy
+
+	defaultRole string
+
+	subdomainGateway bool
+
+	// certs serves certificates from files, if HTTPS is configured with
+	// certificate files or dns-01 challenges
+	certs       *certReloader
+	reloadCerts chan struct{}
+
+	timeout time.Duration
+	auth    *verifier
+	version string
+	domain  string
+	direct  bool
+	bind    config.Bind
 }
 
 // EngineOpts denotes options for the delegator engine
@@ -51,11 +105,70 @@ type EngineOpts struct {
 
 	RequestTimeout time.Duration
 	JWTKey         []byte
+
+	// JWT declares verification of tokens signed by external identity
+	// providers
+	JWT config.JWT
+
+	// Bind declares the addresses node ports are published on, and is used to
+	// select the address requests are proxied to
+	Bind config.Bind
+
+	// RateLimits declares default rate limits for network features
+	RateLimits config.RateLimits
+
+	// Commands declares the default IPFS API command policy
+	Commands config.CommandPolicy
+
+	// DefaultRole is the role of network users without an assigned role
+	DefaultRole string
+
+	// NetworkCache declares caching of network access settings
+	NetworkCache config.NetworkCache
+
+	// GatewayCache declares caching of immutable gateway content - caching is
+	// disabled if no path is provided
+	GatewayCache config.GatewayCache
+
+	// SubdomainGateway enables serving gateway content from a subdomain of
+	// each network's gateway host per CID or IPNS name - it requires a domain
+	SubdomainGateway bool
+
+	// DNSLink declares resolution of DNSLink records of custom domains
+	DNSLink config.DNSLink
+
+	// Breaker declares when requests to unreachable nodes are rejected
+	Breaker config.Breaker
+
+	// BodyLimits declares default request body size limits of features
+	BodyLimits config.BodyLimits
+
+	// Timeouts declares how long gateway requests and streaming uploads may
+	// take - other requests are bounded by RequestTimeout
+	Timeouts config.Timeouts
+
+	// Usage declares how often metered usage is recorded
+	Usage config.Usage
+
+	// CORS declares the default CORS policies of proxied features
+	CORS config.CORS
+
+	// Egress declares default limits on the bytes served by network gateways
+	Egress config.Egress
+}
+
+// timeouts bounds how long proxied requests may take
+type timeouts struct {
+	gateway    time.Duration
+	streamIdle time.Duration
 }
 
-// New instantiates a new delegator engine
-func New(l *zap.SugaredLogger, opts EngineOpts,
-	reg *registry.NodeRegistry, networks temporal.PrivateNetworks) *Engine {
+// New instantiates a new delegator engine. All stores must be provided.
+func New(l *zap.SugaredLogger, opts EngineOpts, reg Nodes,
+	networks temporal.PrivateNetworks, stores Stores) (*Engine, error) {
+	if err := stores.validate(); err != nil {
+		return nil, err
+	}
 
 	var timeFunc = time.Now
 	if opts.DevMode {
@@ -66,36 +179,322 @@ func New(l *zap.SugaredLogger, opts EngineOpts,
 		opts.RequestTimeout = 30 * time.Second
 	}
 
+	if opts.RateLimits.MaxBuckets < 1 {
+		opts.RateLimits.MaxBuckets = config.New().Delegator.RateLimits.MaxBuckets
+	}
+	lim, _ := newLimiter(opts.RateLimits.MaxBuckets)
+
+	if opts.DefaultRole == "" {
+		opts.DefaultRole = config.New().Delegator.DefaultRole
+	}
+	if opts.DNSLink.TTLSeconds == 0 {
+		opts.DNSLink.TTLSeconds = config.New().Delegator.DNSLink.TTLSeconds
+	}
+	if opts.Breaker.FailureThreshold == 0 {
+		opts.Breaker.FailureThreshold = config.New().Delegator.Breaker.FailureThreshold
+	}
+	if opts.Breaker.OpenSeconds == 0 {
+		opts.Breaker.OpenSeconds = config.New().Delegator.Breaker.OpenSeconds
+	}
+	if opts.Breaker.DialTimeoutSeconds == 0 {
+		opts.Breaker.DialTimeoutSeconds = config.New().Delegator.Breaker.DialTimeoutSeconds
+	}
+	if opts.BodyLimits == (config.BodyLimits{}) {
+		opts.BodyLimits = config.New().Delegator.BodyLimits
+	}
+	if opts.Timeouts.GatewaySeconds == 0 {
+		opts.Timeouts.GatewaySeconds = config.New().Delegator.Timeouts.GatewaySeconds
+	}
+	if opts.Timeouts.StreamIdleSeconds == 0 {
+		opts.Timeouts.StreamIdleSeconds = config.New().Delegator.Timeouts.StreamIdleSeconds
+	}
+	if opts.Usage.FlushSeconds == 0 {
+		opts.Usage.FlushSeconds = config.New().Usage.FlushSeconds
+	}
+	if reflect.DeepEqual(opts.CORS, config.CORS{}) {
+		opts.CORS = config.New().Delegator.CORS
+	}
+	if opts.NetworkCache == (config.NetworkCache{}) {
+		opts.NetworkCache = config.New().Delegator.NetworkCache
+	}
+	var m = newMetrics()
+
+	var auth = &verifier{
+		secret:   opts.JWTKey,
+		issuer:   opts.JWT.Issuer,
+		audience: opts.JWT.Audience,
+		leeway:   time.Duration(opts.JWT.LeewaySeconds) * time.Second,
+		timeFunc: timeFunc,
+	}
+	if opts.JWT.JWKSPath != "" {
+		auth.keys = newKeySet(l.Named("delegator.jwks"), opts.JWT.JWKSPath)
+	}
+
+	var content *contentCache
+	if opts.GatewayCache.Path != "" {
+		var defaults = config.New().Delegator.GatewayCache
+		if opts.GatewayCache.MaxSizeMB == 0 {
+			opts.GatewayCache.MaxSizeMB = defaults.MaxSizeMB
+		}
+		if opts.GatewayCache.NetworkMaxSizeMB == 0 {
+			opts.GatewayCache.NetworkMaxSizeMB = defaults.NetworkMaxSizeMB
+		}
+		if opts.GatewayCache.MaxObjectSizeMB == 0 {
+			opts.GatewayCache.MaxObjectSizeMB = defaults.MaxObjectSizeMB
+		}
+		content = newContentCache(l.Named("delegator.content"), m, opts.GatewayCache)
+	}
+
+	// gateway bytes are pending towards transfer caps until they are recorded
+	var (
+		meter  = newMeter(l.Named("delegator.usage"), stores.Usage)
+		egress = newEgress(l.Named("delegator.egress"), stores.Usage, m)
+	)
+	meter.recorded = egress.recorded
+	egress.recording = meter.recording.RLocker()
+
 	return &Engine{
-		l:     l.Named("delegator"),
-		reg:   reg,
-		cache: newCache(30*time.Minute, 30*time.Minute),
+		l:       l.Named("delegator"),
+		audit:   l.Named("delegator.audit"),
+		reg:     reg,
+		cache:   newCache(30*time.Minute, 30*time.Minute),
+		metrics: m,
+
+		networks: newNetworkCache(l.Named("delegator.networks"), networks, stores.Settings, m, opts.NetworkCache),
+		tokens:   stores.Tokens,
+		content:  content,
+		denylist: newDenylist(l.Named("delegator.denylist"), stores.Denylist),
+		domains:  newDomains(l.Named("delegator.domains"), stores.Domains),
+		dnslink:  newDNSLinkResolver(opts.DNSLink.Resolver, time.Duration(opts.DNSLink.TTLSeconds)*time.Second),
+		breakers: newBreakers(l.Named("delegator.breakers"), opts.Breaker),
+
+		gatewayRetries: opts.Breaker.GatewayRetries,
+
+		maintenance: newMaintenance(),
+		inflight:    newInflight(),
+
+		bodyLimits: opts.BodyLimits,
+		meter:      meter,
+		usageFlush: time.Duration(opts.Usage.FlushSeconds) * time.Second,
+
+		egress:       egress,
+		egressLimits: opts.Egress,
+
+		timeouts: timeouts{
+			gateway:    time.Duration(opts.Timeouts.GatewaySeconds) * time.Second,
+			streamIdle: time.Duration(opts.Timeouts.StreamIdleSeconds) * time.Second,
+		},
+		cors: opts.CORS,
+
+		limits:   opts.RateLimits,
+		limiter:  lim,
+		commands: opts.Commands,
+
+		defaultRole: opts.DefaultRole,
+
+		subdomainGateway: opts.SubdomainGateway,
+
+		reloadCerts: make(chan struct{}, 1),
+
+		timeout: opts.RequestTimeout,
+		version: opts.Version,
+		auth:    auth,
+		domain:  opts.Domain,
+		bind:    opts.Bind.WithDefaults(),
+	}, nil
+}
 
-		networks: networks,
+// InvalidateNetwork discards cached access settings of given network, and
+// should be called whenever a network's database entry or settings change
+func (e *Engine) InvalidateNetwork(network string) {
+	e.networks.Invalidate(network)
+}
+
+// ReloadDenylist reloads the denylist entries of given network, or the entries
+// that apply to all networks if no network is provided, and should be called
+// whenever denylist entries change
+func (e *Engine) ReloadDenylist(network string) {
+	if err := e.denylist.reload(network); err != nil {
+		e.l.Errorw("failed to reload denylist - changes will be applied on next reload",
+			"network", network,
+			"error", err)
+	}
+}
+
+// ReloadDomain reloads the custom domain with given host, and should be called
+// whenever a custom domain is verified or removed
+func (e *Engine) ReloadDomain(host string) {
+	if err := e.domains.reload(host); err != nil {
+		e.l.Errorw("failed to reload domain - changes will be applied on next reload",
+			"host", host,
+			"error", err)
+	}
+}
+
+// Reload reloads all denylist entries and custom domains, and should be called
+// whenever changes to them may have been missed
+func (e *Engine) Reload() {
+	if err := e.denylist.load(); err != nil {
+		e.l.Errorw("failed to reload denylist - changes will be applied on next reload",
+			"error", err)
+	}
+	if err := e.domains.load(); err != nil {
+		e.l.Errorw("failed to reload domains - changes will be applied on next reload",
+			"error", err)
+	}
+}
 
-		timeout:   opts.RequestTimeout,
-		version:   opts.Version,
-		keyLookup: func(t *jwt.Token) (interface{}, error) { return opts.JWTKey, nil },
-		timeFunc:  timeFunc,
-		domain:    opts.Domain,
+// ReloadCertificate requests that the served certificate is reloaded from its
+// files, such as after a renewal - certificates are otherwise only reloaded
+// when a periodic check finds that the files have changed
+func (e *Engine) ReloadCertificate() {
+	select {
+	case e.reloadCerts <- struct{}{}:
+	default:
 	}
 }
 
 // Run spins up a server that listens for requests and proxies them appropriately
 func (e *Engine) Run(ctx context.Context, opts config.Delegator) error {
+	// load keys for token verification
+	if e.auth.keys != nil {
+		if err := e.auth.keys.load(); err != nil {
+			e.l.Errorw("failed to load key set", "error", err)
+			return err
+		}
+		go e.auth.keys.watch(ctx, jwksReloadInterval)
+	}
+
+	// index cached gateway content
+	if e.content != nil {
+		if err := e.content.load(); err != nil {
+			e.l.Errorw("failed to load gateway cache", "error", err)
+			return err
+		}
+	}
+
+	// load blocked content
+	if err := e.denylist.load(); err != nil {
+		e.l.Errorw("failed to load denylist", "error", err)
+		return err
+	}
+	go e.denylist.watch(ctx, denylistReloadInterval)
+
+	// load custom domains
+	if err := e.domains.load(); err != nil {
+		e.l.Errorw("failed to load domains", "error", err)
+		return err
+	}
+	go e.domains.watch(ctx, domainsReloadInterval)
+
+	// record usage
+	go e.meter.run(ctx, e.usageFlush)
+	go e.egress.run(ctx, egressSampleInterval, egressReloadInterval)
+
+	// only trust forwarded client addresses from known proxies
+	trusted, err := parseTrustedProxies(opts.TrustedProxies)
+	if err != nil {
+		e.l.Errorw("failed to parse trusted proxies", "error", err)
+		return err
+	}
+
+	var r = e.router(trusted)
+
+	// set up server - proxied requests are bounded per route, since uploads
+	// can take longer than any absolute timeout
+	var srv = &http.Server{
+		Handler: r,
+
+		Addr:              opts.Host + ":" + opts.Port,
+		ReadHeaderTimeout: e.timeout,
+		IdleTimeout:       e.timeout,
+	}
+
+	// set up certificates
+	var acmeChallenges http.Handler
+	switch {
+	case opts.ACME.Enabled():
+		e.l.Infow("setting up certificate issuance",
+			"challenge", opts.ACME.Challenge,
+			"directory", opts.ACME.DirectoryURL)
+		tlsConfig, challenges, err := e.acmeTLSConfig(ctx, opts.ACME)
+		if err != nil {
+			e.l.Errorw("failed to set up certificate issuance", "error", err)
+			return err
+		}
+		srv.TLSConfig, acmeChallenges = tlsConfig, challenges
+	case opts.TLS.CertPath != "":
+		e.certs = newCertReloader(e.l.Named("certs"), opts.TLS.CertPath, opts.TLS.KeyPath)
+		if err := e.certs.load(true); err != nil {
+			e.l.Errorw("failed to load certificate", "error", err)
+			return err
+		}
+		srv.TLSConfig = &tls.Config{GetCertificate: e.certs.GetCertificate}
+	}
+	if e.certs != nil {
+		go e.certs.watch(ctx, certReloadInterval, e.reloadCerts)
+	}
+
+	// serve http-01 challenges
+	if acmeChallenges != nil {
+		go func() {
+			if err := e.runACMEChallenges(ctx, net.JoinHostPort(opts.Host, opts.ACME.HTTPPort), acmeChallenges); err != nil {
+				e.l.Errorw("error encountered - challenge service stopped", "error", err)
+			}
+		}()
+	}
+
+	// serve administrative endpoints separately
+	if opts.Admin.Port != "" {
+		go func() {
+			if err := e.runAdmin(ctx, opts.Admin); err != nil {
+				e.l.Errorw("error encountered - admin service stopped", "error", err)
+			}
+		}()
+	}
+
+	// handle shutdown
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				shutdown, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+				defer cancel()
+				if err := srv.Shutdown(shutdown); err != nil {
+					e.l.Warnw("error encountered during shutdown", "error", err.Error())
+				}
+			}
+		}
+	}()
+
+	// go!
+	if srv.TLSConfig != nil {
+		if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			e.l.Errorw("error encountered - service stopped", "error", err)
+			return err
+		}
+	} else {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			e.l.Errorw("error encountered - service stopped", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// router sets up the delegator's middleware and routes. Networks are routed
+// by subdomain if a domain is configured, and by path otherwise.
+func (e *Engine) router(trusted []*net.IPNet) http.Handler {
 	var r = chi.NewRouter()
 
 	// mount middleware
 	r.Use(
-		cors.New(cors.Options{
-			AllowedOrigins:   []string{"*"},
-			AllowedMethods:   []string{"HEAD", "GET", "POST", "PUT", "PATCH", "DELETE"},
-			AllowedHeaders:   []string{"*"},
-			AllowCredentials: true,
-		}).Handler,
 		middleware.RequestID,
-		middleware.RealIP,
-		log.NewMiddleware(e.l.Named("requests")),
+		withRealIP(trusted),
+		tracing.NewMiddleware("delegator", "delegator.request"),
+		withRequestLabels,
+		log.NewMiddleware(e.l.Named("requests"), e.metrics.observe),
 		middleware.Recoverer,
 	)
 
@@ -122,8 +521,17 @@ func (e *Engine) Run(ctx context.Context, opts config.Delegator) error {
 		}))
 		hr.Map("*.status."+e.domain, chi.NewRouter().Route("/", func(r chi.Router) {
 			r.Use(e.NetworkAndFeatureSubdomainContext)
+			r.HandleFunc("/diagnostics", e.NetworkDiagnostics)
+			r.HandleFunc("/swarm.key", e.NetworkSwarmKey)
 			r.HandleFunc("/*", e.NetworkStatus)
 		}))
+		// custom domains are not known ahead of time, and subdomain gateway
+		// content hosts, if enabled, include the network below the wildcard
+		// label - both are matched by the fallback through CustomDomainContext
+		hr.Map("*", chi.NewRouter().Route("/", func(r chi.Router) {
+			r.Use(e.CustomDomainContext)
+			r.HandleFunc("/*", e.Redirect)
+		}))
 		// mount the host router
 		r.Mount("/", hr)
 	} else {
@@ -134,48 +542,88 @@ func (e *Engine) Run(ctx context.Context, opts config.Delegator) error {
 		r.Route(fmt.Sprintf("/network/{%s}", keyNetwork), func(r chi.Router) {
 			r.Use(e.NetworkPathContext)
 			r.HandleFunc("/status", e.NetworkStatus)
+			r.HandleFunc("/diagnostics", e.NetworkDiagnostics)
+			r.HandleFunc("/swarm.key", e.NetworkSwarmKey)
 			r.Route(fmt.Sprintf("/{%s}", keyFeature), func(r chi.Router) {
+				r.Use(e.FeaturePathContext)
 				r.HandleFunc("/*", e.Redirect)
 			})
 		})
 	}
 
-	// set up server
+	return r
+}
+
+// runAdmin spins up a server for administrative endpoints
+func (e *Engine) runAdmin(ctx context.Context, opts config.Admin) error {
 	var srv = &http.Server{
-		Handler: r,
+		Handler: e.adminRouter(opts.Token),
 
-		Addr:         opts.Host + ":" + opts.Port,
+		Addr:         net.JoinHostPort(opts.Host, opts.Port),
 		WriteTimeout: e.timeout,
 		ReadTimeout:  e.timeout,
 	}
-
-	// handle shutdown
 	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				shutdown, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-				defer cancel()
-				if err := srv.Shutdown(shutdown); err != nil {
-					e.l.Warnw("error encountered during shutdown", "error", err.Error())
-				}
-			}
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdown); err != nil {
+			e.l.Warnw("error encountered during admin shutdown", "error", err.Error())
 		}
 	}()
 
-	// go!
-	if opts.TLS.CertPath != "" {
-		if err := srv.ListenAndServeTLS(opts.TLS.CertPath, opts.TLS.KeyPath); err != nil && err != http.ErrServerClosed {
-			e.l.Errorw("error encountered - service stopped", "error", err)
-			return err
-		}
+	e.l.Infow("spinning up admin server", "address", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// adminRouter sets up administrative endpoints - endpoints other than metrics
+// require given token, and are not served if no token is provided
+func (e *Engine) adminRouter(token string) http.Handler {
+	var r = chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Handle("/metrics", e.metrics.Handler())
+	if token != "" {
+		r.Group(func(r chi.Router) {
+			r.Use(requireAdminToken(token))
+			r.Get("/networks", e.AdminNetworks)
+			r.Put(fmt.Sprintf("/networks/{%s}/maintenance", keyNetwork), e.AdminSetMaintenance)
+			r.Delete(fmt.Sprintf("/networks/{%s}/maintenance", keyNetwork), e.AdminSetMaintenance)
+			r.Get("/proxies", e.AdminProxies)
+			r.Delete(fmt.Sprintf("/proxies/{%s}", keyNetwork), e.AdminPurgeProxies)
+			r.Delete(fmt.Sprintf("/gateway/cache/{%s}", keyNetwork), e.PurgeGatewayCache)
+		})
 	} else {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			e.l.Errorw("error encountered - service stopped", "error", err)
-			return err
-		}
+		e.l.Warnw("no admin token configured - only metrics are served by the admin server")
 	}
+	return r
+}
+
+// runACMEChallenges spins up a server that responds to http-01 challenges,
+// and redirects all other requests to HTTPS
+func (e *Engine) runACMEChallenges(ctx context.Context, addr string, handler http.Handler) error {
+	var srv = &http.Server{
+		Handler: handler,
+
+		Addr:         addr,
+		WriteTimeout: e.timeout,
+		ReadTimeout:  e.timeout,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdown); err != nil {
+			e.l.Warnw("error encountered during challenge server shutdown", "error", err.Error())
+		}
+	}()
 
+	e.l.Infow("spinning up challenge server", "address", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
 	return nil
 }
 
@@ -259,63 +707,176 @@ func (e *Engine) Redirect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// set target port based on feature
-	var port string
+	setRequestLabels(r, n.NetworkID, feature)
+	trace.SpanFromContext(r.Context()).SetAttributes(
+		attribute.String("nexus.network", n.NetworkID),
+		attribute.String("nexus.feature", feature))
+
+	// reject requests from addresses that may not access this feature
+	if !e.enforceAccessRules(w, r, n.NetworkID, feature) {
+		return
+	}
+
+	// reject requests to networks an operator is working on
+	if _, ok := e.maintenance.get(n.NetworkID); ok {
+		res.R(w, r, res.Err("network is under maintenance", http.StatusServiceUnavailable,
+			"network", n.NetworkID))
+		return
+	}
+
+	// apply the network's CORS policy - swarm connections are not made by
+	// browser scripts subject to it
+	if feature != "swarm" && !e.handleCORS(w, r, n.NetworkID, feature) {
+		return
+	}
+
+	// set target port and host based on feature
+	var port, host, user string
 	switch feature {
 	case "swarm":
 		// Swarm access is open to all by default, since it handles authentication
-		// on its own
-		port = n.Ports.Swarm
-	case "api":
-		// IPFS network API access requires an authorized user
-		user, err := getUserFromJWT(r, e.keyLookup, e.timeFunc)
-		if err != nil {
-			res.R(w, r, res.ErrUnauthorized(err.Error()))
+		// on its own. Peers connect using libp2p's WebSocket transport, since raw
+		// TCP swarm connections cannot be proxied.
+		if !isWebSocketUpgrade(r) {
+			res.R(w, r, res.ErrBadRequest("swarm connections must use the WebSocket transport"))
 			return
 		}
-		entry, err := e.networks.GetNetworkByName(n.NetworkID)
-		if err != nil {
-			http.Error(w, "failed to find network", http.StatusNotFound)
+		if n.Ports.SwarmWS == "" {
+			res.R(w, r, res.Err("network does not accept WebSocket swarm connections - it must be restarted to enable them",
+				http.StatusServiceUnavailable))
 			return
 		}
-		var found = false
-		for _, authorized := range entry.Users {
-			if user == authorized {
-				found = true
+		port, host = n.Ports.SwarmWS, e.bind.SwarmWS[0]
+	case "api":
+		// IPFS network API access requires an authorized user or a scoped API
+		// token issued for the network
+		if credential := getBearerToken(r); store.IsAPIToken(credential) {
+			token, err := getAPIToken(e.tokens, credential, n.NetworkID, time.Now())
+			if err != nil {
+				switch err {
+				case errInvalidAuth, store.ErrTokenExpired, store.ErrTokenRevoked:
+					e.metrics.authFailure(n.NetworkID, authFailureReason(err))
+					res.R(w, r, res.ErrUnauthorized(err.Error()))
+				default:
+					e.l.Errorw("failed to look up API token",
+						"network", n.NetworkID, "error", err)
+					res.R(w, r, res.Err("failed to look up API token", http.StatusServiceUnavailable))
+				}
+				return
+			}
+			if command := apiCommand(r.URL.Path); !scopesAllow(token.Scopes, command) {
+				e.metrics.authFailure(n.NetworkID, reasonInsufficientScope)
+				res.R(w, r, res.ErrForbidden(
+					fmt.Sprintf("token does not permit command '%s'", command)))
+				return
+			}
+			user = "token:" + token.TokenID
+			if _, err = e.lookupNetwork(r.Context(), n.NetworkID); err != nil {
+				e.metrics.authFailure(n.NetworkID, reasonUnknownNetwork)
+				res.R(w, r, res.ErrNotFound("failed to find network"))
+				return
+			}
+		} else {
+			var role string
+			if user, role, _, ok = e.authorizeUser(w, r, n.NetworkID); !ok {
+				return
+			}
+			if command := apiCommand(r.URL.Path); !roleAllowsCommand(role, command) {
+				e.metrics.authFailure(n.NetworkID, reasonInsufficientRole)
+				res.R(w, r, res.ErrForbidden(
+					fmt.Sprintf("role '%s' does not permit command '%s'", role, command)))
+				return
 			}
 		}
-		if !found {
-			res.R(w, r, res.ErrForbidden("user not authorized"))
+		// block commands not permitted on this network
+		if !e.enforceCommandPolicy(w, r, n.NetworkID) {
 			return
 		}
-		// set access rules
-		w.Header().Set("Vary", "Origin")
-		if entry.APIAllowedOrigin == "" {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-		} else {
-			w.Header().Set("Access-Control-Allow-Origin", entry.APIAllowedOrigin)
-		}
-		// catch preflights
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+		// block retrieval of denylisted content
+		if !e.enforceDenylist(w, r, n.NetworkID, feature, apiContentPaths(r)...) {
 			return
 		}
-		port = n.Ports.API
+		port, host = n.Ports.API, e.bind.API[0]
 	case "gateway":
 		// Gateway is only open if configured as such
-		if entry, err := e.networks.GetNetworkByName(n.NetworkID); err != nil {
+		if entry, err := e.lookupNetwork(r.Context(), n.NetworkID); err != nil {
+			e.metrics.authFailure(n.NetworkID, reasonUnknownNetwork)
 			res.R(w, r, res.ErrNotFound("failed to find network"))
 			return
 		} else if !entry.GatewayPublic {
+			e.metrics.authFailure(n.NetworkID, reasonGatewayDisabled)
 			res.R(w, r, res.ErrNotFound("failed to find network gateway"))
 			return
 		}
-		port = n.Ports.Gateway
+		// isolate content in subdomains, if enabled - custom domains are
+		// already isolated
+		if _, custom := r.Context().Value(keyDomain).(string); e.subdomainGateway && e.direct && !custom {
+			if location, ok := e.subdomainLocation(r, n.NetworkID); ok {
+				http.Redirect(w, r, location, http.StatusMovedPermanently)
+				return
+			}
+		}
+		// serve content relative to the root of subdomain gateway hosts and
+		// custom domains
+		if root, ok := r.Context().Value(keyContentRoot).(string); ok {
+			r.URL.Path = root + r.URL.Path
+			r.URL.RawPath = ""
+		}
+		// block denylisted content
+		var path = r.URL.Path
+		if !e.direct {
+			path = stripLeadingSegments(path)
+		}
+		if !e.enforceDenylist(w, r, n.NetworkID, feature, path) {
+			return
+		}
+		port, host = n.Ports.Gateway, e.bind.Gateway[0]
 	default:
 		res.R(w, r, res.ErrBadRequest(fmt.Sprintf("invalid feature '%s'", feature)))
 		return
 	}
 
+	// enforce rate limits for network feature
+	if !e.rateLimit(w, r, n.NetworkID, feature, user) {
+		return
+	}
+
+	// reject gateway requests once the network's monthly transfer cap is
+	// reached
+	var egress config.Egress
+	if feature == "gateway" {
+		if egress, ok = e.limitEgress(w, r, n.NetworkID); !ok {
+			return
+		}
+	}
+
+	// bound request bodies and how long requests may take
+	if !e.limitBody(w, r, n.NetworkID, feature) {
+		return
+	}
+	// count bytes as they are transferred, so that requests spanning hours
+	// are split between them - swarm traffic is metered from node containers
+	if feature != "swarm" {
+		r.Body = &meteredReader{ReadCloser: r.Body, meter: e.meter,
+			uploaded: e.metrics.uploadedBytes.WithLabelValues(n.NetworkID, feature),
+			network:  n.NetworkID, feature: feature, user: user}
+		w = &meteredWriter{ResponseWriter: w, meter: e.meter,
+			network: n.NetworkID, feature: feature, user: user}
+	}
+	var cancel func()
+	if feature == "gateway" && egress.BytesPerSecond > 0 {
+		// throttled responses can take longer than the gateway timeout, so
+		// they are only cancelled once idle for as long
+		w, r, cancel = e.withIdleTimeout(w, r, e.timeouts.gateway)
+	} else {
+		w, r, cancel = e.withTimeout(w, r, feature)
+	}
+	defer cancel()
+	if feature == "gateway" {
+		w = &egressWriter{ResponseWriter: w, ctx: r.Context(), egress: e.egress,
+			network: n.NetworkID, limits: egress}
+	}
+
 	// set up target
 	var protocol string
 	if r.URL.Scheme != "" {
@@ -325,7 +886,7 @@ func (e *Engine) Redirect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var (
-		address = fmt.Sprintf("%s:%s", network.Private, port)
+		address = net.JoinHostPort(network.Dialable(host), port)
 		target  = fmt.Sprintf("%s%s%s", protocol, address, r.RequestURI)
 	)
 
@@ -337,21 +898,70 @@ func (e *Engine) Redirect(w http.ResponseWriter, r *http.Request) {
 
 	// set up forwarder, retrieving from cache if available, otherwise set up new
 	var proxy *httputil.ReverseProxy
-	if proxy = e.cache.Get(fmt.Sprintf("%s-%s", n.NetworkID, feature)); proxy == nil {
-		proxy = newProxy(feature, url, e.l, e.direct)
-		e.cache.Cache(fmt.Sprintf("%s-%s", n.NetworkID, feature), proxy)
+	if proxy = e.cache.Get(proxyKey(n.NetworkID, feature)); proxy == nil {
+		e.metrics.cacheLookup(false)
+		proxy = newProxy(feature, url, e.l, e.metrics, e.direct,
+			e.breakers.get(n.NetworkID), e.gatewayRetries)
+		e.cache.Cache(proxyKey(n.NetworkID, feature), proxy)
+	} else {
+		e.metrics.cacheLookup(true)
 	}
 
-	// serve proxy request
+	// serve proxy request, caching immutable gateway content if enabled
+	var done = e.inflight.track(n.NetworkID)
+	defer done()
+	if feature == "gateway" && e.content != nil {
+		var path = r.URL.Path
+		if !e.direct {
+			path = stripLeadingSegments(path)
+		}
+		e.content.serve(w, r, n.NetworkID, path, proxy)
+		return
+	}
 	proxy.ServeHTTP(w, r)
 }
 
+// lookupNetwork retrieves the access settings of given network
+func (e *Engine) lookupNetwork(ctx context.Context, network string) (*models.HostedNetwork, error) {
+	_, span := tracing.Start(ctx, "delegator.lookup_network",
+		attribute.String("nexus.network", network))
+	entry, err := e.networks.GetNetworkByName(network)
+	tracing.Finish(span, err)
+	return entry, err
+}
+
 // Status reports on proxy status
 func (e *Engine) Status(w http.ResponseWriter, r *http.Request) {
+	if !serveCORS(w, r, func() config.CORSPolicy { return e.cors.Status }) {
+		return
+	}
 	res.R(w, r, res.MsgOK("Nexus proxy is online!",
 		"version", e.version))
 }
 
+// PurgeGatewayCache discards cached gateway content of a network
+func (e *Engine) PurgeGatewayCache(w http.ResponseWriter, r *http.Request) {
+	if e.content == nil {
+		res.R(w, r, res.ErrNotFound("gateway cache is not enabled"))
+		return
+	}
+	var network = chi.URLParam(r, string(keyNetwork))
+	if network == "" {
+		res.R(w, r, res.ErrBadRequest("no network provided"))
+		return
+	}
+	if err := e.content.Purge(network); err != nil {
+		e.l.Errorw("failed to purge gateway cache",
+			"network", network,
+			"error", err)
+		res.R(w, r, res.ErrInternalServer("failed to purge gateway cache", err))
+		return
+	}
+	e.l.Infow("gateway cache purged", "network", network)
+	res.R(w, r, res.MsgOK("gateway cache purged",
+		"network", network))
+}
+
 // NetworkStatus reports on the status of a network
 func (e *Engine) NetworkStatus(w http.ResponseWriter, r *http.Request) {
 	n, ok := r.Context().Value(keyNetwork).(*ipfs.NodeInfo)
@@ -359,7 +969,70 @@ func (e *Engine) NetworkStatus(w http.ResponseWriter, r *http.Request) {
 		res.R(w, r, res.Err(http.StatusText(422), 422))
 		return
 	}
+	setRequestLabels(r, n.NetworkID, featureStatus)
+	if !e.handleCORS(w, r, n.NetworkID, "status") {
+		return
+	}
+	if _, ok := e.authorizeFeature(w, r, n.NetworkID, featureStatus); !ok {
+		return
+	}
 
 	res.R(w, r, res.MsgOK(fmt.Sprintf("found network %s", n.NetworkID),
 		"status", "registered"))
 }
+
+// NetworkDiagnostics reports details about a network's node
+func (e *Engine) NetworkDiagnostics(w http.ResponseWriter, r *http.Request) {
+	n, ok := r.Context().Value(keyNetwork).(*ipfs.NodeInfo)
+	if !ok {
+		res.R(w, r, res.Err(http.StatusText(422), 422))
+		return
+	}
+	setRequestLabels(r, n.NetworkID, featureDiagnostics)
+	if !e.handleCORS(w, r, n.NetworkID, "status") {
+		return
+	}
+	if _, ok := e.authorizeFeature(w, r, n.NetworkID, featureDiagnostics); !ok {
+		return
+	}
+
+	res.R(w, r, res.MsgOK(fmt.Sprintf("found network %s", n.NetworkID),
+		"node", n))
+}
+
+// NetworkSwarmKey serves the swarm key of a network, which is required for
+// peers to join the network
+func (e *Engine) NetworkSwarmKey(w http.ResponseWriter, r *http.Request) {
+	n, ok := r.Context().Value(keyNetwork).(*ipfs.NodeInfo)
+	if !ok {
+		res.R(w, r, res.Err(http.StatusText(422), 422))
+		return
+	}
+	setRequestLabels(r, n.NetworkID, featureSwarmKey)
+	if !e.handleCORS(w, r, n.NetworkID, "status") {
+		return
+	}
+	entry, ok := e.authorizeFeature(w, r, n.NetworkID, featureSwarmKey)
+	if !ok {
+		return
+	}
+
+	// prefer key from database, otherwise fall back to the key on the node
+	var key = []byte(entry.SwarmKey)
+	if len(key) == 0 && n.DataDir != "" {
+		var err error
+		if key, err = ioutil.ReadFile(filepath.Join(n.DataDir, "swarm.key")); err != nil {
+			e.l.Errorw("failed to read swarm key",
+				"network", n.NetworkID,
+				"error", err)
+		}
+	}
+	if len(key) == 0 {
+		res.R(w, r, res.ErrNotFound("failed to find swarm key"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(key)
+}
@@ -1,9 +1,11 @@
 package delegator
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -17,10 +19,28 @@ import (
 	"github.com/RTradeLtd/Nexus/config"
 	"github.com/RTradeLtd/Nexus/ipfs"
 	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	"github.com/RTradeLtd/Nexus/temporal"
 	"github.com/RTradeLtd/Nexus/temporal/mock"
 	"github.com/go-chi/chi"
 )
 
+// newTestEngine instantiates an engine that proxies requests to given nodes.
+// Private networks and stores that are not provided are empty.
+func newTestEngine(t *testing.T, opts EngineOpts, networks temporal.PrivateNetworks,
+	stores Stores, nodes ...*ipfs.NodeInfo) *Engine {
+	var l = zaptest.NewLogger(t).Sugar()
+	if networks == nil {
+		networks = &mock.FakePrivateNetworks{}
+	}
+	e, err := New(l, opts, registry.New(l, config.New().Ports, config.Bind{}, nodes...), networks, stores.withEmpty())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
 func TestEngine_Run(t *testing.T) {
 	// claim port for testing unavailable port
 	if port, err := net.Listen("tcp", "127.0.0.1:69"); err != nil && port != nil {
@@ -59,8 +79,7 @@ func TestEngine_Run(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var (
 				networks = &mock.FakePrivateNetworks{}
-				l        = zaptest.NewLogger(t).Sugar()
-				e        = New(l, EngineOpts{"test", true, "domain.com", time.Minute, []byte("hello")}, nil, networks)
+				e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, Domain: "domain.com", RequestTimeout: time.Minute, JWTKey: []byte("hello")}, networks, Stores{})
 			)
 
 			var ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
@@ -92,11 +111,9 @@ func TestEngine_NetworkPathContext(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var (
 				networks = &mock.FakePrivateNetworks{}
-				l        = zaptest.NewLogger(t).Sugar()
-				e        = New(l, EngineOpts{"test", true, "", time.Second, []byte("hello")},
-					registry.New(l, config.New().Ports, &ipfs.NodeInfo{
-						NetworkID: tt.args.nodeName,
-					}), networks)
+				e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: []byte("hello")}, networks, Stores{}, &ipfs.NodeInfo{
+					NetworkID: tt.args.nodeName,
+				})
 			)
 
 			// set up route context and request
@@ -148,9 +165,7 @@ func TestEngine_FeaturePathContext(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var (
 				networks = &mock.FakePrivateNetworks{}
-				l        = zaptest.NewLogger(t).Sugar()
-				e        = New(l, EngineOpts{"test", true, "", time.Second, []byte("hello")},
-					registry.New(l, config.New().Ports), networks)
+				e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: []byte("hello")}, networks, Stores{})
 			)
 
 			// set up route context and request
@@ -203,11 +218,9 @@ func TestEngine_NetworkSubdomainContext(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var (
 				networks = &mock.FakePrivateNetworks{}
-				l        = zaptest.NewLogger(t).Sugar()
-				e        = New(l, EngineOpts{"test", true, "", time.Second, []byte("hello")},
-					registry.New(l, config.New().Ports, &ipfs.NodeInfo{
-						NetworkID: tt.args.nodeName,
-					}), networks)
+				e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: []byte("hello")}, networks, Stores{}, &ipfs.NodeInfo{
+					NetworkID: tt.args.nodeName,
+				})
 			)
 
 			// construct request
@@ -268,10 +281,10 @@ func TestEngine_Redirect(t *testing.T) {
 			fields{&ipfs.NodeInfo{}, nil, nil},
 			args{"", map[contextKey]string{keyFeature: "bobheadxi"}},
 			http.StatusBadRequest},
-		{"OK: swarm",
-			fields{&ipfs.NodeInfo{Ports: ipfs.NodePorts{Swarm: "5000"}}, nil, nil},
+		{"swarm + no websocket upgrade",
+			fields{&ipfs.NodeInfo{Ports: ipfs.NodePorts{Swarm: "5000", SwarmWS: "5001"}}, nil, nil},
 			args{"", map[contextKey]string{keyFeature: "swarm"}},
-			http.StatusBadGateway}, // badgateway because proxy points to nothing
+			http.StatusBadRequest},
 		{"api + bad token",
 			fields{&ipfs.NodeInfo{Ports: ipfs.NodePorts{API: "5000"}}, nil, nil},
 			args{"asdf", map[contextKey]string{keyFeature: "api"}},
@@ -336,9 +349,7 @@ func TestEngine_Redirect(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var (
 				networks = &mock.FakePrivateNetworks{}
-				l        = zaptest.NewLogger(t).Sugar()
-				e        = New(l, EngineOpts{"test", true, "", time.Second, defaultTestKey},
-					registry.New(l, config.New().Ports), networks)
+				e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: defaultTestKey}, networks, Stores{})
 			)
 
 			networks.GetNetworkByNameReturns(tt.fields.network, tt.fields.networkErr)
@@ -366,14 +377,212 @@ func TestEngine_Redirect(t *testing.T) {
 	}
 }
 
+func TestEngine_Redirect_apiToken(t *testing.T) {
+	var (
+		past    = time.Now().Add(-time.Hour)
+		network = &models.HostedNetwork{Name: "test"}
+		node    = &ipfs.NodeInfo{NetworkID: "test", Ports: ipfs.NodePorts{API: "5000"}}
+	)
+	type fields struct {
+		token    *store.APIToken
+		tokenErr error
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		path     string
+		wantCode int
+	}{
+		{"unknown token",
+			fields{nil, nil}, "/api/v0/cat", http.StatusUnauthorized},
+		{"lookup error",
+			fields{nil, errors.New("oh no")}, "/api/v0/cat", http.StatusServiceUnavailable},
+		{"wrong network",
+			fields{&store.APIToken{Network: "other", Scopes: store.Scopes{store.ScopeAdmin}}, nil},
+			"/api/v0/cat", http.StatusUnauthorized},
+		{"expired",
+			fields{&store.APIToken{Network: "test", Scopes: store.Scopes{store.ScopeAdmin}, ExpiresAt: &past}, nil},
+			"/api/v0/cat", http.StatusUnauthorized},
+		{"revoked",
+			fields{&store.APIToken{Network: "test", Scopes: store.Scopes{store.ScopeAdmin}, RevokedAt: &past}, nil},
+			"/api/v0/cat", http.StatusUnauthorized},
+		{"insufficient scope",
+			fields{&store.APIToken{Network: "test", Scopes: store.Scopes{store.ScopeReadOnly}}, nil},
+			"/api/v0/add", http.StatusForbidden},
+		{"OK: scoped",
+			fields{&store.APIToken{Network: "test", Scopes: store.Scopes{store.ScopeReadOnly}}, nil},
+			"/api/v0/cat", http.StatusBadGateway}, // badgateway because proxy points to nothing
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				networks = &mock.FakePrivateNetworks{}
+				tokens   = &smock.FakeTokens{}
+				e        = newTestEngine(t, EngineOpts{Version: "test", RequestTimeout: time.Second, JWTKey: defaultTestKey}, networks, Stores{Tokens: tokens})
+			)
+			networks.GetNetworkByNameReturns(network, nil)
+			tokens.FindTokenReturns(tt.fields.token, tt.fields.tokenErr)
+
+			var ctx = context.WithValue(context.Background(), keyNetwork, node)
+			ctx = context.WithValue(ctx, keyFeature, "api")
+			var (
+				req = httptest.NewRequest("GET", tt.path, nil).WithContext(ctx)
+				rec = httptest.NewRecorder()
+			)
+			req.Header.Set("Authorization", "Bearer "+store.TokenPrefix+"secret")
+			e.Redirect(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status '%d', found '%d'", tt.wantCode, rec.Code)
+			}
+			if tokens.FindTokenCallCount() == 1 &&
+				tokens.FindTokenArgsForCall(0) != store.HashToken(store.TokenPrefix+"secret") {
+				t.Error("token should be looked up by hash")
+			}
+		})
+	}
+}
+
+func TestEngine_Redirect_swarmWebSocket(t *testing.T) {
+	// mock a node's WebSocket listener, which echoes data after the handshake
+	var node = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isWebSocketUpgrade(r) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		buf.Flush()
+		io.Copy(conn, buf)
+	}))
+	defer node.Close()
+	_, port, _ := net.SplitHostPort(node.Listener.Addr().String())
+
+	var (
+		n = ipfs.NodeInfo{NetworkID: "test", Ports: ipfs.NodePorts{SwarmWS: port}}
+		e = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: defaultTestKey}, nil, Stores{}, &n)
+	)
+	tests := []struct {
+		name    string
+		handler http.Handler
+		path    string
+	}{
+		{"redirect", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ctx = context.WithValue(r.Context(), keyNetwork, &n)
+			e.Redirect(w, r.WithContext(context.WithValue(ctx, keyFeature, "swarm")))
+		}), "/"},
+		// middleware must not prevent connections from being hijacked
+		{"router", e.router(nil), "/network/test/swarm/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// hijacked connections are not tracked by the server, so wait for
+			// the handler to return before the test completes
+			var done = make(chan struct{})
+			var delegator = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer close(done)
+				tt.handler.ServeHTTP(w, r)
+			}))
+			defer delegator.Close()
+
+			conn, err := net.Dial("tcp", delegator.Listener.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				conn.Close()
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Error("expected proxied connection to be closed")
+				}
+			}()
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: test.swarm.nexus.temporal.cloud\r\n"+
+				"Upgrade: websocket\r\nConnection: Upgrade\r\n\r\n", tt.path)
+
+			var reader = bufio.NewReader(conn)
+			resp, err := http.ReadResponse(reader, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusSwitchingProtocols {
+				t.Fatalf("expected status '%d', found '%d'", http.StatusSwitchingProtocols, resp.StatusCode)
+			}
+
+			// data should be relayed in both directions once upgraded
+			fmt.Fprint(conn, "hello world")
+			var echo = make([]byte, len("hello world"))
+			if _, err := io.ReadFull(reader, echo); err != nil {
+				t.Fatal(err)
+			}
+			if string(echo) != "hello world" {
+				t.Errorf("expected echo 'hello world', found '%s'", echo)
+			}
+		})
+	}
+}
+
+func TestEngine_router_featurePath(t *testing.T) {
+	// mock a node's gateway
+	var node = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer node.Close()
+	_, port, _ := net.SplitHostPort(node.Listener.Addr().String())
+
+	var (
+		networks = &mock.FakePrivateNetworks{}
+		e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: defaultTestKey}, networks, Stores{},
+			&ipfs.NodeInfo{NetworkID: "test", Ports: ipfs.NodePorts{Gateway: port}})
+		router = e.router(nil)
+	)
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{GatewayPublic: true}, nil)
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{"valid feature", "/network/test/gateway/ipfs/hash", http.StatusOK},
+		{"invalid feature", "/network/test/bobheadxi/ipfs/hash", http.StatusBadRequest},
+		{"unknown network", "/network/bobheadxi/gateway/ipfs/hash", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rec = httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status '%d', found '%d'", tt.wantCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestEngine_Redirect_swarmNoWebSocketPort(t *testing.T) {
+	var (
+		e   = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: defaultTestKey}, nil, Stores{})
+		ctx = context.WithValue(context.WithValue(context.Background(),
+			keyNetwork, &ipfs.NodeInfo{NetworkID: "test", Ports: ipfs.NodePorts{Swarm: "4001"}}),
+			keyFeature, "swarm")
+		req = httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+		rec = httptest.NewRecorder()
+	)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	e.Redirect(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status '%d', found '%d'", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
 func TestEngine_Status(t *testing.T) {
 	var (
 		networks = &mock.FakePrivateNetworks{}
-		l        = zaptest.NewLogger(t).Sugar()
-		e        = New(l,
-			EngineOpts{"test", true, "", time.Second, []byte("hello")},
-			registry.New(l, config.New().Ports),
-			networks)
+		e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, RequestTimeout: time.Second, JWTKey: []byte("hello")}, networks, Stores{})
 	)
 	var (
 		req = httptest.NewRequest("GET", "/", nil)
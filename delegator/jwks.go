@@ -0,0 +1,265 @@
+package delegator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// jwksReloadInterval is how often the key set file is checked for changes
+	jwksReloadInterval = 10 * time.Second
+
+	// jwksMissReloadInterval bounds how often lookups of unknown key IDs
+	// trigger a check for changes
+	jwksMissReloadInterval = time.Second
+)
+
+// jsonWebKey is the JSON representation of a public key, as defined in
+// RFC 7517 and RFC 8037
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA parameters
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC and OKP parameters
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// webKey is a parsed public key
+type webKey struct {
+	kid string
+	alg string
+	key interface{}
+}
+
+// compatible checks if the key can verify tokens signed with given algorithm
+func (k webKey) compatible(alg string) bool {
+	if k.alg != "" && k.alg != alg {
+		return false
+	}
+	switch key := k.key.(type) {
+	case *rsa.PublicKey:
+		return alg == "RS256" || alg == "RS384" || alg == "RS512"
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return alg == "ES256"
+		case elliptic.P384():
+			return alg == "ES384"
+		case elliptic.P521():
+			return alg == "ES512"
+		}
+	case ed25519.PublicKey:
+		return alg == "EdDSA"
+	}
+	return false
+}
+
+// parseJWKS reads the signing keys in a JSON Web Key Set
+func parseJWKS(b []byte) ([]webKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("invalid key set: %s", err.Error())
+	}
+	var keys = make([]webKey, 0, len(set.Keys))
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d ('%s'): %s", i, jwk.Kid, err.Error())
+		}
+		keys = append(keys, webKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	return keys, nil
+}
+
+// parseJWK converts a JSON web key into a public key
+func parseJWK(jwk jsonWebKey) (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %s", err.Error())
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve '%s'", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %s", err.Error())
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %s", err.Error())
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve '%s'", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid public key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type '%s'", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("value is empty")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// keySet provides access to the keys in a JSON Web Key Set file, and reloads
+// them when the file changes
+type keySet struct {
+	l    *zap.SugaredLogger
+	path string
+
+	mux     sync.RWMutex
+	keys    []webKey
+	modTime time.Time
+	size    int64
+	checked time.Time
+}
+
+func newKeySet(l *zap.SugaredLogger, path string) *keySet {
+	return &keySet{l: l, path: path}
+}
+
+// load reads the key set if the file has changed since it was last read
+func (k *keySet) load() error {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	k.checked = time.Now()
+
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return fmt.Errorf("failed to read key set: %s", err.Error())
+	}
+	if k.keys != nil && info.ModTime().Equal(k.modTime) && info.Size() == k.size {
+		return nil
+	}
+	b, err := ioutil.ReadFile(k.path)
+	if err != nil {
+		return fmt.Errorf("failed to read key set: %s", err.Error())
+	}
+	keys, err := parseJWKS(b)
+	if err != nil {
+		return err
+	}
+	k.keys = keys
+	k.modTime = info.ModTime()
+	k.size = info.Size()
+	k.l.Infow("key set loaded",
+		"path", k.path,
+		"keys", len(keys))
+	return nil
+}
+
+// watch periodically reloads the key set until the context is cancelled
+func (k *keySet) watch(ctx context.Context, interval time.Duration) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.load(); err != nil {
+				k.l.Errorw("failed to reload key set - continuing with previous keys",
+					"path", k.path,
+					"error", err)
+			}
+		}
+	}
+}
+
+// lookup retrieves the key with given ID that can verify tokens signed with
+// given algorithm. If no ID is provided, the key set must contain exactly one
+// compatible key. Unknown key IDs trigger a check for changes, so that newly
+// rotated keys are picked up promptly.
+func (k *keySet) lookup(kid, alg string) (interface{}, error) {
+	if key, ok := k.find(kid, alg); ok {
+		return key, nil
+	}
+
+	k.mux.RLock()
+	var stale = time.Since(k.checked) > jwksMissReloadInterval
+	k.mux.RUnlock()
+	if stale {
+		if err := k.load(); err != nil {
+			k.l.Errorw("failed to reload key set",
+				"path", k.path,
+				"error", err)
+		} else if key, ok := k.find(kid, alg); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no key found for kid '%s' and alg '%s'", kid, alg)
+}
+
+func (k *keySet) find(kid, alg string) (interface{}, bool) {
+	k.mux.RLock()
+	defer k.mux.RUnlock()
+	var match interface{}
+	var matches int
+	for _, key := range k.keys {
+		if (kid == "" || key.kid == kid) && key.compatible(alg) {
+			match = key.key
+			matches++
+		}
+	}
+	return match, matches == 1
+}
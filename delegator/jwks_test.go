@@ -0,0 +1,217 @@
+package delegator
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"go.uber.org/zap/zaptest"
+)
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+// newTestJWK generates a private key and its public JSON web key
+func newTestJWK(t *testing.T, kty, kid string) (interface{}, jsonWebKey) {
+	switch kty {
+	case "RSA":
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return priv, jsonWebKey{Kty: "RSA", Kid: kid,
+			N: encodeBigInt(priv.N), E: encodeBigInt(big.NewInt(int64(priv.E)))}
+	case "EC":
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return priv, jsonWebKey{Kty: "EC", Kid: kid, Crv: "P-256",
+			X: encodeBigInt(priv.X), Y: encodeBigInt(priv.Y)}
+	case "OKP":
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return priv, jsonWebKey{Kty: "OKP", Kid: kid, Crv: "Ed25519",
+			X: base64.RawURLEncoding.EncodeToString(pub)}
+	}
+	t.Fatalf("unknown key type %s", kty)
+	return nil, jsonWebKey{}
+}
+
+func writeTestJWKS(t *testing.T, path string, keys ...jsonWebKey) {
+	b, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	var token = jwt.NewWithClaims(method, jwt.MapClaims{"id": "testuser"})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestVerifier_keySet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		path            = filepath.Join(dir, "jwks.json")
+		rsaKey, rsaJWK  = newTestJWK(t, "RSA", "rsa")
+		ecKey, ecJWK    = newTestJWK(t, "EC", "ec")
+		edKey, edJWK    = newTestJWK(t, "OKP", "ed")
+		otherKey, other = newTestJWK(t, "OKP", "ed2")
+		keys            = newKeySet(zaptest.NewLogger(t).Sugar(), path)
+		v               = &verifier{keys: keys, timeFunc: time.Now}
+		noneToken, _    = jwt.New(jwt.SigningMethodNone).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		hmacToken, _    = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": "testuser"}).SignedString([]byte("secret"))
+		edSingleKey, ed = newTestJWK(t, "OKP", "")
+	)
+	writeTestJWKS(t, path, rsaJWK, ecJWK, edJWK, other)
+	if err := keys.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantUser string
+		wantErr  bool
+	}{
+		{"RS256", signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey), "testuser", false},
+		{"ES256", signTestToken(t, jwt.SigningMethodES256, "ec", ecKey), "testuser", false},
+		{"EdDSA", signTestToken(t, signingMethodEdDSA{}, "ed", edKey), "testuser", false},
+		{"wrong kid", signTestToken(t, signingMethodEdDSA{}, "ec", edKey), "", true},
+		{"unknown kid", signTestToken(t, signingMethodEdDSA{}, "bobheadxi", edKey), "", true},
+		{"no kid, multiple keys", signTestToken(t, signingMethodEdDSA{}, "", edKey), "", true},
+		{"wrong signature", signTestToken(t, signingMethodEdDSA{}, "ed", otherKey), "", true},
+		{"none", noneToken, "", true},
+		{"hmac without secret", hmacToken, "", true},
+		{"malformed", "a.b", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.verify(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifier.verify() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && claims["id"] != tt.wantUser {
+				t.Errorf("verifier.verify() id = %v, want %v", claims["id"], tt.wantUser)
+			}
+		})
+	}
+
+	// a single key may be used without a kid
+	writeTestJWKS(t, path, ed)
+	if err := keys.load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.verify(signTestToken(t, signingMethodEdDSA{}, "", edSingleKey)); err != nil {
+		t.Errorf("expected token without kid to be accepted, got %v", err)
+	}
+}
+
+func TestKeySet_rotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		path         = filepath.Join(dir, "jwks.json")
+		_, oldJWK    = newTestJWK(t, "OKP", "2019-01")
+		newKey, jwk  = newTestJWK(t, "OKP", "2019-02")
+		keys         = newKeySet(zaptest.NewLogger(t).Sugar(), path)
+		v            = &verifier{keys: keys, timeFunc: time.Now}
+		rotatedToken = signTestToken(t, signingMethodEdDSA{}, "2019-02", newKey)
+	)
+	writeTestJWKS(t, path, oldJWK)
+	if err := keys.load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.verify(rotatedToken); err == nil {
+		t.Fatal("expected token signed with unknown key to be rejected")
+	}
+
+	// rotate in new key - unknown kids should trigger a reload once the last
+	// check is stale
+	writeTestJWKS(t, path, oldJWK, jwk)
+	keys.mux.Lock()
+	keys.checked = time.Time{}
+	keys.mux.Unlock()
+	if _, err := v.verify(rotatedToken); err != nil {
+		t.Errorf("expected token signed with rotated key to be accepted, got %v", err)
+	}
+
+	// invalid key sets should not replace the current keys
+	if err := ioutil.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.load(); err == nil {
+		t.Error("expected error loading invalid key set")
+	}
+	if _, err := v.verify(rotatedToken); err != nil {
+		t.Errorf("expected previous keys to be retained, got %v", err)
+	}
+}
+
+func Test_parseJWKS(t *testing.T) {
+	tests := []struct {
+		name     string
+		jwks     string
+		wantKeys int
+		wantErr  bool
+	}{
+		{"invalid json", `{`, 0, true},
+		{"empty", `{"keys":[]}`, 0, false},
+		{"unknown key type", `{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`, 0, true},
+		{"unknown curve", `{"keys":[{"kty":"OKP","crv":"X25519","x":"AA"}]}`, 0, true},
+		{"invalid ed25519 key", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AA"}]}`, 0, true},
+		{"invalid rsa key", `{"keys":[{"kty":"RSA","n":"","e":"AQAB"}]}`, 0, true},
+		{"point not on curve", `{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`, 0, true},
+		{"encryption keys are skipped", `{"keys":[{"kty":"RSA","use":"enc"}]}`, 0, false},
+		{"ed25519 key",
+			`{"keys":[{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`,
+			1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseJWKS([]byte(tt.jwks))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseJWKS() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(keys) != tt.wantKeys {
+				t.Errorf("parseJWKS() = %d keys, want %d", len(keys), tt.wantKeys)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package delegator
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+const (
+	metricsNamespace = "nexus"
+	metricsSubsystem = "delegator"
+
+	// labelUnknown is used for requests that were not routed to a network or
+	// feature, so that label cardinality is bounded by registered networks
+	labelUnknown = "unknown"
+)
+
+// Authorization failure reasons
+const (
+	reasonNoToken             = "no_token"
+	reasonInvalidToken        = "invalid_token"
+	reasonExpiredToken        = "expired_token"
+	reasonUnknownNetwork      = "unknown_network"
+	reasonUnauthorized        = "user_not_authorized"
+	reasonGatewayDisabled     = "gateway_not_public"
+	reasonRevokedToken        = "revoked_token"
+	reasonInsufficientScope   = "insufficient_scope"
+	reasonCommandNotPermitted = "command_not_permitted"
+	reasonInsufficientRole    = "insufficient_role"
+	reasonAddressNotPermitted = "address_not_permitted"
+)
+
+// metrics collects delegator statistics for Prometheus. Each engine has its
+// own registry rather than using the global one.
+type metrics struct {
+	registry *prometheus.Registry
+
+	requests     *prometheus.CounterVec
+	latency      *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	cacheLookups *prometheus.CounterVec
+	authFailures *prometheus.CounterVec
+	rateLimited  *prometheus.CounterVec
+
+	networkCacheLookups       *prometheus.CounterVec
+	networkCacheFetchErrors   prometheus.Counter
+	networkCacheInvalidations prometheus.Counter
+
+	gatewayCacheLookups   *prometheus.CounterVec
+	gatewayCacheEvictions prometheus.Counter
+
+	denylistBlocked *prometheus.CounterVec
+
+	upstreamErrors *prometheus.CounterVec
+
+	uploadedBytes *prometheus.CounterVec
+
+	gatewayEgress     *prometheus.CounterVec
+	gatewayThroughput *prometheus.GaugeVec
+	gatewayThrottled  *prometheus.CounterVec
+	gatewayCapped     *prometheus.CounterVec
+
+	// tracked separately to report hit ratio
+	cacheHits   uint64
+	cacheMisses uint64
+}
+
+func newMetrics() *metrics {
+	var m = &metrics{
+		registry: prometheus.NewRegistry(),
+
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "requests_total",
+			Help:      "Number of requests handled, by network, feature, method, and status code.",
+		}, []string{"network", "feature", "method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to handle requests, by network and feature.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"network", "feature"}),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "response_size_bytes",
+			Help:      "Size of response bodies, by network and feature.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 10),
+		}, []string{"network", "feature"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "proxy_cache_lookups_total",
+			Help:      "Number of proxy cache lookups, by result.",
+		}, []string{"result"}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "auth_failures_total",
+			Help:      "Number of rejected requests, by network and reason.",
+		}, []string{"network", "reason"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "rate_limited_total",
+			Help:      "Number of requests rejected for exceeding rate limits, by network and feature.",
+		}, []string{"network", "feature"}),
+
+		networkCacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "network_cache_lookups_total",
+			Help:      "Number of network settings cache lookups, by result.",
+		}, []string{"result"}),
+		networkCacheFetchErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "network_cache_fetch_errors_total",
+			Help:      "Number of failed network settings lookups.",
+		}),
+		networkCacheInvalidations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "network_cache_invalidations_total",
+			Help:      "Number of network settings cache invalidations.",
+		}),
+
+		gatewayCacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "gateway_cache_lookups_total",
+			Help:      "Number of gateway content cache lookups, by result.",
+		}, []string{"result"}),
+		gatewayCacheEvictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "gateway_cache_evictions_total",
+			Help:      "Number of responses evicted from the gateway content cache.",
+		}),
+
+		denylistBlocked: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "denylist_blocked_total",
+			Help:      "Number of requests rejected for denylisted content, by network and feature.",
+		}, []string{"network", "feature"}),
+
+		upstreamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "upstream_errors_total",
+			Help:      "Number of requests that could not be proxied to a node, by network and reason.",
+		}, []string{"network", "reason"}),
+
+		uploadedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "uploaded_bytes_total",
+			Help:      "Number of request body bytes proxied to nodes, by network and feature.",
+		}, []string{"network", "feature"}),
+
+		gatewayEgress: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "gateway_egress_bytes_total",
+			Help:      "Number of gateway response body bytes sent, by network.",
+		}, []string{"network"}),
+		gatewayThroughput: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "gateway_egress_bytes_per_second",
+			Help:      "Gateway response body bytes sent per second over the last sample interval, by network.",
+		}, []string{"network"}),
+		gatewayThrottled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "gateway_egress_throttled_seconds_total",
+			Help:      "Time gateway responses were delayed to stay within egress rates, by network.",
+		}, []string{"network"}),
+		gatewayCapped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "gateway_transfer_cap_exceeded_total",
+			Help:      "Number of gateway requests rejected for exceeding monthly transfer caps, by network.",
+		}, []string{"network"}),
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.requests,
+		m.latency,
+		m.responseSize,
+		m.cacheLookups,
+		m.authFailures,
+		m.rateLimited,
+		m.networkCacheLookups,
+		m.networkCacheFetchErrors,
+		m.networkCacheInvalidations,
+		m.gatewayCacheLookups,
+		m.gatewayCacheEvictions,
+		m.denylistBlocked,
+		m.upstreamErrors,
+		m.uploadedBytes,
+		m.gatewayEgress,
+		m.gatewayThroughput,
+		m.gatewayThrottled,
+		m.gatewayCapped,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "proxy_cache_hit_ratio",
+			Help:      "Ratio of proxy cache lookups that were hits.",
+		}, m.cacheHitRatio),
+	)
+	return m
+}
+
+// Handler serves collected metrics
+func (m *metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// observe records a completed request - it is intended for use with
+// log.NewMiddleware
+func (m *metrics) observe(r *http.Request, status, bytes int, latency time.Duration) {
+	var network, feature = labelUnknown, labelUnknown
+	if l, ok := r.Context().Value(keyLabels).(*requestLabels); ok {
+		if l.network != "" {
+			network = l.network
+		}
+		if l.feature != "" {
+			feature = l.feature
+		}
+	}
+	m.requests.WithLabelValues(network, feature, r.Method, strconv.Itoa(status)).Inc()
+	m.latency.WithLabelValues(network, feature).Observe(latency.Seconds())
+	m.responseSize.WithLabelValues(network, feature).Observe(float64(bytes))
+}
+
+// registerNetworkCache reports the size of the network settings cache
+func (m *metrics) registerNetworkCache(size func() int) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "network_cache_entries",
+		Help:      "Number of entries in the network settings cache.",
+	}, func() float64 { return float64(size()) }))
+}
+
+// registerGatewayCache reports the size of the gateway content cache
+func (m *metrics) registerGatewayCache(size func() int64) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "gateway_cache_size_bytes",
+		Help:      "Total size of content in the gateway content cache.",
+	}, func() float64 { return float64(size()) }))
+}
+
+// gatewayCacheLookup records the result of a gateway content cache lookup
+func (m *metrics) gatewayCacheLookup(result string) {
+	m.gatewayCacheLookups.WithLabelValues(result).Inc()
+}
+
+// denylistBlock records a request rejected for denylisted content
+func (m *metrics) denylistBlock(network, feature string) {
+	m.denylistBlocked.WithLabelValues(network, feature).Inc()
+}
+
+// upstreamError records a request that could not be proxied to a node
+func (m *metrics) upstreamError(network, reason string) {
+	m.upstreamErrors.WithLabelValues(network, reason).Inc()
+}
+
+// networkCacheLookup records the result of a network settings cache lookup
+func (m *metrics) networkCacheLookup(result string) {
+	m.networkCacheLookups.WithLabelValues(result).Inc()
+}
+
+// cacheLookup records the result of a proxy cache lookup
+func (m *metrics) cacheLookup(hit bool) {
+	if hit {
+		atomic.AddUint64(&m.cacheHits, 1)
+		m.cacheLookups.WithLabelValues("hit").Inc()
+	} else {
+		atomic.AddUint64(&m.cacheMisses, 1)
+		m.cacheLookups.WithLabelValues("miss").Inc()
+	}
+}
+
+func (m *metrics) cacheHitRatio() float64 {
+	var (
+		hits   = atomic.LoadUint64(&m.cacheHits)
+		misses = atomic.LoadUint64(&m.cacheMisses)
+	)
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// authFailure records a rejected request
+func (m *metrics) authFailure(network, reason string) {
+	m.authFailures.WithLabelValues(network, reason).Inc()
+}
+
+// authFailureReason maps authentication errors to failure reasons
+func authFailureReason(err error) string {
+	switch err {
+	case errNoAuth:
+		return reasonNoToken
+	case errExpiredAuth, store.ErrTokenExpired:
+		return reasonExpiredToken
+	case store.ErrTokenRevoked:
+		return reasonRevokedToken
+	default:
+		return reasonInvalidToken
+	}
+}
+
+// requestLabels is populated as a request is routed, and used to label the
+// request's metrics once it completes
+type requestLabels struct {
+	network string
+	feature string
+}
+
+// withRequestLabels creates a handler that provides a place for routing
+// handlers to record a request's network and feature. It must be mounted
+// before log.NewMiddleware for the labels to be visible to metrics.
+func withRequestLabels(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(
+			context.WithValue(r.Context(), keyLabels, &requestLabels{}),
+		))
+	})
+}
+
+// setRequestLabels records the network and feature of a request
+func setRequestLabels(r *http.Request, network, feature string) {
+	if l, ok := r.Context().Value(keyLabels).(*requestLabels); ok {
+		l.network, l.feature = network, feature
+	}
+}
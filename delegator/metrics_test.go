@@ -0,0 +1,117 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestMetrics_observe(t *testing.T) {
+	tests := []struct {
+		name        string
+		network     string
+		feature     string
+		wantNetwork string
+		wantFeature string
+	}{
+		{"unrouted", "", "", labelUnknown, labelUnknown},
+		{"routed", "bobheadxi", "api", "bobheadxi", "api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m = newMetrics()
+			var handler = withRequestLabels(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.network != "" {
+					setRequestLabels(r, tt.network, tt.feature)
+				}
+				m.observe(r, http.StatusOK, 1024, time.Second)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+			if v := testutil.ToFloat64(m.requests.WithLabelValues(
+				tt.wantNetwork, tt.wantFeature, "GET", "200")); v != 1 {
+				t.Errorf("expected 1 request recorded, found %v", v)
+			}
+		})
+	}
+}
+
+func TestMetrics_cacheHitRatio(t *testing.T) {
+	var m = newMetrics()
+	if r := m.cacheHitRatio(); r != 0 {
+		t.Errorf("expected ratio 0 with no lookups, got %v", r)
+	}
+	m.cacheLookup(true)
+	m.cacheLookup(true)
+	m.cacheLookup(true)
+	m.cacheLookup(false)
+	if r := m.cacheHitRatio(); r != 0.75 {
+		t.Errorf("expected ratio 0.75, got %v", r)
+	}
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	var m = newMetrics()
+	m.authFailure("bobheadxi", reasonNoToken)
+
+	var rec = httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := ioutil.ReadAll(rec.Body)
+	for _, want := range []string{
+		`nexus_delegator_auth_failures_total{network="bobheadxi",reason="no_token"} 1`,
+		"nexus_delegator_proxy_cache_hit_ratio 0",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected '%s' in metrics output", want)
+		}
+	}
+}
+
+func Test_authFailureReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errNoAuth, reasonNoToken},
+		{errExpiredAuth, reasonExpiredToken},
+		{errInvalidAuth, reasonInvalidToken},
+		{errors.New("oh no"), reasonInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := authFailureReason(tt.err); got != tt.want {
+				t.Errorf("authFailureReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_Redirect_metrics(t *testing.T) {
+	var (
+		networks = &mock.FakePrivateNetworks{}
+		e        = newTestEngine(t, EngineOpts{Version: "test", RequestTimeout: time.Second, JWTKey: defaultTestKey}, networks, Stores{})
+		node     = &ipfs.NodeInfo{NetworkID: "bobheadxi", Ports: ipfs.NodePorts{API: "5000"}}
+	)
+
+	// expired token should be recorded as such
+	var ctx = context.WithValue(context.WithValue(context.Background(),
+		keyNetwork, node), keyFeature, "api")
+	var req = httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", validToken))
+	e.Redirect(httptest.NewRecorder(), req)
+	if v := testutil.ToFloat64(e.metrics.authFailures.WithLabelValues(
+		"bobheadxi", reasonExpiredToken)); v != 1 {
+		t.Errorf("expected 1 expired token failure, found %v", v)
+	}
+}
@@ -0,0 +1,178 @@
+package delegator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/RTradeLtd/database/v2/models"
+	"github.com/RTradeLtd/gorm"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+	"github.com/RTradeLtd/Nexus/temporal"
+)
+
+// Results of network cache lookups
+const (
+	lookupHit         = "hit"
+	lookupNegativeHit = "negative_hit"
+	lookupMiss        = "miss"
+	lookupStale       = "stale"
+)
+
+// notFoundError wraps errors for records that do not exist, which are cached
+type notFoundError struct{ error }
+
+// cacheEntry is a cached lookup result
+type cacheEntry struct {
+	value   interface{}
+	err     error
+	fetched time.Time
+}
+
+// networkCache is a read-through cache of network access settings - each
+// network's Temporal entry, which declares users, gateway access, and allowed
+// origins, and its Nexus settings, which declare policies. Concurrent misses
+// for the same key share a single lookup, and expired entries continue to be
+// served for a while if they cannot be refreshed.
+type networkCache struct {
+	l        *zap.SugaredLogger
+	networks temporal.PrivateNetworks
+	settings store.Settings
+	metrics  *metrics
+
+	ttl         time.Duration
+	negativeTTL time.Duration
+	staleTTL    time.Duration
+	timeFunc    func() time.Time
+
+	group   singleflight.Group
+	mux     sync.RWMutex
+	entries map[string]cacheEntry
+
+	// generation is incremented on invalidation, so that lookups that were in
+	// flight during an invalidation do not cache outdated results
+	generation uint64
+}
+
+func newNetworkCache(l *zap.SugaredLogger, networks temporal.PrivateNetworks,
+	settings store.Settings, m *metrics, opts config.NetworkCache) *networkCache {
+	var c = &networkCache{
+		l:        l,
+		networks: networks,
+		settings: settings,
+		metrics:  m,
+
+		ttl:         time.Duration(opts.TTLSeconds) * time.Second,
+		negativeTTL: time.Duration(opts.NegativeTTLSeconds) * time.Second,
+		staleTTL:    time.Duration(opts.StaleSeconds) * time.Second,
+		timeFunc:    time.Now,
+
+		entries: make(map[string]cacheEntry),
+	}
+	m.registerNetworkCache(c.Size)
+	return c
+}
+
+// GetNetworkByName retrieves the Temporal entry of given network
+func (c *networkCache) GetNetworkByName(name string) (*models.HostedNetwork, error) {
+	v, err := c.get("network/"+name, func() (interface{}, error) {
+		n, err := c.networks.GetNetworkByName(name)
+		if err != nil && gorm.IsRecordNotFoundError(err) {
+			return nil, notFoundError{err}
+		}
+		return n, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*models.HostedNetwork), nil
+}
+
+// GetNetworkSettings retrieves the Nexus settings of given network
+func (c *networkCache) GetNetworkSettings(network string) (*store.NetworkSettings, error) {
+	v, err := c.get("settings/"+network, func() (interface{}, error) {
+		return c.settings.GetNetworkSettings(network)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*store.NetworkSettings), nil
+}
+
+// Invalidate discards everything cached for given network
+func (c *networkCache) Invalidate(network string) {
+	c.mux.Lock()
+	for _, key := range []string{"network/" + network, "settings/" + network} {
+		delete(c.entries, key)
+		c.group.Forget(key)
+	}
+	c.generation++
+	c.mux.Unlock()
+	c.metrics.networkCacheInvalidations.Inc()
+}
+
+// Size returns the number of cached entries
+func (c *networkCache) Size() int {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return len(c.entries)
+}
+
+func (c *networkCache) get(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	var now = c.timeFunc()
+	c.mux.RLock()
+	cached, found := c.entries[key]
+	var generation = c.generation
+	c.mux.RUnlock()
+	if found {
+		if cached.err != nil && now.Sub(cached.fetched) < c.negativeTTL {
+			c.metrics.networkCacheLookup(lookupNegativeHit)
+			return nil, cached.err
+		}
+		if cached.err == nil && now.Sub(cached.fetched) < c.ttl {
+			c.metrics.networkCacheLookup(lookupHit)
+			return cached.value, nil
+		}
+	}
+
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		v, err := fetch()
+		if err == nil {
+			c.store(key, generation, cacheEntry{value: v, fetched: c.timeFunc()})
+		} else if nf, ok := err.(notFoundError); ok {
+			c.store(key, generation, cacheEntry{err: nf.error, fetched: c.timeFunc()})
+		}
+		return v, err
+	})
+	if err == nil {
+		c.metrics.networkCacheLookup(lookupMiss)
+		return v, nil
+	}
+	if nf, ok := err.(notFoundError); ok {
+		c.metrics.networkCacheLookup(lookupMiss)
+		return nil, nf.error
+	}
+
+	// serve stale data if lookups are failing
+	c.metrics.networkCacheFetchErrors.Inc()
+	if found && cached.err == nil && now.Sub(cached.fetched) < c.ttl+c.staleTTL {
+		c.l.Warnw("failed to refresh network settings - serving stale data",
+			"key", key,
+			"age", now.Sub(cached.fetched),
+			"error", err)
+		c.metrics.networkCacheLookup(lookupStale)
+		return cached.value, nil
+	}
+	return nil, err
+}
+
+func (c *networkCache) store(key string, generation uint64, e cacheEntry) {
+	c.mux.Lock()
+	if c.generation == generation {
+		c.entries[key] = e
+	}
+	c.mux.Unlock()
+}
@@ -0,0 +1,168 @@
+package delegator
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/database/v2/models"
+	"github.com/RTradeLtd/gorm"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func newTestNetworkCache(t *testing.T) (*networkCache, *mock.FakePrivateNetworks, *smock.FakeSettings, *time.Time) {
+	var (
+		networks = &mock.FakePrivateNetworks{}
+		settings = &smock.FakeSettings{}
+		now      = time.Now()
+		c        = newNetworkCache(zaptest.NewLogger(t).Sugar(), networks, settings, newMetrics(),
+			config.NetworkCache{TTLSeconds: 30, NegativeTTLSeconds: 5, StaleSeconds: 300})
+	)
+	c.timeFunc = func() time.Time { return now }
+	return c, networks, settings, &now
+}
+
+func TestNetworkCache_GetNetworkByName(t *testing.T) {
+	c, networks, _, now := newTestNetworkCache(t)
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{Name: "asdf"}, nil)
+
+	// first lookup misses, subsequent lookups hit
+	for i := 0; i < 3; i++ {
+		n, err := c.GetNetworkByName("asdf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.Name != "asdf" {
+			t.Errorf("unexpected network %+v", n)
+		}
+	}
+	if networks.GetNetworkByNameCallCount() != 1 {
+		t.Errorf("expected 1 database lookup, got %d", networks.GetNetworkByNameCallCount())
+	}
+	if v := testutil.ToFloat64(c.metrics.networkCacheLookups.WithLabelValues(lookupHit)); v != 2 {
+		t.Errorf("expected 2 hits, got %v", v)
+	}
+
+	// expired entries should be refreshed
+	*now = now.Add(time.Minute)
+	if _, err := c.GetNetworkByName("asdf"); err != nil {
+		t.Fatal(err)
+	}
+	if networks.GetNetworkByNameCallCount() != 2 {
+		t.Errorf("expected 2 database lookups, got %d", networks.GetNetworkByNameCallCount())
+	}
+}
+
+func TestNetworkCache_negative(t *testing.T) {
+	c, networks, _, now := newTestNetworkCache(t)
+	networks.GetNetworkByNameReturns(nil, gorm.ErrRecordNotFound)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetNetworkByName("asdf"); err != gorm.ErrRecordNotFound {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	}
+	if networks.GetNetworkByNameCallCount() != 1 {
+		t.Errorf("expected 1 database lookup, got %d", networks.GetNetworkByNameCallCount())
+	}
+	if v := testutil.ToFloat64(c.metrics.networkCacheLookups.WithLabelValues(lookupNegativeHit)); v != 1 {
+		t.Errorf("expected 1 negative hit, got %v", v)
+	}
+
+	// negative entries expire sooner
+	*now = now.Add(10 * time.Second)
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{Name: "asdf"}, nil)
+	if _, err := c.GetNetworkByName("asdf"); err != nil {
+		t.Errorf("expected network to be found, got %v", err)
+	}
+}
+
+func TestNetworkCache_stale(t *testing.T) {
+	c, _, settings, now := newTestNetworkCache(t)
+	settings.GetNetworkSettingsReturns(&store.NetworkSettings{Network: "asdf"}, nil)
+	if _, err := c.GetNetworkSettings("asdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	// stale data should be served if the database is unavailable
+	settings.GetNetworkSettingsReturns(nil, errors.New("oh no"))
+	*now = now.Add(time.Minute)
+	s, err := c.GetNetworkSettings("asdf")
+	if err != nil {
+		t.Fatalf("expected stale settings, got %v", err)
+	}
+	if s.Network != "asdf" {
+		t.Errorf("unexpected settings %+v", s)
+	}
+	if v := testutil.ToFloat64(c.metrics.networkCacheLookups.WithLabelValues(lookupStale)); v != 1 {
+		t.Errorf("expected 1 stale lookup, got %v", v)
+	}
+	if v := testutil.ToFloat64(c.metrics.networkCacheFetchErrors); v != 1 {
+		t.Errorf("expected 1 fetch error, got %v", v)
+	}
+
+	// stale data should eventually be discarded
+	*now = now.Add(time.Hour)
+	if _, err := c.GetNetworkSettings("asdf"); err == nil {
+		t.Error("expected error once stale data expires")
+	}
+}
+
+func TestNetworkCache_Invalidate(t *testing.T) {
+	c, networks, settings, _ := newTestNetworkCache(t)
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{Name: "asdf"}, nil)
+	settings.GetNetworkSettingsReturns(&store.NetworkSettings{Network: "asdf"}, nil)
+	c.GetNetworkByName("asdf")
+	c.GetNetworkSettings("asdf")
+	if c.Size() != 2 {
+		t.Fatalf("expected 2 entries, got %d", c.Size())
+	}
+
+	c.Invalidate("asdf")
+	if c.Size() != 0 {
+		t.Errorf("expected no entries, got %d", c.Size())
+	}
+	c.GetNetworkByName("asdf")
+	c.GetNetworkSettings("asdf")
+	if networks.GetNetworkByNameCallCount() != 2 || settings.GetNetworkSettingsCallCount() != 2 {
+		t.Error("expected invalidated entries to be fetched again")
+	}
+	if v := testutil.ToFloat64(c.metrics.networkCacheInvalidations); v != 1 {
+		t.Errorf("expected 1 invalidation, got %v", v)
+	}
+}
+
+func TestNetworkCache_singleflight(t *testing.T) {
+	c, networks, _, _ := newTestNetworkCache(t)
+	var release = make(chan struct{})
+	networks.GetNetworkByNameStub = func(string) (*models.HostedNetwork, error) {
+		<-release
+		return &models.HostedNetwork{Name: "asdf"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetNetworkByName("asdf"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if networks.GetNetworkByNameCallCount() > 2 {
+		t.Errorf("expected concurrent lookups to be shared, got %d database lookups",
+			networks.GetNetworkByNameCallCount())
+	}
+}
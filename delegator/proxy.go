@@ -9,7 +9,13 @@ import (
 	"go.uber.org/zap"
 )
 
-func newProxy(feature string, target *url.URL, l *zap.SugaredLogger, direct bool) *httputil.ReverseProxy {
+func newProxy(feature string, target *url.URL, l *zap.SugaredLogger, m *metrics,
+	direct bool, b *breaker, retries int) *httputil.ReverseProxy {
+	// only gateway requests are retried, since API commands may not be
+	// idempotent regardless of method
+	if feature != "gateway" {
+		retries = 0
+	}
 	return &httputil.ReverseProxy{
 		Director: func(req *http.Request) {
 			// if set up as an indirect proxy, we need to remove delgator-specific
@@ -32,7 +38,31 @@ func newProxy(feature string, target *url.URL, l *zap.SugaredLogger, direct bool
 				"path", req.URL.Path,
 				"url", req.URL)
 		},
+		// responses follow the network's CORS policy rather than the node's
+		ModifyResponse: func(resp *http.Response) error {
+			for _, h := range corsHeaders {
+				resp.Header.Del(h)
+			}
+			return nil
+		},
+		Transport:    newBreakerTransport(b, retries),
+		ErrorHandler: upstreamErrorHandler(l, m, b),
+	}
+}
+
+// isWebSocketUpgrade checks if the request is a WebSocket handshake
+func isWebSocketUpgrade(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, v := range r.Header["Connection"] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
 	}
+	return false
 }
 
 func validateFeature(feature string) bool {
@@ -2,14 +2,22 @@ package delegator
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"testing"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/tracing"
 )
 
 func Test_newProxy(t *testing.T) {
@@ -50,7 +58,8 @@ func Test_newProxy(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var l = zaptest.NewLogger(t).Sugar()
-			var got = newProxy(tt.args.feature, tt.args.target, l, tt.args.direct)
+			var b = newBreakers(l, config.New().Delegator.Breaker).get("blah")
+			var got = newProxy(tt.args.feature, tt.args.target, l, newMetrics(), tt.args.direct, b, 0)
 
 			// test proxy Director
 			var r = httptest.NewRequest(tt.req.method, tt.req.address, tt.req.body)
@@ -72,6 +81,40 @@ func Test_newProxy(t *testing.T) {
 	}
 }
 
+func Test_newProxy_tracing(t *testing.T) {
+	var recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	if _, err := tracing.New(zaptest.NewLogger(t).Sugar(), config.Tracing{}, "test", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	var traceparent string
+	var upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	ctx, span := tracing.Start(context.Background(), "test.request")
+	var req = httptest.NewRequest("GET", "/ipfs/"+testCID, nil).WithContext(ctx)
+	var l = zaptest.NewLogger(t).Sugar()
+	var b = newBreakers(l, config.New().Delegator.Breaker).get("test")
+	newProxy("gateway", target, l, newMetrics(), true, b, 0).ServeHTTP(httptest.NewRecorder(), req)
+	span.End()
+
+	var spans = recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "delegator.proxy" {
+		t.Fatalf("expected proxy span, got %v", spans)
+	}
+	if spans[0].Parent().SpanID() != span.SpanContext().SpanID() {
+		t.Error("expected proxy span to be a child of the request span")
+	}
+	if want := "00-" + span.SpanContext().TraceID().String() + "-" +
+		spans[0].SpanContext().SpanID().String() + "-01"; traceparent != want {
+		t.Errorf("expected node to receive traceparent '%s', got '%s'", want, traceparent)
+	}
+}
+
 func Test_stripLeadingPaths(t *testing.T) {
 	type args struct {
 		path string
@@ -0,0 +1,196 @@
+package delegator
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bobheadxi/res"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+// bucket is a token bucket that tracks the request allowance of a single
+// network feature or user
+type bucket struct {
+	mux    sync.Mutex
+	limit  config.RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// decision denotes the outcome of a rate limit check
+type decision struct {
+	allowed   bool
+	limit     int
+	remaining int
+	// reset is the time until the bucket is full again
+	reset time.Duration
+	// retry is the time until a request would be allowed, if this one was not
+	retry time.Duration
+}
+
+func newBucket(limit config.RateLimit, now time.Time) *bucket {
+	return &bucket{limit: limit, tokens: float64(burst(limit)), last: now}
+}
+
+// take attempts to consume a token from the bucket. If the limit has changed
+// since the last request, the bucket is adjusted to the new limit.
+func (b *bucket) take(now time.Time, limit config.RateLimit) decision {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	var capacity = float64(burst(limit))
+	b.limit = limit
+
+	// refill tokens based on time elapsed since last request
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * limit.Rate
+		b.last = now
+	}
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+
+	var d = decision{limit: int(capacity)}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.allowed = true
+	} else {
+		d.retry = seconds((1 - b.tokens) / limit.Rate)
+	}
+	d.remaining = int(math.Floor(b.tokens))
+	d.reset = seconds((capacity - b.tokens) / limit.Rate)
+	return d
+}
+
+// limiter tracks request allowances for many keys, bounded in memory by
+// evicting the least recently used buckets
+type limiter struct {
+	buckets  *lru.Cache
+	timeFunc func() time.Time
+
+	// guards bucket creation
+	mux sync.Mutex
+}
+
+func newLimiter(size int) (*limiter, error) {
+	buckets, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limiter: %s", err.Error())
+	}
+	return &limiter{buckets: buckets, timeFunc: time.Now}, nil
+}
+
+// take consumes a request allowance for given key
+func (l *limiter) take(key string, limit config.RateLimit) decision {
+	var now = l.timeFunc()
+	l.mux.Lock()
+	var b *bucket
+	if v, found := l.buckets.Get(key); found {
+		b = v.(*bucket)
+	} else {
+		b = newBucket(limit, now)
+		l.buckets.Add(key, b)
+	}
+	l.mux.Unlock()
+	return b.take(now, limit)
+}
+
+// Size returns the number of tracked buckets
+func (l *limiter) Size() int { return l.buckets.Len() }
+
+// rateLimit consumes the request allowance of given network feature and, if
+// provided, user, and writes the appropriate headers. If the request is not
+// allowed, a response is written and false is returned.
+func (e *Engine) rateLimit(w http.ResponseWriter, r *http.Request, network, feature, user string) bool {
+	// swarm connections are long-lived and not subject to request limits
+	if feature == "swarm" {
+		return true
+	}
+
+	var limits = e.limits.Feature(feature)
+	if s, err := e.networks.GetNetworkSettings(network); err != nil {
+		e.l.Warnw("failed to retrieve network settings - using default rate limits",
+			"network", network,
+			"error", err)
+	} else if s != nil {
+		limits = s.RateLimits.Feature(feature, e.limits)
+	}
+
+	// check user allowance first, so that users who have exceeded their own
+	// limit cannot drain the allowance of the rest of the network
+	var decisions = make([]decision, 0, 2)
+	if user != "" && limits.User.Enabled() {
+		var d = e.limiter.take(fmt.Sprintf("user:%s/%s/%s", network, feature, user), limits.User)
+		decisions = append(decisions, d)
+		if !d.allowed {
+			return e.rejectRateLimited(w, r, network, feature, d)
+		}
+	}
+	if limits.Network.Enabled() {
+		decisions = append(decisions,
+			e.limiter.take(fmt.Sprintf("network:%s/%s", network, feature), limits.Network))
+	}
+	if len(decisions) == 0 {
+		return true
+	}
+
+	var d = strictest(decisions...)
+	if !d.allowed {
+		return e.rejectRateLimited(w, r, network, feature, d)
+	}
+	setRateLimitHeaders(w, d)
+	return true
+}
+
+func (e *Engine) rejectRateLimited(w http.ResponseWriter, r *http.Request,
+	network, feature string, d decision) bool {
+	e.metrics.rateLimited.WithLabelValues(network, feature).Inc()
+	setRateLimitHeaders(w, d)
+	res.R(w, r, res.Err("rate limit exceeded", http.StatusTooManyRequests))
+	return false
+}
+
+// setRateLimitHeaders writes the given decision as RateLimit-* headers, and a
+// Retry-After header if the request was not allowed
+func setRateLimitHeaders(w http.ResponseWriter, d decision) {
+	var h = w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(d.limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(d.remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.reset)))
+	if !d.allowed {
+		h.Set("Retry-After", strconv.Itoa(ceilSeconds(d.retry)))
+	}
+}
+
+// strictest combines decisions - the request is only allowed if all decisions
+// allow it, and the most restrictive allowance is reported
+func strictest(decisions ...decision) decision {
+	var d = decisions[0]
+	for _, next := range decisions[1:] {
+		if !next.allowed {
+			if d.allowed || next.retry > d.retry {
+				d = next
+			}
+		} else if d.allowed && next.remaining < d.remaining {
+			d = next
+		}
+	}
+	return d
+}
+
+func burst(limit config.RateLimit) int {
+	if limit.Burst < 1 {
+		return 1
+	}
+	return limit.Burst
+}
+
+func seconds(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
+
+func ceilSeconds(d time.Duration) int { return int(math.Ceil(d.Seconds())) }
@@ -0,0 +1,163 @@
+package delegator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+)
+
+func Test_bucket_take(t *testing.T) {
+	var (
+		start = time.Now()
+		limit = config.RateLimit{Rate: 1, Burst: 2}
+		b     = newBucket(limit, start)
+	)
+
+	// burst should be allowed
+	for i := 0; i < 2; i++ {
+		if d := b.take(start, limit); !d.allowed {
+			t.Fatalf("request %d unexpectedly rejected", i)
+		}
+	}
+
+	// bucket is now empty
+	d := b.take(start, limit)
+	if d.allowed {
+		t.Fatal("request unexpectedly allowed")
+	}
+	if d.limit != 2 || d.remaining != 0 || d.retry != time.Second || d.reset != 2*time.Second {
+		t.Errorf("unexpected decision %+v", d)
+	}
+
+	// bucket should refill over time
+	if d := b.take(start.Add(time.Second), limit); !d.allowed {
+		t.Error("request unexpectedly rejected after refill")
+	}
+
+	// bucket should adjust to new limits
+	var higher = config.RateLimit{Rate: 10, Burst: 20}
+	if d := b.take(start.Add(time.Minute), higher); !d.allowed || d.limit != 20 || d.remaining != 19 {
+		t.Errorf("unexpected decision after limit change %+v", d)
+	}
+}
+
+func Test_limiter_bounded(t *testing.T) {
+	l, err := newLimiter(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var limit = config.RateLimit{Rate: 1, Burst: 1}
+	for _, key := range []string{"a", "b", "c"} {
+		l.take(key, limit)
+	}
+	if l.Size() != 2 {
+		t.Errorf("expected 2 tracked buckets, found %d", l.Size())
+	}
+
+	// shared state - second request for same key should be rejected
+	if d := l.take("c", limit); d.allowed {
+		t.Error("expected request to be rejected")
+	}
+	if _, err := newLimiter(0); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
+
+func Test_strictest(t *testing.T) {
+	var (
+		allowedMany = decision{allowed: true, remaining: 10}
+		allowedFew  = decision{allowed: true, remaining: 1}
+		rejectShort = decision{retry: time.Second}
+		rejectLong  = decision{retry: time.Minute}
+	)
+	tests := []struct {
+		name string
+		in   []decision
+		want decision
+	}{
+		{"single", []decision{allowedMany}, allowedMany},
+		{"fewest remaining", []decision{allowedMany, allowedFew}, allowedFew},
+		{"rejection wins", []decision{allowedFew, rejectShort}, rejectShort},
+		{"longest retry", []decision{rejectShort, allowedFew, rejectLong}, rejectLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strictest(tt.in...); got != tt.want {
+				t.Errorf("strictest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_rateLimit(t *testing.T) {
+	var defaults = config.RateLimits{
+		API: config.FeatureRateLimits{
+			Network: config.RateLimit{Rate: 1, Burst: 3},
+			User:    config.RateLimit{Rate: 1, Burst: 1},
+		},
+	}
+	type fields struct {
+		settings    *store.NetworkSettings
+		settingsErr error
+	}
+	type args struct {
+		feature string
+		users   []string
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		args         args
+		wantRejected int
+	}{
+		{"swarm is not limited",
+			fields{nil, nil}, args{"swarm", []string{"", "", "", ""}}, 0},
+		{"gateway has no default limit",
+			fields{nil, nil}, args{"gateway", []string{"", "", "", ""}}, 0},
+		{"network limit",
+			fields{nil, nil}, args{"api", []string{"a", "b", "c", "d"}}, 1},
+		{"user limit",
+			fields{nil, nil}, args{"api", []string{"a", "a", "b"}}, 1},
+		{"settings error uses defaults",
+			fields{nil, errors.New("oh no")}, args{"api", []string{"a", "a"}}, 1},
+		{"override",
+			fields{&store.NetworkSettings{RateLimits: store.RateLimits{
+				"gateway": {Network: config.RateLimit{Rate: 1, Burst: 1}},
+			}}, nil}, args{"gateway", []string{"", ""}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				settings = &smock.FakeSettings{}
+				e        = newTestEngine(t, EngineOpts{RateLimits: defaults}, nil, Stores{Settings: settings})
+				rejected int
+			)
+			settings.GetNetworkSettingsReturns(tt.fields.settings, tt.fields.settingsErr)
+
+			for _, user := range tt.args.users {
+				var rec = httptest.NewRecorder()
+				if !e.rateLimit(rec, httptest.NewRequest("GET", "/", nil), "bobheadxi", tt.args.feature, user) {
+					rejected++
+					if rec.Code != http.StatusTooManyRequests {
+						t.Errorf("expected status %d, found %d", http.StatusTooManyRequests, rec.Code)
+					}
+					if rec.Header().Get("Retry-After") == "" {
+						t.Error("expected Retry-After header")
+					}
+				}
+				if tt.args.feature == "api" && rec.Header().Get("RateLimit-Limit") == "" {
+					t.Error("expected RateLimit-Limit header")
+				}
+			}
+			if rejected != tt.wantRejected {
+				t.Errorf("expected %d rejected requests, found %d", tt.wantRejected, rejected)
+			}
+		})
+	}
+}
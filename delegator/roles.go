@@ -0,0 +1,110 @@
+package delegator
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/RTradeLtd/database/v2/models"
+	"github.com/bobheadxi/res"
+
+	"github.com/RTradeLtd/Nexus/store"
+	"github.com/RTradeLtd/Nexus/tracing"
+)
+
+// Nexus features that are granted by role
+const (
+	featureStatus      = "status"
+	featureDiagnostics = "diagnostics"
+	featureSwarmKey    = "swarm_key"
+)
+
+// roleFeatures declares the Nexus features each role grants access to
+var roleFeatures = map[string][]string{
+	store.RoleViewer: {featureStatus},
+	store.RoleWriter: {featureStatus, featureDiagnostics},
+	store.RoleAdmin:  {featureStatus, featureDiagnostics, featureSwarmKey},
+}
+
+// roleAllowsCommand checks if the role permits given IPFS API command. Viewers
+// are limited to the commands permitted by read-only API tokens.
+func roleAllowsCommand(role, command string) bool {
+	switch role {
+	case store.RoleWriter, store.RoleAdmin:
+		return true
+	case store.RoleViewer:
+		return scopesAllow(store.Scopes{store.ScopeReadOnly}, command)
+	default:
+		return false
+	}
+}
+
+// roleAllowsFeature checks if the role grants access to given Nexus feature
+func roleAllowsFeature(role, feature string) bool {
+	for _, f := range roleFeatures[role] {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeUser authenticates the requesting user, and checks that they are a
+// member of the network - either listed by Temporal, or assigned a role in
+// Nexus. The user's role and the network are returned. If the user is not
+// authorized, an error response is written and ok is false.
+func (e *Engine) authorizeUser(w http.ResponseWriter, r *http.Request, network string) (
+	user, role string, entry *models.HostedNetwork, ok bool) {
+	_, span := tracing.Start(r.Context(), "delegator.verify_jwt")
+	user, err := getUserFromJWT(r, e.auth)
+	tracing.Finish(span, err)
+	if err != nil {
+		e.metrics.authFailure(network, authFailureReason(err))
+		res.R(w, r, res.ErrUnauthorized(err.Error()))
+		return "", "", nil, false
+	}
+	entry, err = e.lookupNetwork(r.Context(), network)
+	if err != nil {
+		e.metrics.authFailure(network, reasonUnknownNetwork)
+		res.R(w, r, res.ErrNotFound("failed to find network"))
+		return "", "", nil, false
+	}
+
+	var roles store.Roles
+	if s, err := e.networks.GetNetworkSettings(network); err != nil {
+		e.l.Warnw("failed to retrieve network settings - using default roles",
+			"network", network,
+			"error", err)
+	} else if s != nil {
+		roles = s.Roles
+	}
+	_, found := roles[user]
+	for _, authorized := range entry.Users {
+		if user == authorized {
+			found = true
+		}
+	}
+	if !found {
+		e.metrics.authFailure(network, reasonUnauthorized)
+		res.R(w, r, res.ErrForbidden("user not authorized"))
+		return "", "", nil, false
+	}
+	return user, roles.Role(user, e.defaultRole), entry, true
+}
+
+// authorizeFeature checks that the requesting user's role grants access to
+// given Nexus feature. If the user is not authorized, an error response is
+// written and ok is false.
+func (e *Engine) authorizeFeature(w http.ResponseWriter, r *http.Request, network, feature string) (
+	entry *models.HostedNetwork, ok bool) {
+	_, role, entry, ok := e.authorizeUser(w, r, network)
+	if !ok {
+		return nil, false
+	}
+	if !roleAllowsFeature(role, feature) {
+		e.metrics.authFailure(network, reasonInsufficientRole)
+		res.R(w, r, res.ErrForbidden(
+			fmt.Sprintf("role '%s' does not permit access to %s", role, feature)))
+		return nil, false
+	}
+	return entry, true
+}
@@ -0,0 +1,161 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func Test_roleAllowsCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		command string
+		want    bool
+	}{
+		{"unknown role", "owner", "cat", false},
+		{"viewer read", store.RoleViewer, "cat", true},
+		{"viewer write", store.RoleViewer, "add", false},
+		{"writer write", store.RoleWriter, "add", true},
+		{"admin write", store.RoleAdmin, "pin/add", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roleAllowsCommand(tt.role, tt.command); got != tt.want {
+				t.Errorf("roleAllowsCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_roleAllowsFeature(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		feature string
+		want    bool
+	}{
+		{"unknown role", "owner", featureStatus, false},
+		{"viewer status", store.RoleViewer, featureStatus, true},
+		{"viewer diagnostics", store.RoleViewer, featureDiagnostics, false},
+		{"writer diagnostics", store.RoleWriter, featureDiagnostics, true},
+		{"writer swarm key", store.RoleWriter, featureSwarmKey, false},
+		{"admin swarm key", store.RoleAdmin, featureSwarmKey, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roleAllowsFeature(tt.role, tt.feature); got != tt.want {
+				t.Errorf("roleAllowsFeature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_roles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-roles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "swarm.key"), []byte("disk key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	type fields struct {
+		network     *models.HostedNetwork
+		settings    *store.NetworkSettings
+		settingsErr error
+	}
+	type args struct {
+		handler func(e *Engine) http.HandlerFunc
+		feature string
+		path    string
+	}
+	var (
+		redirect    = func(e *Engine) http.HandlerFunc { return e.Redirect }
+		status      = func(e *Engine) http.HandlerFunc { return e.NetworkStatus }
+		diagnostics = func(e *Engine) http.HandlerFunc { return e.NetworkDiagnostics }
+		swarmKey    = func(e *Engine) http.HandlerFunc { return e.NetworkSwarmKey }
+		member      = &models.HostedNetwork{Users: []string{"testuser"}}
+		viewer      = &store.NetworkSettings{Roles: store.Roles{"testuser": store.RoleViewer}}
+		admin       = &store.NetworkSettings{Roles: store.Roles{"testuser": store.RoleAdmin}}
+	)
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantCode int
+		wantBody string
+	}{
+		{"not a member",
+			fields{&models.HostedNetwork{}, nil, nil},
+			args{status, "", "/"}, http.StatusForbidden, ""},
+		{"member by role",
+			fields{&models.HostedNetwork{}, viewer, nil},
+			args{status, "", "/"}, http.StatusOK, ""},
+		{"settings error uses default role",
+			fields{member, nil, errors.New("oh no")},
+			args{diagnostics, "", "/"}, http.StatusOK, ""},
+		{"viewer read command",
+			fields{member, viewer, nil},
+			args{redirect, "api", "/api/v0/cat"}, http.StatusBadGateway, ""}, // badgateway because proxy points to nothing
+		{"viewer write command",
+			fields{member, viewer, nil},
+			args{redirect, "api", "/api/v0/add"}, http.StatusForbidden, ""},
+		{"viewer diagnostics",
+			fields{member, viewer, nil},
+			args{diagnostics, "", "/"}, http.StatusForbidden, ""},
+		{"default role swarm key",
+			fields{member, nil, nil},
+			args{swarmKey, "", "/"}, http.StatusForbidden, ""},
+		{"admin swarm key from database",
+			fields{&models.HostedNetwork{Users: []string{"testuser"}, SwarmKey: "db key"}, admin, nil},
+			args{swarmKey, "", "/"}, http.StatusOK, "db key"},
+		{"admin swarm key from disk",
+			fields{member, admin, nil},
+			args{swarmKey, "", "/"}, http.StatusOK, "disk key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				networks = &mock.FakePrivateNetworks{}
+				settings = &smock.FakeSettings{}
+				e        = newTestEngine(t, EngineOpts{DevMode: true, RequestTimeout: time.Second, JWTKey: defaultTestKey}, networks, Stores{Settings: settings})
+				node     = &ipfs.NodeInfo{NetworkID: "test", DataDir: dir, Ports: ipfs.NodePorts{API: "5000"}}
+			)
+			networks.GetNetworkByNameReturns(tt.fields.network, nil)
+			settings.GetNetworkSettingsReturns(tt.fields.settings, tt.fields.settingsErr)
+
+			var ctx = context.WithValue(context.Background(), keyNetwork, node)
+			if tt.args.feature != "" {
+				ctx = context.WithValue(ctx, keyFeature, tt.args.feature)
+			}
+			var (
+				req = httptest.NewRequest("GET", tt.args.path, nil).WithContext(ctx)
+				rec = httptest.NewRecorder()
+			)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", validToken))
+			tt.args.handler(e)(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status '%d', found '%d'", tt.wantCode, rec.Code)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("expected body '%s', found '%s'", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package delegator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// Stores provides the Nexus-managed state of networks. All stores are required.
+type Stores struct {
+	Settings store.Settings
+	Tokens   store.Tokens
+	Denylist store.Denylist
+	Domains  store.Domains
+	Usage    store.Usage
+}
+
+// validate checks that all stores are provided
+func (s Stores) validate() error {
+	var missing []string
+	for name, set := range map[string]bool{
+		"settings": s.Settings != nil,
+		"tokens":   s.Tokens != nil,
+		"denylist": s.Denylist != nil,
+		"domains":  s.Domains != nil,
+		"usage":    s.Usage != nil,
+	} {
+		if !set {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing stores: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
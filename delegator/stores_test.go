@@ -0,0 +1,80 @@
+package delegator
+
+import (
+	"testing"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// withEmpty replaces stores that are not provided with empty stores
+func (s Stores) withEmpty() Stores {
+	if s.Settings == nil {
+		s.Settings = emptyStore{}
+	}
+	if s.Tokens == nil {
+		s.Tokens = emptyStore{}
+	}
+	if s.Denylist == nil {
+		s.Denylist = emptyStore{}
+	}
+	if s.Domains == nil {
+		s.Domains = emptyStore{}
+	}
+	if s.Usage == nil {
+		s.Usage = emptyStore{}
+	}
+	return s
+}
+
+// emptyStore is a store that holds nothing, used in tests in place of stores
+// that are not relevant
+type emptyStore struct{}
+
+func (emptyStore) GetNetworkSettings(network string) (*store.NetworkSettings, error) {
+	return &store.NetworkSettings{Network: network}, nil
+}
+func (emptyStore) SaveNetworkSettings(*store.NetworkSettings) error { return nil }
+
+func (emptyStore) CreateToken(*store.APIToken) error            { return nil }
+func (emptyStore) FindToken(string) (*store.APIToken, error)    { return nil, nil }
+func (emptyStore) ListTokens(string) ([]*store.APIToken, error) { return nil, nil }
+func (emptyStore) RevokeToken(string, string) error             { return nil }
+
+func (emptyStore) AddDenylistEntries([]*store.DenylistEntry) error            { return nil }
+func (emptyStore) RemoveDenylistEntries(string, []string) (int64, error)      { return 0, nil }
+func (emptyStore) ListDenylistEntries(string) ([]*store.DenylistEntry, error) { return nil, nil }
+func (emptyStore) AllDenylistEntries() ([]*store.DenylistEntry, error)        { return nil, nil }
+
+func (emptyStore) AddDomain(*store.CustomDomain) error               { return nil }
+func (emptyStore) GetDomain(string) (*store.CustomDomain, error)     { return nil, nil }
+func (emptyStore) ListDomains(string) ([]*store.CustomDomain, error) { return nil, nil }
+func (emptyStore) AllDomains() ([]*store.CustomDomain, error)        { return nil, nil }
+func (emptyStore) VerifyDomain(string) error                         { return nil }
+func (emptyStore) RemoveDomain(string, string) error                 { return nil }
+
+func (emptyStore) AddUsage([]*store.UsageRecord) error                       { return nil }
+func (emptyStore) QueryUsage(store.UsageQuery) ([]*store.UsageRecord, error) { return nil, nil }
+
+func TestStores_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		stores  Stores
+		wantErr string
+	}{
+		{"all provided", Stores{}.withEmpty(), ""},
+		{"none provided", Stores{}, "missing stores: denylist, domains, settings, tokens, usage"},
+		{"usage missing", Stores{Settings: emptyStore{}, Tokens: emptyStore{},
+			Denylist: emptyStore{}, Domains: emptyStore{}}, "missing stores: usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.stores.validate()
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err.Error() != tt.wantErr {
+				t.Errorf("validate() error = %s, want %s", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
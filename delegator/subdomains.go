@@ -0,0 +1,166 @@
+package delegator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/bobheadxi/res"
+	cid "github.com/ipfs/go-cid"
+	mbase "github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// maxLabelLength is the maximum length of a DNS label
+const maxLabelLength = 63
+
+// ContentSubdomainContext creates a handler that injects relevant network and
+// content context into requests to subdomain gateway hosts, of the form
+// "<cid>.ipfs.<network>.gateway.<domain>" or
+// "<name>.ipns.<network>.gateway.<domain>". Each CID or IPNS name is served
+// from its own origin, so content cannot access data of other content.
+func (e *Engine) ContentSubdomainContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var host = r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		label, namespace, network, ok := e.contentHost(host)
+		if !ok {
+			res.R(w, r, res.ErrNotFound(http.StatusText(http.StatusNotFound)))
+			return
+		}
+		root, err := rootFromLabel(namespace, label)
+		if err != nil {
+			res.R(w, r, res.ErrBadRequest(err.Error()))
+			return
+		}
+
+		n, err := e.reg.Get(network)
+		if err != nil {
+			res.R(w, r, res.ErrNotFound(err.Error()))
+			return
+		}
+
+		var ctx = context.WithValue(r.Context(), keyFeature, "gateway")
+		ctx = context.WithValue(ctx, keyNetwork, &n)
+		ctx = context.WithValue(ctx, keyContentRoot, "/"+namespace+"/"+root)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// contentHost splits a subdomain gateway host into its content label,
+// namespace, and network
+func (e *Engine) contentHost(host string) (label, namespace, network string, ok bool) {
+	var suffix = ".gateway." + e.domain
+	if !strings.HasSuffix(host, suffix) {
+		return "", "", "", false
+	}
+	var parts = strings.Split(strings.TrimSuffix(host, suffix), ".")
+	if len(parts) != 3 || (parts[1] != "ipfs" && parts[1] != "ipns") {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// subdomainLocation returns the subdomain gateway URL of a path-style gateway
+// request, such as "/ipfs/<cid>/<path>" - requests for content with no valid
+// subdomain form are not redirected
+func (e *Engine) subdomainLocation(r *http.Request, network string) (string, bool) {
+	var namespace string
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/ipfs/"):
+		namespace = "ipfs"
+	case strings.HasPrefix(r.URL.Path, "/ipns/"):
+		namespace = "ipns"
+	default:
+		return "", false
+	}
+	var (
+		parts = strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"+namespace+"/"), "/", 2)
+		rest  = "/"
+	)
+	if len(parts) == 2 {
+		rest += parts[1]
+	}
+	label, ok := labelFromRoot(namespace, parts[0])
+	if !ok {
+		return "", false
+	}
+
+	var host = label + "." + namespace + "." + network + ".gateway." + e.domain
+	if _, port, err := net.SplitHostPort(r.Host); err == nil {
+		host = net.JoinHostPort(host, port)
+	}
+	var scheme = "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	var location = scheme + "://" + host + rest
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	return location, true
+}
+
+// labelFromRoot converts the root of a content path to its subdomain form.
+// CIDs are converted to base32 CIDv1, and IPNS keys to base36 CIDv1, since
+// hostnames are case-insensitive. DNSLink names are inlined by replacing '-'
+// with '--' and '.' with '-'.
+func labelFromRoot(namespace, root string) (string, bool) {
+	var label string
+	if id, err := cid.Decode(root); err == nil {
+		if namespace == "ipns" {
+			label, err = cid.NewCidV1(cid.Libp2pKey, id.Hash()).StringOfBase(mbase.Base36)
+			if err != nil {
+				return "", false
+			}
+		} else {
+			label = cid.NewCidV1(id.Type(), id.Hash()).String()
+		}
+	} else if m, err := mh.FromB58String(root); err == nil && namespace == "ipns" {
+		label, err = cid.NewCidV1(cid.Libp2pKey, m).StringOfBase(mbase.Base36)
+		if err != nil {
+			return "", false
+		}
+	} else if namespace == "ipns" && strings.Contains(root, ".") {
+		label = strings.Replace(strings.Replace(root, "-", "--", -1), ".", "-", -1)
+	} else {
+		return "", false
+	}
+	if len(label) > maxLabelLength {
+		return "", false
+	}
+	return strings.ToLower(label), true
+}
+
+// rootFromLabel converts a subdomain label to the root of a content path.
+// IPNS keys are converted to base58 peer IDs, which all nodes understand.
+func rootFromLabel(namespace, label string) (string, error) {
+	if namespace == "ipfs" {
+		id, err := cid.Decode(label)
+		if err != nil {
+			return "", fmt.Errorf("invalid CID '%s'", label)
+		}
+		return id.String(), nil
+	}
+	if id, err := cid.Decode(label); err == nil {
+		return id.Hash().B58String(), nil
+	}
+	// restore inlined DNSLink names
+	var name strings.Builder
+	for i := 0; i < len(label); i++ {
+		switch {
+		case label[i] == '-' && i+1 < len(label) && label[i+1] == '-':
+			name.WriteByte('-')
+			i++
+		case label[i] == '-':
+			name.WriteByte('.')
+		default:
+			name.WriteByte(label[i])
+		}
+	}
+	return name.String(), nil
+}
@@ -0,0 +1,134 @@
+package delegator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+const testPeerKey = "k2k4r8jl0yz8qjgqbmc2cdu5hkqek5rj6flgnlkyywynci20j0iuyfuj"
+
+func TestLabelFromRoot(t *testing.T) {
+	tests := []struct {
+		namespace string
+		root      string
+		want      string
+		wantOK    bool
+	}{
+		{"ipfs", testCID, testCIDv1, true},
+		{"ipfs", testCIDv1, testCIDv1, true},
+		{"ipfs", "asdf", "", false},
+		{"ipfs", "bafkrgqhhyivzstcz3hhswshfjgy6ertgmnqeleynhwt4dlfsthi4hn7zgh4uvlsb5xncykzapi3ocd4lzogukir6ksdy6wzrnz6ohnv4aglcs", "", false},
+		{"ipns", "QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN", testPeerKey, true},
+		{"ipns", testPeerKey, testPeerKey, true},
+		{"ipns", "en.wikipedia-on-ipfs.org", "en-wikipedia--on--ipfs-org", true},
+		{"ipns", "localhost", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace+"/"+tt.root, func(t *testing.T) {
+			got, ok := labelFromRoot(tt.namespace, tt.root)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("labelFromRoot() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRootFromLabel(t *testing.T) {
+	tests := []struct {
+		namespace string
+		label     string
+		want      string
+		wantErr   bool
+	}{
+		{"ipfs", testCIDv1, testCIDv1, false},
+		{"ipfs", "asdf", "", true},
+		{"ipns", testPeerKey, "QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN", false},
+		{"ipns", "en-wikipedia--on--ipfs-org", "en.wikipedia-on-ipfs.org", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace+"/"+tt.label, func(t *testing.T) {
+			got, err := rootFromLabel(tt.namespace, tt.label)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("rootFromLabel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("rootFromLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_subdomainGateway(t *testing.T) {
+	tests := []struct {
+		name         string
+		host         string
+		path         string
+		wantCode     int
+		wantLocation string
+		wantPath     string
+	}{
+		{"path-style redirect", "test.gateway.domain.com", "/ipfs/" + testCID + "/readme?a=b",
+			http.StatusMovedPermanently, "http://" + testCIDv1 + ".ipfs.test.gateway.domain.com/readme?a=b", ""},
+		{"path-style ipns redirect", "test.gateway.domain.com:8080", "/ipns/en.wikipedia-on-ipfs.org",
+			http.StatusMovedPermanently, "http://en-wikipedia--on--ipfs-org.ipns.test.gateway.domain.com:8080/", ""},
+		{"path-style redirect from content host", testCIDv1 + ".ipfs.test.gateway.domain.com", "/ipfs/" + testCIDv1 + "/a",
+			http.StatusMovedPermanently, "http://" + testCIDv1 + ".ipfs.test.gateway.domain.com/a", ""},
+		{"content host", testCIDv1 + ".ipfs.test.gateway.domain.com", "/readme",
+			http.StatusOK, "", "/ipfs/" + testCIDv1 + "/readme"},
+		{"ipns content host", "en-wikipedia--on--ipfs-org.ipns.test.gateway.domain.com", "/",
+			http.StatusOK, "", "/ipns/en.wikipedia-on-ipfs.org/"},
+		{"unknown network", testCIDv1 + ".ipfs.other.gateway.domain.com", "/",
+			http.StatusNotFound, "", ""},
+		{"invalid CID", "asdf.ipfs.test.gateway.domain.com", "/",
+			http.StatusBadRequest, "", ""},
+		{"unknown host", "test.blah.domain.com", "/",
+			http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gateway = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(r.URL.Path))
+				}))
+				port     = gateway.URL[strings.LastIndex(gateway.URL, ":")+1:]
+				networks = &mock.FakePrivateNetworks{}
+				e        = newTestEngine(t, EngineOpts{Domain: "domain.com", SubdomainGateway: true}, networks, Stores{}, &ipfs.NodeInfo{
+					NetworkID: "test",
+					Ports:     ipfs.NodePorts{Gateway: port},
+				})
+				req = httptest.NewRequest("GET", tt.path, nil)
+				rec = httptest.NewRecorder()
+			)
+			req.Host = tt.host
+			defer gateway.Close()
+			e.direct = true
+			networks.GetNetworkByNameReturns(&models.HostedNetwork{GatewayPublic: true}, nil)
+
+			var handler http.Handler = http.HandlerFunc(e.Redirect)
+			if strings.HasPrefix(tt.host, "test.gateway.") {
+				handler = e.NetworkAndFeatureSubdomainContext(handler)
+			} else {
+				handler = e.ContentSubdomainContext(handler)
+			}
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d (%s)", tt.wantCode, rec.Code, rec.Body)
+			}
+			if location := rec.Header().Get("Location"); location != tt.wantLocation {
+				t.Errorf("expected location '%s', got '%s'", tt.wantLocation, location)
+			}
+			if tt.wantPath != "" && rec.Body.String() != tt.wantPath {
+				t.Errorf("expected node to be requested at '%s', got '%s'", tt.wantPath, rec.Body)
+			}
+		})
+	}
+}
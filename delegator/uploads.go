@@ -0,0 +1,128 @@
+package delegator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bobheadxi/res"
+)
+
+// streamingCommands lists API commands that stream uploads to the node, which
+// are bounded by how long they go without transferring data rather than by
+// how long they take in total
+var streamingCommands = []string{"add", "dag/import"}
+
+// isStreamingUpload checks if the request is a streaming upload
+func isStreamingUpload(feature, path string) bool {
+	return feature == "api" && matchesAnyCommand(apiCommand(path), streamingCommands)
+}
+
+// limitBody bounds the size of the request body according to the network's
+// body limits. If the request is rejected, a response is written and false is
+// returned.
+func (e *Engine) limitBody(w http.ResponseWriter, r *http.Request, network, feature string) bool {
+	var limit = e.bodyLimits.Feature(feature)
+	if s, err := e.networks.GetNetworkSettings(network); err != nil {
+		e.l.Warnw("failed to retrieve network settings - using default body limits",
+			"network", network,
+			"error", err)
+	} else if s != nil {
+		limit = s.BodyLimits.Feature(feature, e.bodyLimits)
+	}
+	if r.Body == nil {
+		r.Body = http.NoBody
+	}
+	if limit > 0 {
+		var max = limit << 20
+		if r.ContentLength > max {
+			res.R(w, r, res.Err(fmt.Sprintf("request body exceeds limit of %dMB", limit),
+				http.StatusRequestEntityTooLarge))
+			return false
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, max)
+	}
+	return true
+}
+
+// withTimeout bounds how long a proxied request may take. Streaming uploads
+// are cancelled once they stop transferring data, gateway requests once the
+// gateway timeout passes, and other requests once the request timeout passes.
+// Swarm connections are long-lived and not bounded. The returned function
+// must be called once the request completes.
+func (e *Engine) withTimeout(w http.ResponseWriter, r *http.Request, feature string) (http.ResponseWriter, *http.Request, func()) {
+	switch {
+	case feature == "swarm":
+		return w, r, func() {}
+	case isStreamingUpload(feature, r.URL.Path):
+		return e.withIdleTimeout(w, r, e.timeouts.streamIdle)
+	case feature == "gateway":
+		ctx, cancel := context.WithTimeout(r.Context(), e.timeouts.gateway)
+		return w, r.WithContext(ctx), cancel
+	default:
+		ctx, cancel := context.WithTimeout(r.Context(), e.timeout)
+		return w, r.WithContext(ctx), cancel
+	}
+}
+
+// withIdleTimeout cancels a proxied request once it has not transferred data
+// for given timeout. The returned function must be called once the request
+// completes.
+func (e *Engine) withIdleTimeout(w http.ResponseWriter, r *http.Request, timeout time.Duration) (http.ResponseWriter, *http.Request, func()) {
+	ctx, cancel := context.WithCancel(r.Context())
+	var idle = &idleTimer{timeout: timeout, timer: time.AfterFunc(timeout, cancel)}
+	if r.Body != nil {
+		r.Body = &idleReader{ReadCloser: r.Body, idle: idle}
+	}
+	return &idleWriter{ResponseWriter: w, idle: idle}, r.WithContext(ctx), func() {
+		idle.timer.Stop()
+		cancel()
+	}
+}
+
+// idleTimer cancels a request once it has been idle for its timeout
+type idleTimer struct {
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// touch records activity, resetting the timer
+func (i *idleTimer) touch() { i.timer.Reset(i.timeout) }
+
+// idleReader records activity whenever data is read
+type idleReader struct {
+	io.ReadCloser
+	idle *idleTimer
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.idle.touch()
+	}
+	return n, err
+}
+
+// idleWriter records activity whenever data is written
+type idleWriter struct {
+	http.ResponseWriter
+	idle *idleTimer
+}
+
+func (w *idleWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	if n > 0 {
+		w.idle.touch()
+	}
+	return n, err
+}
+
+// Flush allows streamed responses, such as upload progress, to be flushed to
+// the client
+func (w *idleWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
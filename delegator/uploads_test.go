@@ -0,0 +1,195 @@
+package delegator
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bobheadxi/res"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+)
+
+func Test_isStreamingUpload(t *testing.T) {
+	tests := []struct {
+		name    string
+		feature string
+		path    string
+		want    bool
+	}{
+		{"add", "api", "/api/v0/add", true},
+		{"indirect add", "api", "/network/test/api/api/v0/add", true},
+		{"dag import", "api", "/api/v0/dag/import", true},
+		{"dag get", "api", "/api/v0/dag/get", false},
+		{"cat", "api", "/api/v0/cat", false},
+		{"gateway", "gateway", "/api/v0/add", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStreamingUpload(tt.feature, tt.path); got != tt.want {
+				t.Errorf("isStreamingUpload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_limitBody(t *testing.T) {
+	var defaults = config.BodyLimits{API: 1, Gateway: 1}
+	type fields struct {
+		settings    *store.NetworkSettings
+		settingsErr error
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		feature  string
+		length   int
+		wantCode int
+	}{
+		{"no body", fields{nil, nil}, "api", 0, 0},
+		{"within limit", fields{nil, nil}, "api", 1 << 20, 0},
+		{"exceeds limit", fields{nil, nil}, "api", 1<<20 + 1, http.StatusRequestEntityTooLarge},
+		{"settings error uses defaults", fields{nil, errors.New("oh no")}, "gateway", 1<<20 + 1, http.StatusRequestEntityTooLarge},
+		{"override allows",
+			fields{&store.NetworkSettings{BodyLimits: store.BodyLimits{"api": 2}}, nil},
+			"api", 1<<20 + 1, 0},
+		{"swarm not limited", fields{nil, nil}, "swarm", 1<<20 + 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var settings = &smock.FakeSettings{}
+			settings.GetNetworkSettingsReturns(tt.fields.settings, tt.fields.settingsErr)
+			var e = newTestEngine(t, EngineOpts{BodyLimits: defaults}, nil, Stores{Settings: settings})
+
+			var rec = httptest.NewRecorder()
+			var req = httptest.NewRequest("POST", "/api/v0/add", strings.NewReader(strings.Repeat("a", tt.length)))
+			ok := e.limitBody(rec, req, "bobheadxi", tt.feature)
+			if ok != (tt.wantCode == 0) {
+				t.Fatalf("Engine.limitBody() = %v, want %v", ok, tt.wantCode == 0)
+			}
+			if !ok {
+				if rec.Code != tt.wantCode {
+					t.Errorf("expected status %d, found %d", tt.wantCode, rec.Code)
+				}
+				return
+			}
+			if n, err := io.Copy(ioutil.Discard, req.Body); err != nil || n != int64(tt.length) {
+				t.Errorf("expected to read %d bytes, read %d (%v)", tt.length, n, err)
+			}
+		})
+	}
+}
+
+func TestEngine_limitBody_streamed(t *testing.T) {
+	var upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	var e = newTestEngine(t, EngineOpts{BodyLimits: config.BodyLimits{API: 1}}, nil, Stores{})
+	var b = e.breakers.get("bobheadxi")
+	var proxy = newProxy("api", target, e.l, e.metrics, true, b, 0)
+
+	// bodies of unknown length are only rejected once the limit is exceeded
+	var rec = httptest.NewRecorder()
+	var req = httptest.NewRequest("POST", "/api/v0/add",
+		ioutil.NopCloser(strings.NewReader(strings.Repeat("a", 2<<20))))
+	req.ContentLength = -1
+	if !e.limitBody(rec, req, "bobheadxi", "api") {
+		t.Fatal("expected body of unknown length to be allowed")
+	}
+	proxy.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status %d, found %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+	var reason string
+	if _, err := res.Unmarshal(rec.Body, res.KV{Key: "reason", Value: &reason}); err != nil {
+		t.Fatal(err)
+	}
+	if reason != upstreamTooLarge {
+		t.Errorf("expected reason '%s', found '%s'", upstreamTooLarge, reason)
+	}
+	if b.failures != 0 {
+		t.Errorf("expected oversized body not to count as a node failure, found %d failures", b.failures)
+	}
+}
+
+func TestEngine_withTimeout(t *testing.T) {
+	var e = newTestEngine(t, EngineOpts{
+		RequestTimeout: time.Hour,
+		Timeouts:       config.Timeouts{GatewaySeconds: 60, StreamIdleSeconds: 60},
+	}, nil, Stores{})
+	e.timeouts.streamIdle = 250 * time.Millisecond
+
+	t.Run("swarm", func(t *testing.T) {
+		_, r, cancel := e.withTimeout(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), "swarm")
+		defer cancel()
+		if _, ok := r.Context().Deadline(); ok {
+			t.Error("expected no deadline for swarm connections")
+		}
+	})
+	t.Run("gateway", func(t *testing.T) {
+		_, r, cancel := e.withTimeout(httptest.NewRecorder(), httptest.NewRequest("GET", "/ipfs/"+testCID, nil), "gateway")
+		defer cancel()
+		if deadline, ok := r.Context().Deadline(); !ok || time.Until(deadline) > time.Minute {
+			t.Errorf("expected gateway deadline, found %v", deadline)
+		}
+	})
+	t.Run("api", func(t *testing.T) {
+		_, r, cancel := e.withTimeout(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v0/cat", nil), "api")
+		defer cancel()
+		if deadline, ok := r.Context().Deadline(); !ok || time.Until(deadline) < time.Minute {
+			t.Errorf("expected request deadline, found %v", deadline)
+		}
+	})
+	t.Run("streaming upload", func(t *testing.T) {
+		var pr, pw = io.Pipe()
+		var active = time.Now()
+		w, r, cancel := e.withTimeout(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v0/add", pr), "api")
+		defer cancel()
+		if _, ok := r.Context().Deadline(); ok {
+			t.Error("expected no absolute deadline for streaming uploads")
+		}
+
+		// activity keeps the request alive past the idle timeout - slow test
+		// runs may leave the request idle for longer than the timeout, so it
+		// is only an error for it to be cancelled sooner
+		go func() {
+			for i := 0; i < 10; i++ {
+				pw.Write([]byte("a"))
+				time.Sleep(50 * time.Millisecond)
+			}
+			w.Write([]byte("progress"))
+		}()
+		var buf = make([]byte, 1)
+		for i := 0; i < 10; i++ {
+			var reading = time.Now()
+			if _, err := r.Body.Read(buf); err != nil {
+				t.Fatal(err)
+			}
+			if r.Context().Err() != nil {
+				if time.Since(active) < e.timeouts.streamIdle {
+					t.Fatal("expected active upload not to be cancelled")
+				}
+				break
+			}
+			active = reading
+		}
+
+		// idle requests are cancelled
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+			t.Error("expected idle upload to be cancelled")
+		}
+	})
+}
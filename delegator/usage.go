@@ -0,0 +1,173 @@
+package delegator
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// usageKey identifies the hourly aggregate bytes are counted towards
+type usageKey struct {
+	hour    time.Time
+	network string
+	feature string
+	user    string
+}
+
+// usageCounts are the bytes sent to and by a network
+type usageCounts struct {
+	in  int64
+	out int64
+}
+
+// meter counts bytes transferred by network features for each user, and
+// periodically adds them to the hourly aggregates in the database
+type meter struct {
+	l     *zap.SugaredLogger
+	usage store.Usage
+	now   func() time.Time
+	// recorded, if set, is called with records once they have been added to
+	// the database
+	recorded func(records []*store.UsageRecord)
+	// recording is held while records are added to the database and passed
+	// to recorded, so that readers holding it see records as either both
+	// stored and reported, or neither
+	recording sync.RWMutex
+
+	mux     sync.Mutex
+	pending map[usageKey]usageCounts
+}
+
+func newMeter(l *zap.SugaredLogger, usage store.Usage) *meter {
+	return &meter{
+		l:       l,
+		usage:   usage,
+		now:     time.Now,
+		pending: make(map[usageKey]usageCounts),
+	}
+}
+
+// add counts bytes sent to and by given network's feature for given user
+func (m *meter) add(network, feature, user string, in, out int64) {
+	if in == 0 && out == 0 {
+		return
+	}
+	var k = usageKey{store.UsageHour(m.now()), network, feature, user}
+	m.mux.Lock()
+	var c = m.pending[k]
+	c.in += in
+	c.out += out
+	m.pending[k] = c
+	m.mux.Unlock()
+}
+
+// flush adds counted bytes to the aggregates in the database. Counts that
+// cannot be written are kept for the next flush.
+func (m *meter) flush() error {
+	m.mux.Lock()
+	var pending = m.pending
+	m.pending = make(map[usageKey]usageCounts)
+	m.mux.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var records = make([]*store.UsageRecord, 0, len(pending))
+	for k, c := range pending {
+		records = append(records, &store.UsageRecord{
+			Hour:     k.hour,
+			Network:  k.network,
+			Feature:  k.feature,
+			User:     k.user,
+			BytesIn:  c.in,
+			BytesOut: c.out,
+		})
+	}
+	m.recording.Lock()
+	defer m.recording.Unlock()
+	if err := m.usage.AddUsage(records); err != nil {
+		m.mux.Lock()
+		for k, c := range pending {
+			var current = m.pending[k]
+			current.in += c.in
+			current.out += c.out
+			m.pending[k] = current
+		}
+		m.mux.Unlock()
+		return err
+	}
+	if m.recorded != nil {
+		m.recorded(records)
+	}
+	return nil
+}
+
+// run flushes counted bytes at given interval, and once more when the given
+// context is cancelled
+func (m *meter) run(ctx context.Context, interval time.Duration) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := m.flush(); err != nil {
+				m.l.Errorw("failed to record usage - counts since last flush are lost",
+					"error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := m.flush(); err != nil {
+				m.l.Warnw("failed to record usage - retrying on next flush",
+					"error", err)
+			}
+		}
+	}
+}
+
+// meteredWriter counts the bytes written to a response body towards the usage
+// of the hour they are written in
+type meteredWriter struct {
+	http.ResponseWriter
+	meter   *meter
+	network string
+	feature string
+	user    string
+}
+
+func (w *meteredWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.meter.add(w.network, w.feature, w.user, 0, int64(n))
+	return n, err
+}
+
+// Flush allows streamed responses to be flushed to the client
+func (w *meteredWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// meteredReader counts the bytes read from a request body towards the usage
+// of the hour they are read in, as well as the uploaded bytes metric
+type meteredReader struct {
+	io.ReadCloser
+	meter    *meter
+	uploaded prometheus.Counter
+	network  string
+	feature  string
+	user     string
+}
+
+func (r *meteredReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.meter.add(r.network, r.feature, r.user, int64(n), 0)
+	r.uploaded.Add(float64(n))
+	return n, err
+}
@@ -0,0 +1,191 @@
+package delegator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/database/v2/models"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	"github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+// sortedRecords orders records by feature and hour
+func sortedRecords(records []*store.UsageRecord) []*store.UsageRecord {
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Feature != records[j].Feature {
+			return records[i].Feature < records[j].Feature
+		}
+		return records[i].Hour.Before(records[j].Hour)
+	})
+	return records
+}
+
+func Test_meter(t *testing.T) {
+	var (
+		usage = &smock.FakeUsage{}
+		m     = newMeter(zaptest.NewLogger(t).Sugar(), usage)
+		now   = time.Date(2019, 4, 1, 10, 30, 0, 0, time.UTC)
+	)
+	m.now = func() time.Time { return now }
+
+	// nothing to record
+	m.add("bobheadxi", "api", "", 0, 0)
+	if err := m.flush(); err != nil || usage.AddUsageCallCount() != 0 {
+		t.Fatalf("expected no usage to be recorded, got %d calls (%v)", usage.AddUsageCallCount(), err)
+	}
+
+	// counts are aggregated by hour, network, feature, and user
+	m.add("bobheadxi", "api", "bob", 10, 100)
+	m.add("bobheadxi", "api", "bob", 5, 50)
+	m.add("bobheadxi", "gateway", "", 1, 1000)
+
+	// counts are kept if they cannot be recorded
+	usage.AddUsageReturns(errors.New("oh no"))
+	if err := m.flush(); err == nil {
+		t.Fatal("expected error")
+	}
+	now = now.Add(time.Hour)
+	m.add("bobheadxi", "api", "bob", 1, 1)
+
+	usage.AddUsageReturns(nil)
+	if err := m.flush(); err != nil {
+		t.Fatal(err)
+	}
+	var records = sortedRecords(usage.AddUsageArgsForCall(1))
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	var hour = time.Date(2019, 4, 1, 10, 0, 0, 0, time.UTC)
+	for i, want := range []store.UsageRecord{
+		{Hour: hour, Network: "bobheadxi", Feature: "api", User: "bob", BytesIn: 15, BytesOut: 150},
+		{Hour: hour.Add(time.Hour), Network: "bobheadxi", Feature: "api", User: "bob", BytesIn: 1, BytesOut: 1},
+		{Hour: hour, Network: "bobheadxi", Feature: "gateway", BytesIn: 1, BytesOut: 1000},
+	} {
+		if *records[i] != want {
+			t.Errorf("expected record %+v, got %+v", want, *records[i])
+		}
+	}
+
+	// recorded counts are not recorded again
+	if err := m.flush(); err != nil || usage.AddUsageCallCount() != 2 {
+		t.Errorf("expected no further usage to be recorded, got %d calls (%v)", usage.AddUsageCallCount(), err)
+	}
+}
+
+func TestEngine_Redirect_usage(t *testing.T) {
+	var upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	var (
+		networks = &mock.FakePrivateNetworks{}
+		usage    = &smock.FakeUsage{}
+		e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, JWTKey: defaultTestKey}, networks, Stores{Usage: usage})
+		node     = &ipfs.NodeInfo{NetworkID: "bobheadxi", Ports: ipfs.NodePorts{Gateway: target.Port()}}
+	)
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{GatewayPublic: true}, nil)
+
+	// request and response bodies are counted
+	var ctx = context.WithValue(context.WithValue(context.Background(),
+		keyNetwork, node), keyFeature, "gateway")
+	var req = httptest.NewRequest("POST", "/ipfs/"+testCID, strings.NewReader("hi")).WithContext(ctx)
+	var rec = httptest.NewRecorder()
+	e.Redirect(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected gateway response, found status %d", rec.Code)
+	}
+
+	if err := e.meter.flush(); err != nil {
+		t.Fatal(err)
+	}
+	if usage.AddUsageCallCount() != 1 {
+		t.Fatalf("expected usage to be recorded once, got %d", usage.AddUsageCallCount())
+	}
+	var records = usage.AddUsageArgsForCall(0)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if r := records[0]; r.Network != "bobheadxi" || r.Feature != "gateway" ||
+		r.BytesIn != 2 || r.BytesOut != int64(len("hello world")) {
+		t.Errorf("unexpected record %+v", r)
+	}
+}
+
+// hourlyRecorder advances the hour on each write after the first, and closes
+// written once the first write is done
+type hourlyRecorder struct {
+	*httptest.ResponseRecorder
+	hours   *int64
+	writes  int
+	written chan struct{}
+}
+
+func (w *hourlyRecorder) Write(b []byte) (int, error) {
+	if w.writes++; w.writes > 1 {
+		atomic.AddInt64(w.hours, 1)
+	}
+	n, err := w.ResponseRecorder.Write(b)
+	if w.writes == 1 {
+		close(w.written)
+	}
+	return n, err
+}
+
+func TestEngine_Redirect_usageSpanningHours(t *testing.T) {
+	var rec = &hourlyRecorder{ResponseRecorder: httptest.NewRecorder(),
+		hours: new(int64), written: make(chan struct{})}
+	var upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+		<-rec.written
+		w.Write([]byte(" world"))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	var (
+		networks = &mock.FakePrivateNetworks{}
+		usage    = &smock.FakeUsage{}
+		e        = newTestEngine(t, EngineOpts{Version: "test", DevMode: true, JWTKey: defaultTestKey}, networks, Stores{Usage: usage})
+		node     = &ipfs.NodeInfo{NetworkID: "bobheadxi", Ports: ipfs.NodePorts{Gateway: target.Port()}}
+		hour     = time.Date(2019, 4, 1, 10, 0, 0, 0, time.UTC)
+	)
+	e.meter.now = func() time.Time {
+		return hour.Add(time.Duration(atomic.LoadInt64(rec.hours))*time.Hour + 59*time.Minute)
+	}
+	networks.GetNetworkByNameReturns(&models.HostedNetwork{GatewayPublic: true}, nil)
+
+	// bytes are counted towards the hour they are sent in
+	var ctx = context.WithValue(context.WithValue(context.Background(),
+		keyNetwork, node), keyFeature, "gateway")
+	e.Redirect(rec, httptest.NewRequest("GET", "/ipfs/"+testCID, nil).WithContext(ctx))
+	if rec.Code != http.StatusOK || rec.Body.String() != "hello world" {
+		t.Fatalf("expected gateway response, found status %d (%q)", rec.Code, rec.Body.String())
+	}
+	if err := e.meter.flush(); err != nil {
+		t.Fatal(err)
+	}
+	var records = sortedRecords(usage.AddUsageArgsForCall(0))
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	for i, want := range []int64{int64(len("hello")), int64(len(" world"))} {
+		if r := records[i]; !r.Hour.Equal(hour.Add(time.Duration(i)*time.Hour)) || r.BytesOut != want {
+			t.Errorf("expected %d bytes in hour %d, got %+v", want, i, r)
+		}
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/RTradeLtd/config/v2 v2.1.1
 	github.com/RTradeLtd/ctl v0.0.0-20181106024051-2febb33f6fd1
 	github.com/RTradeLtd/database/v2 v2.2.1
+	github.com/RTradeLtd/gorm v2.0.0+incompatible
 	github.com/RTradeLtd/grpc v2.0.0+incompatible
 	github.com/RTradeLtd/hostrouter v0.0.0-20190303073300-b9bb5dff8b5a
 	github.com/bobheadxi/res v0.0.0-20190326235810-8af2705a88a4
@@ -17,16 +18,28 @@ require (
 	github.com/docker/go-connections v0.4.0
 	github.com/docker/go-units v0.3.3 // indirect
 	github.com/go-chi/chi v4.0.2+incompatible
-	github.com/go-chi/cors v1.0.0
 	github.com/go-chi/render v1.0.1
+	github.com/golang/protobuf v1.5.2
 	github.com/gorilla/mux v1.7.0 // indirect
 	github.com/grpc-ecosystem/go-grpc-middleware v1.0.0
+	github.com/hashicorp/golang-lru v0.5.1
+	github.com/ipfs/go-cid v0.0.7
 	github.com/morikuni/aec v0.0.0-20170113033406-39771216ff4c // indirect
+	github.com/multiformats/go-multibase v0.0.3
+	github.com/multiformats/go-multihash v0.0.13
 	github.com/opencontainers/go-digest v1.0.0-rc1 // indirect
 	github.com/opencontainers/image-spec v1.0.1 // indirect
+	github.com/prometheus/client_golang v0.9.3-0.20190127221311-3c4408c8b829
 	github.com/sirupsen/logrus v1.3.0 // indirect
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
 	go.uber.org/zap v1.9.1
-	golang.org/x/net v0.0.0-20190311183353-d8887717615a
-	google.golang.org/grpc v1.19.0
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	golang.org/x/net v0.0.0-20210226172049-e18ecbb05110
+	golang.org/x/sync v0.0.0-20190423024810-112230192c58
+	google.golang.org/grpc v1.40.0
 	gotest.tools v2.2.0+incompatible // indirect
 )
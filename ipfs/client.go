@@ -17,8 +17,8 @@ import (
 	"github.com/docker/go-connections/nat"
 	"go.uber.org/zap"
 
+	"github.com/RTradeLtd/Nexus/config"
 	"github.com/RTradeLtd/Nexus/log"
-	"github.com/RTradeLtd/Nexus/network"
 )
 
 // Client is the primary implementation of the NodeClient interface. Instantiate
@@ -30,6 +30,7 @@ type Client struct {
 	ipfsImage string
 	dataDir   string
 	fileMode  os.FileMode
+	bind      config.Bind
 }
 
 // Nodes retrieves a list of active IPFS ndoes
@@ -120,25 +121,23 @@ func (c *Client) CreateNode(ctx context.Context, n *NodeInfo, opts NodeOpts) err
 		return fmt.Errorf("failed to set up filesystem for node: %s", err.Error())
 	}
 
-	// set up basic configuration
+	// set up basic configuration - each port is published on every configured
+	// bind address, which allows nodes to be reachable over IPv4, IPv6, or both
 	var (
+		bind  = c.bind.WithDefaults()
 		ports = nat.PortMap{
-			// TODO: make this private - blocked by lack of multiaddr support for /http
-			// paths, which means delegator can't work with go-ipfs swarm.
-			// See https://github.com/multiformats/multiaddr/issues/63
-			containerSwarmPort + "/tcp": []nat.PortBinding{
-				{HostIP: network.Public, HostPort: n.Ports.Swarm}},
+			// Swarm TCP connections are made directly to the node, since libp2p
+			// over raw TCP cannot be proxied by the delegator
+			containerSwarmPort + "/tcp": portBindings(bind.Swarm, n.Ports.Swarm),
 
 			// API server connections can be made via delegator. Suffers from same
 			// issue as above, but direct API exposure is dangeorous since it is
 			// authenticated. Delegator can handle authentication
-			containerAPIPort + "/tcp": []nat.PortBinding{
-				{HostIP: network.Private, HostPort: n.Ports.API}},
+			containerAPIPort + "/tcp": portBindings(bind.API, n.Ports.API),
 
 			// Gateway connections can be made via delegator, with access controlled
 			// by database
-			containerGatewayPort + "/tcp": []nat.PortBinding{
-				{HostIP: network.Private, HostPort: n.Ports.Gateway}},
+			containerGatewayPort + "/tcp": portBindings(bind.Gateway, n.Ports.Gateway),
 		}
 		volumes = []string{
 			c.getDataDir(n.NetworkID) + ":/data/ipfs",
@@ -150,6 +149,13 @@ func (c *Client) CreateNode(ctx context.Context, n *NodeInfo, opts NodeOpts) err
 		labels = n.labels(n.BootstrapPeers, c.getDataDir(n.NetworkID))
 	)
 
+	// Swarm WebSocket connections can be made via delegator, which allows peers
+	// to connect over TLS on standard ports. Nodes created before the WebSocket
+	// transport was introduced do not have a port assigned.
+	if n.Ports.SwarmWS != "" {
+		ports[containerSwarmWSPort+"/tcp"] = portBindings(bind.SwarmWS, n.Ports.SwarmWS)
+	}
+
 	// remove restart policy if AutoRemove is enabled
 	if opts.AutoRemove {
 		restartPolicy = container.RestartPolicy{}
@@ -361,21 +367,9 @@ func (c *Client) NodeStats(ctx context.Context, n *NodeInfo) (NodeStats, error)
 	var l = c.l.With("node", n)
 
 	// retrieve details from stats API
-	s, err := c.d.ContainerStats(ctx, n.DockerID, false)
+	stats, err := c.containerStats(ctx, l, n)
 	if err != nil {
-		l.Errorw("failed to get container stats", "error", err)
-		return NodeStats{}, errors.New("failed to get node stats")
-	}
-	defer s.Body.Close()
-	b, err := ioutil.ReadAll(s.Body)
-	if err != nil {
-		l.Errorw("failed to read container stats", "error", err)
-		return NodeStats{}, errors.New("failed to get node stats")
-	}
-	var stats rawContainerStats
-	if err = json.Unmarshal(b, &stats); err != nil {
-		l.Errorw("failed to read container stats", "error", err)
-		return NodeStats{}, errors.New("failed to get node stats")
+		return NodeStats{}, err
 	}
 
 	// retrieve details from container inspection
@@ -419,6 +413,44 @@ func (c *Client) NodeStats(ctx context.Context, n *NodeInfo) (NodeStats, error)
 	}, nil
 }
 
+// NodeTraffic is the number of bytes a node's container has received and sent
+// over the network since it started
+type NodeTraffic struct {
+	RxBytes int64
+	TxBytes int64
+}
+
+// NodeTraffic retrieves the network counters of the provided node. Counters
+// are reset whenever the node's container restarts.
+func (c *Client) NodeTraffic(ctx context.Context, n *NodeInfo) (NodeTraffic, error) {
+	stats, err := c.containerStats(ctx, c.l.With("node", n), n)
+	if err != nil {
+		return NodeTraffic{}, err
+	}
+	return stats.traffic(), nil
+}
+
+// containerStats retrieves a snapshot of the provided node's container stats
+func (c *Client) containerStats(ctx context.Context, l *zap.SugaredLogger, n *NodeInfo) (rawContainerStats, error) {
+	s, err := c.d.ContainerStats(ctx, n.DockerID, false)
+	if err != nil {
+		l.Errorw("failed to get container stats", "error", err)
+		return rawContainerStats{}, errors.New("failed to get node stats")
+	}
+	defer s.Body.Close()
+	b, err := ioutil.ReadAll(s.Body)
+	if err != nil {
+		l.Errorw("failed to read container stats", "error", err)
+		return rawContainerStats{}, errors.New("failed to get node stats")
+	}
+	var stats rawContainerStats
+	if err = json.Unmarshal(b, &stats); err != nil {
+		l.Errorw("failed to read container stats", "error", err)
+		return rawContainerStats{}, errors.New("failed to get node stats")
+	}
+	return stats, nil
+}
+
 // Event is a node-related container event
 type Event struct {
 	Time   int64    `json:"time"`
@@ -444,7 +476,7 @@ func (c *Client) Watch(ctx context.Context) (<-chan Event, <-chan error) {
 		for {
 			select {
 			case <-ctx.Done():
-				break
+				return
 
 			// pipe errors back
 			case err := <-eventsErrCh:
@@ -63,17 +63,17 @@ func Test_client_NodeOperations(t *testing.T) {
 	}{
 		{"invalid config", args{
 			&NodeInfo{
-				"test1", "", NodePorts{"4001", "5001", "8080"}, NodeResources{}, "", "", "", nil},
+				"test1", "", NodePorts{"4001", "5001", "8080", "8081"}, NodeResources{}, nil, "", "", "", nil},
 			NodeOpts{},
 		}, true},
 		{"new node", args{
 			&NodeInfo{
-				"test2", "", NodePorts{"4001", "5001", "8080"}, NodeResources{}, "", "", "", nil},
+				"test2", "", NodePorts{"4001", "5001", "8080", "8081"}, NodeResources{}, nil, "", "", "", nil},
 			NodeOpts{[]byte(key), false},
 		}, false},
 		{"with bootstrap", args{
 			&NodeInfo{
-				"test3", "", NodePorts{"4001", "5001", "8080"}, NodeResources{}, "", "", "",
+				"test3", "", NodePorts{"4001", "5001", "8080", "8081"}, NodeResources{}, nil, "", "", "",
 				[]string{
 					"/ip4/104.131.131.82/tcp/4001/ipfs/QmaCpDMGvV2BGHeYERUEnRQAwe3N8SzbUtfsmvsqQLuvuJ",
 					"/ip4/104.236.179.241/tcp/4001/ipfs/QmSoLPppuBtQSGwKDZT2M73ULpjvfd3aZ6ha4oFGL1KrGM",
@@ -154,7 +154,7 @@ func Test_client_UpdateNode(t *testing.T) {
 
 	var n = &NodeInfo{
 		NetworkID: "test_update",
-		Ports:     NodePorts{"4001", "5001", "8080"},
+		Ports:     NodePorts{"4001", "5001", "8080", "8081"},
 		Resources: NodeResources{},
 		BootstrapPeers: []string{
 			"/ip4/104.131.131.82/tcp/4001/ipfs/QmaCpDMGvV2BGHeYERUEnRQAwe3N8SzbUtfsmvsqQLuvuJ",
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
+
+	"github.com/RTradeLtd/Nexus/tracing"
 )
 
 func (c *Client) getDataDir(network string) string {
@@ -19,7 +21,10 @@ func (c *Client) getDataDir(network string) string {
 	return p
 }
 
-func (c *Client) waitForNode(ctx context.Context, dockerID string) error {
+func (c *Client) waitForNode(ctx context.Context, dockerID string) (err error) {
+	ctx, span := tracing.Start(ctx, "ipfs.wait_for_node")
+	defer func() { tracing.Finish(span, err) }()
+
 	logs, err := c.d.ContainerLogs(ctx, dockerID, types.ContainerLogsOptions{
 		ShowStdout: true,
 		Follow:     true,
@@ -37,5 +37,6 @@ func newNodeStartScript(diskMax int) (string, error) {
 	}
 	return fmt.Sprintf(string(f),
 		diskMax,
+		containerSwarmWSPort,
 	), nil
 }
@@ -44,7 +44,7 @@ func Test_newNodeStartScript(t *testing.T) {
 	)
 
 	f, _ := ioutil.ReadFile("./internal/ipfs_start.sh")
-	expected := fmt.Sprintf(string(f), disk)
+	expected := fmt.Sprintf(string(f), disk, containerSwarmWSPort)
 
 	got, err := newNodeStartScript(disk)
 	if err != nil {
@@ -4,10 +4,12 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
 )
 
 const (
 	containerSwarmPort   = "4001"
+	containerSwarmWSPort = "8081"
 	containerAPIPort     = "5001"
 	containerGatewayPort = "8080"
 )
@@ -27,6 +29,16 @@ func containerResources(n *NodeInfo) container.Resources {
 	}
 }
 
+// portBindings generates a binding for the given host port on each of the
+// provided host addresses
+func portBindings(hosts []string, port string) []nat.PortBinding {
+	var bindings = make([]nat.PortBinding, len(hosts))
+	for i, h := range hosts {
+		bindings[i] = nat.PortBinding{HostIP: h, HostPort: port}
+	}
+	return bindings
+}
+
 type rawContainerStats struct {
 	Read      time.Time `json:"read"`
 	Preread   time.Time `json:"preread"`
@@ -117,16 +129,28 @@ type rawContainerStats struct {
 	} `json:"memory_stats"`
 	Name     string `json:"name"`
 	ID       string `json:"id"`
-	Networks struct {
-		Eth0 struct {
-			RxBytes   int `json:"rx_bytes"`
-			RxPackets int `json:"rx_packets"`
-			RxErrors  int `json:"rx_errors"`
-			RxDropped int `json:"rx_dropped"`
-			TxBytes   int `json:"tx_bytes"`
-			TxPackets int `json:"tx_packets"`
-			TxErrors  int `json:"tx_errors"`
-			TxDropped int `json:"tx_dropped"`
-		} `json:"eth0"`
-	} `json:"networks"`
+	Networks map[string]containerNetworkStats `json:"networks"`
+}
+
+// containerNetworkStats are the counters of a container's network interface
+type containerNetworkStats struct {
+	RxBytes   int64 `json:"rx_bytes"`
+	RxPackets int64 `json:"rx_packets"`
+	RxErrors  int64 `json:"rx_errors"`
+	RxDropped int64 `json:"rx_dropped"`
+	TxBytes   int64 `json:"tx_bytes"`
+	TxPackets int64 `json:"tx_packets"`
+	TxErrors  int64 `json:"tx_errors"`
+	TxDropped int64 `json:"tx_dropped"`
+}
+
+// traffic totals the bytes transferred over all of the container's network
+// interfaces
+func (s rawContainerStats) traffic() NodeTraffic {
+	var t NodeTraffic
+	for _, n := range s.Networks {
+		t.RxBytes += n.RxBytes
+		t.TxBytes += n.TxBytes
+	}
+	return t
 }
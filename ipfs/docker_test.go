@@ -0,0 +1,61 @@
+package ipfs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+)
+
+func Test_portBindings(t *testing.T) {
+	type args struct {
+		hosts []string
+		port  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []nat.PortBinding
+	}{
+		{"no hosts", args{nil, "4001"}, []nat.PortBinding{}},
+		{"ipv4", args{[]string{"0.0.0.0"}, "4001"},
+			[]nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "4001"}}},
+		{"dual-stack", args{[]string{"0.0.0.0", "::"}, "4001"},
+			[]nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "4001"}, {HostIP: "::", HostPort: "4001"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := portBindings(tt.args.hosts, tt.args.port); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("portBindings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_rawContainerStats_traffic(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want NodeTraffic
+	}{
+		{"no networks", `{}`, NodeTraffic{}},
+		{"one interface",
+			`{"networks":{"eth0":{"rx_bytes":1024,"tx_bytes":2048}}}`,
+			NodeTraffic{RxBytes: 1024, TxBytes: 2048}},
+		{"multiple interfaces",
+			`{"networks":{"eth0":{"rx_bytes":1024,"tx_bytes":2048},"eth1":{"rx_bytes":1,"tx_bytes":2}}}`,
+			NodeTraffic{RxBytes: 1025, TxBytes: 2050}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stats rawContainerStats
+			if err := json.Unmarshal([]byte(tt.raw), &stats); err != nil {
+				t.Fatal(err)
+			}
+			if got := stats.traffic(); got != tt.want {
+				t.Errorf("traffic() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
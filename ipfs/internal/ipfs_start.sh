@@ -8,6 +8,7 @@ set -e
 
 # arguments provided through string templates
 DISK_MAX=%dGB
+SWARM_WS_PORT=%s
 
 # set variables
 user=ipfs
@@ -37,6 +38,20 @@ fi
 # set datastore quota
 ipfs config Datastore.StorageMax $DISK_MAX
 
+# listen for swarm connections over WebSockets on both address families for
+# peers that connect through the delegator, keeping any swarm addresses that
+# are already configured
+for swarm_ws in "/ip4/0.0.0.0/tcp/$SWARM_WS_PORT/ws" "/ip6/::/tcp/$SWARM_WS_PORT/ws"; do
+  swarm=$(ipfs config Addresses.Swarm | tr -d ' \n')
+  case "$swarm" in
+    *"\"$swarm_ws\""*) ;;
+    "[]"|"null"|"")
+      ipfs config --json Addresses.Swarm "[\"$swarm_ws\"]" ;;
+    *)
+      ipfs config --json Addresses.Swarm "$(echo "$swarm" | sed "s|]\$|,\"$swarm_ws\"]|")" ;;
+  esac
+done
+
 # release locks
 ipfs repo fsck
 
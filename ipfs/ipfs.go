@@ -22,6 +22,7 @@ type NodeClient interface {
 	StopNode(ctx context.Context, n *NodeInfo) (err error)
 	RemoveNode(ctx context.Context, network string) (err error)
 	NodeStats(ctx context.Context, n *NodeInfo) (stats NodeStats, err error)
+	NodeTraffic(ctx context.Context, n *NodeInfo) (traffic NodeTraffic, err error)
 	Watch(ctx context.Context) (<-chan Event, <-chan error)
 }
 
@@ -52,6 +53,7 @@ func NewClient(logger *zap.SugaredLogger, ipfsOpts config.IPFS) (NodeClient, err
 		ipfsImage: ipfsImage,
 		dataDir:   ipfsOpts.DataDirectory,
 		fileMode:  os.FileMode(mode),
+		bind:      ipfsOpts.Bind.WithDefaults(),
 	}
 
 	// initialize directories
@@ -19,7 +19,7 @@ func newTestClient() (NodeClient, error) {
 	d.NegotiateAPIVersion(context.Background())
 
 	l, _ := log.NewLogger("", true)
-	return &Client{l, d, ipfsImage, "./tmp", 0755}, nil
+	return &Client{l, d, ipfsImage, "./tmp", 0755, config.New().IPFS.Bind}, nil
 }
 
 func TestNewClient(t *testing.T) {
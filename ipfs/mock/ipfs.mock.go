@@ -36,6 +36,20 @@ type FakeNodeClient struct {
 		result1 ipfs.NodeStats
 		result2 error
 	}
+	NodeTrafficStub        func(context.Context, *ipfs.NodeInfo) (ipfs.NodeTraffic, error)
+	nodeTrafficMutex       sync.RWMutex
+	nodeTrafficArgsForCall []struct {
+		arg1 context.Context
+		arg2 *ipfs.NodeInfo
+	}
+	nodeTrafficReturns struct {
+		result1 ipfs.NodeTraffic
+		result2 error
+	}
+	nodeTrafficReturnsOnCall map[int]struct {
+		result1 ipfs.NodeTraffic
+		result2 error
+	}
 	NodesStub        func(context.Context) ([]*ipfs.NodeInfo, error)
 	nodesMutex       sync.RWMutex
 	nodesArgsForCall []struct {
@@ -228,6 +242,70 @@ func (fake *FakeNodeClient) NodeStatsReturnsOnCall(i int, result1 ipfs.NodeStats
 	}{result1, result2}
 }
 
+func (fake *FakeNodeClient) NodeTraffic(arg1 context.Context, arg2 *ipfs.NodeInfo) (ipfs.NodeTraffic, error) {
+	fake.nodeTrafficMutex.Lock()
+	ret, specificReturn := fake.nodeTrafficReturnsOnCall[len(fake.nodeTrafficArgsForCall)]
+	fake.nodeTrafficArgsForCall = append(fake.nodeTrafficArgsForCall, struct {
+		arg1 context.Context
+		arg2 *ipfs.NodeInfo
+	}{arg1, arg2})
+	fake.recordInvocation("NodeTraffic", []interface{}{arg1, arg2})
+	fake.nodeTrafficMutex.Unlock()
+	if fake.NodeTrafficStub != nil {
+		return fake.NodeTrafficStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.nodeTrafficReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeNodeClient) NodeTrafficCallCount() int {
+	fake.nodeTrafficMutex.RLock()
+	defer fake.nodeTrafficMutex.RUnlock()
+	return len(fake.nodeTrafficArgsForCall)
+}
+
+func (fake *FakeNodeClient) NodeTrafficCalls(stub func(context.Context, *ipfs.NodeInfo) (ipfs.NodeTraffic, error)) {
+	fake.nodeTrafficMutex.Lock()
+	defer fake.nodeTrafficMutex.Unlock()
+	fake.NodeTrafficStub = stub
+}
+
+func (fake *FakeNodeClient) NodeTrafficArgsForCall(i int) (context.Context, *ipfs.NodeInfo) {
+	fake.nodeTrafficMutex.RLock()
+	defer fake.nodeTrafficMutex.RUnlock()
+	argsForCall := fake.nodeTrafficArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNodeClient) NodeTrafficReturns(result1 ipfs.NodeTraffic, result2 error) {
+	fake.nodeTrafficMutex.Lock()
+	defer fake.nodeTrafficMutex.Unlock()
+	fake.NodeTrafficStub = nil
+	fake.nodeTrafficReturns = struct {
+		result1 ipfs.NodeTraffic
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeNodeClient) NodeTrafficReturnsOnCall(i int, result1 ipfs.NodeTraffic, result2 error) {
+	fake.nodeTrafficMutex.Lock()
+	defer fake.nodeTrafficMutex.Unlock()
+	fake.NodeTrafficStub = nil
+	if fake.nodeTrafficReturnsOnCall == nil {
+		fake.nodeTrafficReturnsOnCall = make(map[int]struct {
+			result1 ipfs.NodeTraffic
+			result2 error
+		})
+	}
+	fake.nodeTrafficReturnsOnCall[i] = struct {
+		result1 ipfs.NodeTraffic
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeNodeClient) Nodes(arg1 context.Context) ([]*ipfs.NodeInfo, error) {
 	fake.nodesMutex.Lock()
 	ret, specificReturn := fake.nodesReturnsOnCall[len(fake.nodesArgsForCall)]
@@ -544,6 +622,8 @@ func (fake *FakeNodeClient) Invocations() map[string][][]interface{} {
 	defer fake.createNodeMutex.RUnlock()
 	fake.nodeStatsMutex.RLock()
 	defer fake.nodeStatsMutex.RUnlock()
+	fake.nodeTrafficMutex.RLock()
+	defer fake.nodeTrafficMutex.RUnlock()
 	fake.nodesMutex.RLock()
 	defer fake.nodesMutex.RUnlock()
 	fake.removeNodeMutex.RLock()
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 )
@@ -16,12 +17,16 @@ const (
 	keyDataDir        = "data_dir"
 
 	keyPortSwarm   = "ports.swarm"
+	keyPortSwarmWS = "ports.swarm_ws"
 	keyPortAPI     = "ports.api"
 	keyPortGateway = "ports.gateway"
 
 	keyResourcesDisk   = "resources.disk"
 	keyResourcesMemory = "resources.memory"
 	keyResourcesCPUs   = "resources.cpus"
+
+	// keyLabelsPrefix namespaces user-defined labels
+	keyLabelsPrefix = "labels."
 )
 
 // NodeInfo defines metadata about an IPFS node
@@ -32,6 +37,9 @@ type NodeInfo struct {
 	Ports     NodePorts     `json:"ports"`
 	Resources NodeResources `json:"resources"`
 
+	// Labels are user-defined tags associated with this node's network
+	Labels map[string]string `json:"labels,omitempty"`
+
 	// Metadata set by node client:
 	// DockerID is the ID of the node's Docker container
 	DockerID string `json:"docker_id"`
@@ -46,9 +54,10 @@ type NodeInfo struct {
 
 // NodePorts declares the exposed ports of an IPFS node
 type NodePorts struct {
-	Swarm   string `json:"swarm"`   // default: 4001
-	API     string `json:"api"`     // default: 5001
-	Gateway string `json:"gateway"` // default: 8080
+	Swarm   string `json:"swarm"`    // default: 4001
+	API     string `json:"api"`      // default: 5001
+	Gateway string `json:"gateway"`  // default: 8080
+	SwarmWS string `json:"swarm_ws"` // default: 8081
 }
 
 // NodeResources declares resource quotas for this node
@@ -75,6 +84,17 @@ func newNode(id, name string, attributes map[string]string) (NodeInfo, error) {
 		cpus, _ = strconv.Atoi(attributes[keyResourcesCPUs])
 	)
 
+	// parse user-defined labels
+	var labels map[string]string
+	for k, v := range attributes {
+		if strings.HasPrefix(k, keyLabelsPrefix) {
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[strings.TrimPrefix(k, keyLabelsPrefix)] = v
+		}
+	}
+
 	// create node metadata to return
 	return NodeInfo{
 		NetworkID: attributes[keyNetworkID],
@@ -84,12 +104,14 @@ func newNode(id, name string, attributes map[string]string) (NodeInfo, error) {
 			Swarm:   attributes[keyPortSwarm],
 			API:     attributes[keyPortAPI],
 			Gateway: attributes[keyPortGateway],
+			SwarmWS: attributes[keyPortSwarmWS],
 		},
 		Resources: NodeResources{
 			DiskGB:   disk,
 			MemoryGB: mem,
 			CPUs:     cpus,
 		},
+		Labels: labels,
 
 		DockerID:       id,
 		ContainerName:  name,
@@ -123,11 +145,12 @@ func (n *NodeInfo) withDefaults() {
 
 func (n *NodeInfo) labels(peers []string, dataDir string) map[string]string {
 	var peerBytes, _ = json.Marshal(peers)
-	return map[string]string{
+	var labels = map[string]string{
 		keyNetworkID: n.NetworkID,
 		keyJobID:     n.JobID,
 
 		keyPortSwarm:   n.Ports.Swarm,
+		keyPortSwarmWS: n.Ports.SwarmWS,
 		keyPortAPI:     n.Ports.API,
 		keyPortGateway: n.Ports.Gateway,
 
@@ -138,6 +161,10 @@ func (n *NodeInfo) labels(peers []string, dataDir string) map[string]string {
 		keyResourcesDisk:   strconv.Itoa(n.Resources.DiskGB),
 		keyResourcesMemory: strconv.Itoa(n.Resources.MemoryGB),
 	}
+	for k, v := range n.Labels {
+		labels[keyLabelsPrefix+k] = v
+	}
+	return labels
 }
 
 func (n *NodeInfo) updateFromContainerDetails(c *types.Container) {
@@ -156,6 +183,8 @@ func (n *NodeInfo) updateFromContainerDetails(c *types.Container) {
 			switch private {
 			case containerSwarmPort:
 				n.Ports.Swarm = public
+			case containerSwarmWSPort:
+				n.Ports.SwarmWS = public
 			case containerAPIPort:
 				n.Ports.API = public
 			case containerGatewayPort:
@@ -46,6 +46,12 @@ func Test_newNode(t *testing.T) {
 				MemoryGB: 4,
 			}},
 			false},
+		{"parse labels",
+			args{"1", "ipfs-node1", map[string]string{keyLabelsPrefix + "tier": "trial"}},
+			NodeInfo{DockerID: "1", ContainerName: "ipfs-node1", Labels: map[string]string{
+				"tier": "trial",
+			}},
+			false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -61,6 +67,29 @@ func Test_newNode(t *testing.T) {
 	}
 }
 
+func TestNodeInfo_labels(t *testing.T) {
+	var n = NodeInfo{
+		NetworkID: "network1",
+		Labels:    map[string]string{"tier": "trial", "network_id": "network2"},
+	}
+	var labels = n.labels(nil, "")
+	if labels[keyLabelsPrefix+"tier"] != "trial" {
+		t.Errorf("expected namespaced label, got %v", labels)
+	}
+	if labels[keyNetworkID] != "network1" {
+		t.Errorf("user-defined label overwrote %s", keyNetworkID)
+	}
+
+	// labels should survive a round trip
+	got, err := newNode("1", "ipfs-network1", labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Labels, n.Labels) {
+		t.Errorf("newNode() labels = %v, want %v", got.Labels, n.Labels)
+	}
+}
+
 func TestNodeInfo_updateFromContainerDetails(t *testing.T) {
 	type args struct {
 		c *types.Container
@@ -77,6 +106,7 @@ func TestNodeInfo_updateFromContainerDetails(t *testing.T) {
 				{PrivatePort: 4001, PublicPort: 3456},
 				{PrivatePort: 5001, PublicPort: 2345},
 				{PrivatePort: 8080, PublicPort: 1234},
+				{PrivatePort: 8081, PublicPort: 4567},
 			},
 		}}, NodeInfo{
 			DockerID: "abcde",
@@ -84,6 +114,7 @@ func TestNodeInfo_updateFromContainerDetails(t *testing.T) {
 				Swarm:   "3456",
 				API:     "2345",
 				Gateway: "1234",
+				SwarmWS: "4567",
 			},
 		}},
 	}
@@ -6,17 +6,23 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// RequestObserver is called with the details of each completed request
+type RequestObserver func(r *http.Request, status, bytes int, latency time.Duration)
+
 type loggerMiddleware struct {
-	l *zap.Logger
+	l         *zap.Logger
+	observers []RequestObserver
 }
 
 // NewMiddleware instantiates a middleware function that logs all requests
-// using the provided logger
-func NewMiddleware(l *zap.SugaredLogger) func(next http.Handler) http.Handler {
-	return loggerMiddleware{l.Desugar()}.Handler
+// using the provided logger. Observers, if provided, are also notified of each
+// request, and can be used to collect metrics.
+func NewMiddleware(l *zap.SugaredLogger, observers ...RequestObserver) func(next http.Handler) http.Handler {
+	return loggerMiddleware{l.Desugar(), observers}.Handler
 }
 
 func (z loggerMiddleware) Handler(next http.Handler) http.Handler {
@@ -25,11 +31,18 @@ func (z loggerMiddleware) Handler(next http.Handler) http.Handler {
 		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 		next.ServeHTTP(ww, r)
 		latency := time.Since(start)
+		for _, observe := range z.observers {
+			observe(r, ww.Status(), ww.BytesWritten(), latency)
+		}
 
 		var requestID string
 		if reqID := r.Context().Value(middleware.RequestIDKey); reqID != nil {
 			requestID = reqID.(string)
 		}
+		var traceID string
+		if span := trace.SpanFromContext(r.Context()).SpanContext(); span.HasTraceID() {
+			traceID = span.TraceID().String()
+		}
 		z.l.Info("request completed",
 			// request metadata
 			zap.String("path", r.URL.Path),
@@ -39,11 +52,13 @@ func (z loggerMiddleware) Handler(next http.Handler) http.Handler {
 
 			// response metadata
 			zap.Int("status", ww.Status()),
+			zap.Int("bytes", ww.BytesWritten()),
 			zap.Duration("took", latency),
 
 			// additional metadata
 			zap.String("real-ip", r.RemoteAddr),
-			zap.String("request-id", requestID))
+			zap.String("request-id", requestID),
+			zap.String("trace-id", traceID))
 	},
 	)
 }
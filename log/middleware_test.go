@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
@@ -67,3 +68,29 @@ func Test_loggerMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func Test_loggerMiddleware_observers(t *testing.T) {
+	var (
+		l, _     = NewTestLogger()
+		observed int
+		status   int
+		bytes    int
+	)
+	m := chi.NewRouter()
+	m.Use(NewMiddleware(l, func(r *http.Request, s, b int, latency time.Duration) {
+		observed++
+		status, bytes = s, b
+	}))
+	m.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("hello"))
+	})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://testing/", nil))
+
+	if observed != 1 {
+		t.Errorf("expected observer to be called once, got %d", observed)
+	}
+	if status != http.StatusTeapot || bytes != 5 {
+		t.Errorf("observed status %d and %d bytes, want %d and %d", status, bytes, http.StatusTeapot, 5)
+	}
+}
@@ -14,22 +14,35 @@ const (
 
 	// Public denotes 0.0.0.0
 	Public = "0.0.0.0"
+
+	// PrivateIPv6 denotes the IPv6 localhost
+	PrivateIPv6 = "::1"
+
+	// PublicIPv6 denotes ::
+	PublicIPv6 = "::"
 )
 
 // Registry manages host network usage
 type Registry struct {
 	l *zap.SugaredLogger
 
-	host  string
+	hosts []string
 	ports []string
 
 	recent *cache
 }
 
-// NewRegistry creates a new registry with given host address and available
-// port ranges. Elements of portRanges can be "<PORT>" or "<LOWER>-<UPPER>"
-func NewRegistry(logger *zap.SugaredLogger, host string, portRanges []string) *Registry {
+// NewRegistry creates a new registry with given host addresses and available
+// port ranges. Elements of portRanges can be "<PORT>" or "<LOWER>-<UPPER>". A
+// port is only considered available if it is free on every given host, which
+// can include both IPv4 and IPv6 addresses for dual-stack setups.
+func NewRegistry(logger *zap.SugaredLogger, hosts []string, portRanges []string) *Registry {
 	var l = logger.Named("network")
+	if len(hosts) == 0 {
+		l.Warnw("no host addresses were provided - defaulting to private",
+			"default", Private)
+		hosts = []string{Private}
+	}
 
 	// mark available ports
 	var ports []string
@@ -45,7 +58,7 @@ func NewRegistry(logger *zap.SugaredLogger, host string, portRanges []string) *R
 
 	return &Registry{
 		l:      l,
-		host:   host,
+		hosts:  hosts,
 		ports:  ports,
 		recent: c,
 	}
@@ -65,11 +78,9 @@ func (reg *Registry) AssignPort() (string, error) {
 
 		// attempt to claim port, placing it in cache
 		reg.recent.Cache(p)
-		l, err := net.Listen("tcp", reg.host+":"+p)
-		if err != nil {
+		if !reg.available(p) {
 			continue
 		}
-		l.Close()
 
 		return p, nil
 	}
@@ -78,6 +89,18 @@ func (reg *Registry) AssignPort() (string, error) {
 	return "", errors.New("no available port found")
 }
 
+// available checks if the given port can be bound on every host address
+func (reg *Registry) available(port string) bool {
+	for _, h := range reg.hosts {
+		l, err := net.Listen(Family(h), net.JoinHostPort(h, port))
+		if err != nil {
+			return false
+		}
+		l.Close()
+	}
+	return true
+}
+
 // Close stops the registry cache cleanup
 func (reg *Registry) Close() {
 	reg.recent.stop <- true
@@ -10,16 +10,28 @@ import (
 
 func TestNewRegistry(t *testing.T) {
 	l, _ := log.NewTestLogger()
-	NewRegistry(l, "127.0.0.1", []string{"1234"})
-	NewRegistry(l, "127.0.0.1", nil)
+	NewRegistry(l, []string{"127.0.0.1"}, []string{"1234"})
+	NewRegistry(l, []string{"127.0.0.1", "::1"}, []string{"1234"})
+	NewRegistry(l, nil, nil)
 }
 
 func TestRegistry_AssignPort(t *testing.T) {
 	// lock a port for testing
-	p1, _ := net.Listen("tcp", "127.0.0.1:9999")
+	p1, _ := net.Listen("tcp4", "127.0.0.1:9999")
 	defer p1.Close()
 
+	// lock a port on IPv6 only, leaving it free on IPv4
+	if p2, err := net.Listen("tcp6", "[::1]:9997"); err == nil {
+		defer p2.Close()
+	}
+
+	var (
+		local = []string{"127.0.0.1"}
+		dual  = []string{"127.0.0.1", "::1"}
+	)
+
 	type fields struct {
+		hosts []string
 		ports []string
 	}
 	tests := []struct {
@@ -28,16 +40,17 @@ func TestRegistry_AssignPort(t *testing.T) {
 		want    string
 		wantErr bool
 	}{
-		{"nil ports", fields{nil}, "", true},
-		{"no ports", fields{[]string{}}, "", true},
-		{"no available port", fields{[]string{"9999"}}, "", true},
-		{"available port", fields{[]string{"9998"}}, "9998", false},
-		{"cache and try next port", fields{[]string{"9999", "9998"}}, "9998", false},
+		{"nil ports", fields{local, nil}, "", true},
+		{"no ports", fields{local, []string{}}, "", true},
+		{"no available port", fields{local, []string{"9999"}}, "", true},
+		{"available port", fields{local, []string{"9998"}}, "9998", false},
+		{"cache and try next port", fields{local, []string{"9999", "9998"}}, "9998", false},
+		{"port unavailable on one family", fields{dual, []string{"9997"}}, "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			l, _ := log.NewTestLogger()
-			reg := &Registry{l: l, host: "127.0.0.1", ports: tt.fields.ports,
+			reg := &Registry{l: l, hosts: tt.fields.hosts, ports: tt.fields.ports,
 				recent: newCache(5*time.Minute, 10*time.Minute)}
 			defer reg.Close()
 			got, err := reg.AssignPort()
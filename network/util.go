@@ -2,6 +2,7 @@ package network
 
 import (
 	"math/rand"
+	"net"
 	"time"
 )
 
@@ -12,3 +13,39 @@ func random(max int) int {
 	rand.Seed(time.Now().Unix())
 	return rand.Intn(max)
 }
+
+// Family returns the TCP network family of the given host address - "tcp4"
+// for IPv4 addresses and "tcp6" for IPv6 addresses. Unknown addresses, such as
+// hostnames, return "tcp".
+func Family(host string) string {
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return "tcp"
+	case ip.To4() != nil:
+		return "tcp4"
+	default:
+		return "tcp6"
+	}
+}
+
+// Dialable converts the given bind address into an address that can be dialed
+// from this host. Unspecified addresses such as "0.0.0.0" and "::" are mapped
+// to the loopback address of the same family.
+func Dialable(host string) string {
+	switch host {
+	case "", Public:
+		return Private
+	case PublicIPv6:
+		return PrivateIPv6
+	default:
+		return host
+	}
+}
+
+// IsPrivate checks if the given host address is a loopback or private network
+// address. Hostnames are not resolved, and are not considered private.
+func IsPrivate(host string) bool {
+	var ip = net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate())
+}
@@ -6,3 +6,68 @@ func Test_random(t *testing.T) {
 	random(5)
 	random(0)
 }
+
+func TestFamily(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{"ipv4", "127.0.0.1", "tcp4"},
+		{"ipv4 unspecified", "0.0.0.0", "tcp4"},
+		{"ipv6", "::1", "tcp6"},
+		{"ipv6 unspecified", "::", "tcp6"},
+		{"hostname", "localhost", "tcp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Family(tt.host); got != tt.want {
+				t.Errorf("Family() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialable(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{"empty", "", Private},
+		{"ipv4 unspecified", Public, Private},
+		{"ipv6 unspecified", PublicIPv6, PrivateIPv6},
+		{"specific address", "10.0.0.1", "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Dialable(tt.host); got != tt.want {
+				t.Errorf("Dialable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPrivate(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{"ipv4 loopback", "127.0.0.1", true},
+		{"ipv4 private", "10.0.0.1", true},
+		{"ipv4 public", "8.8.8.8", false},
+		{"ipv4 unspecified", Public, false},
+		{"ipv6 loopback", "::1", true},
+		{"ipv6 unique local", "fd00::1", true},
+		{"ipv6 public", "2001:4860:4860::8888", false},
+		{"hostname", "localhost", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPrivate(tt.host); got != tt.want {
+				t.Errorf("IsPrivate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
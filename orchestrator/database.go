@@ -4,10 +4,16 @@ import (
 	"errors"
 
 	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
 	"github.com/RTradeLtd/database/v2/models"
 )
 
-func getNodeFromDatabaseEntry(jobID string, network *models.HostedNetwork) *ipfs.NodeInfo {
+func getNodeFromDatabaseEntry(jobID string, network *models.HostedNetwork,
+	settings *store.NetworkSettings) *ipfs.NodeInfo {
+	var labels map[string]string
+	if settings != nil && len(settings.Labels) > 0 {
+		labels = settings.Labels
+	}
 	return &ipfs.NodeInfo{
 		NetworkID: network.Name,
 		JobID:     jobID,
@@ -16,6 +22,7 @@ func getNodeFromDatabaseEntry(jobID string, network *models.HostedNetwork) *ipfs
 			MemoryGB: network.ResourcesMemoryGB,
 			CPUs:     network.ResourcesCPUs,
 		},
+		Labels:         labels,
 		BootstrapPeers: network.BootstrapPeerAddresses,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
 
 	tcfg "github.com/RTradeLtd/config/v2"
 	"github.com/RTradeLtd/database/v2"
@@ -69,3 +70,32 @@ func Test_getOptionsFromDatabaseEntry(t *testing.T) {
 		})
 	}
 }
+
+func Test_getNodeFromDatabaseEntry(t *testing.T) {
+	type args struct {
+		network  *models.HostedNetwork
+		settings *store.NetworkSettings
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantLabels map[string]string
+	}{
+		{"no settings", args{&models.HostedNetwork{Name: "test"}, nil}, nil},
+		{"no labels", args{&models.HostedNetwork{Name: "test"}, &store.NetworkSettings{}}, nil},
+		{"with labels", args{&models.HostedNetwork{Name: "test"}, &store.NetworkSettings{
+			Labels: store.Labels{"tier": "trial"},
+		}}, map[string]string{"tier": "trial"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getNodeFromDatabaseEntry("1234", tt.args.network, tt.args.settings)
+			if got.NetworkID != tt.args.network.Name {
+				t.Errorf("getNodeFromDatabaseEntry() network = %v, want %v", got.NetworkID, tt.args.network.Name)
+			}
+			if !reflect.DeepEqual(got.Labels, tt.wantLabels) {
+				t.Errorf("getNodeFromDatabaseEntry() labels = %v, want %v", got.Labels, tt.wantLabels)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// AddDenylistEntries blocks access to content on given network, or on all
+// networks if no network is provided. The list is parsed as a denylist in the
+// compact denylist format, and the number of rules read is returned.
+func (o *Orchestrator) AddDenylistEntries(network, reason, list string) (int, error) {
+	if err := o.checkDenylistNetwork(network); err != nil {
+		return 0, err
+	}
+	rules, err := store.ParseDenylist(list)
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return 0, errors.New("no denylist rules provided")
+	}
+
+	var entries = make([]*store.DenylistEntry, len(rules))
+	for i, rule := range rules {
+		entries[i] = &store.DenylistEntry{Network: network, Rule: rule, Reason: reason}
+	}
+	if err := o.denylist.AddDenylistEntries(entries); err != nil {
+		o.l.Errorw("failed to add denylist entries",
+			"network", network,
+			"error", err)
+		return 0, fmt.Errorf("failed to add denylist entries: %s", err.Error())
+	}
+	o.l.Infow("denylist entries added",
+		"network", network,
+		"entries", len(entries),
+		"reason", reason)
+	o.denylistChanged(network)
+	return len(entries), nil
+}
+
+// RemoveDenylistEntries unblocks content on given network, or on all networks
+// if no network is provided, and returns the number of entries removed
+func (o *Orchestrator) RemoveDenylistEntries(network string, rules []string) (int64, error) {
+	if len(rules) == 0 {
+		return 0, errors.New("no denylist rules provided")
+	}
+	var normalized = make([]string, len(rules))
+	for i, rule := range rules {
+		var err error
+		if normalized[i], err = store.ParseDenylistRule(rule); err != nil {
+			return 0, fmt.Errorf("invalid rule '%s': %s", rule, err.Error())
+		}
+	}
+	removed, err := o.denylist.RemoveDenylistEntries(network, normalized)
+	if err != nil {
+		o.l.Errorw("failed to remove denylist entries",
+			"network", network,
+			"error", err)
+		return 0, fmt.Errorf("failed to remove denylist entries: %s", err.Error())
+	}
+	o.l.Infow("denylist entries removed",
+		"network", network,
+		"entries", removed)
+	o.denylistChanged(network)
+	return removed, nil
+}
+
+// Denylist retrieves the denylist entries of given network, or the entries
+// that apply to all networks if no network is provided
+func (o *Orchestrator) Denylist(network string) ([]*store.DenylistEntry, error) {
+	if err := o.checkDenylistNetwork(network); err != nil {
+		return nil, err
+	}
+	entries, err := o.denylist.ListDenylistEntries(network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve denylist: %s", err.Error())
+	}
+	return entries, nil
+}
+
+// checkDenylistNetwork checks that a network exists, if one is provided
+func (o *Orchestrator) checkDenylistNetwork(network string) error {
+	if network == "" {
+		return nil
+	}
+	if _, err := o.nm.GetNetworkByName(network); err != nil {
+		return fmt.Errorf("no network with name '%s' found", network)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package orchestrator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	tmock "github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestOrchestrator_AddDenylistEntries(t *testing.T) {
+	type args struct {
+		network string
+		list    string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		getErr    bool
+		saveErr   bool
+		wantRules []string
+		wantErr   bool
+	}{
+		{"unknown network", args{"bobheadxi", "/ipns/example.com"}, true, false, nil, true},
+		{"invalid rule", args{"bobheadxi", "/ipfs/asdf"}, false, false, nil, true},
+		{"no rules", args{"bobheadxi", "# nothing"}, false, false, nil, true},
+		{"save error", args{"bobheadxi", "/ipns/example.com"}, false, true, nil, true},
+		{"network", args{"bobheadxi", "/ipns/example.com\n/ipns/temporal.cloud"}, false, false,
+			[]string{"/ipns/example.com", "/ipns/temporal.cloud"}, false},
+		{"all networks", args{"", "/ipns/example.com"}, true, false,
+			[]string{"/ipns/example.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _     = log.NewTestLogger()
+				nm       = &tmock.FakePrivateNetworks{}
+				denylist = &smock.FakeDenylist{}
+				o        = &Orchestrator{l: l, nm: nm, denylist: denylist}
+				changed  []string
+			)
+			o.OnDenylistChange(func(network string) { changed = append(changed, network) })
+			if tt.getErr {
+				nm.GetNetworkByNameReturns(nil, errors.New("oh no"))
+			} else {
+				nm.GetNetworkByNameReturns(&models.HostedNetwork{Name: tt.args.network}, nil)
+			}
+			if tt.saveErr {
+				denylist.AddDenylistEntriesReturns(errors.New("oh no"))
+			}
+
+			count, err := o.AddDenylistEntries(tt.args.network, "dmca", tt.args.list)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.AddDenylistEntries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if len(changed) != 0 {
+					t.Error("expected no change notification")
+				}
+				return
+			}
+			if count != len(tt.wantRules) {
+				t.Errorf("expected %d entries added, got %d", len(tt.wantRules), count)
+			}
+			var entries = denylist.AddDenylistEntriesArgsForCall(0)
+			var rules = make([]string, len(entries))
+			for i, e := range entries {
+				if e.Network != tt.args.network || e.Reason != "dmca" {
+					t.Errorf("unexpected entry %+v", e)
+				}
+				rules[i] = e.Rule
+			}
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("expected rules %v, got %v", tt.wantRules, rules)
+			}
+			if !reflect.DeepEqual(changed, []string{tt.args.network}) {
+				t.Errorf("expected change notification for '%s', got %v", tt.args.network, changed)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_RemoveDenylistEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []string
+		removeErr bool
+		wantErr   bool
+	}{
+		{"no rules", nil, false, true},
+		{"invalid rule", []string{"/ipfs/asdf"}, false, true},
+		{"remove error", []string{"/ipns/example.com"}, true, true},
+		{"ok", []string{"QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _     = log.NewTestLogger()
+				denylist = &smock.FakeDenylist{}
+				o        = &Orchestrator{l: l, denylist: denylist}
+			)
+			if tt.removeErr {
+				denylist.RemoveDenylistEntriesReturns(0, errors.New("oh no"))
+			} else {
+				denylist.RemoveDenylistEntriesReturns(1, nil)
+			}
+
+			removed, err := o.RemoveDenylistEntries("bobheadxi", tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.RemoveDenylistEntries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				if removed != 1 {
+					t.Errorf("expected 1 entry removed, got %d", removed)
+				}
+				// rules should be normalized to match stored entries
+				if _, rules := denylist.RemoveDenylistEntriesArgsForCall(0); !reflect.DeepEqual(rules,
+					[]string{"/ipfs/bafybeiccfclkdtucu6y4yc5cpr6y3yuinr67svmii46v5cfcrkp47ihehy"}) {
+					t.Errorf("unexpected rules %v", rules)
+				}
+			}
+		})
+	}
+}
+
+func TestOrchestrator_Denylist(t *testing.T) {
+	var (
+		l, _     = log.NewTestLogger()
+		nm       = &tmock.FakePrivateNetworks{}
+		denylist = &smock.FakeDenylist{}
+		o        = &Orchestrator{l: l, nm: nm, denylist: denylist}
+		want     = []*store.DenylistEntry{{Network: "bobheadxi", Rule: "/ipns/example.com"}}
+	)
+	nm.GetNetworkByNameReturns(nil, errors.New("oh no"))
+	if _, err := o.Denylist("bobheadxi"); err == nil {
+		t.Error("expected error for unknown network")
+	}
+
+	nm.GetNetworkByNameReturns(&models.HostedNetwork{Name: "bobheadxi"}, nil)
+	denylist.ListDenylistEntriesReturns(want, nil)
+	entries, err := o.Denylist("bobheadxi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Orchestrator.Denylist() = %v, want %v", entries, want)
+	}
+}
@@ -0,0 +1,114 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// domainVerifyTimeout bounds the DNS lookups made to verify a custom domain
+const domainVerifyTimeout = 10 * time.Second
+
+// AddDomain registers a custom domain for given network's gateway. Content is
+// served from rootPath if one is provided, or from the host's DNSLink record
+// otherwise. The domain is not served until it is verified with VerifyDomain.
+func (o *Orchestrator) AddDomain(network, host, rootPath string) (*store.CustomDomain, error) {
+	if _, err := o.nm.GetNetworkByName(network); err != nil {
+		return nil, fmt.Errorf("no network with name '%s' found", network)
+	}
+	d, err := store.NewCustomDomain(network, host, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.domains.AddDomain(d); err != nil {
+		o.l.Errorw("failed to add domain",
+			"network", network,
+			"host", d.Host,
+			"error", err)
+		return nil, fmt.Errorf("failed to add domain: %s", err.Error())
+	}
+	o.l.Infow("domain added",
+		"network", network,
+		"host", d.Host,
+		"root_path", d.RootPath)
+	return d, nil
+}
+
+// VerifyDomain checks that the domain's challenge record contains its token,
+// and marks the domain as verified if it does, after which it is served
+func (o *Orchestrator) VerifyDomain(host string) (*store.CustomDomain, error) {
+	host, err := store.ParseDomainHost(host)
+	if err != nil {
+		return nil, err
+	}
+	d, err := o.domains.GetDomain(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve domain: %s", err.Error())
+	}
+	if d == nil {
+		return nil, fmt.Errorf("no domain '%s' found", host)
+	}
+	if d.Verified() {
+		return d, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), domainVerifyTimeout)
+	defer cancel()
+	records, err := o.lookupTXT(ctx, d.ChallengeRecord())
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up '%s': %s", d.ChallengeRecord(), err.Error())
+	}
+	var found bool
+	for _, r := range records {
+		if strings.TrimSpace(r) == d.Token {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("TXT record '%s' does not contain the verification token",
+			d.ChallengeRecord())
+	}
+
+	if err := o.domains.VerifyDomain(host); err != nil {
+		return nil, fmt.Errorf("failed to verify domain: %s", err.Error())
+	}
+	var now = time.Now()
+	d.VerifiedAt = &now
+	o.l.Infow("domain verified",
+		"network", d.Network,
+		"host", host)
+	o.domainChanged(host)
+	return d, nil
+}
+
+// RemoveDomain stops serving a network's custom domain
+func (o *Orchestrator) RemoveDomain(network, host string) error {
+	host, err := store.ParseDomainHost(host)
+	if err != nil {
+		return err
+	}
+	if err := o.domains.RemoveDomain(network, host); err != nil {
+		return err
+	}
+	o.l.Infow("domain removed",
+		"network", network,
+		"host", host)
+	o.domainChanged(host)
+	return nil
+}
+
+// Domains retrieves the custom domains of given network
+func (o *Orchestrator) Domains(network string) ([]*store.CustomDomain, error) {
+	if _, err := o.nm.GetNetworkByName(network); err != nil {
+		return nil, fmt.Errorf("no network with name '%s' found", network)
+	}
+	domains, err := o.domains.ListDomains(network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve domains: %s", err.Error())
+	}
+	return domains, nil
+}
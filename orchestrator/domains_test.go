@@ -0,0 +1,169 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	tmock "github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestOrchestrator_AddDomain(t *testing.T) {
+	type args struct {
+		network  string
+		host     string
+		rootPath string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		getErr  bool
+		saveErr bool
+		wantErr bool
+	}{
+		{"unknown network", args{"bobheadxi", "docs.example.com", ""}, true, false, true},
+		{"invalid host", args{"bobheadxi", "localhost", ""}, false, false, true},
+		{"invalid root path", args{"bobheadxi", "docs.example.com", "/ipfs/asdf"}, false, false, true},
+		{"save error", args{"bobheadxi", "docs.example.com", ""}, false, true, true},
+		{"dnslink", args{"bobheadxi", "Docs.Example.com", ""}, false, false, false},
+		{"root path", args{"bobheadxi", "docs.example.com", "/ipns/example.com"}, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _    = log.NewTestLogger()
+				nm      = &tmock.FakePrivateNetworks{}
+				domains = &smock.FakeDomains{}
+				o       = &Orchestrator{l: l, nm: nm, domains: domains}
+			)
+			if tt.getErr {
+				nm.GetNetworkByNameReturns(nil, errors.New("oh no"))
+			} else {
+				nm.GetNetworkByNameReturns(&models.HostedNetwork{Name: tt.args.network}, nil)
+			}
+			if tt.saveErr {
+				domains.AddDomainReturns(errors.New("oh no"))
+			}
+
+			d, err := o.AddDomain(tt.args.network, tt.args.host, tt.args.rootPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.AddDomain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if domains.AddDomainArgsForCall(0) != d {
+				t.Error("expected returned domain to be stored")
+			}
+			if d.Host != "docs.example.com" || d.Network != tt.args.network ||
+				d.RootPath != tt.args.rootPath || d.Token == "" || d.Verified() {
+				t.Errorf("unexpected domain %+v", d)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_VerifyDomain(t *testing.T) {
+	var verifiedAt = time.Now()
+	tests := []struct {
+		name      string
+		domain    *store.CustomDomain
+		records   []string
+		lookupErr bool
+		wantErr   bool
+	}{
+		{"not found", nil, nil, false, true},
+		{"already verified", &store.CustomDomain{Host: "docs.example.com", VerifiedAt: &verifiedAt},
+			nil, true, false},
+		{"lookup error", &store.CustomDomain{Host: "docs.example.com", Token: "abc"},
+			nil, true, true},
+		{"wrong token", &store.CustomDomain{Host: "docs.example.com", Token: "abc"},
+			[]string{"def"}, false, true},
+		{"verified", &store.CustomDomain{Host: "docs.example.com", Token: "abc"},
+			[]string{"def", "abc"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _    = log.NewTestLogger()
+				domains = &smock.FakeDomains{}
+				o       = &Orchestrator{l: l, domains: domains}
+				lookups []string
+				changed []string
+			)
+			o.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+				lookups = append(lookups, name)
+				if tt.lookupErr {
+					return nil, errors.New("oh no")
+				}
+				return tt.records, nil
+			}
+			o.OnDomainChange(func(host string) { changed = append(changed, host) })
+			domains.GetDomainReturns(tt.domain, nil)
+
+			d, err := o.VerifyDomain("docs.example.com")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.VerifyDomain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if domains.VerifyDomainCallCount() != 0 || len(changed) != 0 {
+					t.Error("expected domain to remain unverified")
+				}
+				return
+			}
+			if !d.Verified() {
+				t.Error("expected domain to be verified")
+			}
+			if tt.domain.VerifiedAt == &verifiedAt {
+				return
+			}
+			if len(lookups) != 1 || lookups[0] != "_nexus-challenge.docs.example.com" {
+				t.Errorf("unexpected lookups %v", lookups)
+			}
+			if domains.VerifyDomainCallCount() != 1 || len(changed) != 1 || changed[0] != "docs.example.com" {
+				t.Error("expected domain to be verified and change to be announced")
+			}
+		})
+	}
+}
+
+func TestOrchestrator_RemoveDomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		removeErr bool
+		wantErr   bool
+	}{
+		{"invalid host", "localhost", false, true},
+		{"remove error", "docs.example.com", true, true},
+		{"removed", "docs.example.com", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _    = log.NewTestLogger()
+				domains = &smock.FakeDomains{}
+				o       = &Orchestrator{l: l, domains: domains}
+				changed []string
+			)
+			o.OnDomainChange(func(host string) { changed = append(changed, host) })
+			if tt.removeErr {
+				domains.RemoveDomainReturns(errors.New("oh no"))
+			}
+			if err := o.RemoveDomain("bobheadxi", tt.host); (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.RemoveDomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (len(changed) == 1) == tt.wantErr {
+				t.Errorf("unexpected change notifications %v", changed)
+			}
+		})
+	}
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/RTradeLtd/Nexus/temporal"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/RTradeLtd/Nexus/config"
 	"github.com/RTradeLtd/Nexus/ipfs"
 	"github.com/RTradeLtd/Nexus/log"
 	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/store"
+	"github.com/RTradeLtd/Nexus/tracing"
 )
 
 // Orchestrator contains most primary application logic and manages node
@@ -21,18 +26,36 @@ import (
 type Orchestrator struct {
 	Registry *registry.NodeRegistry
 
-	l  *zap.SugaredLogger
-	nm temporal.PrivateNetworks
-
-	client  ipfs.NodeClient
-	address string
+	l        *zap.SugaredLogger
+	nm       temporal.PrivateNetworks
+	settings store.Settings
+	tokens   store.Tokens
+	denylist store.Denylist
+	domains  store.Domains
+	usage    store.Usage
+
+	// lookupTXT resolves TXT records, and is used to verify custom domains
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+
+	client    ipfs.NodeClient
+	addresses []string
+	bind      config.Bind
+
+	changesMux       sync.RWMutex
+	onChange         []func(network string)
+	onDenylistChange []func(network string)
+	onDomainChange   []func(host string)
 }
 
-// New instantiates and bootstraps a new Orchestrator
-func New(logger *zap.SugaredLogger, address string, ports config.Ports, dev bool,
-	c ipfs.NodeClient, networks temporal.PrivateNetworks) (*Orchestrator, error) {
+// New instantiates and bootstraps a new Orchestrator. Addresses are the
+// external addresses of this host, which are published along with each
+// network's swarm port.
+func New(logger *zap.SugaredLogger, addresses []string, ports config.Ports, bind config.Bind,
+	dev bool, c ipfs.NodeClient, networks temporal.PrivateNetworks, settings store.Settings,
+	tokens store.Tokens, denylist store.Denylist, domains store.Domains,
+	usage store.Usage) (*Orchestrator, error) {
 	var l = logger.Named("orchestrator")
-	if address == "" {
+	if len(addresses) == 0 || addresses[0] == "" {
 		l.Warn("host address not set")
 	}
 
@@ -46,16 +69,23 @@ func New(logger *zap.SugaredLogger, address string, ports config.Ports, dev bool
 	if len(nodes) > 0 {
 		l.Infow("bootstrapping with discovered nodes", "nodes", nodes)
 	}
-	reg := registry.New(l, ports, nodes...)
+	reg := registry.New(l, ports, bind, nodes...)
 
 	// set up orchestrator
 	var o = &Orchestrator{
 		Registry: reg,
 
-		l:       l,
-		nm:      networks,
-		client:  c,
-		address: address,
+		l:         l,
+		nm:        networks,
+		settings:  settings,
+		tokens:    tokens,
+		denylist:  denylist,
+		domains:   domains,
+		usage:     usage,
+		lookupTXT: net.DefaultResolver.LookupTXT,
+		client:    c,
+		addresses: addresses,
+		bind:      bind,
 	}
 
 	// reboot offline nodes
@@ -65,42 +95,133 @@ func New(logger *zap.SugaredLogger, address string, ports config.Ports, dev bool
 	return o, nil
 }
 
+// OnNetworkChange registers a callback that is invoked whenever the
+// orchestrator may have changed a network's database entry or settings
+func (o *Orchestrator) OnNetworkChange(fn func(network string)) {
+	o.changesMux.Lock()
+	o.onChange = append(o.onChange, fn)
+	o.changesMux.Unlock()
+}
+
+func (o *Orchestrator) networkChanged(network string) {
+	o.changesMux.RLock()
+	defer o.changesMux.RUnlock()
+	for _, fn := range o.onChange {
+		fn(network)
+	}
+}
+
+// OnDenylistChange registers a callback that is invoked whenever the
+// orchestrator changes the denylist entries of a network, or the entries that
+// apply to all networks if the network is empty
+func (o *Orchestrator) OnDenylistChange(fn func(network string)) {
+	o.changesMux.Lock()
+	o.onDenylistChange = append(o.onDenylistChange, fn)
+	o.changesMux.Unlock()
+}
+
+func (o *Orchestrator) denylistChanged(network string) {
+	o.changesMux.RLock()
+	defer o.changesMux.RUnlock()
+	for _, fn := range o.onDenylistChange {
+		fn(network)
+	}
+}
+
+// OnDomainChange registers a callback that is invoked whenever the
+// orchestrator changes or removes a custom domain
+func (o *Orchestrator) OnDomainChange(fn func(host string)) {
+	o.changesMux.Lock()
+	o.onDomainChange = append(o.onDomainChange, fn)
+	o.changesMux.Unlock()
+}
+
+func (o *Orchestrator) domainChanged(host string) {
+	o.changesMux.RLock()
+	defer o.changesMux.RUnlock()
+	for _, fn := range o.onDomainChange {
+		fn(host)
+	}
+}
+
 // Run initializes the orchestrator's background tasks. Cancelling the context
 // will end the tasks and release the orchestrator's resources.
 func (o *Orchestrator) Run(ctx context.Context) error {
-	go o.client.Watch(ctx)
+	var events, errs = o.client.Watch(ctx)
 	go func() {
-		select {
-		case <-ctx.Done():
-			o.l.Info("releasing orchestrator resources")
-
-			// close registry
-			o.Registry.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				o.l.Info("releasing orchestrator resources")
+
+				// close registry
+				o.Registry.Close()
+				return
+
+			// track node states in registry
+			case e := <-events:
+				o.updateNodeState(e)
+
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+				} else if err != nil {
+					o.l.Warnw("error encountered watching nodes", "error", err)
+				}
+			}
 		}
 	}()
 	return nil
 }
 
+// updateNodeState updates the registered state of the node in given event
+func (o *Orchestrator) updateNodeState(e ipfs.Event) {
+	var state registry.State
+	switch e.Status {
+	case "start":
+		state = registry.StateRunning
+	case "die":
+		state = registry.StateStopped
+	default:
+		return
+	}
+	if err := o.Registry.SetState(e.Node.NetworkID, state); err != nil {
+		o.l.Debugw("received event for unregistered node",
+			"event", e, "error", err)
+	}
+}
+
 // NetworkDetails provides information about an instantiated network
 type NetworkDetails struct {
 	NetworkID string
 	PeerID    string
 	SwarmPort string
 	SwarmKey  string
+
+	// SwarmAddr is the primary address of the network's swarm, and
+	// AltSwarmAddrs are any other addresses it can be reached through, such as
+	// an IPv6 address on dual-stack hosts
+	SwarmAddr     string
+	AltSwarmAddrs []string
 }
 
 // NetworkUp intializes a node for given network
-func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (NetworkDetails, error) {
+func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (details NetworkDetails, err error) {
 	if network == "" {
 		return NetworkDetails{}, errors.New("invalid network name provided")
 	}
 
+	ctx, span := tracing.Start(ctx, "orchestrator.network_up",
+		attribute.String("nexus.network", network))
+	defer func() { tracing.Finish(span, err) }()
+
 	var start = time.Now()
 	var jobID = generateID()
 	var l = log.NewProcessLogger(o.l, "network_up",
 		"job_id", jobID,
 		"network", network)
 	l.Info("network up process started")
+	defer o.networkChanged(network)
 
 	// check if request is valid
 	n, err := o.nm.GetNetworkByName(network)
@@ -116,6 +237,13 @@ func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (NetworkDe
 	}
 	l.Info("network retrieved from database")
 
+	settings, err := o.settings.GetNetworkSettings(network)
+	if err != nil {
+		l.Warnw("failed to fetch network settings from database - continuing without labels",
+			"error", err)
+		settings = nil
+	}
+
 	// set options based on database entry
 	opts, err := getOptionsFromDatabaseEntry(n)
 	if err != nil {
@@ -125,8 +253,11 @@ func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (NetworkDe
 	}
 
 	// register node for network
-	newNode := getNodeFromDatabaseEntry(jobID, n)
-	if err := o.Registry.Register(newNode); err != nil {
+	newNode := getNodeFromDatabaseEntry(jobID, n, settings)
+	_, registerSpan := tracing.Start(ctx, "orchestrator.register")
+	err = o.Registry.Register(newNode)
+	tracing.Finish(registerSpan, err)
+	if err != nil {
 		l.Errorw("no available ports",
 			"error", err)
 		return NetworkDetails{}, fmt.Errorf("failed to allocate resources for network '%s': %s", network, err)
@@ -135,7 +266,10 @@ func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (NetworkDe
 	// instantiate node
 	l = l.With("node", newNode)
 	l.Info("network registered, creating node")
-	if err := o.client.CreateNode(ctx, newNode, opts); err != nil {
+	createCtx, createSpan := tracing.Start(ctx, "ipfs.create_node")
+	err = o.client.CreateNode(createCtx, newNode, opts)
+	tracing.Finish(createSpan, err)
+	if err != nil {
 		l.Errorw("unable to create node - deregistering",
 			"error", err)
 		o.Registry.Deregister(newNode.NetworkID)
@@ -143,7 +277,9 @@ func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (NetworkDe
 	}
 	l.Info("node created")
 
-	s, err := o.client.NodeStats(ctx, newNode)
+	statsCtx, statsSpan := tracing.Start(ctx, "ipfs.node_stats")
+	s, err := o.client.NodeStats(statsCtx, newNode)
+	tracing.Finish(statsSpan, err)
 	if err != nil {
 		l.Errorw("failed to get node stats after node started up successfully", "error", err)
 		return NetworkDetails{NetworkID: network}, fmt.Errorf("failed to get stats about network '%s': %s",
@@ -153,10 +289,14 @@ func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (NetworkDe
 	// update network in database
 	n.PeerKey = s.PeerKey
 	n.SwarmKey = string(opts.SwarmKey)
-	n.SwarmAddr = fmt.Sprintf("%s:%s", o.address, newNode.Ports.Swarm)
+	swarmAddr, altSwarmAddrs := swarmAddresses(o.addresses, o.bind.Swarm, newNode.Ports.Swarm)
+	n.SwarmAddr = swarmAddr
 	var now = time.Now()
 	n.Activated = &now
-	if err := o.nm.SaveNetwork(n); err != nil {
+	_, saveSpan := tracing.Start(ctx, "database.save_network")
+	err = o.nm.SaveNetwork(n)
+	tracing.Finish(saveSpan, err)
+	if err != nil {
 		l.Errorw("failed to update database - removing node",
 			"error", err,
 			"entry", n)
@@ -173,6 +313,9 @@ func (o *Orchestrator) NetworkUp(ctx context.Context, network string) (NetworkDe
 		PeerID:    s.PeerID,
 		SwarmPort: newNode.Ports.Swarm,
 		SwarmKey:  n.SwarmKey,
+
+		SwarmAddr:     swarmAddr,
+		AltSwarmAddrs: altSwarmAddrs,
 	}, nil
 }
 
@@ -194,6 +337,7 @@ func (o *Orchestrator) NetworkUpdate(ctx context.Context, network string) error
 		"job_id", jobID,
 		"network", network)
 	l.Info("network up process started")
+	defer o.networkChanged(network)
 
 	// retrieve from database
 	n, err := o.nm.GetNetworkByName(network)
@@ -206,8 +350,16 @@ func (o *Orchestrator) NetworkUpdate(ctx context.Context, network string) error
 	l = l.With("network.db_id", n.ID)
 	l.Info("network retrieved from database")
 
-	// construct new node based on new config and old settings
-	var new = getNodeFromDatabaseEntry(jobID, n)
+	settings, err := o.settings.GetNetworkSettings(network)
+	if err != nil {
+		l.Warnw("failed to fetch network settings from database - continuing without labels",
+			"error", err)
+		settings = nil
+	}
+
+	// construct new node based on new config and old settings - labels of the
+	// node's container are only updated when the node is next created
+	var new = getNodeFromDatabaseEntry(jobID, n, settings)
 	new.DockerID = node.DockerID
 	new.Ports = node.Ports
 	new.DataDir = node.DataDir
@@ -245,6 +397,7 @@ func (o *Orchestrator) NetworkDown(ctx context.Context, network string) error {
 		"job_id", jobID,
 		"network", network)
 	l.Info("network up process started")
+	defer o.networkChanged(network)
 
 	// retrieve node from registry
 	node, err := o.Registry.Get(network)
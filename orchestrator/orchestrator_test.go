@@ -15,6 +15,7 @@ import (
 	"github.com/RTradeLtd/Nexus/ipfs/mock"
 	"github.com/RTradeLtd/Nexus/log"
 	"github.com/RTradeLtd/Nexus/registry"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
 )
 
 func TestNew(t *testing.T) {
@@ -45,7 +46,7 @@ func TestNew(t *testing.T) {
 				t.Fatalf("failed to reach database: %s\n", err.Error())
 			}
 
-			_, err = New(l, "", config.Ports{}, true, client, models.NewHostedNetworkManager(dbm.DB))
+			_, err = New(l, nil, config.Ports{}, config.Bind{}, true, client, models.NewHostedNetworkManager(dbm.DB), &smock.FakeSettings{}, &smock.FakeTokens{}, &smock.FakeDenylist{}, &smock.FakeDomains{}, &smock.FakeUsage{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -61,7 +62,7 @@ func TestOrchestrator_Run(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to reach database: %s\n", err.Error())
 	}
-	o, err := New(l, "", config.Ports{}, true, client, models.NewHostedNetworkManager(dbm.DB))
+	o, err := New(l, nil, config.Ports{}, config.Bind{}, true, client, models.NewHostedNetworkManager(dbm.DB), &smock.FakeSettings{}, &smock.FakeTokens{}, &smock.FakeDenylist{}, &smock.FakeDomains{}, &smock.FakeUsage{})
 	if err != nil {
 		t.Error(err)
 		return
@@ -118,11 +119,12 @@ func TestOrchestrator_NetworkUp(t *testing.T) {
 			l, _ := log.NewTestLogger()
 			client := &mock.FakeNodeClient{}
 			o := &Orchestrator{
-				Registry: registry.New(l, tt.fields.regPorts),
-				l:        l,
-				nm:       nm,
-				client:   client,
-				address:  "127.0.0.1",
+				Registry:  registry.New(l, tt.fields.regPorts, config.Bind{}),
+				l:         l,
+				nm:        nm,
+				settings:  &smock.FakeSettings{},
+				client:    client,
+				addresses: []string{"127.0.0.1"},
 			}
 
 			if tt.createErr {
@@ -183,11 +185,12 @@ func TestOrchestrator_NetworkDown(t *testing.T) {
 			l, _ := log.NewTestLogger()
 			client := &mock.FakeNodeClient{}
 			o := &Orchestrator{
-				Registry: registry.New(l, config.New().Ports, &tt.fields.node),
-				l:        l,
-				nm:       nm,
-				client:   client,
-				address:  "127.0.0.1",
+				Registry:  registry.New(l, config.New().Ports, config.Bind{}, &tt.fields.node),
+				l:         l,
+				nm:        nm,
+				settings:  &smock.FakeSettings{},
+				client:    client,
+				addresses: []string{"127.0.0.1"},
 			}
 
 			if tt.createErr {
@@ -225,10 +228,10 @@ func TestOrchestrator_NetworkRemove(t *testing.T) {
 			l, _ := log.NewTestLogger()
 			client := &mock.FakeNodeClient{}
 			o := &Orchestrator{
-				Registry: registry.New(l, config.New().Ports, &tt.fields.node),
-				l:        l,
-				client:   client,
-				address:  "127.0.0.1",
+				Registry:  registry.New(l, config.New().Ports, config.Bind{}, &tt.fields.node),
+				l:         l,
+				client:    client,
+				addresses: []string{"127.0.0.1"},
 			}
 
 			if tt.createErr {
@@ -292,11 +295,11 @@ func TestOrchestrator_NetworkUpdate(t *testing.T) {
 			l, _ := log.NewTestLogger()
 			client := &mock.FakeNodeClient{}
 			o := &Orchestrator{
-				Registry: registry.New(l, config.New().Ports, &tt.fields.node),
-				l:        l,
-				client:   client,
-				nm:       nm,
-				address:  "127.0.0.1",
+				Registry:  registry.New(l, config.New().Ports, config.Bind{}, &tt.fields.node),
+				l:         l,
+				client:    client,
+				nm:        nm,
+				addresses: []string{"127.0.0.1"},
 			}
 
 			if tt.createErr {
@@ -334,10 +337,10 @@ func TestOrchestrator_NetworkStatus(t *testing.T) {
 			l, _ := log.NewTestLogger()
 			client := &mock.FakeNodeClient{}
 			o := &Orchestrator{
-				Registry: registry.New(l, config.New().Ports, &tt.fields.node),
-				l:        l,
-				client:   client,
-				address:  "127.0.0.1",
+				Registry:  registry.New(l, config.New().Ports, config.Bind{}, &tt.fields.node),
+				l:         l,
+				client:    client,
+				addresses: []string{"127.0.0.1"},
 			}
 
 			if tt.createErr {
@@ -375,10 +378,10 @@ func TestOrchestrator_NetworkDiagnostics(t *testing.T) {
 			l, _ := log.NewTestLogger()
 			client := &mock.FakeNodeClient{}
 			o := &Orchestrator{
-				Registry: registry.New(l, config.New().Ports, &tt.fields.node),
-				l:        l,
-				client:   client,
-				address:  "127.0.0.1",
+				Registry:  registry.New(l, config.New().Ports, config.Bind{}, &tt.fields.node),
+				l:         l,
+				client:    client,
+				addresses: []string{"127.0.0.1"},
 			}
 
 			if tt.createErr {
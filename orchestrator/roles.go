@@ -0,0 +1,82 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// NetworkUser is a member of a network
+type NetworkUser struct {
+	User string
+	// Role is the user's assigned role, or empty if the user has the default
+	// role
+	Role string
+}
+
+// NetworkUsers lists the members of a network - users listed by Temporal, and
+// users assigned a role in Nexus
+func (o *Orchestrator) NetworkUsers(network string) ([]NetworkUser, error) {
+	if network == "" {
+		return nil, errors.New("invalid network name provided")
+	}
+	entry, err := o.nm.GetNetworkByName(network)
+	if err != nil {
+		return nil, fmt.Errorf("no network with name '%s' found", network)
+	}
+	s, err := o.settings.GetNetworkSettings(network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve settings for network '%s': %s", network, err.Error())
+	}
+
+	var roles = make(map[string]string, len(entry.Users)+len(s.Roles))
+	for _, u := range entry.Users {
+		roles[u] = ""
+	}
+	for u, role := range s.Roles {
+		roles[u] = role
+	}
+	var users = make([]NetworkUser, 0, len(roles))
+	for u, role := range roles {
+		users = append(users, NetworkUser{User: u, Role: role})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].User < users[j].User })
+	return users, nil
+}
+
+// SetUserRole assigns a role to a network user. An empty role removes the
+// user's assigned role.
+func (o *Orchestrator) SetUserRole(network, user, role string) error {
+	if user == "" {
+		return errors.New("invalid user provided")
+	}
+	if role != "" && !store.ValidRole(role) {
+		return fmt.Errorf("unknown role '%s'", role)
+	}
+	s, err := o.NetworkSettings(network)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		delete(s.Roles, user)
+	} else {
+		if s.Roles == nil {
+			s.Roles = store.Roles{}
+		}
+		s.Roles[user] = role
+	}
+	if err := o.settings.SaveNetworkSettings(s); err != nil {
+		o.l.Errorw("failed to save network settings",
+			"network", network,
+			"error", err)
+		return fmt.Errorf("failed to update role for user '%s': %s", user, err.Error())
+	}
+	o.l.Infow("network user role updated",
+		"network", network,
+		"user", user,
+		"role", role)
+	o.networkChanged(network)
+	return nil
+}
@@ -0,0 +1,97 @@
+package orchestrator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	tmock "github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestOrchestrator_NetworkUsers(t *testing.T) {
+	var (
+		l, _     = log.NewTestLogger()
+		nm       = &tmock.FakePrivateNetworks{}
+		settings = &smock.FakeSettings{}
+		o        = &Orchestrator{l: l, nm: nm, settings: settings}
+	)
+	nm.GetNetworkByNameReturns(&models.HostedNetwork{Users: []string{"bobheadxi", "postables"}}, nil)
+	settings.GetNetworkSettingsReturns(&store.NetworkSettings{
+		Roles: store.Roles{"postables": store.RoleAdmin, "xiaoxiangirl": store.RoleViewer},
+	}, nil)
+
+	users, err := o.NetworkUsers("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want = []NetworkUser{
+		{User: "bobheadxi"},
+		{User: "postables", Role: store.RoleAdmin},
+		{User: "xiaoxiangirl", Role: store.RoleViewer},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("Orchestrator.NetworkUsers() = %v, want %v", users, want)
+	}
+}
+
+func TestOrchestrator_SetUserRole(t *testing.T) {
+	type args struct {
+		user string
+		role string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		saveErr   bool
+		wantRoles store.Roles
+		wantErr   bool
+	}{
+		{"no user", args{"", store.RoleAdmin}, false, nil, true},
+		{"unknown role", args{"bobheadxi", "owner"}, false, nil, true},
+		{"save error", args{"bobheadxi", store.RoleAdmin}, true, nil, true},
+		{"assign", args{"bobheadxi", store.RoleAdmin}, false,
+			store.Roles{"bobheadxi": store.RoleAdmin, "postables": store.RoleViewer}, false},
+		{"remove", args{"postables", ""}, false, store.Roles{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _     = log.NewTestLogger()
+				nm       = &tmock.FakePrivateNetworks{}
+				settings = &smock.FakeSettings{}
+				o        = &Orchestrator{l: l, nm: nm, settings: settings}
+			)
+			nm.GetNetworkByNameReturns(&models.HostedNetwork{Name: "test"}, nil)
+			settings.GetNetworkSettingsReturns(&store.NetworkSettings{
+				Network: "test",
+				Roles:   store.Roles{"postables": store.RoleViewer},
+			}, nil)
+			if tt.saveErr {
+				settings.SaveNetworkSettingsReturns(errors.New("oh no"))
+			}
+			var changed []string
+			o.OnNetworkChange(func(network string) { changed = append(changed, network) })
+
+			if err := o.SetUserRole("test", tt.args.user, tt.args.role); (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.SetUserRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				var saved = settings.SaveNetworkSettingsArgsForCall(0)
+				if !reflect.DeepEqual(saved.Roles, tt.wantRoles) {
+					t.Errorf("saved roles = %v, want %v", saved.Roles, tt.wantRoles)
+				}
+				if !reflect.DeepEqual(changed, []string{"test"}) {
+					t.Errorf("network changes = %v, want [test]", changed)
+				}
+			} else if len(changed) > 0 {
+				t.Errorf("unexpected network changes %v", changed)
+			}
+		})
+	}
+}
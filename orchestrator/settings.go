@@ -0,0 +1,111 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// NetworkSettings retrieves the Nexus-specific settings of given network
+func (o *Orchestrator) NetworkSettings(network string) (*store.NetworkSettings, error) {
+	if network == "" {
+		return nil, errors.New("invalid network name provided")
+	}
+	if _, err := o.nm.GetNetworkByName(network); err != nil {
+		return nil, fmt.Errorf("no network with name '%s' found", network)
+	}
+	s, err := o.settings.GetNetworkSettings(network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve settings for network '%s': %s", network, err.Error())
+	}
+	return s, nil
+}
+
+// UpdateNetworkSettings applies given JSON object to the settings of given
+// network. Labels are updated in the registry immediately, but the labels of
+// a running node's container are only updated when the node is next created.
+func (o *Orchestrator) UpdateNetworkSettings(network string, patch []byte) (*store.NetworkSettings, error) {
+	s, err := o.NetworkSettings(network)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Apply(patch); err != nil {
+		return nil, err
+	}
+	if err := o.settings.SaveNetworkSettings(s); err != nil {
+		o.l.Errorw("failed to save network settings",
+			"network", network,
+			"error", err)
+		return nil, fmt.Errorf("failed to update settings for network '%s': %s", network, err.Error())
+	}
+	o.l.Infow("network settings updated",
+		"network", network,
+		"settings", s)
+	o.networkChanged(network)
+
+	// reflect changes in registry if network is online
+	var labels map[string]string
+	if len(s.Labels) > 0 {
+		labels = s.Labels
+	}
+	if err := o.Registry.SetLabels(network, labels); err != nil {
+		o.l.Debugw("network not registered - skipping registry update",
+			"network", network)
+	}
+
+	return s, nil
+}
+
+// BulkAction denotes an operation that can be applied to many networks at once
+type BulkAction string
+
+const (
+	// BulkUpdate executes NetworkUpdate on each network
+	BulkUpdate BulkAction = "update"
+
+	// BulkDown executes NetworkDown on each network
+	BulkDown BulkAction = "down"
+)
+
+// BulkResult denotes the outcome of a bulk action on a single network
+type BulkResult struct {
+	Network string
+	Err     error
+}
+
+// NetworksBulk applies given action to each registered network with labels
+// that match given selector. An error is only returned if the action could not
+// be started - errors for individual networks are reported in the results.
+func (o *Orchestrator) NetworksBulk(ctx context.Context, selector registry.Selector,
+	action BulkAction) ([]BulkResult, error) {
+	if selector.Empty() {
+		return nil, errors.New("a label selector is required for bulk actions")
+	}
+	var exec func(ctx context.Context, network string) error
+	switch action {
+	case BulkUpdate:
+		exec = o.NetworkUpdate
+	case BulkDown:
+		exec = o.NetworkDown
+	default:
+		return nil, fmt.Errorf("unknown bulk action '%s'", action)
+	}
+
+	entries, _, err := o.Registry.Query(registry.Query{Labels: selector})
+	if err != nil {
+		return nil, err
+	}
+	o.l.Infow("executing bulk action",
+		"action", action,
+		"selector", selector.String(),
+		"networks", len(entries))
+
+	var results = make([]BulkResult, len(entries))
+	for i, e := range entries {
+		results[i] = BulkResult{Network: e.NetworkID, Err: exec(ctx, e.NetworkID)}
+	}
+	return results, nil
+}
@@ -0,0 +1,145 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/ipfs/mock"
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	tmock "github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestOrchestrator_UpdateNetworkSettings(t *testing.T) {
+	type args struct {
+		network string
+		patch   string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		getErr     bool
+		saveErr    bool
+		wantLabels map[string]string
+		wantErr    bool
+	}{
+		{"invalid network", args{"", `{}`}, false, false, nil, true},
+		{"unknown network", args{"bobheadxi", `{}`}, true, false, nil, true},
+		{"invalid patch", args{"bobheadxi", `{"color":"blue"}`}, false, false, nil, true},
+		{"save error", args{"bobheadxi", `{"labels":{"tier":"trial"}}`}, false, true, nil, true},
+		{"set labels", args{"bobheadxi", `{"labels":{"tier":"trial"}}`}, false, false,
+			map[string]string{"tier": "trial"}, false},
+		{"unset labels", args{"bobheadxi", `{"labels":null}`}, false, false, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _     = log.NewTestLogger()
+				nm       = &tmock.FakePrivateNetworks{}
+				settings = &smock.FakeSettings{}
+				o        = &Orchestrator{
+					Registry: registry.New(l, config.New().Ports, config.Bind{},
+						&ipfs.NodeInfo{NetworkID: "bobheadxi", Labels: map[string]string{"tier": "paid"}}),
+					l:        l,
+					nm:       nm,
+					settings: settings,
+				}
+			)
+			defer o.Registry.Close()
+			if tt.getErr {
+				nm.GetNetworkByNameReturns(nil, errors.New("oh no"))
+			} else {
+				nm.GetNetworkByNameReturns(&models.HostedNetwork{Name: tt.args.network}, nil)
+			}
+			settings.GetNetworkSettingsReturns(&store.NetworkSettings{Network: tt.args.network}, nil)
+			if tt.saveErr {
+				settings.SaveNetworkSettingsReturns(errors.New("oh no"))
+			}
+
+			_, err := o.UpdateNetworkSettings(tt.args.network, []byte(tt.args.patch))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.UpdateNetworkSettings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				n, _ := o.Registry.Get(tt.args.network)
+				if !reflect.DeepEqual(n.Labels, tt.wantLabels) {
+					t.Errorf("registry labels = %v, want %v", n.Labels, tt.wantLabels)
+				}
+			}
+		})
+	}
+}
+
+func TestOrchestrator_NetworksBulk(t *testing.T) {
+	type args struct {
+		selector string
+		action   BulkAction
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantResults []string
+		wantErr     bool
+	}{
+		{"no selector", args{"", BulkDown}, nil, true},
+		{"unknown action", args{"tier=trial", "explode"}, nil, true},
+		{"no matches", args{"tier=enterprise", BulkDown}, []string{}, false},
+		{"down", args{"tier=trial", BulkDown}, []string{"team-a", "team-c"}, false},
+		{"update", args{"tier!=trial", BulkUpdate}, []string{"team-b"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _   = log.NewTestLogger()
+				client = &mock.FakeNodeClient{}
+				nm     = &tmock.FakePrivateNetworks{}
+				o      = &Orchestrator{
+					Registry: registry.New(l, config.New().Ports, config.Bind{},
+						&ipfs.NodeInfo{NetworkID: "team-a", Labels: map[string]string{"tier": "trial"}},
+						&ipfs.NodeInfo{NetworkID: "team-b", Labels: map[string]string{"tier": "paid"}},
+						&ipfs.NodeInfo{NetworkID: "team-c", Labels: map[string]string{"tier": "trial"}}),
+					l:        l,
+					nm:       nm,
+					settings: &smock.FakeSettings{},
+					client:   client,
+				}
+			)
+			defer o.Registry.Close()
+			nm.GetNetworkByNameStub = func(name string) (*models.HostedNetwork, error) {
+				return &models.HostedNetwork{Name: name}, nil
+			}
+
+			selector, err := registry.ParseSelector(tt.args.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results, err := o.NetworksBulk(context.Background(), selector, tt.args.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.NetworksBulk() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var networks = make([]string, len(results))
+			for i, r := range results {
+				if r.Err != nil {
+					t.Errorf("unexpected error for network '%s': %v", r.Network, r.Err)
+				}
+				networks[i] = r.Network
+			}
+			if !reflect.DeepEqual(networks, tt.wantResults) {
+				t.Errorf("Orchestrator.NetworksBulk() = %v, want %v", networks, tt.wantResults)
+			}
+		})
+	}
+}
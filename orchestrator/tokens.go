@@ -0,0 +1,62 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// CreateAPIToken issues a new scoped API token for given network. The returned
+// secret is not stored, and cannot be retrieved again.
+func (o *Orchestrator) CreateAPIToken(network, name string, scopes []string,
+	expiry time.Duration) (string, *store.APIToken, error) {
+	if network == "" {
+		return "", nil, errors.New("invalid network name provided")
+	}
+	if _, err := o.nm.GetNetworkByName(network); err != nil {
+		return "", nil, fmt.Errorf("no network with name '%s' found", network)
+	}
+	secret, t, err := store.NewAPIToken(network, name, scopes, expiry)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := o.tokens.CreateToken(t); err != nil {
+		o.l.Errorw("failed to save API token",
+			"network", network,
+			"error", err)
+		return "", nil, fmt.Errorf("failed to create token for network '%s': %s", network, err.Error())
+	}
+	o.l.Infow("API token created",
+		"network", network,
+		"token", t.TokenID,
+		"scopes", t.Scopes)
+	return secret, t, nil
+}
+
+// RevokeAPIToken revokes the token with given ID
+func (o *Orchestrator) RevokeAPIToken(network, tokenID string) error {
+	if network == "" || tokenID == "" {
+		return errors.New("network and token ID must be provided")
+	}
+	if err := o.tokens.RevokeToken(network, tokenID); err != nil {
+		return fmt.Errorf("failed to revoke token '%s': %s", tokenID, err.Error())
+	}
+	o.l.Infow("API token revoked",
+		"network", network,
+		"token", tokenID)
+	return nil
+}
+
+// APITokens lists the tokens issued for given network
+func (o *Orchestrator) APITokens(network string) ([]*store.APIToken, error) {
+	if network == "" {
+		return nil, errors.New("invalid network name provided")
+	}
+	tokens, err := o.tokens.ListTokens(network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tokens for network '%s': %s", network, err.Error())
+	}
+	return tokens, nil
+}
@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/database/v2/models"
+
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+	tmock "github.com/RTradeLtd/Nexus/temporal/mock"
+)
+
+func TestOrchestrator_CreateAPIToken(t *testing.T) {
+	type args struct {
+		network string
+		scopes  []string
+		expiry  time.Duration
+	}
+	tests := []struct {
+		name    string
+		args    args
+		getErr  bool
+		saveErr bool
+		wantErr bool
+	}{
+		{"invalid network", args{"", []string{store.ScopeAdmin}, 0}, false, false, true},
+		{"unknown network", args{"bobheadxi", []string{store.ScopeAdmin}, 0}, true, false, true},
+		{"invalid scope", args{"bobheadxi", []string{"root"}, 0}, false, false, true},
+		{"save error", args{"bobheadxi", []string{store.ScopeAdmin}, 0}, false, true, true},
+		{"ok", args{"bobheadxi", []string{store.ScopePinOnly}, time.Hour}, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _   = log.NewTestLogger()
+				nm     = &tmock.FakePrivateNetworks{}
+				tokens = &smock.FakeTokens{}
+				o      = &Orchestrator{l: l, nm: nm, tokens: tokens}
+			)
+			if tt.getErr {
+				nm.GetNetworkByNameReturns(nil, errors.New("oh no"))
+			} else {
+				nm.GetNetworkByNameReturns(&models.HostedNetwork{Name: tt.args.network}, nil)
+			}
+			if tt.saveErr {
+				tokens.CreateTokenReturns(errors.New("oh no"))
+			}
+
+			secret, token, err := o.CreateAPIToken(tt.args.network, "ci", tt.args.scopes, tt.args.expiry)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.CreateAPIToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				if !store.IsAPIToken(secret) || token.Hash != store.HashToken(secret) {
+					t.Errorf("unexpected token %s for secret %s", token.Hash, secret)
+				}
+				if tokens.CreateTokenCallCount() != 1 {
+					t.Error("expected token to be saved")
+				}
+			}
+		})
+	}
+}
+
+func TestOrchestrator_RevokeAPIToken(t *testing.T) {
+	type args struct {
+		network string
+		tokenID string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		revokeErr bool
+		wantErr   bool
+	}{
+		{"missing args", args{"bobheadxi", ""}, false, true},
+		{"revoke error", args{"bobheadxi", "abcd"}, true, true},
+		{"ok", args{"bobheadxi", "abcd"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _   = log.NewTestLogger()
+				tokens = &smock.FakeTokens{}
+				o      = &Orchestrator{l: l, tokens: tokens}
+			)
+			if tt.revokeErr {
+				tokens.RevokeTokenReturns(errors.New("oh no"))
+			}
+			if err := o.RevokeAPIToken(tt.args.network, tt.args.tokenID); (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.RevokeAPIToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
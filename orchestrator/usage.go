@@ -0,0 +1,187 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+// trafficSample is the last known value of a node container's network
+// counters, and of the API and gateway traffic delegators have recorded for
+// the node
+type trafficSample struct {
+	dockerID string
+	traffic  ipfs.NodeTraffic
+	// proxied is the traffic recorded by delegators for hours starting at or
+	// after proxiedSince, and owed is proxied traffic that has not been
+	// subtracted from the node's counters yet
+	proxiedSince time.Time
+	proxied      ipfs.NodeTraffic
+	owed         ipfs.NodeTraffic
+}
+
+// MeterTraffic samples the network counters of node containers at given
+// interval until the context is cancelled, and records the traffic since each
+// node's previous sample as swarm usage. The counters cover all traffic of a
+// node, so API and gateway traffic recorded by delegators is subtracted from
+// them.
+func (o *Orchestrator) MeterTraffic(ctx context.Context, interval time.Duration) {
+	var (
+		ticker = time.NewTicker(interval)
+		last   = make(map[string]trafficSample)
+	)
+	defer ticker.Stop()
+	last = o.sampleTraffic(ctx, last)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			last = o.sampleTraffic(ctx, last)
+		}
+	}
+}
+
+// sampleTraffic records the traffic of each node since its previous sample,
+// and returns the new samples. Nodes without a previous sample only have
+// their counters sampled, since it is not known when counting began. If the
+// traffic cannot be recorded, the previous samples are returned so that it is
+// recorded with the next sample.
+func (o *Orchestrator) sampleTraffic(ctx context.Context, last map[string]trafficSample) map[string]trafficSample {
+	var (
+		hour = store.UsageHour(time.Now())
+		// delegators record usage periodically, so proxied traffic is
+		// tracked from the previous hour to catch usage recorded late
+		since   = hour.Add(-time.Hour)
+		next    = make(map[string]trafficSample)
+		records []*store.UsageRecord
+	)
+	for _, n := range o.Registry.List() {
+		prev, found := last[n.NetworkID]
+		traffic, err := o.client.NodeTraffic(ctx, &n)
+		if err != nil {
+			o.l.Debugw("failed to sample node traffic",
+				"network", n.NetworkID,
+				"error", err)
+			if found {
+				next[n.NetworkID] = prev
+			}
+			continue
+		}
+		var querySince = since
+		if found {
+			querySince = prev.proxiedSince
+		}
+		total, proxied, err := o.proxiedTraffic(n.NetworkID, querySince, since)
+		if err != nil {
+			o.l.Warnw("failed to retrieve proxied traffic - retrying on next sample",
+				"network", n.NetworkID,
+				"error", err)
+			if found {
+				next[n.NetworkID] = prev
+			}
+			continue
+		}
+		var sample = trafficSample{dockerID: n.DockerID, traffic: traffic,
+			proxiedSince: since, proxied: proxied}
+		if !found {
+			next[n.NetworkID] = sample
+			continue
+		}
+
+		// counters are reset when containers are recreated or restarted
+		var delta = traffic
+		if prev.dockerID == n.DockerID &&
+			traffic.RxBytes >= prev.traffic.RxBytes && traffic.TxBytes >= prev.traffic.TxBytes {
+			delta.RxBytes -= prev.traffic.RxBytes
+			delta.TxBytes -= prev.traffic.TxBytes
+		}
+		// proxied traffic can be recorded after the node's counters have
+		// been sampled, so any that exceeds the node's traffic is subtracted
+		// from later samples
+		var owed = ipfs.NodeTraffic{
+			RxBytes: prev.owed.RxBytes + nonNegative(total.RxBytes-prev.proxied.RxBytes),
+			TxBytes: prev.owed.TxBytes + nonNegative(total.TxBytes-prev.proxied.TxBytes),
+		}
+		delta.RxBytes, owed.RxBytes = subtractOwed(delta.RxBytes, owed.RxBytes)
+		delta.TxBytes, owed.TxBytes = subtractOwed(delta.TxBytes, owed.TxBytes)
+		sample.owed = owed
+		next[n.NetworkID] = sample
+		if delta.RxBytes == 0 && delta.TxBytes == 0 {
+			continue
+		}
+		records = append(records, &store.UsageRecord{
+			Hour:     hour,
+			Network:  n.NetworkID,
+			Feature:  "swarm",
+			BytesIn:  delta.RxBytes,
+			BytesOut: delta.TxBytes,
+		})
+	}
+	if len(records) == 0 {
+		return next
+	}
+	if err := o.usage.AddUsage(records); err != nil {
+		o.l.Warnw("failed to record node traffic - retrying on next sample",
+			"error", err)
+		return last
+	}
+	return next
+}
+
+// proxiedTraffic retrieves the API and gateway traffic delegators have
+// recorded for given network in hours starting at or after since, and the
+// portion of it recorded in hours starting at or after window
+func (o *Orchestrator) proxiedTraffic(network string, since, window time.Time) (total, windowed ipfs.NodeTraffic, err error) {
+	records, err := o.usage.QueryUsage(store.UsageQuery{Network: network, Since: since})
+	if err != nil {
+		return total, windowed, fmt.Errorf("failed to retrieve usage: %s", err.Error())
+	}
+	for _, r := range records {
+		if r.Feature == "swarm" {
+			continue
+		}
+		total.RxBytes += r.BytesIn
+		total.TxBytes += r.BytesOut
+		if !r.Hour.Before(window) {
+			windowed.RxBytes += r.BytesIn
+			windowed.TxBytes += r.BytesOut
+		}
+	}
+	return total, windowed, nil
+}
+
+// subtractOwed subtracts as much of owed from n as possible, and returns
+// what remains of both
+func subtractOwed(n, owed int64) (int64, int64) {
+	if owed > n {
+		return 0, owed - n
+	}
+	return n - owed, 0
+}
+
+func nonNegative(n int64) int64 {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Usage retrieves the hourly usage aggregates that match given query
+func (o *Orchestrator) Usage(q store.UsageQuery) ([]*store.UsageRecord, error) {
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Until.After(q.Since) {
+		return nil, errors.New("end of time range must be after its start")
+	}
+	records, err := o.usage.QueryUsage(q)
+	if err != nil {
+		o.l.Errorw("failed to retrieve usage",
+			"query", q,
+			"error", err)
+		return nil, fmt.Errorf("failed to retrieve usage: %s", err.Error())
+	}
+	return records, nil
+}
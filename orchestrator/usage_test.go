@@ -0,0 +1,185 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/ipfs/mock"
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/registry"
+	"github.com/RTradeLtd/Nexus/store"
+	smock "github.com/RTradeLtd/Nexus/store/mock"
+)
+
+func TestOrchestrator_sampleTraffic(t *testing.T) {
+	var (
+		l, _   = log.NewTestLogger()
+		client = &mock.FakeNodeClient{}
+		usage  = &smock.FakeUsage{}
+		o      = &Orchestrator{
+			Registry: registry.New(l, config.New().Ports, config.Bind{},
+				&ipfs.NodeInfo{NetworkID: "bobheadxi", DockerID: "1"}),
+			l:      l,
+			client: client,
+			usage:  usage,
+		}
+		traffic = func(rx, tx int64) {
+			client.NodeTrafficReturns(ipfs.NodeTraffic{RxBytes: rx, TxBytes: tx}, nil)
+		}
+	)
+	defer o.Registry.Close()
+
+	// first samples are not recorded, since it is unknown when counting began
+	traffic(100, 200)
+	var last = o.sampleTraffic(context.Background(), map[string]trafficSample{})
+	if usage.AddUsageCallCount() != 0 {
+		t.Fatal("expected first sample not to be recorded")
+	}
+
+	// traffic since the previous sample is recorded
+	traffic(150, 300)
+	last = o.sampleTraffic(context.Background(), last)
+	if usage.AddUsageCallCount() != 1 {
+		t.Fatal("expected traffic to be recorded")
+	}
+	if r := usage.AddUsageArgsForCall(0)[0]; r.Network != "bobheadxi" || r.Feature != "swarm" ||
+		r.BytesIn != 50 || r.BytesOut != 100 {
+		t.Errorf("unexpected record %+v", r)
+	}
+
+	// no traffic is not recorded
+	last = o.sampleTraffic(context.Background(), last)
+	if usage.AddUsageCallCount() != 1 {
+		t.Error("expected idle node not to be recorded")
+	}
+
+	// traffic that fails to be recorded is recorded with the next sample
+	traffic(200, 400)
+	usage.AddUsageReturns(errors.New("oh no"))
+	last = o.sampleTraffic(context.Background(), last)
+	usage.AddUsageReturns(nil)
+	traffic(210, 410)
+	last = o.sampleTraffic(context.Background(), last)
+	if r := usage.AddUsageArgsForCall(2)[0]; r.BytesIn != 60 || r.BytesOut != 110 {
+		t.Errorf("expected traffic since last recorded sample, got %+v", r)
+	}
+
+	// counters reset when containers restart
+	traffic(5, 10)
+	last = o.sampleTraffic(context.Background(), last)
+	if r := usage.AddUsageArgsForCall(3)[0]; r.BytesIn != 5 || r.BytesOut != 10 {
+		t.Errorf("expected traffic since restart, got %+v", r)
+	}
+
+	// nodes that cannot be sampled keep their previous sample
+	client.NodeTrafficReturns(ipfs.NodeTraffic{}, errors.New("oh no"))
+	last = o.sampleTraffic(context.Background(), last)
+	if s := last["bobheadxi"]; s.traffic.RxBytes != 5 || s.dockerID != "1" {
+		t.Errorf("expected previous sample to be kept, got %+v", s)
+	}
+}
+
+func TestOrchestrator_sampleTraffic_proxied(t *testing.T) {
+	var (
+		l, _   = log.NewTestLogger()
+		client = &mock.FakeNodeClient{}
+		usage  = &smock.FakeUsage{}
+		o      = &Orchestrator{
+			Registry: registry.New(l, config.New().Ports, config.Bind{},
+				&ipfs.NodeInfo{NetworkID: "bobheadxi", DockerID: "1"}),
+			l:      l,
+			client: client,
+			usage:  usage,
+		}
+		hour    = store.UsageHour(time.Now())
+		traffic = func(rx, tx int64) {
+			client.NodeTrafficReturns(ipfs.NodeTraffic{RxBytes: rx, TxBytes: tx}, nil)
+		}
+		proxied = func(records ...*store.UsageRecord) {
+			usage.QueryUsageReturns(append(records,
+				&store.UsageRecord{Hour: hour, Network: "bobheadxi", Feature: "swarm", BytesIn: 1000, BytesOut: 1000}), nil)
+		}
+	)
+	defer o.Registry.Close()
+
+	traffic(100, 200)
+	proxied()
+	var last = o.sampleTraffic(context.Background(), map[string]trafficSample{})
+
+	// API and gateway traffic recorded by delegators is not counted as swarm
+	// traffic
+	traffic(150, 300)
+	proxied(&store.UsageRecord{Hour: hour, Network: "bobheadxi", Feature: "gateway", BytesIn: 10, BytesOut: 60})
+	last = o.sampleTraffic(context.Background(), last)
+	if r := usage.AddUsageArgsForCall(0)[0]; r.Feature != "swarm" || r.BytesIn != 40 || r.BytesOut != 40 {
+		t.Errorf("expected swarm usage to exclude proxied traffic, got %+v", r)
+	}
+
+	// proxied traffic recorded after the node's counters were sampled is
+	// subtracted from later samples
+	traffic(160, 310)
+	proxied(&store.UsageRecord{Hour: hour, Network: "bobheadxi", Feature: "gateway", BytesIn: 10, BytesOut: 60},
+		&store.UsageRecord{Hour: hour, Network: "bobheadxi", Feature: "api", User: "bob", BytesIn: 30, BytesOut: 30})
+	last = o.sampleTraffic(context.Background(), last)
+	if usage.AddUsageCallCount() != 1 {
+		t.Fatal("expected traffic covered by proxied traffic not to be recorded")
+	}
+	traffic(200, 350)
+	last = o.sampleTraffic(context.Background(), last)
+	if r := usage.AddUsageArgsForCall(1)[0]; r.BytesIn != 20 || r.BytesOut != 20 {
+		t.Errorf("expected remaining proxied traffic to be subtracted, got %+v", r)
+	}
+
+	// samples are kept if proxied traffic cannot be retrieved
+	usage.QueryUsageReturns(nil, errors.New("oh no"))
+	traffic(300, 450)
+	last = o.sampleTraffic(context.Background(), last)
+	if s := last["bobheadxi"]; s.traffic.RxBytes != 200 {
+		t.Errorf("expected previous sample to be kept, got %+v", s)
+	}
+}
+
+func TestOrchestrator_Usage(t *testing.T) {
+	var hour = store.UsageHour(time.Now())
+	tests := []struct {
+		name     string
+		query    store.UsageQuery
+		queryErr bool
+		wantErr  bool
+	}{
+		{"all usage", store.UsageQuery{}, false, false},
+		{"bounded", store.UsageQuery{Network: "bobheadxi", Since: hour, Until: hour.Add(time.Hour)}, false, false},
+		{"only start", store.UsageQuery{Since: hour}, false, false},
+		{"invalid range", store.UsageQuery{Since: hour, Until: hour}, false, true},
+		{"query error", store.UsageQuery{}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				l, _  = log.NewTestLogger()
+				usage = &smock.FakeUsage{}
+				o     = &Orchestrator{l: l, usage: usage}
+			)
+			if tt.queryErr {
+				usage.QueryUsageReturns(nil, errors.New("oh no"))
+			} else {
+				usage.QueryUsageReturns([]*store.UsageRecord{{Network: "bobheadxi"}}, nil)
+			}
+			records, err := o.Usage(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Orchestrator.Usage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(records) != 1 || usage.QueryUsageArgsForCall(0) != tt.query {
+				t.Errorf("unexpected records %v", records)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"io"
+	"net"
+
+	"github.com/RTradeLtd/Nexus/network"
 )
 
 func generateID() string {
@@ -13,6 +16,42 @@ func generateID() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
+// swarmAddresses generates the addresses through which the given swarm port
+// can be reached. Each host address is only published if the swarm port is
+// bound on its address family. The first published address is the primary
+// one, and the rest are returned as alternates.
+func swarmAddresses(addresses, bind []string, port string) (primary string, alternates []string) {
+	for _, addr := range addresses {
+		if addr == "" || !boundOnFamily(addr, bind) {
+			continue
+		}
+		if primary == "" {
+			primary = net.JoinHostPort(addr, port)
+		} else {
+			alternates = append(alternates, net.JoinHostPort(addr, port))
+		}
+	}
+	if primary == "" {
+		return ":" + port, nil
+	}
+	return primary, alternates
+}
+
+// boundOnFamily checks if any of the given bind addresses share a family with
+// addr. Hostnames and empty bind lists are assumed to match.
+func boundOnFamily(addr string, bind []string) bool {
+	var family = network.Family(addr)
+	if family == "tcp" || len(bind) == 0 {
+		return true
+	}
+	for _, b := range bind {
+		if network.Family(b) == family {
+			return true
+		}
+	}
+	return false
+}
+
 func rebootOfflineNodes(orch *Orchestrator) {
 	offlineNetworks, err := orch.nm.GetOfflineNetworks(false)
 	if err != nil {
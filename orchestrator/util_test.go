@@ -1,9 +1,48 @@
 package orchestrator
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func Test_generateID(t *testing.T) {
 	if id := generateID(); id == "" {
 		t.Errorf("invalid ID generated")
 	}
 }
+
+func Test_swarmAddresses(t *testing.T) {
+	type args struct {
+		addresses []string
+		bind      []string
+		port      string
+	}
+	tests := []struct {
+		name           string
+		args           args
+		wantPrimary    string
+		wantAlternates []string
+	}{
+		{"no address", args{nil, []string{"0.0.0.0"}, "4001"}, ":4001", nil},
+		{"ipv4", args{[]string{"1.2.3.4"}, []string{"0.0.0.0"}, "4001"}, "1.2.3.4:4001", nil},
+		{"ipv6 not bound", args{[]string{"1.2.3.4", "2001:db8::1"}, []string{"0.0.0.0"}, "4001"},
+			"1.2.3.4:4001", nil},
+		{"ipv4 not bound", args{[]string{"1.2.3.4", "2001:db8::1"}, []string{"::"}, "4001"},
+			"[2001:db8::1]:4001", nil},
+		{"dual-stack", args{[]string{"1.2.3.4", "2001:db8::1"}, []string{"0.0.0.0", "::"}, "4001"},
+			"1.2.3.4:4001", []string{"[2001:db8::1]:4001"}},
+		{"hostname", args{[]string{"nexus.temporal.cloud"}, []string{"::"}, "4001"},
+			"nexus.temporal.cloud:4001", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, alternates := swarmAddresses(tt.args.addresses, tt.args.bind, tt.args.port)
+			if primary != tt.wantPrimary {
+				t.Errorf("swarmAddresses() primary = %v, want %v", primary, tt.wantPrimary)
+			}
+			if !reflect.DeepEqual(alternates, tt.wantAlternates) {
+				t.Errorf("swarmAddresses() alternates = %v, want %v", alternates, tt.wantAlternates)
+			}
+		})
+	}
+}
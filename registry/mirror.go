@@ -0,0 +1,253 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/rpc"
+)
+
+// Defaults for reconnecting to the daemon
+const (
+	mirrorMinBackoff       = time.Second
+	mirrorHeartbeatTimeout = 90 * time.Second
+)
+
+// Mirror is a read-only copy of the node registry of a daemon, kept up to date
+// by watching the daemon's registry with Sync. Known nodes remain available
+// while the daemon cannot be reached, so that requests can continue to be
+// routed to them.
+type Mirror struct {
+	l                *zap.SugaredLogger
+	minBackoff       time.Duration
+	maxBackoff       time.Duration
+	heartbeatTimeout time.Duration
+
+	// node mirror - locked by Mirror::nm
+	nodes  map[string]ipfs.NodeInfo
+	synced bool
+	nm     sync.RWMutex
+
+	changesMux       sync.RWMutex
+	onNetworkChange  []func(network string)
+	onDenylistChange []func(network string)
+	onDomainChange   []func(host string)
+	onResync         []func()
+}
+
+// NewMirror sets up an empty mirror. Reconnection attempts back off
+// exponentially, waiting at most maxBackoff between attempts.
+func NewMirror(logger *zap.SugaredLogger, maxBackoff time.Duration) *Mirror {
+	if maxBackoff < mirrorMinBackoff {
+		maxBackoff = mirrorMinBackoff
+	}
+	return &Mirror{
+		l:                logger.Named("mirror"),
+		minBackoff:       mirrorMinBackoff,
+		maxBackoff:       maxBackoff,
+		heartbeatTimeout: mirrorHeartbeatTimeout,
+		nodes:            make(map[string]ipfs.NodeInfo),
+	}
+}
+
+// Get retrieves details about node with given network
+func (m *Mirror) Get(network string) (ipfs.NodeInfo, error) {
+	if network == "" {
+		return ipfs.NodeInfo{}, errors.New(ErrInvalidNetwork)
+	}
+
+	m.nm.RLock()
+	defer m.nm.RUnlock()
+	n, found := m.nodes[network]
+	if !found {
+		return ipfs.NodeInfo{}, fmt.Errorf("node for network '%s' not found", network)
+	}
+	return n, nil
+}
+
+// List retrieves a list of all known nodes
+func (m *Mirror) List() []ipfs.NodeInfo {
+	m.nm.RLock()
+	defer m.nm.RUnlock()
+	var nodes = make([]ipfs.NodeInfo, 0, len(m.nodes))
+	for _, n := range m.nodes {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// OnNetworkChange registers a callback that is invoked whenever the daemon
+// reports that a network's database entry or settings may have changed
+func (m *Mirror) OnNetworkChange(fn func(network string)) {
+	m.changesMux.Lock()
+	m.onNetworkChange = append(m.onNetworkChange, fn)
+	m.changesMux.Unlock()
+}
+
+// OnDenylistChange registers a callback that is invoked whenever the daemon
+// reports that the denylist entries of a network, or the entries that apply
+// to all networks if the network is empty, have changed
+func (m *Mirror) OnDenylistChange(fn func(network string)) {
+	m.changesMux.Lock()
+	m.onDenylistChange = append(m.onDenylistChange, fn)
+	m.changesMux.Unlock()
+}
+
+// OnDomainChange registers a callback that is invoked whenever the daemon
+// reports that a custom domain has changed or been removed
+func (m *Mirror) OnDomainChange(fn func(host string)) {
+	m.changesMux.Lock()
+	m.onDomainChange = append(m.onDomainChange, fn)
+	m.changesMux.Unlock()
+}
+
+// OnResync registers a callback that is invoked whenever the mirror resyncs
+// with the daemon after losing its connection, since denylist and domain
+// changes may have been missed while disconnected
+func (m *Mirror) OnResync(fn func()) {
+	m.changesMux.Lock()
+	m.onResync = append(m.onResync, fn)
+	m.changesMux.Unlock()
+}
+
+func (m *Mirror) notify(fns *[]func(string), arg string) {
+	m.changesMux.RLock()
+	defer m.changesMux.RUnlock()
+	for _, fn := range *fns {
+		fn(arg)
+	}
+}
+
+// Sync watches the daemon's registry until the context is cancelled. The
+// connection is re-established whenever it is lost, or whenever the daemon
+// stops sending heartbeats.
+func (m *Mirror) Sync(ctx context.Context, c rpc.ControlClient) {
+	var backoff = m.minBackoff
+	for {
+		var synced = false
+		err := m.watch(ctx, c, func() {
+			backoff = m.minBackoff
+			synced = true
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		m.nm.RLock()
+		var known = len(m.nodes)
+		m.nm.RUnlock()
+		if synced {
+			m.l.Warnw("lost connection to daemon - routing requests to known nodes until it is restored",
+				"nodes", known,
+				"error", err)
+		} else {
+			m.l.Warnw("failed to watch daemon registry",
+				"nodes", known,
+				"retry_in", backoff,
+				"error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > m.maxBackoff {
+			backoff = m.maxBackoff
+		}
+	}
+}
+
+// watch applies events from a single WatchRegistry stream until it ends,
+// calling synced once a snapshot has been applied
+func (m *Mirror) watch(ctx context.Context, c rpc.ControlClient, synced func()) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.WatchRegistry(ctx, &rpc.WatchRegistryRequest{})
+	if err != nil {
+		return err
+	}
+
+	// give up on the stream if the daemon goes quiet
+	var timeout = time.AfterFunc(m.heartbeatTimeout, cancel)
+	defer timeout.Stop()
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		timeout.Reset(m.heartbeatTimeout)
+		m.apply(event)
+		if event.GetType() == rpc.RegistryEvent_SNAPSHOT {
+			synced()
+		}
+	}
+}
+
+// apply updates the mirror with an event from the daemon's registry
+func (m *Mirror) apply(event *rpc.RegistryEvent) {
+	switch event.GetType() {
+	case rpc.RegistryEvent_SNAPSHOT:
+		var nodes = make(map[string]ipfs.NodeInfo, len(event.GetNodes()))
+		for _, n := range event.GetNodes() {
+			nodes[n.GetNetwork()] = newNodeInfo(n)
+		}
+		m.nm.Lock()
+		var previous, resync = m.nodes, m.synced
+		m.nodes, m.synced = nodes, true
+		m.nm.Unlock()
+		m.l.Infow("synced registry from daemon", "nodes", len(nodes))
+
+		// changes may have been missed while disconnected
+		for network := range previous {
+			m.notify(&m.onNetworkChange, network)
+		}
+		if resync {
+			m.changesMux.RLock()
+			for _, fn := range m.onResync {
+				fn()
+			}
+			m.changesMux.RUnlock()
+		}
+
+	case rpc.RegistryEvent_NODE_UPDATED:
+		m.nm.Lock()
+		for _, n := range event.GetNodes() {
+			m.nodes[n.GetNetwork()] = newNodeInfo(n)
+		}
+		m.nm.Unlock()
+
+	case rpc.RegistryEvent_NODE_REMOVED:
+		m.nm.Lock()
+		delete(m.nodes, event.GetNetwork())
+		m.nm.Unlock()
+
+	case rpc.RegistryEvent_NETWORK_CHANGED:
+		m.notify(&m.onNetworkChange, event.GetNetwork())
+
+	case rpc.RegistryEvent_DENYLIST_CHANGED:
+		m.notify(&m.onDenylistChange, event.GetNetwork())
+
+	case rpc.RegistryEvent_DOMAIN_CHANGED:
+		m.notify(&m.onDomainChange, event.GetHost())
+	}
+}
+
+// newNodeInfo converts a node from its gRPC representation
+func newNodeInfo(n *rpc.RegistryNode) ipfs.NodeInfo {
+	return ipfs.NodeInfo{
+		NetworkID: n.GetNetwork(),
+		Ports: ipfs.NodePorts{
+			Swarm:   n.GetSwarmPort(),
+			SwarmWS: n.GetSwarmWsPort(),
+			API:     n.GetApiPort(),
+			Gateway: n.GetGatewayPort(),
+		},
+		Labels: n.GetLabels(),
+	}
+}
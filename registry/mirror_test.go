@@ -0,0 +1,208 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/RTradeLtd/Nexus/log"
+	"github.com/RTradeLtd/Nexus/rpc"
+)
+
+// testWatchClient serves WatchRegistry calls with streams of events sent to
+// its streams channel, one stream per call
+type testWatchClient struct {
+	rpc.ControlClient
+	streams chan chan *rpc.RegistryEvent
+}
+
+func (c *testWatchClient) WatchRegistry(ctx context.Context, in *rpc.WatchRegistryRequest,
+	opts ...grpc.CallOption) (rpc.Control_WatchRegistryClient, error) {
+	select {
+	case events := <-c.streams:
+		return &testWatchStream{ctx: ctx, events: events}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type testWatchStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events chan *rpc.RegistryEvent
+}
+
+func (s *testWatchStream) Recv() (*rpc.RegistryEvent, error) {
+	select {
+	case e, ok := <-s.events:
+		if !ok {
+			return nil, io.EOF
+		}
+		return e, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func newTestMirror() *Mirror {
+	l, _ := log.NewTestLogger()
+	var m = NewMirror(l, time.Millisecond)
+	m.minBackoff = time.Millisecond
+	return m
+}
+
+func networks(m *Mirror) []string {
+	var names []string
+	for _, n := range m.List() {
+		names = append(names, n.NetworkID)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestMirror_apply(t *testing.T) {
+	var m = newTestMirror()
+	var (
+		mux     sync.Mutex
+		changes []string
+	)
+	var record = func(kind string) func(string) {
+		return func(v string) {
+			mux.Lock()
+			changes = append(changes, kind+":"+v)
+			mux.Unlock()
+		}
+	}
+	m.OnNetworkChange(record("network"))
+	m.OnDenylistChange(record("denylist"))
+	m.OnDomainChange(record("domain"))
+
+	m.apply(&rpc.RegistryEvent{Type: rpc.RegistryEvent_SNAPSHOT, Nodes: []*rpc.RegistryNode{
+		{Network: "bobheadxi", ApiPort: "5001"},
+		{Network: "postables", ApiPort: "5002"},
+	}})
+	m.apply(&rpc.RegistryEvent{Type: rpc.RegistryEvent_NODE_UPDATED, Nodes: []*rpc.RegistryNode{
+		{Network: "bobheadxi", ApiPort: "5003", Labels: map[string]string{"tier": "trial"}},
+	}})
+	m.apply(&rpc.RegistryEvent{Type: rpc.RegistryEvent_NODE_REMOVED, Network: "postables"})
+	m.apply(&rpc.RegistryEvent{Type: rpc.RegistryEvent_NETWORK_CHANGED, Network: "bobheadxi"})
+	m.apply(&rpc.RegistryEvent{Type: rpc.RegistryEvent_DENYLIST_CHANGED})
+	m.apply(&rpc.RegistryEvent{Type: rpc.RegistryEvent_DOMAIN_CHANGED, Host: "example.com"})
+	m.apply(&rpc.RegistryEvent{Type: rpc.RegistryEvent_HEARTBEAT})
+
+	if got := networks(m); !reflect.DeepEqual(got, []string{"bobheadxi"}) {
+		t.Errorf("expected only bobheadxi, got %v", got)
+	}
+	n, err := m.Get("bobheadxi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Ports.API != "5003" || n.Labels["tier"] != "trial" {
+		t.Errorf("expected updated node, got %+v", n)
+	}
+	if _, err := m.Get("postables"); err == nil {
+		t.Error("expected removed node not to be found")
+	}
+	if _, err := m.Get(""); err == nil {
+		t.Error("expected error for invalid network")
+	}
+	var want = []string{"network:bobheadxi", "denylist:", "domain:example.com"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected changes %v, got %v", want, changes)
+	}
+}
+
+func TestMirror_Sync(t *testing.T) {
+	var (
+		m      = newTestMirror()
+		client = &testWatchClient{streams: make(chan chan *rpc.RegistryEvent)}
+	)
+	var invalidated = make(chan string, 10)
+	m.OnNetworkChange(func(network string) { invalidated <- network })
+	var resynced = make(chan struct{}, 10)
+	m.OnResync(func() { resynced <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var done = make(chan struct{})
+	go func() {
+		m.Sync(ctx, client)
+		close(done)
+	}()
+
+	// sync and then lose the connection
+	var first = make(chan *rpc.RegistryEvent, 1)
+	client.streams <- first
+	first <- &rpc.RegistryEvent{Type: rpc.RegistryEvent_SNAPSHOT, Nodes: []*rpc.RegistryNode{
+		{Network: "bobheadxi"},
+		{Network: "postables"},
+	}}
+	close(first)
+
+	// known nodes are kept until the connection is restored
+	var second = make(chan *rpc.RegistryEvent, 1)
+	client.streams <- second
+	if got := networks(m); !reflect.DeepEqual(got, []string{"bobheadxi", "postables"}) {
+		t.Errorf("expected known nodes to be kept, got %v", got)
+	}
+	if len(resynced) != 0 {
+		t.Error("expected first sync not to be reported as a resync")
+	}
+
+	// resync replaces known nodes, and invalidates networks that may have
+	// changed while disconnected
+	second <- &rpc.RegistryEvent{Type: rpc.RegistryEvent_SNAPSHOT, Nodes: []*rpc.RegistryNode{
+		{Network: "bobheadxi"},
+	}}
+	var seen = map[string]bool{}
+	for len(seen) < 2 {
+		select {
+		case n := <-invalidated:
+			seen[n] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected networks to be invalidated, got %v", seen)
+		}
+	}
+	if got := networks(m); !reflect.DeepEqual(got, []string{"bobheadxi"}) {
+		t.Errorf("expected resynced nodes, got %v", got)
+	}
+	select {
+	case <-resynced:
+	case <-time.After(time.Second):
+		t.Error("expected resync to be reported")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected sync to stop once cancelled")
+	}
+}
+
+func TestMirror_watch_heartbeat(t *testing.T) {
+	var (
+		m      = newTestMirror()
+		client = &testWatchClient{streams: make(chan chan *rpc.RegistryEvent, 1)}
+	)
+	m.heartbeatTimeout = 50 * time.Millisecond
+
+	// streams that go quiet are abandoned
+	var events = make(chan *rpc.RegistryEvent, 1)
+	events <- &rpc.RegistryEvent{Type: rpc.RegistryEvent_SNAPSHOT}
+	client.streams <- events
+	var synced bool
+	var err = m.watch(context.Background(), client, func() { synced = true })
+	if !synced {
+		t.Error("expected snapshot to be applied")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected stream to be cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/ipfs"
+)
+
+// State denotes the state of a registered node
+type State string
+
+const (
+	// StateRunning indicates the node is online
+	StateRunning State = "running"
+
+	// StateStopped indicates the node has gone offline, but is still registered
+	StateStopped State = "stopped"
+)
+
+// status tracks the state of a node, and when it entered that state
+type status struct {
+	state State
+	since time.Time
+}
+
+// Sort fields available for queries
+const (
+	SortName   = "name"
+	SortUptime = "uptime"
+	SortDisk   = "disk"
+	SortMemory = "memory"
+	SortCPUs   = "cpus"
+)
+
+// Range declares an inclusive range of values. A Max of 0 denotes no upper
+// bound.
+type Range struct {
+	Min int
+	Max int
+}
+
+// ParseRange reads a range of the form "<N>", "<LOWER>-<UPPER>", "<LOWER>-" or
+// "-<UPPER>". An empty string is an unbounded range.
+func ParseRange(s string) (Range, error) {
+	if s == "" {
+		return Range{}, nil
+	}
+	if !strings.Contains(s, "-") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid range '%s'", s)
+		}
+		return Range{n, n}, nil
+	}
+	var (
+		bounds = strings.SplitN(s, "-", 2)
+		r      Range
+		err    error
+	)
+	if bounds[0] != "" {
+		if r.Min, err = strconv.Atoi(bounds[0]); err != nil {
+			return Range{}, fmt.Errorf("invalid lower bound in range '%s'", s)
+		}
+	}
+	if bounds[1] != "" {
+		if r.Max, err = strconv.Atoi(bounds[1]); err != nil {
+			return Range{}, fmt.Errorf("invalid upper bound in range '%s'", s)
+		}
+		if r.Max < r.Min {
+			return Range{}, fmt.Errorf("invalid range '%s'", s)
+		}
+	}
+	return r, nil
+}
+
+// Contains checks if given value is within the range
+func (r Range) Contains(v int) bool {
+	return v >= r.Min && (r.Max == 0 || v <= r.Max)
+}
+
+// Query declares parameters for listing registered nodes. Zero values are
+// ignored.
+type Query struct {
+	// Pattern filters nodes by network name, using shell-style glob patterns
+	Pattern string
+	// State filters nodes by their current state
+	State State
+	// MinUptime and MaxUptime filter nodes by time spent in their current state
+	MinUptime time.Duration
+	MaxUptime time.Duration
+	// Disk, Memory, and CPUs filter nodes by assigned resources
+	Disk   Range
+	Memory Range
+	CPUs   Range
+	// Labels filters nodes by label selector
+	Labels Selector
+
+	// Sort sets the field to order results by - defaults to SortName
+	Sort       string
+	Descending bool
+
+	// Cursor, if provided, continues a previous query from where it left off
+	Cursor string
+	// Limit sets the maximum number of results - 0 returns all results
+	Limit int
+}
+
+// Entry is a node returned by a registry query
+type Entry struct {
+	ipfs.NodeInfo
+	State  State
+	Uptime time.Duration
+
+	since time.Time
+}
+
+// cursor denotes the position of the last entry returned by a query
+type cursor struct {
+	Key  int64  `json:"k"`
+	Name string `json:"n"`
+}
+
+// Query retrieves a sorted list of nodes that match given query, as well as a
+// cursor that can be used to retrieve the next page of results if there are
+// any remaining.
+func (r *NodeRegistry) Query(q Query) ([]Entry, string, error) {
+	if q.Pattern != "" {
+		if _, err := path.Match(q.Pattern, ""); err != nil {
+			return nil, "", fmt.Errorf("invalid pattern '%s': %s", q.Pattern, err.Error())
+		}
+	}
+	if q.Sort == "" {
+		q.Sort = SortName
+	}
+	if !validSort(q.Sort) {
+		return nil, "", fmt.Errorf("invalid sort field '%s'", q.Sort)
+	}
+	if q.Limit < 0 {
+		return nil, "", errors.New("limit must not be negative")
+	}
+	var after *cursor
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &c
+	}
+
+	// collect matching entries
+	var (
+		now     = time.Now()
+		entries = make([]Entry, 0)
+	)
+	r.nm.RLock()
+	for id, n := range r.nodes {
+		var s = r.status[id]
+		var e = Entry{NodeInfo: *n, State: s.state, Uptime: now.Sub(s.since), since: s.since}
+		if q.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	r.nm.RUnlock()
+
+	// order results, using network name as a tiebreaker for stable pagination
+	var less = func(a, b cursor) bool {
+		if a.Key != b.Key {
+			return (a.Key < b.Key) != q.Descending
+		}
+		return (a.Name < b.Name) != q.Descending
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return less(sortKey(entries[i], q.Sort), sortKey(entries[j], q.Sort))
+	})
+
+	// skip entries up to and including the cursor position
+	if after != nil {
+		var start = sort.Search(len(entries), func(i int) bool {
+			return less(*after, sortKey(entries[i], q.Sort))
+		})
+		entries = entries[start:]
+	}
+
+	// apply limit and generate cursor for next page
+	if q.Limit == 0 || len(entries) <= q.Limit {
+		return entries, "", nil
+	}
+	entries = entries[:q.Limit]
+	next, err := encodeCursor(sortKey(entries[len(entries)-1], q.Sort))
+	if err != nil {
+		return nil, "", err
+	}
+	return entries, next, nil
+}
+
+func (q *Query) matches(e Entry) bool {
+	if q.Pattern != "" {
+		if ok, _ := path.Match(q.Pattern, e.NetworkID); !ok {
+			return false
+		}
+	}
+	if q.State != "" && e.State != q.State {
+		return false
+	}
+	if e.Uptime < q.MinUptime || (q.MaxUptime != 0 && e.Uptime > q.MaxUptime) {
+		return false
+	}
+	if !q.Disk.Contains(e.Resources.DiskGB) ||
+		!q.Memory.Contains(e.Resources.MemoryGB) ||
+		!q.CPUs.Contains(e.Resources.CPUs) {
+		return false
+	}
+	return q.Labels.Matches(e.Labels)
+}
+
+func validSort(field string) bool {
+	switch field {
+	case SortName, SortUptime, SortDisk, SortMemory, SortCPUs:
+		return true
+	default:
+		return false
+	}
+}
+
+// sortKey generates the position of given entry in the requested ordering
+func sortKey(e Entry, field string) cursor {
+	var c = cursor{Name: e.NetworkID}
+	switch field {
+	case SortUptime:
+		// uptime changes between queries, so order by start time instead
+		c.Key = -e.since.UnixNano()
+	case SortDisk:
+		c.Key = int64(e.Resources.DiskGB)
+	case SortMemory:
+		c.Key = int64(e.Resources.MemoryGB)
+	case SortCPUs:
+		c.Key = int64(e.Resources.CPUs)
+	}
+	return c
+}
+
+func encodeCursor(c cursor) (string, error) {
+	b, err := json.Marshal(&c)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate cursor: %s", err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	return c, nil
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RTradeLtd/Nexus/config"
+	"github.com/RTradeLtd/Nexus/ipfs"
+	"github.com/RTradeLtd/Nexus/log"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    Range
+		wantErr bool
+	}{
+		{"empty", "", Range{}, false},
+		{"single", "4", Range{4, 4}, false},
+		{"range", "2-8", Range{2, 8}, false},
+		{"lower bound", "2-", Range{2, 0}, false},
+		{"upper bound", "-8", Range{0, 8}, false},
+		{"not a number", "abc", Range{}, true},
+		{"bad lower bound", "abc-8", Range{}, true},
+		{"bad upper bound", "2-abc", Range{}, true},
+		{"inverted", "8-2", Range{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRange(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestQueryRegistry() *NodeRegistry {
+	l, _ := log.NewTestLogger()
+	return New(l, config.New().Ports, config.Bind{},
+		&ipfs.NodeInfo{NetworkID: "team-a",
+			Resources: ipfs.NodeResources{DiskGB: 100, MemoryGB: 4, CPUs: 4},
+			Labels:    map[string]string{"tier": "trial"}},
+		&ipfs.NodeInfo{NetworkID: "team-b",
+			Resources: ipfs.NodeResources{DiskGB: 50, MemoryGB: 8, CPUs: 2},
+			Labels:    map[string]string{"tier": "paid"}},
+		&ipfs.NodeInfo{NetworkID: "solo",
+			Resources: ipfs.NodeResources{DiskGB: 200, MemoryGB: 2, CPUs: 1}},
+	)
+}
+
+func names(entries []Entry) []string {
+	var n = make([]string, len(entries))
+	for i, e := range entries {
+		n[i] = e.NetworkID
+	}
+	return n
+}
+
+func TestNodeRegistry_Query(t *testing.T) {
+	r := newTestQueryRegistry()
+	defer r.Close()
+	r.SetState("solo", StateStopped)
+
+	tests := []struct {
+		name     string
+		query    Query
+		want     []string
+		wantNext bool
+		wantErr  bool
+	}{
+		{"all", Query{}, []string{"solo", "team-a", "team-b"}, false, false},
+		{"invalid pattern", Query{Pattern: "[team"}, nil, false, true},
+		{"invalid sort", Query{Sort: "color"}, nil, false, true},
+		{"invalid limit", Query{Limit: -1}, nil, false, true},
+		{"invalid cursor", Query{Cursor: "???"}, nil, false, true},
+		{"pattern", Query{Pattern: "team-*"}, []string{"team-a", "team-b"}, false, false},
+		{"state", Query{State: StateStopped}, []string{"solo"}, false, false},
+		{"min uptime", Query{MinUptime: time.Hour}, []string{}, false, false},
+		{"max uptime", Query{MaxUptime: time.Hour}, []string{"solo", "team-a", "team-b"}, false, false},
+		{"disk", Query{Disk: Range{Min: 100}}, []string{"solo", "team-a"}, false, false},
+		{"memory", Query{Memory: Range{Min: 4, Max: 8}}, []string{"team-a", "team-b"}, false, false},
+		{"cpus", Query{CPUs: Range{Max: 2}}, []string{"solo", "team-b"}, false, false},
+		{"labels", Query{Labels: mustParseSelector(t, "tier=trial")}, []string{"team-a"}, false, false},
+		{"labels not set", Query{Labels: mustParseSelector(t, "!tier")}, []string{"solo"}, false, false},
+		{"sort by disk", Query{Sort: SortDisk}, []string{"team-b", "team-a", "solo"}, false, false},
+		{"sort by cpus descending", Query{Sort: SortCPUs, Descending: true},
+			[]string{"team-a", "team-b", "solo"}, false, false},
+		{"limit", Query{Limit: 2}, []string{"solo", "team-a"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next, err := r.Query(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NodeRegistry.Query() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(names(got), tt.want) {
+				t.Errorf("NodeRegistry.Query() = %v, want %v", names(got), tt.want)
+			}
+			if (next != "") != tt.wantNext {
+				t.Errorf("NodeRegistry.Query() cursor = '%s', wantNext %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestNodeRegistry_Query_pagination(t *testing.T) {
+	r := newTestQueryRegistry()
+	defer r.Close()
+
+	for _, sort := range []string{SortName, SortUptime, SortDisk, SortMemory, SortCPUs} {
+		t.Run(sort, func(t *testing.T) {
+			all, _, err := r.Query(Query{Sort: sort})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// page through results one at a time
+			var (
+				paged  = make([]Entry, 0)
+				cursor string
+			)
+			for i := 0; i < len(all); i++ {
+				page, next, err := r.Query(Query{Sort: sort, Cursor: cursor, Limit: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				paged = append(paged, page...)
+				if cursor = next; cursor == "" {
+					break
+				}
+			}
+			if cursor != "" {
+				t.Errorf("unexpected cursor '%s' after last page", cursor)
+			}
+			if !reflect.DeepEqual(names(paged), names(all)) {
+				t.Errorf("paged results = %v, want %v", names(paged), names(all))
+			}
+		})
+	}
+}
+
+func TestNodeRegistry_SetState(t *testing.T) {
+	r := newTestRegistry()
+	defer r.Close()
+
+	tests := []struct {
+		name    string
+		network string
+		wantErr bool
+	}{
+		{"invalid input", "", true},
+		{"unknown network", "timhortons", true},
+		{"known network", "bobheadxi", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.SetState(tt.network, StateStopped); (err != nil) != tt.wantErr {
+				t.Errorf("NodeRegistry.SetState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	entries, _, _ := r.Query(Query{State: StateStopped})
+	if len(entries) != 1 {
+		t.Errorf("expected 1 stopped node, found %d", len(entries))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -25,35 +26,49 @@ type NodeRegistry struct {
 	l *zap.SugaredLogger
 
 	// node registry - locked by NodeRegistry::nm
-	nodes map[string]*ipfs.NodeInfo
-	nm    sync.RWMutex
+	nodes  map[string]*ipfs.NodeInfo
+	status map[string]status
+	nm     sync.RWMutex
 
 	// port registry
 	swarmPorts   *network.Registry
+	swarmWSPorts *network.Registry
 	apiPorts     *network.Registry
 	gatewayPorts *network.Registry
+
+	changesMux sync.RWMutex
+	onChange   []func(network string, node *ipfs.NodeInfo)
 }
 
-// New sets up a new registry with provided nodes
-func New(logger *zap.SugaredLogger, ports config.Ports, nodes ...*ipfs.NodeInfo) *NodeRegistry {
+// New sets up a new registry with provided nodes. Ports are checked for
+// availability on each of the addresses declared in bind.
+func New(logger *zap.SugaredLogger, ports config.Ports, bind config.Bind, nodes ...*ipfs.NodeInfo) *NodeRegistry {
 	// parse nodes
-	m := make(map[string]*ipfs.NodeInfo)
+	var (
+		m   = make(map[string]*ipfs.NodeInfo)
+		s   = make(map[string]status)
+		now = time.Now()
+	)
 	if nodes != nil {
 		for _, n := range nodes {
 			m[n.NetworkID] = n
+			s[n.NetworkID] = status{StateRunning, now}
 		}
 	}
 
 	// build registry
+	bind = bind.WithDefaults()
 	return &NodeRegistry{
-		l:     logger.Named("registry"),
-		nodes: m,
+		l:      logger.Named("registry"),
+		nodes:  m,
+		status: s,
 
 		// See documentation regarding public/private-ness of IPFS ports in package
 		// ipfs
-		swarmPorts:   network.NewRegistry(logger, network.Public, ports.Swarm),
-		apiPorts:     network.NewRegistry(logger, network.Private, ports.API),
-		gatewayPorts: network.NewRegistry(logger, network.Private, ports.Gateway),
+		swarmPorts:   network.NewRegistry(logger, bind.Swarm, ports.Swarm),
+		swarmWSPorts: network.NewRegistry(logger, bind.SwarmWS, ports.SwarmWS),
+		apiPorts:     network.NewRegistry(logger, bind.API, ports.API),
+		gatewayPorts: network.NewRegistry(logger, bind.Gateway, ports.Gateway),
 	}
 }
 
@@ -71,23 +86,30 @@ func (r *NodeRegistry) Register(node *ipfs.NodeInfo) error {
 	}
 
 	// assign ports to this node - do not assign new ones if ports are already
-	// provided in node.Ports
+	// provided in node.Ports. Nodes created before the WebSocket transport was
+	// introduced do not have a WebSocket swarm port, and are not assigned one,
+	// since it would not be published by the existing container.
 	if node.Ports.Swarm == "" || node.Ports.Gateway == "" || node.Ports.API == "" {
 		var err error
-		var swarm, api, gateway string
+		var swarm, swarmWS, api, gateway string
 		if swarm, err = r.swarmPorts.AssignPort(); err != nil {
 			return fmt.Errorf("failed to register node: %s", err.Error())
 		}
+		if swarmWS, err = r.swarmWSPorts.AssignPort(); err != nil {
+			return fmt.Errorf("failed to register node: %s", err.Error())
+		}
 		if api, err = r.apiPorts.AssignPort(); err != nil {
 			return fmt.Errorf("failed to register node: %s", err.Error())
 		}
 		if gateway, err = r.gatewayPorts.AssignPort(); err != nil {
 			return fmt.Errorf("failed to register node: %s", err.Error())
 		}
-		node.Ports = ipfs.NodePorts{Swarm: swarm, API: api, Gateway: gateway}
+		node.Ports = ipfs.NodePorts{Swarm: swarm, SwarmWS: swarmWS, API: api, Gateway: gateway}
 	}
 
 	r.nodes[node.NetworkID] = node
+	r.status[node.NetworkID] = status{StateRunning, time.Now()}
+	r.changed(node.NetworkID, node)
 
 	return nil
 }
@@ -106,9 +128,78 @@ func (r *NodeRegistry) Deregister(network string) error {
 	}
 
 	delete(r.nodes, network)
+	delete(r.status, network)
+	r.changed(network, nil)
+	return nil
+}
+
+// SetState updates the state of the node with given network. The node's
+// uptime is reset if its state changes.
+func (r *NodeRegistry) SetState(network string, state State) error {
+	if network == "" {
+		return errors.New(ErrInvalidNetwork)
+	}
+
+	r.nm.Lock()
+	defer r.nm.Unlock()
+	n, found := r.nodes[network]
+	if !found {
+		return fmt.Errorf("node for network '%s' not found", network)
+	}
+	if r.status[network].state != state {
+		r.status[network] = status{state, time.Now()}
+		r.changed(network, n)
+	}
 	return nil
 }
 
+// SetLabels replaces the labels of the node with given network
+func (r *NodeRegistry) SetLabels(network string, labels map[string]string) error {
+	if network == "" {
+		return errors.New(ErrInvalidNetwork)
+	}
+
+	r.nm.Lock()
+	defer r.nm.Unlock()
+	n, found := r.nodes[network]
+	if !found {
+		return fmt.Errorf("node for network '%s' not found", network)
+	}
+
+	// copy node so that previously retrieved node info is left unchanged
+	var updated = *n
+	updated.Labels = labels
+	r.nodes[network] = &updated
+	r.changed(network, &updated)
+	return nil
+}
+
+// OnChange registers a callback that is invoked whenever a node is
+// registered, deregistered, or updated, with a copy of the node or nil if it
+// was deregistered. Callbacks are invoked while the change is applied, so
+// that they observe changes in order - they must not block or query the
+// registry.
+func (r *NodeRegistry) OnChange(fn func(network string, node *ipfs.NodeInfo)) {
+	r.changesMux.Lock()
+	r.onChange = append(r.onChange, fn)
+	r.changesMux.Unlock()
+}
+
+// changed notifies callbacks of a change to given network's node - it must be
+// called while the registry is locked
+func (r *NodeRegistry) changed(network string, node *ipfs.NodeInfo) {
+	r.changesMux.RLock()
+	defer r.changesMux.RUnlock()
+	for _, fn := range r.onChange {
+		if node == nil {
+			fn(network, nil)
+			continue
+		}
+		var n = *node
+		fn(network, &n)
+	}
+}
+
 // List retrieves a list of all known nodes
 func (r *NodeRegistry) List() []ipfs.NodeInfo {
 	var (
@@ -150,4 +241,5 @@ func (r *NodeRegistry) Close() {
 	r.apiPorts.Close()
 	r.gatewayPorts.Close()
 	r.swarmPorts.Close()
+	r.swarmWSPorts.Close()
 }
@@ -1,6 +1,9 @@
 package registry
 
 import (
+	"reflect"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/RTradeLtd/Nexus/config"
@@ -17,7 +20,7 @@ func newTestRegistry() *NodeRegistry {
 	// create a registry with a mock node for testing
 	n := defaultNode
 	l, _ := log.NewTestLogger()
-	return New(l, config.New().Ports, &n)
+	return New(l, config.New().Ports, config.Bind{}, &n)
 }
 
 func TestNew(t *testing.T) {
@@ -31,15 +34,19 @@ func TestNodeRegistry_Register(t *testing.T) {
 
 	cfg := config.New().Ports
 	cfg.Swarm = []string{}
-	rNoSwarm := New(r.l, cfg)
+	rNoSwarm := New(r.l, cfg, config.Bind{})
+
+	cfg = config.New().Ports
+	cfg.SwarmWS = []string{}
+	rNoSwarmWS := New(r.l, cfg, config.Bind{})
 
 	cfg = config.New().Ports
 	cfg.API = []string{}
-	rNoAPI := New(r.l, cfg)
+	rNoAPI := New(r.l, cfg, config.Bind{})
 
 	cfg = config.New().Ports
 	cfg.Gateway = []string{}
-	rNoGateway := New(r.l, cfg)
+	rNoGateway := New(r.l, cfg, config.Bind{})
 
 	type args struct {
 		node *ipfs.NodeInfo
@@ -53,6 +60,7 @@ func TestNodeRegistry_Register(t *testing.T) {
 		{"invalid input", r, args{&ipfs.NodeInfo{}}, true},
 		{"existing network", r, args{&ipfs.NodeInfo{NetworkID: "bobheadxi"}}, true},
 		{"no swarm port", rNoSwarm, args{&ipfs.NodeInfo{NetworkID: "timhortons"}}, true},
+		{"no websocket swarm port", rNoSwarmWS, args{&ipfs.NodeInfo{NetworkID: "tacobell"}}, true},
 		{"no api port", rNoAPI, args{&ipfs.NodeInfo{NetworkID: "kfc"}}, true},
 		{"no gateway port", rNoGateway, args{&ipfs.NodeInfo{NetworkID: "mcdonalds"}}, true},
 		{"successful registration", r, args{&ipfs.NodeInfo{NetworkID: "postables"}}, false},
@@ -66,7 +74,7 @@ func TestNodeRegistry_Register(t *testing.T) {
 			// check if port assignment should be empty
 			if tt.wantErr && tt.args.node.Ports.Swarm != "" {
 				t.Error("port should be unassigned")
-			} else if !tt.wantErr && tt.args.node.Ports.Swarm == "" {
+			} else if !tt.wantErr && (tt.args.node.Ports.Swarm == "" || tt.args.node.Ports.SwarmWS == "") {
 				t.Error("port should be assigned")
 			}
 		})
@@ -97,6 +105,37 @@ func TestNodeRegistry_Deregister(t *testing.T) {
 	}
 }
 
+func TestNodeRegistry_SetLabels(t *testing.T) {
+	type args struct {
+		network string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"invalid input", args{""}, true},
+		{"unknown network", args{"timhortons"}, true},
+		{"known network", args{"bobheadxi"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRegistry()
+			defer r.Close()
+			var labels = map[string]string{"tier": "trial"}
+			if err := r.SetLabels(tt.args.network, labels); (err != nil) != tt.wantErr {
+				t.Errorf("NodeRegistry.SetLabels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				n, _ := r.Get(tt.args.network)
+				if !reflect.DeepEqual(n.Labels, labels) {
+					t.Errorf("NodeRegistry.SetLabels() labels = %v, want %v", n.Labels, labels)
+				}
+			}
+		})
+	}
+}
+
 func TestNodeRegistry_List(t *testing.T) {
 	r := newTestRegistry()
 	nodes := r.List()
@@ -131,3 +170,66 @@ func TestNodeRegistry_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeRegistry_OnChange(t *testing.T) {
+	r := newTestRegistry()
+	defer r.Close()
+
+	// callbacks receive the node as of each change
+	var changes []string
+	r.OnChange(func(network string, node *ipfs.NodeInfo) {
+		var labels = "removed"
+		if node != nil {
+			labels = strconv.Itoa(len(node.Labels))
+		}
+		changes = append(changes, network+":"+labels)
+	})
+
+	r.Register(&ipfs.NodeInfo{NetworkID: "postables"})
+	r.SetState("postables", StateStopped)
+	r.SetState("postables", StateStopped)
+	r.SetLabels("postables", map[string]string{"tier": "trial"})
+	r.Deregister("postables")
+	r.Deregister("postables")
+
+	var want = []string{"postables:0", "postables:0", "postables:1", "postables:removed"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected changes %v, got %v", want, changes)
+	}
+}
+
+func TestNodeRegistry_OnChange_order(t *testing.T) {
+	r := newTestRegistry()
+	defer r.Close()
+
+	// the last change observed must match the registry, however concurrent
+	// changes are interleaved
+	var (
+		mux  sync.Mutex
+		last *ipfs.NodeInfo
+	)
+	r.OnChange(func(network string, node *ipfs.NodeInfo) {
+		mux.Lock()
+		last = node
+		mux.Unlock()
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			r.Register(&ipfs.NodeInfo{NetworkID: "postables",
+				Ports: ipfs.NodePorts{Swarm: "4001", API: "5001", Gateway: "8080"}})
+		}()
+		go func() {
+			defer wg.Done()
+			r.Deregister("postables")
+		}()
+	}
+	wg.Wait()
+
+	_, err := r.Get("postables")
+	if registered := err == nil; registered != (last != nil) {
+		t.Errorf("expected last change to match registry (registered: %v), got %+v", registered, last)
+	}
+}
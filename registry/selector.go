@@ -0,0 +1,191 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// operator denotes how a requirement matches a label
+type operator string
+
+const (
+	opEquals    operator = "="
+	opNotEquals operator = "!="
+	opIn        operator = "in"
+	opNotIn     operator = "notin"
+	opExists    operator = "exists"
+	opNotExists operator = "!exists"
+)
+
+// requirement is a single condition on a label
+type requirement struct {
+	key    string
+	op     operator
+	values []string
+}
+
+func (r requirement) matches(labels map[string]string) bool {
+	var v, found = labels[r.key]
+	switch r.op {
+	case opExists:
+		return found
+	case opNotExists:
+		return !found
+	case opEquals:
+		return found && v == r.values[0]
+	case opNotEquals:
+		return !found || v != r.values[0]
+	case opIn:
+		return found && contains(r.values, v)
+	case opNotIn:
+		return !found || !contains(r.values, v)
+	default:
+		return false
+	}
+}
+
+// Selector filters nodes by their labels. The zero value matches everything.
+type Selector struct {
+	requirements []requirement
+}
+
+// ParseSelector reads a comma-separated list of label requirements, all of
+// which must hold for a node to match. Supported requirements are:
+//
+//	<KEY>=<VALUE>, <KEY>==<VALUE>  label is set to value
+//	<KEY>!=<VALUE>                 label is not set to value
+//	<KEY> in (<V1>,<V2>)           label is set to one of the values
+//	<KEY> notin (<V1>,<V2>)        label is not set to any of the values
+//	<KEY>                          label is set
+//	!<KEY>                         label is not set
+//
+// For example, "tier in (trial,free),!deprecated" selects all trial and free
+// tier networks that are not deprecated.
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for _, expr := range splitRequirements(s) {
+		if expr = strings.TrimSpace(expr); expr == "" {
+			continue
+		}
+		r, err := parseRequirement(expr)
+		if err != nil {
+			return Selector{}, err
+		}
+		sel.requirements = append(sel.requirements, r)
+	}
+	return sel, nil
+}
+
+// Empty checks if the selector has no requirements
+func (s Selector) Empty() bool { return len(s.requirements) == 0 }
+
+// Matches checks if given labels satisfy all the selector's requirements
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s.requirements {
+		if !r.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the selector in its parseable form
+func (s Selector) String() string {
+	var exprs = make([]string, len(s.requirements))
+	for i, r := range s.requirements {
+		switch r.op {
+		case opExists:
+			exprs[i] = r.key
+		case opNotExists:
+			exprs[i] = "!" + r.key
+		case opIn, opNotIn:
+			exprs[i] = fmt.Sprintf("%s %s (%s)", r.key, r.op, strings.Join(r.values, ","))
+		default:
+			exprs[i] = r.key + string(r.op) + r.values[0]
+		}
+	}
+	return strings.Join(exprs, ",")
+}
+
+func parseRequirement(expr string) (requirement, error) {
+	// set-based requirements
+	for _, op := range []operator{opNotIn, opIn} {
+		var sep = " " + string(op) + " "
+		if i := strings.Index(expr, sep); i > 0 {
+			var (
+				key = strings.TrimSpace(expr[:i])
+				set = strings.TrimSpace(expr[i+len(sep):])
+			)
+			if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+				return requirement{}, fmt.Errorf("invalid set in requirement '%s'", expr)
+			}
+			var values []string
+			for _, v := range strings.Split(set[1:len(set)-1], ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+			if key == "" || len(values) == 0 {
+				return requirement{}, fmt.Errorf("invalid requirement '%s'", expr)
+			}
+			return requirement{key: key, op: op, values: values}, nil
+		}
+	}
+
+	// equality-based requirements
+	for _, sep := range []string{"!=", "==", "="} {
+		if i := strings.Index(expr, sep); i >= 0 {
+			var key = strings.TrimSpace(expr[:i])
+			if key == "" || strings.ContainsAny(key, "=!") {
+				return requirement{}, fmt.Errorf("invalid requirement '%s'", expr)
+			}
+			var op = opEquals
+			if sep == "!=" {
+				op = opNotEquals
+			}
+			return requirement{key: key, op: op, values: []string{strings.TrimSpace(expr[i+len(sep):])}}, nil
+		}
+	}
+
+	// existence requirements
+	var op = opExists
+	if strings.HasPrefix(expr, "!") {
+		expr, op = strings.TrimSpace(expr[1:]), opNotExists
+	}
+	if expr == "" || strings.ContainsAny(expr, " ()!") {
+		return requirement{}, fmt.Errorf("invalid requirement '%s'", expr)
+	}
+	return requirement{key: expr, op: op}, nil
+}
+
+// splitRequirements splits a selector on commas that are not within a set
+func splitRequirements(s string) []string {
+	var (
+		exprs []string
+		depth int
+		start int
+	)
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				exprs = append(exprs, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(exprs, s[start:])
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"testing"
+)
+
+func mustParseSelector(t *testing.T, s string) Selector {
+	sel, err := ParseSelector(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sel
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{"empty", "", "", false},
+		{"equals", "tier=trial", "tier=trial", false},
+		{"double equals", "tier == trial", "tier=trial", false},
+		{"not equals", "tier!=trial", "tier!=trial", false},
+		{"in", "tier in (trial, free)", "tier in (trial,free)", false},
+		{"notin", "tier notin (trial)", "tier notin (trial)", false},
+		{"exists", "tier", "tier", false},
+		{"not exists", "!tier", "!tier", false},
+		{"multiple", "tier in (trial,free),!deprecated, region=us",
+			"tier in (trial,free),!deprecated,region=us", false},
+		{"no key", "=trial", "", true},
+		{"bad set", "tier in trial", "", true},
+		{"empty set", "tier in ()", "", true},
+		{"bad key", "tier trial", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSelector(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSelector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseSelector() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	var labels = map[string]string{"tier": "trial", "region": "us"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"tier=trial", true},
+		{"tier=paid", false},
+		{"tier!=paid", true},
+		{"owner!=bob", true},
+		{"tier in (trial,free)", true},
+		{"tier in (paid)", false},
+		{"owner in (bob)", false},
+		{"tier notin (paid)", true},
+		{"owner notin (bob)", true},
+		{"region", true},
+		{"owner", false},
+		{"!owner", true},
+		{"!region", false},
+		{"tier=trial,region=eu", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			if got := mustParseSelector(t, tt.selector).Matches(labels); got != tt.want {
+				t.Errorf("Selector.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
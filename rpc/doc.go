@@ -0,0 +1,3 @@
+// Package rpc provides the gRPC definitions for Nexus's Control service, which
+// extends the core network operations provided by github.com/RTradeLtd/grpc/nexus
+package rpc
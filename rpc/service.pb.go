@@ -0,0 +1,2958 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: rpc/service.proto
+
+package rpc
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type RegistryEvent_Type int32
+
+const (
+	// SNAPSHOT replaces all known nodes with nodes - it is always the first
+	// event sent, and is sent again if the watcher falls behind
+	RegistryEvent_SNAPSHOT RegistryEvent_Type = 0
+	// NODE_UPDATED adds or replaces the node in nodes
+	RegistryEvent_NODE_UPDATED RegistryEvent_Type = 1
+	// NODE_REMOVED removes the node of network
+	RegistryEvent_NODE_REMOVED RegistryEvent_Type = 2
+	// NETWORK_CHANGED indicates the database entry or settings of network may
+	// have changed
+	RegistryEvent_NETWORK_CHANGED RegistryEvent_Type = 3
+	// DENYLIST_CHANGED indicates the denylist entries of network, or of all
+	// networks if network is empty, have changed
+	RegistryEvent_DENYLIST_CHANGED RegistryEvent_Type = 4
+	// DOMAIN_CHANGED indicates the custom domain host has changed
+	RegistryEvent_DOMAIN_CHANGED RegistryEvent_Type = 5
+	// HEARTBEAT is sent periodically so that watchers can detect broken
+	// connections
+	RegistryEvent_HEARTBEAT RegistryEvent_Type = 6
+)
+
+var RegistryEvent_Type_name = map[int32]string{
+	0: "SNAPSHOT",
+	1: "NODE_UPDATED",
+	2: "NODE_REMOVED",
+	3: "NETWORK_CHANGED",
+	4: "DENYLIST_CHANGED",
+	5: "DOMAIN_CHANGED",
+	6: "HEARTBEAT",
+}
+var RegistryEvent_Type_value = map[string]int32{
+	"SNAPSHOT":         0,
+	"NODE_UPDATED":     1,
+	"NODE_REMOVED":     2,
+	"NETWORK_CHANGED":  3,
+	"DENYLIST_CHANGED": 4,
+	"DOMAIN_CHANGED":   5,
+	"HEARTBEAT":        6,
+}
+
+func (x RegistryEvent_Type) String() string {
+	return proto.EnumName(RegistryEvent_Type_name, int32(x))
+}
+func (RegistryEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{35, 0}
+}
+
+type ListNetworksRequest struct {
+	// pattern filters networks by name using glob syntax, e.g. "team-*"
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// state filters networks by node state, e.g. "running" or "stopped"
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// min_uptime and max_uptime filter networks by node uptime, e.g. "1h30m"
+	MinUptime string `protobuf:"bytes,3,opt,name=min_uptime,json=minUptime,proto3" json:"min_uptime,omitempty"`
+	MaxUptime string `protobuf:"bytes,4,opt,name=max_uptime,json=maxUptime,proto3" json:"max_uptime,omitempty"`
+	// disk, memory, and cpus filter networks by node resources, in the form
+	// "<N>", "<LOWER>-<UPPER>", "<LOWER>-", or "-<UPPER>"
+	Disk   string `protobuf:"bytes,5,opt,name=disk,proto3" json:"disk,omitempty"`
+	Memory string `protobuf:"bytes,6,opt,name=memory,proto3" json:"memory,omitempty"`
+	Cpus   string `protobuf:"bytes,7,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	// labels filters networks by label selector, e.g. "tier in (trial,free)" or
+	// "tier=trial,!deprecated"
+	Labels string `protobuf:"bytes,8,opt,name=labels,proto3" json:"labels,omitempty"`
+	// sort orders results by "name", "uptime", "disk", "memory", or "cpus" -
+	// prefix with "-" for descending order
+	Sort string `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`
+	// cursor continues a previous listing from where it left off
+	Cursor string `protobuf:"bytes,10,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// limit sets the maximum number of networks to return
+	Limit                int32    `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListNetworksRequest) Reset()         { *m = ListNetworksRequest{} }
+func (m *ListNetworksRequest) String() string { return proto.CompactTextString(m) }
+func (*ListNetworksRequest) ProtoMessage()    {}
+func (*ListNetworksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{0}
+}
+func (m *ListNetworksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNetworksRequest.Unmarshal(m, b)
+}
+func (m *ListNetworksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNetworksRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListNetworksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNetworksRequest.Merge(dst, src)
+}
+func (m *ListNetworksRequest) XXX_Size() int {
+	return xxx_messageInfo_ListNetworksRequest.Size(m)
+}
+func (m *ListNetworksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNetworksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNetworksRequest proto.InternalMessageInfo
+
+func (m *ListNetworksRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetMinUptime() string {
+	if m != nil {
+		return m.MinUptime
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetMaxUptime() string {
+	if m != nil {
+		return m.MaxUptime
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetDisk() string {
+	if m != nil {
+		return m.Disk
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetMemory() string {
+	if m != nil {
+		return m.Memory
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetCpus() string {
+	if m != nil {
+		return m.Cpus
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetLabels() string {
+	if m != nil {
+		return m.Labels
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListNetworksRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type NetworkInfo struct {
+	Network              string            `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	State                string            `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Uptime               int64             `protobuf:"varint,3,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	SwarmPort            string            `protobuf:"bytes,4,opt,name=swarm_port,json=swarmPort,proto3" json:"swarm_port,omitempty"`
+	ApiPort              string            `protobuf:"bytes,5,opt,name=api_port,json=apiPort,proto3" json:"api_port,omitempty"`
+	GatewayPort          string            `protobuf:"bytes,6,opt,name=gateway_port,json=gatewayPort,proto3" json:"gateway_port,omitempty"`
+	DiskGb               int32             `protobuf:"varint,7,opt,name=disk_gb,json=diskGb,proto3" json:"disk_gb,omitempty"`
+	MemoryGb             int32             `protobuf:"varint,8,opt,name=memory_gb,json=memoryGb,proto3" json:"memory_gb,omitempty"`
+	Cpus                 int32             `protobuf:"varint,9,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	Labels               map[string]string `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *NetworkInfo) Reset()         { *m = NetworkInfo{} }
+func (m *NetworkInfo) String() string { return proto.CompactTextString(m) }
+func (*NetworkInfo) ProtoMessage()    {}
+func (*NetworkInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{1}
+}
+func (m *NetworkInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkInfo.Unmarshal(m, b)
+}
+func (m *NetworkInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkInfo.Marshal(b, m, deterministic)
+}
+func (dst *NetworkInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkInfo.Merge(dst, src)
+}
+func (m *NetworkInfo) XXX_Size() int {
+	return xxx_messageInfo_NetworkInfo.Size(m)
+}
+func (m *NetworkInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkInfo proto.InternalMessageInfo
+
+func (m *NetworkInfo) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *NetworkInfo) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *NetworkInfo) GetUptime() int64 {
+	if m != nil {
+		return m.Uptime
+	}
+	return 0
+}
+
+func (m *NetworkInfo) GetSwarmPort() string {
+	if m != nil {
+		return m.SwarmPort
+	}
+	return ""
+}
+
+func (m *NetworkInfo) GetApiPort() string {
+	if m != nil {
+		return m.ApiPort
+	}
+	return ""
+}
+
+func (m *NetworkInfo) GetGatewayPort() string {
+	if m != nil {
+		return m.GatewayPort
+	}
+	return ""
+}
+
+func (m *NetworkInfo) GetDiskGb() int32 {
+	if m != nil {
+		return m.DiskGb
+	}
+	return 0
+}
+
+func (m *NetworkInfo) GetMemoryGb() int32 {
+	if m != nil {
+		return m.MemoryGb
+	}
+	return 0
+}
+
+func (m *NetworkInfo) GetCpus() int32 {
+	if m != nil {
+		return m.Cpus
+	}
+	return 0
+}
+
+func (m *NetworkInfo) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type ListNetworksResponse struct {
+	Networks []*NetworkInfo `protobuf:"bytes,1,rep,name=networks,proto3" json:"networks,omitempty"`
+	// next_cursor is set if there are more networks to list
+	NextCursor           string   `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListNetworksResponse) Reset()         { *m = ListNetworksResponse{} }
+func (m *ListNetworksResponse) String() string { return proto.CompactTextString(m) }
+func (*ListNetworksResponse) ProtoMessage()    {}
+func (*ListNetworksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{2}
+}
+func (m *ListNetworksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNetworksResponse.Unmarshal(m, b)
+}
+func (m *ListNetworksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNetworksResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListNetworksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNetworksResponse.Merge(dst, src)
+}
+func (m *ListNetworksResponse) XXX_Size() int {
+	return xxx_messageInfo_ListNetworksResponse.Size(m)
+}
+func (m *ListNetworksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNetworksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNetworksResponse proto.InternalMessageInfo
+
+func (m *ListNetworksResponse) GetNetworks() []*NetworkInfo {
+	if m != nil {
+		return m.Networks
+	}
+	return nil
+}
+
+func (m *ListNetworksResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type NetworkSettingsRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NetworkSettingsRequest) Reset()         { *m = NetworkSettingsRequest{} }
+func (m *NetworkSettingsRequest) String() string { return proto.CompactTextString(m) }
+func (*NetworkSettingsRequest) ProtoMessage()    {}
+func (*NetworkSettingsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{3}
+}
+func (m *NetworkSettingsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkSettingsRequest.Unmarshal(m, b)
+}
+func (m *NetworkSettingsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkSettingsRequest.Marshal(b, m, deterministic)
+}
+func (dst *NetworkSettingsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkSettingsRequest.Merge(dst, src)
+}
+func (m *NetworkSettingsRequest) XXX_Size() int {
+	return xxx_messageInfo_NetworkSettingsRequest.Size(m)
+}
+func (m *NetworkSettingsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkSettingsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkSettingsRequest proto.InternalMessageInfo
+
+func (m *NetworkSettingsRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+type UpdateNetworkSettingsRequest struct {
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// settings is a JSON object - fields that are present replace the network's
+	// existing settings, and fields set to null are reset
+	Settings             string   `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateNetworkSettingsRequest) Reset()         { *m = UpdateNetworkSettingsRequest{} }
+func (m *UpdateNetworkSettingsRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateNetworkSettingsRequest) ProtoMessage()    {}
+func (*UpdateNetworkSettingsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{4}
+}
+func (m *UpdateNetworkSettingsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateNetworkSettingsRequest.Unmarshal(m, b)
+}
+func (m *UpdateNetworkSettingsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateNetworkSettingsRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateNetworkSettingsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateNetworkSettingsRequest.Merge(dst, src)
+}
+func (m *UpdateNetworkSettingsRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateNetworkSettingsRequest.Size(m)
+}
+func (m *UpdateNetworkSettingsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateNetworkSettingsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateNetworkSettingsRequest proto.InternalMessageInfo
+
+func (m *UpdateNetworkSettingsRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *UpdateNetworkSettingsRequest) GetSettings() string {
+	if m != nil {
+		return m.Settings
+	}
+	return ""
+}
+
+type NetworkSettingsResponse struct {
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// settings is the network's settings as a JSON object
+	Settings             string   `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NetworkSettingsResponse) Reset()         { *m = NetworkSettingsResponse{} }
+func (m *NetworkSettingsResponse) String() string { return proto.CompactTextString(m) }
+func (*NetworkSettingsResponse) ProtoMessage()    {}
+func (*NetworkSettingsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{5}
+}
+func (m *NetworkSettingsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkSettingsResponse.Unmarshal(m, b)
+}
+func (m *NetworkSettingsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkSettingsResponse.Marshal(b, m, deterministic)
+}
+func (dst *NetworkSettingsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkSettingsResponse.Merge(dst, src)
+}
+func (m *NetworkSettingsResponse) XXX_Size() int {
+	return xxx_messageInfo_NetworkSettingsResponse.Size(m)
+}
+func (m *NetworkSettingsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkSettingsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkSettingsResponse proto.InternalMessageInfo
+
+func (m *NetworkSettingsResponse) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *NetworkSettingsResponse) GetSettings() string {
+	if m != nil {
+		return m.Settings
+	}
+	return ""
+}
+
+type BulkNetworkActionRequest struct {
+	// selector selects networks to act on by label, e.g. "tier=trial"
+	Selector string `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// action is the operation to apply, either "update" or "down"
+	Action               string   `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BulkNetworkActionRequest) Reset()         { *m = BulkNetworkActionRequest{} }
+func (m *BulkNetworkActionRequest) String() string { return proto.CompactTextString(m) }
+func (*BulkNetworkActionRequest) ProtoMessage()    {}
+func (*BulkNetworkActionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{6}
+}
+func (m *BulkNetworkActionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkNetworkActionRequest.Unmarshal(m, b)
+}
+func (m *BulkNetworkActionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkNetworkActionRequest.Marshal(b, m, deterministic)
+}
+func (dst *BulkNetworkActionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkNetworkActionRequest.Merge(dst, src)
+}
+func (m *BulkNetworkActionRequest) XXX_Size() int {
+	return xxx_messageInfo_BulkNetworkActionRequest.Size(m)
+}
+func (m *BulkNetworkActionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkNetworkActionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkNetworkActionRequest proto.InternalMessageInfo
+
+func (m *BulkNetworkActionRequest) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+func (m *BulkNetworkActionRequest) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+type BulkNetworkActionResult struct {
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// error is set if the action failed for this network
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BulkNetworkActionResult) Reset()         { *m = BulkNetworkActionResult{} }
+func (m *BulkNetworkActionResult) String() string { return proto.CompactTextString(m) }
+func (*BulkNetworkActionResult) ProtoMessage()    {}
+func (*BulkNetworkActionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{7}
+}
+func (m *BulkNetworkActionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkNetworkActionResult.Unmarshal(m, b)
+}
+func (m *BulkNetworkActionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkNetworkActionResult.Marshal(b, m, deterministic)
+}
+func (dst *BulkNetworkActionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkNetworkActionResult.Merge(dst, src)
+}
+func (m *BulkNetworkActionResult) XXX_Size() int {
+	return xxx_messageInfo_BulkNetworkActionResult.Size(m)
+}
+func (m *BulkNetworkActionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkNetworkActionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkNetworkActionResult proto.InternalMessageInfo
+
+func (m *BulkNetworkActionResult) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *BulkNetworkActionResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type BulkNetworkActionResponse struct {
+	Results              []*BulkNetworkActionResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *BulkNetworkActionResponse) Reset()         { *m = BulkNetworkActionResponse{} }
+func (m *BulkNetworkActionResponse) String() string { return proto.CompactTextString(m) }
+func (*BulkNetworkActionResponse) ProtoMessage()    {}
+func (*BulkNetworkActionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{8}
+}
+func (m *BulkNetworkActionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BulkNetworkActionResponse.Unmarshal(m, b)
+}
+func (m *BulkNetworkActionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BulkNetworkActionResponse.Marshal(b, m, deterministic)
+}
+func (dst *BulkNetworkActionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BulkNetworkActionResponse.Merge(dst, src)
+}
+func (m *BulkNetworkActionResponse) XXX_Size() int {
+	return xxx_messageInfo_BulkNetworkActionResponse.Size(m)
+}
+func (m *BulkNetworkActionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BulkNetworkActionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BulkNetworkActionResponse proto.InternalMessageInfo
+
+func (m *BulkNetworkActionResponse) GetResults() []*BulkNetworkActionResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type APIToken struct {
+	TokenId string   `protobuf:"bytes,1,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	Network string   `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	Name    string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes  []string `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// timestamps are in unix seconds, and are 0 if unset
+	CreatedAt            int64    `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt            int64    `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RevokedAt            int64    `protobuf:"varint,7,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *APIToken) Reset()         { *m = APIToken{} }
+func (m *APIToken) String() string { return proto.CompactTextString(m) }
+func (*APIToken) ProtoMessage()    {}
+func (*APIToken) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{9}
+}
+func (m *APIToken) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIToken.Unmarshal(m, b)
+}
+func (m *APIToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIToken.Marshal(b, m, deterministic)
+}
+func (dst *APIToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIToken.Merge(dst, src)
+}
+func (m *APIToken) XXX_Size() int {
+	return xxx_messageInfo_APIToken.Size(m)
+}
+func (m *APIToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIToken.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIToken proto.InternalMessageInfo
+
+func (m *APIToken) GetTokenId() string {
+	if m != nil {
+		return m.TokenId
+	}
+	return ""
+}
+
+func (m *APIToken) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *APIToken) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *APIToken) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *APIToken) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *APIToken) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *APIToken) GetRevokedAt() int64 {
+	if m != nil {
+		return m.RevokedAt
+	}
+	return 0
+}
+
+type CreateAPITokenRequest struct {
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// name is a description of the token's purpose
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// scopes are the permissions granted to the token - one or more of
+	// "read-only", "pin-only", or "admin"
+	Scopes []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// expires_in sets the token's lifetime, e.g. "720h" - tokens without one
+	// never expire
+	ExpiresIn            string   `protobuf:"bytes,4,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAPITokenRequest) Reset()         { *m = CreateAPITokenRequest{} }
+func (m *CreateAPITokenRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateAPITokenRequest) ProtoMessage()    {}
+func (*CreateAPITokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{10}
+}
+func (m *CreateAPITokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPITokenRequest.Unmarshal(m, b)
+}
+func (m *CreateAPITokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPITokenRequest.Marshal(b, m, deterministic)
+}
+func (dst *CreateAPITokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPITokenRequest.Merge(dst, src)
+}
+func (m *CreateAPITokenRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateAPITokenRequest.Size(m)
+}
+func (m *CreateAPITokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPITokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAPITokenRequest proto.InternalMessageInfo
+
+func (m *CreateAPITokenRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *CreateAPITokenRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateAPITokenRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *CreateAPITokenRequest) GetExpiresIn() string {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return ""
+}
+
+type CreateAPITokenResponse struct {
+	Token *APIToken `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// secret is the credential to provide as a bearer token - it is not stored,
+	// and cannot be retrieved again
+	Secret               string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAPITokenResponse) Reset()         { *m = CreateAPITokenResponse{} }
+func (m *CreateAPITokenResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateAPITokenResponse) ProtoMessage()    {}
+func (*CreateAPITokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{11}
+}
+func (m *CreateAPITokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPITokenResponse.Unmarshal(m, b)
+}
+func (m *CreateAPITokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPITokenResponse.Marshal(b, m, deterministic)
+}
+func (dst *CreateAPITokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPITokenResponse.Merge(dst, src)
+}
+func (m *CreateAPITokenResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateAPITokenResponse.Size(m)
+}
+func (m *CreateAPITokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPITokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAPITokenResponse proto.InternalMessageInfo
+
+func (m *CreateAPITokenResponse) GetToken() *APIToken {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *CreateAPITokenResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+type RevokeAPITokenRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	TokenId              string   `protobuf:"bytes,2,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeAPITokenRequest) Reset()         { *m = RevokeAPITokenRequest{} }
+func (m *RevokeAPITokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPITokenRequest) ProtoMessage()    {}
+func (*RevokeAPITokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{12}
+}
+func (m *RevokeAPITokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPITokenRequest.Unmarshal(m, b)
+}
+func (m *RevokeAPITokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPITokenRequest.Marshal(b, m, deterministic)
+}
+func (dst *RevokeAPITokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPITokenRequest.Merge(dst, src)
+}
+func (m *RevokeAPITokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPITokenRequest.Size(m)
+}
+func (m *RevokeAPITokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPITokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeAPITokenRequest proto.InternalMessageInfo
+
+func (m *RevokeAPITokenRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *RevokeAPITokenRequest) GetTokenId() string {
+	if m != nil {
+		return m.TokenId
+	}
+	return ""
+}
+
+type RevokeAPITokenResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeAPITokenResponse) Reset()         { *m = RevokeAPITokenResponse{} }
+func (m *RevokeAPITokenResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPITokenResponse) ProtoMessage()    {}
+func (*RevokeAPITokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{13}
+}
+func (m *RevokeAPITokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPITokenResponse.Unmarshal(m, b)
+}
+func (m *RevokeAPITokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPITokenResponse.Marshal(b, m, deterministic)
+}
+func (dst *RevokeAPITokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPITokenResponse.Merge(dst, src)
+}
+func (m *RevokeAPITokenResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPITokenResponse.Size(m)
+}
+func (m *RevokeAPITokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPITokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeAPITokenResponse proto.InternalMessageInfo
+
+type ListAPITokensRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAPITokensRequest) Reset()         { *m = ListAPITokensRequest{} }
+func (m *ListAPITokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAPITokensRequest) ProtoMessage()    {}
+func (*ListAPITokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{14}
+}
+func (m *ListAPITokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAPITokensRequest.Unmarshal(m, b)
+}
+func (m *ListAPITokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAPITokensRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListAPITokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAPITokensRequest.Merge(dst, src)
+}
+func (m *ListAPITokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAPITokensRequest.Size(m)
+}
+func (m *ListAPITokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAPITokensRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAPITokensRequest proto.InternalMessageInfo
+
+func (m *ListAPITokensRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+type ListAPITokensResponse struct {
+	Tokens               []*APIToken `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListAPITokensResponse) Reset()         { *m = ListAPITokensResponse{} }
+func (m *ListAPITokensResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAPITokensResponse) ProtoMessage()    {}
+func (*ListAPITokensResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{15}
+}
+func (m *ListAPITokensResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAPITokensResponse.Unmarshal(m, b)
+}
+func (m *ListAPITokensResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAPITokensResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListAPITokensResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAPITokensResponse.Merge(dst, src)
+}
+func (m *ListAPITokensResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAPITokensResponse.Size(m)
+}
+func (m *ListAPITokensResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAPITokensResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAPITokensResponse proto.InternalMessageInfo
+
+func (m *ListAPITokensResponse) GetTokens() []*APIToken {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type NetworkUser struct {
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// role is the user's assigned role, or empty if the user has the default role
+	Role                 string   `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NetworkUser) Reset()         { *m = NetworkUser{} }
+func (m *NetworkUser) String() string { return proto.CompactTextString(m) }
+func (*NetworkUser) ProtoMessage()    {}
+func (*NetworkUser) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{16}
+}
+func (m *NetworkUser) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkUser.Unmarshal(m, b)
+}
+func (m *NetworkUser) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkUser.Marshal(b, m, deterministic)
+}
+func (dst *NetworkUser) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkUser.Merge(dst, src)
+}
+func (m *NetworkUser) XXX_Size() int {
+	return xxx_messageInfo_NetworkUser.Size(m)
+}
+func (m *NetworkUser) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkUser.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkUser proto.InternalMessageInfo
+
+func (m *NetworkUser) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *NetworkUser) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type NetworkUsersRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NetworkUsersRequest) Reset()         { *m = NetworkUsersRequest{} }
+func (m *NetworkUsersRequest) String() string { return proto.CompactTextString(m) }
+func (*NetworkUsersRequest) ProtoMessage()    {}
+func (*NetworkUsersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{17}
+}
+func (m *NetworkUsersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkUsersRequest.Unmarshal(m, b)
+}
+func (m *NetworkUsersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkUsersRequest.Marshal(b, m, deterministic)
+}
+func (dst *NetworkUsersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkUsersRequest.Merge(dst, src)
+}
+func (m *NetworkUsersRequest) XXX_Size() int {
+	return xxx_messageInfo_NetworkUsersRequest.Size(m)
+}
+func (m *NetworkUsersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkUsersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkUsersRequest proto.InternalMessageInfo
+
+func (m *NetworkUsersRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+type NetworkUsersResponse struct {
+	Users                []*NetworkUser `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *NetworkUsersResponse) Reset()         { *m = NetworkUsersResponse{} }
+func (m *NetworkUsersResponse) String() string { return proto.CompactTextString(m) }
+func (*NetworkUsersResponse) ProtoMessage()    {}
+func (*NetworkUsersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{18}
+}
+func (m *NetworkUsersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetworkUsersResponse.Unmarshal(m, b)
+}
+func (m *NetworkUsersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetworkUsersResponse.Marshal(b, m, deterministic)
+}
+func (dst *NetworkUsersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkUsersResponse.Merge(dst, src)
+}
+func (m *NetworkUsersResponse) XXX_Size() int {
+	return xxx_messageInfo_NetworkUsersResponse.Size(m)
+}
+func (m *NetworkUsersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkUsersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkUsersResponse proto.InternalMessageInfo
+
+func (m *NetworkUsersResponse) GetUsers() []*NetworkUser {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+type SetUserRoleRequest struct {
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	User    string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	// role is one of "viewer", "writer", or "admin" - an empty role removes the
+	// user's assigned role
+	Role                 string   `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetUserRoleRequest) Reset()         { *m = SetUserRoleRequest{} }
+func (m *SetUserRoleRequest) String() string { return proto.CompactTextString(m) }
+func (*SetUserRoleRequest) ProtoMessage()    {}
+func (*SetUserRoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{19}
+}
+func (m *SetUserRoleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetUserRoleRequest.Unmarshal(m, b)
+}
+func (m *SetUserRoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetUserRoleRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetUserRoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetUserRoleRequest.Merge(dst, src)
+}
+func (m *SetUserRoleRequest) XXX_Size() int {
+	return xxx_messageInfo_SetUserRoleRequest.Size(m)
+}
+func (m *SetUserRoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetUserRoleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetUserRoleRequest proto.InternalMessageInfo
+
+func (m *SetUserRoleRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *SetUserRoleRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *SetUserRoleRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type DenylistEntry struct {
+	Rule   string `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// created_at is in unix seconds
+	CreatedAt            int64    `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DenylistEntry) Reset()         { *m = DenylistEntry{} }
+func (m *DenylistEntry) String() string { return proto.CompactTextString(m) }
+func (*DenylistEntry) ProtoMessage()    {}
+func (*DenylistEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{20}
+}
+func (m *DenylistEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DenylistEntry.Unmarshal(m, b)
+}
+func (m *DenylistEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DenylistEntry.Marshal(b, m, deterministic)
+}
+func (dst *DenylistEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DenylistEntry.Merge(dst, src)
+}
+func (m *DenylistEntry) XXX_Size() int {
+	return xxx_messageInfo_DenylistEntry.Size(m)
+}
+func (m *DenylistEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_DenylistEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DenylistEntry proto.InternalMessageInfo
+
+func (m *DenylistEntry) GetRule() string {
+	if m != nil {
+		return m.Rule
+	}
+	return ""
+}
+
+func (m *DenylistEntry) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *DenylistEntry) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type AddDenylistEntriesRequest struct {
+	// network is the network to block content on - entries without a network
+	// apply to all networks
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// entries are rules in the compact denylist format: "/ipfs/<cid>[/<path>]",
+	// "/ipns/<name>[/<path>]", or "//<double hash>"
+	Entries              []string `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddDenylistEntriesRequest) Reset()         { *m = AddDenylistEntriesRequest{} }
+func (m *AddDenylistEntriesRequest) String() string { return proto.CompactTextString(m) }
+func (*AddDenylistEntriesRequest) ProtoMessage()    {}
+func (*AddDenylistEntriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{21}
+}
+func (m *AddDenylistEntriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddDenylistEntriesRequest.Unmarshal(m, b)
+}
+func (m *AddDenylistEntriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddDenylistEntriesRequest.Marshal(b, m, deterministic)
+}
+func (dst *AddDenylistEntriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddDenylistEntriesRequest.Merge(dst, src)
+}
+func (m *AddDenylistEntriesRequest) XXX_Size() int {
+	return xxx_messageInfo_AddDenylistEntriesRequest.Size(m)
+}
+func (m *AddDenylistEntriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddDenylistEntriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddDenylistEntriesRequest proto.InternalMessageInfo
+
+func (m *AddDenylistEntriesRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *AddDenylistEntriesRequest) GetEntries() []string {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *AddDenylistEntriesRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type RemoveDenylistEntriesRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Entries              []string `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveDenylistEntriesRequest) Reset()         { *m = RemoveDenylistEntriesRequest{} }
+func (m *RemoveDenylistEntriesRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveDenylistEntriesRequest) ProtoMessage()    {}
+func (*RemoveDenylistEntriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{22}
+}
+func (m *RemoveDenylistEntriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveDenylistEntriesRequest.Unmarshal(m, b)
+}
+func (m *RemoveDenylistEntriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveDenylistEntriesRequest.Marshal(b, m, deterministic)
+}
+func (dst *RemoveDenylistEntriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveDenylistEntriesRequest.Merge(dst, src)
+}
+func (m *RemoveDenylistEntriesRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveDenylistEntriesRequest.Size(m)
+}
+func (m *RemoveDenylistEntriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveDenylistEntriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveDenylistEntriesRequest proto.InternalMessageInfo
+
+func (m *RemoveDenylistEntriesRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *RemoveDenylistEntriesRequest) GetEntries() []string {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type UpdateDenylistResponse struct {
+	// count is the number of entries added or removed
+	Count                int64    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateDenylistResponse) Reset()         { *m = UpdateDenylistResponse{} }
+func (m *UpdateDenylistResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateDenylistResponse) ProtoMessage()    {}
+func (*UpdateDenylistResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{23}
+}
+func (m *UpdateDenylistResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateDenylistResponse.Unmarshal(m, b)
+}
+func (m *UpdateDenylistResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateDenylistResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateDenylistResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateDenylistResponse.Merge(dst, src)
+}
+func (m *UpdateDenylistResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateDenylistResponse.Size(m)
+}
+func (m *UpdateDenylistResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateDenylistResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateDenylistResponse proto.InternalMessageInfo
+
+func (m *UpdateDenylistResponse) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type ListDenylistRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDenylistRequest) Reset()         { *m = ListDenylistRequest{} }
+func (m *ListDenylistRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDenylistRequest) ProtoMessage()    {}
+func (*ListDenylistRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{24}
+}
+func (m *ListDenylistRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDenylistRequest.Unmarshal(m, b)
+}
+func (m *ListDenylistRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDenylistRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListDenylistRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDenylistRequest.Merge(dst, src)
+}
+func (m *ListDenylistRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDenylistRequest.Size(m)
+}
+func (m *ListDenylistRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDenylistRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDenylistRequest proto.InternalMessageInfo
+
+func (m *ListDenylistRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+type ListDenylistResponse struct {
+	Entries              []*DenylistEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListDenylistResponse) Reset()         { *m = ListDenylistResponse{} }
+func (m *ListDenylistResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDenylistResponse) ProtoMessage()    {}
+func (*ListDenylistResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{25}
+}
+func (m *ListDenylistResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDenylistResponse.Unmarshal(m, b)
+}
+func (m *ListDenylistResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDenylistResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListDenylistResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDenylistResponse.Merge(dst, src)
+}
+func (m *ListDenylistResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDenylistResponse.Size(m)
+}
+func (m *ListDenylistResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDenylistResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDenylistResponse proto.InternalMessageInfo
+
+func (m *ListDenylistResponse) GetEntries() []*DenylistEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type Domain struct {
+	Host    string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Network string `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	// root_path is the content path served, or empty if content is served from
+	// the host's DNSLink record
+	RootPath string `protobuf:"bytes,3,opt,name=root_path,json=rootPath,proto3" json:"root_path,omitempty"`
+	Verified bool   `protobuf:"varint,4,opt,name=verified,proto3" json:"verified,omitempty"`
+	// challenge_record is the name of the TXT record that must contain
+	// challenge_token for the domain to be verified
+	ChallengeRecord string `protobuf:"bytes,5,opt,name=challenge_record,json=challengeRecord,proto3" json:"challenge_record,omitempty"`
+	ChallengeToken  string `protobuf:"bytes,6,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	// created_at and verified_at are in unix seconds
+	CreatedAt            int64    `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	VerifiedAt           int64    `protobuf:"varint,8,opt,name=verified_at,json=verifiedAt,proto3" json:"verified_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Domain) Reset()         { *m = Domain{} }
+func (m *Domain) String() string { return proto.CompactTextString(m) }
+func (*Domain) ProtoMessage()    {}
+func (*Domain) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{26}
+}
+func (m *Domain) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Domain.Unmarshal(m, b)
+}
+func (m *Domain) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Domain.Marshal(b, m, deterministic)
+}
+func (dst *Domain) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Domain.Merge(dst, src)
+}
+func (m *Domain) XXX_Size() int {
+	return xxx_messageInfo_Domain.Size(m)
+}
+func (m *Domain) XXX_DiscardUnknown() {
+	xxx_messageInfo_Domain.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Domain proto.InternalMessageInfo
+
+func (m *Domain) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Domain) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *Domain) GetRootPath() string {
+	if m != nil {
+		return m.RootPath
+	}
+	return ""
+}
+
+func (m *Domain) GetVerified() bool {
+	if m != nil {
+		return m.Verified
+	}
+	return false
+}
+
+func (m *Domain) GetChallengeRecord() string {
+	if m != nil {
+		return m.ChallengeRecord
+	}
+	return ""
+}
+
+func (m *Domain) GetChallengeToken() string {
+	if m != nil {
+		return m.ChallengeToken
+	}
+	return ""
+}
+
+func (m *Domain) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Domain) GetVerifiedAt() int64 {
+	if m != nil {
+		return m.VerifiedAt
+	}
+	return 0
+}
+
+type AddDomainRequest struct {
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Host    string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	// root_path is an optional "/ipfs/<cid>[/<path>]" or "/ipns/<name>[/<path>]"
+	// path to serve - the host's DNSLink record is used if not provided
+	RootPath             string   `protobuf:"bytes,3,opt,name=root_path,json=rootPath,proto3" json:"root_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddDomainRequest) Reset()         { *m = AddDomainRequest{} }
+func (m *AddDomainRequest) String() string { return proto.CompactTextString(m) }
+func (*AddDomainRequest) ProtoMessage()    {}
+func (*AddDomainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{27}
+}
+func (m *AddDomainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddDomainRequest.Unmarshal(m, b)
+}
+func (m *AddDomainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddDomainRequest.Marshal(b, m, deterministic)
+}
+func (dst *AddDomainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddDomainRequest.Merge(dst, src)
+}
+func (m *AddDomainRequest) XXX_Size() int {
+	return xxx_messageInfo_AddDomainRequest.Size(m)
+}
+func (m *AddDomainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddDomainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddDomainRequest proto.InternalMessageInfo
+
+func (m *AddDomainRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *AddDomainRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *AddDomainRequest) GetRootPath() string {
+	if m != nil {
+		return m.RootPath
+	}
+	return ""
+}
+
+type VerifyDomainRequest struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyDomainRequest) Reset()         { *m = VerifyDomainRequest{} }
+func (m *VerifyDomainRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyDomainRequest) ProtoMessage()    {}
+func (*VerifyDomainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{28}
+}
+func (m *VerifyDomainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyDomainRequest.Unmarshal(m, b)
+}
+func (m *VerifyDomainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyDomainRequest.Marshal(b, m, deterministic)
+}
+func (dst *VerifyDomainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyDomainRequest.Merge(dst, src)
+}
+func (m *VerifyDomainRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyDomainRequest.Size(m)
+}
+func (m *VerifyDomainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyDomainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyDomainRequest proto.InternalMessageInfo
+
+func (m *VerifyDomainRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+type RemoveDomainRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Host                 string   `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveDomainRequest) Reset()         { *m = RemoveDomainRequest{} }
+func (m *RemoveDomainRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveDomainRequest) ProtoMessage()    {}
+func (*RemoveDomainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{29}
+}
+func (m *RemoveDomainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveDomainRequest.Unmarshal(m, b)
+}
+func (m *RemoveDomainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveDomainRequest.Marshal(b, m, deterministic)
+}
+func (dst *RemoveDomainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveDomainRequest.Merge(dst, src)
+}
+func (m *RemoveDomainRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveDomainRequest.Size(m)
+}
+func (m *RemoveDomainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveDomainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveDomainRequest proto.InternalMessageInfo
+
+func (m *RemoveDomainRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *RemoveDomainRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+type RemoveDomainResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveDomainResponse) Reset()         { *m = RemoveDomainResponse{} }
+func (m *RemoveDomainResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveDomainResponse) ProtoMessage()    {}
+func (*RemoveDomainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{30}
+}
+func (m *RemoveDomainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveDomainResponse.Unmarshal(m, b)
+}
+func (m *RemoveDomainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveDomainResponse.Marshal(b, m, deterministic)
+}
+func (dst *RemoveDomainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveDomainResponse.Merge(dst, src)
+}
+func (m *RemoveDomainResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoveDomainResponse.Size(m)
+}
+func (m *RemoveDomainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveDomainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveDomainResponse proto.InternalMessageInfo
+
+type ListDomainsRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDomainsRequest) Reset()         { *m = ListDomainsRequest{} }
+func (m *ListDomainsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDomainsRequest) ProtoMessage()    {}
+func (*ListDomainsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{31}
+}
+func (m *ListDomainsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDomainsRequest.Unmarshal(m, b)
+}
+func (m *ListDomainsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDomainsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListDomainsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDomainsRequest.Merge(dst, src)
+}
+func (m *ListDomainsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDomainsRequest.Size(m)
+}
+func (m *ListDomainsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDomainsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDomainsRequest proto.InternalMessageInfo
+
+func (m *ListDomainsRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+type ListDomainsResponse struct {
+	Domains              []*Domain `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListDomainsResponse) Reset()         { *m = ListDomainsResponse{} }
+func (m *ListDomainsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDomainsResponse) ProtoMessage()    {}
+func (*ListDomainsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{32}
+}
+func (m *ListDomainsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDomainsResponse.Unmarshal(m, b)
+}
+func (m *ListDomainsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDomainsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListDomainsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDomainsResponse.Merge(dst, src)
+}
+func (m *ListDomainsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDomainsResponse.Size(m)
+}
+func (m *ListDomainsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDomainsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDomainsResponse proto.InternalMessageInfo
+
+func (m *ListDomainsResponse) GetDomains() []*Domain {
+	if m != nil {
+		return m.Domains
+	}
+	return nil
+}
+
+type WatchRegistryRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRegistryRequest) Reset()         { *m = WatchRegistryRequest{} }
+func (m *WatchRegistryRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRegistryRequest) ProtoMessage()    {}
+func (*WatchRegistryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{33}
+}
+func (m *WatchRegistryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRegistryRequest.Unmarshal(m, b)
+}
+func (m *WatchRegistryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRegistryRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchRegistryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRegistryRequest.Merge(dst, src)
+}
+func (m *WatchRegistryRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRegistryRequest.Size(m)
+}
+func (m *WatchRegistryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRegistryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRegistryRequest proto.InternalMessageInfo
+
+type RegistryNode struct {
+	Network              string            `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	SwarmPort            string            `protobuf:"bytes,2,opt,name=swarm_port,json=swarmPort,proto3" json:"swarm_port,omitempty"`
+	SwarmWsPort          string            `protobuf:"bytes,3,opt,name=swarm_ws_port,json=swarmWsPort,proto3" json:"swarm_ws_port,omitempty"`
+	ApiPort              string            `protobuf:"bytes,4,opt,name=api_port,json=apiPort,proto3" json:"api_port,omitempty"`
+	GatewayPort          string            `protobuf:"bytes,5,opt,name=gateway_port,json=gatewayPort,proto3" json:"gateway_port,omitempty"`
+	Labels               map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RegistryNode) Reset()         { *m = RegistryNode{} }
+func (m *RegistryNode) String() string { return proto.CompactTextString(m) }
+func (*RegistryNode) ProtoMessage()    {}
+func (*RegistryNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{34}
+}
+func (m *RegistryNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegistryNode.Unmarshal(m, b)
+}
+func (m *RegistryNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegistryNode.Marshal(b, m, deterministic)
+}
+func (dst *RegistryNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegistryNode.Merge(dst, src)
+}
+func (m *RegistryNode) XXX_Size() int {
+	return xxx_messageInfo_RegistryNode.Size(m)
+}
+func (m *RegistryNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegistryNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegistryNode proto.InternalMessageInfo
+
+func (m *RegistryNode) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *RegistryNode) GetSwarmPort() string {
+	if m != nil {
+		return m.SwarmPort
+	}
+	return ""
+}
+
+func (m *RegistryNode) GetSwarmWsPort() string {
+	if m != nil {
+		return m.SwarmWsPort
+	}
+	return ""
+}
+
+func (m *RegistryNode) GetApiPort() string {
+	if m != nil {
+		return m.ApiPort
+	}
+	return ""
+}
+
+func (m *RegistryNode) GetGatewayPort() string {
+	if m != nil {
+		return m.GatewayPort
+	}
+	return ""
+}
+
+func (m *RegistryNode) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type RegistryEvent struct {
+	Type                 RegistryEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=rpc.RegistryEvent_Type" json:"type,omitempty"`
+	Nodes                []*RegistryNode    `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Network              string             `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	Host                 string             `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RegistryEvent) Reset()         { *m = RegistryEvent{} }
+func (m *RegistryEvent) String() string { return proto.CompactTextString(m) }
+func (*RegistryEvent) ProtoMessage()    {}
+func (*RegistryEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{35}
+}
+func (m *RegistryEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegistryEvent.Unmarshal(m, b)
+}
+func (m *RegistryEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegistryEvent.Marshal(b, m, deterministic)
+}
+func (dst *RegistryEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegistryEvent.Merge(dst, src)
+}
+func (m *RegistryEvent) XXX_Size() int {
+	return xxx_messageInfo_RegistryEvent.Size(m)
+}
+func (m *RegistryEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegistryEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegistryEvent proto.InternalMessageInfo
+
+func (m *RegistryEvent) GetType() RegistryEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return RegistryEvent_SNAPSHOT
+}
+
+func (m *RegistryEvent) GetNodes() []*RegistryNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *RegistryEvent) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *RegistryEvent) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+type UsageRequest struct {
+	// network and feature filter the usage retrieved - usage of all networks
+	// and features is retrieved if they are not provided
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Feature string `protobuf:"bytes,2,opt,name=feature,proto3" json:"feature,omitempty"`
+	// since and until bound the hours usage is retrieved for, in unix seconds -
+	// hours starting at or after since, and before until, are included, and
+	// either may be 0 to leave the range open
+	Since                int64    `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until                int64    `protobuf:"varint,4,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageRequest) Reset()         { *m = UsageRequest{} }
+func (m *UsageRequest) String() string { return proto.CompactTextString(m) }
+func (*UsageRequest) ProtoMessage()    {}
+func (*UsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{36}
+}
+func (m *UsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageRequest.Unmarshal(m, b)
+}
+func (m *UsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageRequest.Marshal(b, m, deterministic)
+}
+func (dst *UsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageRequest.Merge(dst, src)
+}
+func (m *UsageRequest) XXX_Size() int {
+	return xxx_messageInfo_UsageRequest.Size(m)
+}
+func (m *UsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageRequest proto.InternalMessageInfo
+
+func (m *UsageRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *UsageRequest) GetFeature() string {
+	if m != nil {
+		return m.Feature
+	}
+	return ""
+}
+
+func (m *UsageRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *UsageRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+type UsageRecord struct {
+	// hour is the start of the hour usage is aggregated in, in unix seconds
+	Hour    int64  `protobuf:"varint,1,opt,name=hour,proto3" json:"hour,omitempty"`
+	Network string `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	Feature string `protobuf:"bytes,3,opt,name=feature,proto3" json:"feature,omitempty"`
+	// user is empty for swarm traffic and anonymous requests
+	User string `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	// bytes_in are bytes sent to the network, and bytes_out are bytes sent by
+	// the network
+	BytesIn              int64    `protobuf:"varint,5,opt,name=bytes_in,json=bytesIn,proto3" json:"bytes_in,omitempty"`
+	BytesOut             int64    `protobuf:"varint,6,opt,name=bytes_out,json=bytesOut,proto3" json:"bytes_out,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageRecord) Reset()         { *m = UsageRecord{} }
+func (m *UsageRecord) String() string { return proto.CompactTextString(m) }
+func (*UsageRecord) ProtoMessage()    {}
+func (*UsageRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{37}
+}
+func (m *UsageRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageRecord.Unmarshal(m, b)
+}
+func (m *UsageRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageRecord.Marshal(b, m, deterministic)
+}
+func (dst *UsageRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageRecord.Merge(dst, src)
+}
+func (m *UsageRecord) XXX_Size() int {
+	return xxx_messageInfo_UsageRecord.Size(m)
+}
+func (m *UsageRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageRecord proto.InternalMessageInfo
+
+func (m *UsageRecord) GetHour() int64 {
+	if m != nil {
+		return m.Hour
+	}
+	return 0
+}
+
+func (m *UsageRecord) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *UsageRecord) GetFeature() string {
+	if m != nil {
+		return m.Feature
+	}
+	return ""
+}
+
+func (m *UsageRecord) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *UsageRecord) GetBytesIn() int64 {
+	if m != nil {
+		return m.BytesIn
+	}
+	return 0
+}
+
+func (m *UsageRecord) GetBytesOut() int64 {
+	if m != nil {
+		return m.BytesOut
+	}
+	return 0
+}
+
+type UsageResponse struct {
+	Records              []*UsageRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *UsageResponse) Reset()         { *m = UsageResponse{} }
+func (m *UsageResponse) String() string { return proto.CompactTextString(m) }
+func (*UsageResponse) ProtoMessage()    {}
+func (*UsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ad52ebe42292d281, []int{38}
+}
+func (m *UsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageResponse.Unmarshal(m, b)
+}
+func (m *UsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageResponse.Marshal(b, m, deterministic)
+}
+func (dst *UsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageResponse.Merge(dst, src)
+}
+func (m *UsageResponse) XXX_Size() int {
+	return xxx_messageInfo_UsageResponse.Size(m)
+}
+func (m *UsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageResponse proto.InternalMessageInfo
+
+func (m *UsageResponse) GetRecords() []*UsageRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ListNetworksRequest)(nil), "rpc.ListNetworksRequest")
+	proto.RegisterType((*NetworkInfo)(nil), "rpc.NetworkInfo")
+	proto.RegisterMapType((map[string]string)(nil), "rpc.NetworkInfo.LabelsEntry")
+	proto.RegisterType((*ListNetworksResponse)(nil), "rpc.ListNetworksResponse")
+	proto.RegisterType((*NetworkSettingsRequest)(nil), "rpc.NetworkSettingsRequest")
+	proto.RegisterType((*UpdateNetworkSettingsRequest)(nil), "rpc.UpdateNetworkSettingsRequest")
+	proto.RegisterType((*NetworkSettingsResponse)(nil), "rpc.NetworkSettingsResponse")
+	proto.RegisterType((*BulkNetworkActionRequest)(nil), "rpc.BulkNetworkActionRequest")
+	proto.RegisterType((*BulkNetworkActionResult)(nil), "rpc.BulkNetworkActionResult")
+	proto.RegisterType((*BulkNetworkActionResponse)(nil), "rpc.BulkNetworkActionResponse")
+	proto.RegisterType((*APIToken)(nil), "rpc.APIToken")
+	proto.RegisterType((*CreateAPITokenRequest)(nil), "rpc.CreateAPITokenRequest")
+	proto.RegisterType((*CreateAPITokenResponse)(nil), "rpc.CreateAPITokenResponse")
+	proto.RegisterType((*RevokeAPITokenRequest)(nil), "rpc.RevokeAPITokenRequest")
+	proto.RegisterType((*RevokeAPITokenResponse)(nil), "rpc.RevokeAPITokenResponse")
+	proto.RegisterType((*ListAPITokensRequest)(nil), "rpc.ListAPITokensRequest")
+	proto.RegisterType((*ListAPITokensResponse)(nil), "rpc.ListAPITokensResponse")
+	proto.RegisterType((*NetworkUser)(nil), "rpc.NetworkUser")
+	proto.RegisterType((*NetworkUsersRequest)(nil), "rpc.NetworkUsersRequest")
+	proto.RegisterType((*NetworkUsersResponse)(nil), "rpc.NetworkUsersResponse")
+	proto.RegisterType((*SetUserRoleRequest)(nil), "rpc.SetUserRoleRequest")
+	proto.RegisterType((*DenylistEntry)(nil), "rpc.DenylistEntry")
+	proto.RegisterType((*AddDenylistEntriesRequest)(nil), "rpc.AddDenylistEntriesRequest")
+	proto.RegisterType((*RemoveDenylistEntriesRequest)(nil), "rpc.RemoveDenylistEntriesRequest")
+	proto.RegisterType((*UpdateDenylistResponse)(nil), "rpc.UpdateDenylistResponse")
+	proto.RegisterType((*ListDenylistRequest)(nil), "rpc.ListDenylistRequest")
+	proto.RegisterType((*ListDenylistResponse)(nil), "rpc.ListDenylistResponse")
+	proto.RegisterType((*Domain)(nil), "rpc.Domain")
+	proto.RegisterType((*AddDomainRequest)(nil), "rpc.AddDomainRequest")
+	proto.RegisterType((*VerifyDomainRequest)(nil), "rpc.VerifyDomainRequest")
+	proto.RegisterType((*RemoveDomainRequest)(nil), "rpc.RemoveDomainRequest")
+	proto.RegisterType((*RemoveDomainResponse)(nil), "rpc.RemoveDomainResponse")
+	proto.RegisterType((*ListDomainsRequest)(nil), "rpc.ListDomainsRequest")
+	proto.RegisterType((*ListDomainsResponse)(nil), "rpc.ListDomainsResponse")
+	proto.RegisterType((*WatchRegistryRequest)(nil), "rpc.WatchRegistryRequest")
+	proto.RegisterType((*RegistryNode)(nil), "rpc.RegistryNode")
+	proto.RegisterMapType((map[string]string)(nil), "rpc.RegistryNode.LabelsEntry")
+	proto.RegisterType((*RegistryEvent)(nil), "rpc.RegistryEvent")
+	proto.RegisterType((*UsageRequest)(nil), "rpc.UsageRequest")
+	proto.RegisterType((*UsageRecord)(nil), "rpc.UsageRecord")
+	proto.RegisterType((*UsageResponse)(nil), "rpc.UsageResponse")
+	proto.RegisterEnum("rpc.RegistryEvent_Type", RegistryEvent_Type_name, RegistryEvent_Type_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ControlClient is the client API for Control service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ControlClient interface {
+	ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error)
+	GetNetworkSettings(ctx context.Context, in *NetworkSettingsRequest, opts ...grpc.CallOption) (*NetworkSettingsResponse, error)
+	UpdateNetworkSettings(ctx context.Context, in *UpdateNetworkSettingsRequest, opts ...grpc.CallOption) (*NetworkSettingsResponse, error)
+	BulkNetworkAction(ctx context.Context, in *BulkNetworkActionRequest, opts ...grpc.CallOption) (*BulkNetworkActionResponse, error)
+	CreateAPIToken(ctx context.Context, in *CreateAPITokenRequest, opts ...grpc.CallOption) (*CreateAPITokenResponse, error)
+	RevokeAPIToken(ctx context.Context, in *RevokeAPITokenRequest, opts ...grpc.CallOption) (*RevokeAPITokenResponse, error)
+	ListAPITokens(ctx context.Context, in *ListAPITokensRequest, opts ...grpc.CallOption) (*ListAPITokensResponse, error)
+	ListNetworkUsers(ctx context.Context, in *NetworkUsersRequest, opts ...grpc.CallOption) (*NetworkUsersResponse, error)
+	SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*NetworkUsersResponse, error)
+	AddDenylistEntries(ctx context.Context, in *AddDenylistEntriesRequest, opts ...grpc.CallOption) (*UpdateDenylistResponse, error)
+	RemoveDenylistEntries(ctx context.Context, in *RemoveDenylistEntriesRequest, opts ...grpc.CallOption) (*UpdateDenylistResponse, error)
+	ListDenylist(ctx context.Context, in *ListDenylistRequest, opts ...grpc.CallOption) (*ListDenylistResponse, error)
+	AddDomain(ctx context.Context, in *AddDomainRequest, opts ...grpc.CallOption) (*Domain, error)
+	VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*Domain, error)
+	RemoveDomain(ctx context.Context, in *RemoveDomainRequest, opts ...grpc.CallOption) (*RemoveDomainResponse, error)
+	ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error)
+	WatchRegistry(ctx context.Context, in *WatchRegistryRequest, opts ...grpc.CallOption) (Control_WatchRegistryClient, error)
+	GetUsage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error)
+}
+
+type controlClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewControlClient(cc *grpc.ClientConn) ControlClient {
+	return &controlClient{cc}
+}
+
+func (c *controlClient) ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error) {
+	out := new(ListNetworksResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/ListNetworks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) GetNetworkSettings(ctx context.Context, in *NetworkSettingsRequest, opts ...grpc.CallOption) (*NetworkSettingsResponse, error) {
+	out := new(NetworkSettingsResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/GetNetworkSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) UpdateNetworkSettings(ctx context.Context, in *UpdateNetworkSettingsRequest, opts ...grpc.CallOption) (*NetworkSettingsResponse, error) {
+	out := new(NetworkSettingsResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/UpdateNetworkSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) BulkNetworkAction(ctx context.Context, in *BulkNetworkActionRequest, opts ...grpc.CallOption) (*BulkNetworkActionResponse, error) {
+	out := new(BulkNetworkActionResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/BulkNetworkAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) CreateAPIToken(ctx context.Context, in *CreateAPITokenRequest, opts ...grpc.CallOption) (*CreateAPITokenResponse, error) {
+	out := new(CreateAPITokenResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/CreateAPIToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RevokeAPIToken(ctx context.Context, in *RevokeAPITokenRequest, opts ...grpc.CallOption) (*RevokeAPITokenResponse, error) {
+	out := new(RevokeAPITokenResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/RevokeAPIToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ListAPITokens(ctx context.Context, in *ListAPITokensRequest, opts ...grpc.CallOption) (*ListAPITokensResponse, error) {
+	out := new(ListAPITokensResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/ListAPITokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ListNetworkUsers(ctx context.Context, in *NetworkUsersRequest, opts ...grpc.CallOption) (*NetworkUsersResponse, error) {
+	out := new(NetworkUsersResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/ListNetworkUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*NetworkUsersResponse, error) {
+	out := new(NetworkUsersResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/SetUserRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) AddDenylistEntries(ctx context.Context, in *AddDenylistEntriesRequest, opts ...grpc.CallOption) (*UpdateDenylistResponse, error) {
+	out := new(UpdateDenylistResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/AddDenylistEntries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RemoveDenylistEntries(ctx context.Context, in *RemoveDenylistEntriesRequest, opts ...grpc.CallOption) (*UpdateDenylistResponse, error) {
+	out := new(UpdateDenylistResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/RemoveDenylistEntries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ListDenylist(ctx context.Context, in *ListDenylistRequest, opts ...grpc.CallOption) (*ListDenylistResponse, error) {
+	out := new(ListDenylistResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/ListDenylist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) AddDomain(ctx context.Context, in *AddDomainRequest, opts ...grpc.CallOption) (*Domain, error) {
+	out := new(Domain)
+	err := c.cc.Invoke(ctx, "/rpc.Control/AddDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*Domain, error) {
+	out := new(Domain)
+	err := c.cc.Invoke(ctx, "/rpc.Control/VerifyDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RemoveDomain(ctx context.Context, in *RemoveDomainRequest, opts ...grpc.CallOption) (*RemoveDomainResponse, error) {
+	out := new(RemoveDomainResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/RemoveDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error) {
+	out := new(ListDomainsResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/ListDomains", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) WatchRegistry(ctx context.Context, in *WatchRegistryRequest, opts ...grpc.CallOption) (Control_WatchRegistryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[0], "/rpc.Control/WatchRegistry", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlWatchRegistryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_WatchRegistryClient interface {
+	Recv() (*RegistryEvent, error)
+	grpc.ClientStream
+}
+
+type controlWatchRegistryClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlWatchRegistryClient) Recv() (*RegistryEvent, error) {
+	m := new(RegistryEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) GetUsage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error) {
+	out := new(UsageResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Control/GetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ControlServer is the server API for Control service.
+type ControlServer interface {
+	ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error)
+	GetNetworkSettings(context.Context, *NetworkSettingsRequest) (*NetworkSettingsResponse, error)
+	UpdateNetworkSettings(context.Context, *UpdateNetworkSettingsRequest) (*NetworkSettingsResponse, error)
+	BulkNetworkAction(context.Context, *BulkNetworkActionRequest) (*BulkNetworkActionResponse, error)
+	CreateAPIToken(context.Context, *CreateAPITokenRequest) (*CreateAPITokenResponse, error)
+	RevokeAPIToken(context.Context, *RevokeAPITokenRequest) (*RevokeAPITokenResponse, error)
+	ListAPITokens(context.Context, *ListAPITokensRequest) (*ListAPITokensResponse, error)
+	ListNetworkUsers(context.Context, *NetworkUsersRequest) (*NetworkUsersResponse, error)
+	SetUserRole(context.Context, *SetUserRoleRequest) (*NetworkUsersResponse, error)
+	AddDenylistEntries(context.Context, *AddDenylistEntriesRequest) (*UpdateDenylistResponse, error)
+	RemoveDenylistEntries(context.Context, *RemoveDenylistEntriesRequest) (*UpdateDenylistResponse, error)
+	ListDenylist(context.Context, *ListDenylistRequest) (*ListDenylistResponse, error)
+	AddDomain(context.Context, *AddDomainRequest) (*Domain, error)
+	VerifyDomain(context.Context, *VerifyDomainRequest) (*Domain, error)
+	RemoveDomain(context.Context, *RemoveDomainRequest) (*RemoveDomainResponse, error)
+	ListDomains(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error)
+	WatchRegistry(*WatchRegistryRequest, Control_WatchRegistryServer) error
+	GetUsage(context.Context, *UsageRequest) (*UsageResponse, error)
+}
+
+func RegisterControlServer(s *grpc.Server, srv ControlServer) {
+	s.RegisterService(&_Control_serviceDesc, srv)
+}
+
+func _Control_ListNetworks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNetworksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ListNetworks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/ListNetworks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ListNetworks(ctx, req.(*ListNetworksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_GetNetworkSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetNetworkSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/GetNetworkSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetNetworkSettings(ctx, req.(*NetworkSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_UpdateNetworkSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNetworkSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).UpdateNetworkSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/UpdateNetworkSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).UpdateNetworkSettings(ctx, req.(*UpdateNetworkSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_BulkNetworkAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkNetworkActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).BulkNetworkAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/BulkNetworkAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).BulkNetworkAction(ctx, req.(*BulkNetworkActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_CreateAPIToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPITokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).CreateAPIToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/CreateAPIToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).CreateAPIToken(ctx, req.(*CreateAPITokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_RevokeAPIToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPITokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).RevokeAPIToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/RevokeAPIToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RevokeAPIToken(ctx, req.(*RevokeAPITokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_ListAPITokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPITokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ListAPITokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/ListAPITokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ListAPITokens(ctx, req.(*ListAPITokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_ListNetworkUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ListNetworkUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/ListNetworkUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ListNetworkUsers(ctx, req.(*NetworkUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SetUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/SetUserRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetUserRole(ctx, req.(*SetUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_AddDenylistEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDenylistEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).AddDenylistEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/AddDenylistEntries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).AddDenylistEntries(ctx, req.(*AddDenylistEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_RemoveDenylistEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDenylistEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).RemoveDenylistEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/RemoveDenylistEntries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RemoveDenylistEntries(ctx, req.(*RemoveDenylistEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_ListDenylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDenylistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ListDenylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/ListDenylist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ListDenylist(ctx, req.(*ListDenylistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_AddDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).AddDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/AddDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).AddDomain(ctx, req.(*AddDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_VerifyDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).VerifyDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/VerifyDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).VerifyDomain(ctx, req.(*VerifyDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_RemoveDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).RemoveDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/RemoveDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RemoveDomain(ctx, req.(*RemoveDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_ListDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).ListDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/ListDomains",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).ListDomains(ctx, req.(*ListDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_WatchRegistry_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRegistryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).WatchRegistry(m, &controlWatchRegistryServer{stream})
+}
+
+type Control_WatchRegistryServer interface {
+	Send(*RegistryEvent) error
+	grpc.ServerStream
+}
+
+type controlWatchRegistryServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlWatchRegistryServer) Send(m *RegistryEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Control/GetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).GetUsage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Control_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "rpc.Control",
+	HandlerType: (*ControlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListNetworks",
+			Handler:    _Control_ListNetworks_Handler,
+		},
+		{
+			MethodName: "GetNetworkSettings",
+			Handler:    _Control_GetNetworkSettings_Handler,
+		},
+		{
+			MethodName: "UpdateNetworkSettings",
+			Handler:    _Control_UpdateNetworkSettings_Handler,
+		},
+		{
+			MethodName: "BulkNetworkAction",
+			Handler:    _Control_BulkNetworkAction_Handler,
+		},
+		{
+			MethodName: "CreateAPIToken",
+			Handler:    _Control_CreateAPIToken_Handler,
+		},
+		{
+			MethodName: "RevokeAPIToken",
+			Handler:    _Control_RevokeAPIToken_Handler,
+		},
+		{
+			MethodName: "ListAPITokens",
+			Handler:    _Control_ListAPITokens_Handler,
+		},
+		{
+			MethodName: "ListNetworkUsers",
+			Handler:    _Control_ListNetworkUsers_Handler,
+		},
+		{
+			MethodName: "SetUserRole",
+			Handler:    _Control_SetUserRole_Handler,
+		},
+		{
+			MethodName: "AddDenylistEntries",
+			Handler:    _Control_AddDenylistEntries_Handler,
+		},
+		{
+			MethodName: "RemoveDenylistEntries",
+			Handler:    _Control_RemoveDenylistEntries_Handler,
+		},
+		{
+			MethodName: "ListDenylist",
+			Handler:    _Control_ListDenylist_Handler,
+		},
+		{
+			MethodName: "AddDomain",
+			Handler:    _Control_AddDomain_Handler,
+		},
+		{
+			MethodName: "VerifyDomain",
+			Handler:    _Control_VerifyDomain_Handler,
+		},
+		{
+			MethodName: "RemoveDomain",
+			Handler:    _Control_RemoveDomain_Handler,
+		},
+		{
+			MethodName: "ListDomains",
+			Handler:    _Control_ListDomains_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _Control_GetUsage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRegistry",
+			Handler:       _Control_WatchRegistry_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/service.proto",
+}
+
+func init() { proto.RegisterFile("rpc/service.proto", fileDescriptor_service_ad52ebe42292d281) }
+
+var fileDescriptor_service_ad52ebe42292d281 = []byte{
+	// 1787 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xeb, 0x6e, 0xdb, 0xc8,
+	0x15, 0xb6, 0x44, 0x5d, 0x8f, 0xec, 0xac, 0x32, 0xbe, 0x84, 0xa6, 0x93, 0x6e, 0x32, 0x45, 0xbb,
+	0xd9, 0xb6, 0xf0, 0x6e, 0xbd, 0xdd, 0xde, 0x51, 0x40, 0xb1, 0x04, 0x47, 0xd8, 0xac, 0xec, 0xd2,
+	0x72, 0x82, 0x5d, 0xa0, 0x10, 0x68, 0x6a, 0x22, 0x13, 0x96, 0x48, 0x96, 0x1c, 0x3a, 0x11, 0xd0,
+	0x7f, 0x05, 0xfa, 0x12, 0x6d, 0x7f, 0xf4, 0x0d, 0xfa, 0x18, 0x7d, 0x99, 0xbe, 0x43, 0x31, 0x57,
+	0x0f, 0x29, 0xda, 0x51, 0x17, 0xfb, 0x6f, 0xce, 0x65, 0xce, 0x9c, 0xf9, 0xe6, 0xcc, 0xe1, 0x37,
+	0x84, 0x87, 0x49, 0xec, 0x7f, 0x96, 0x92, 0xe4, 0x26, 0xf0, 0xc9, 0x61, 0x9c, 0x44, 0x34, 0x42,
+	0x56, 0x12, 0xfb, 0xf8, 0x9f, 0x55, 0xd8, 0x7e, 0x15, 0xa4, 0x74, 0x44, 0xe8, 0xbb, 0x28, 0xb9,
+	0x4e, 0x5d, 0xf2, 0xe7, 0x8c, 0xa4, 0x14, 0xd9, 0xd0, 0x8c, 0x3d, 0x4a, 0x49, 0x12, 0xda, 0x95,
+	0xa7, 0x95, 0xe7, 0x6d, 0x57, 0x89, 0x68, 0x07, 0xea, 0x29, 0xf5, 0x28, 0xb1, 0xab, 0x5c, 0x2f,
+	0x04, 0xf4, 0x04, 0x60, 0x11, 0x84, 0x93, 0x2c, 0xa6, 0xc1, 0x82, 0xd8, 0x16, 0x37, 0xb5, 0x17,
+	0x41, 0x78, 0xc1, 0x15, 0xdc, 0xec, 0xbd, 0x57, 0xe6, 0x9a, 0x34, 0x7b, 0xef, 0xa5, 0x19, 0x41,
+	0x6d, 0x1a, 0xa4, 0xd7, 0x76, 0x9d, 0x1b, 0xf8, 0x18, 0xed, 0x41, 0x63, 0x41, 0x16, 0x51, 0xb2,
+	0xb4, 0x1b, 0x5c, 0x2b, 0x25, 0xe6, 0xeb, 0xc7, 0x59, 0x6a, 0x37, 0x85, 0x2f, 0x1b, 0x33, 0xdf,
+	0xb9, 0x77, 0x49, 0xe6, 0xa9, 0xdd, 0x12, 0xbe, 0x42, 0x62, 0xbe, 0x69, 0x94, 0x50, 0xbb, 0x2d,
+	0x7c, 0xd9, 0x98, 0xf9, 0xfa, 0x59, 0x92, 0x46, 0x89, 0x0d, 0xc2, 0x57, 0x48, 0x6c, 0x5f, 0xf3,
+	0x60, 0x11, 0x50, 0xbb, 0xf3, 0xb4, 0xf2, 0xbc, 0xee, 0x0a, 0x01, 0xff, 0xb7, 0x0a, 0x1d, 0x89,
+	0xcd, 0x30, 0x7c, 0x1b, 0x31, 0x5c, 0x42, 0x21, 0x2a, 0x5c, 0xa4, 0x78, 0x07, 0x2e, 0x7b, 0xd0,
+	0x30, 0x30, 0xb1, 0xdc, 0x46, 0xa6, 0x01, 0x49, 0xdf, 0x79, 0xc9, 0x62, 0x12, 0xb3, 0xfc, 0x24,
+	0x20, 0x5c, 0x73, 0xc6, 0x92, 0xdc, 0x87, 0x96, 0x17, 0x07, 0xc2, 0x28, 0x40, 0x69, 0x7a, 0x71,
+	0xc0, 0x4d, 0xcf, 0x60, 0x73, 0xe6, 0x51, 0xf2, 0xce, 0x5b, 0x0a, 0xb3, 0x40, 0xa7, 0x23, 0x75,
+	0xdc, 0xe5, 0x11, 0x34, 0x19, 0x84, 0x93, 0xd9, 0x25, 0x47, 0xa9, 0xee, 0x36, 0x98, 0x78, 0x72,
+	0x89, 0x0e, 0xa0, 0x2d, 0x50, 0x64, 0xa6, 0x16, 0x37, 0xb5, 0x84, 0xe2, 0xe4, 0x52, 0x03, 0xdb,
+	0xe6, 0x7a, 0x3e, 0x46, 0xbf, 0xd0, 0xc0, 0xc2, 0x53, 0xeb, 0x79, 0xe7, 0xe8, 0xf1, 0x61, 0x12,
+	0xfb, 0x87, 0x06, 0x20, 0x87, 0xaf, 0xb8, 0x79, 0x10, 0xd2, 0x64, 0xa9, 0x60, 0x77, 0x7e, 0x03,
+	0x1d, 0x43, 0x8d, 0xba, 0x60, 0x5d, 0x93, 0xa5, 0xc4, 0x8b, 0x0d, 0x19, 0x56, 0x37, 0xde, 0x3c,
+	0xd3, 0x58, 0x71, 0xe1, 0xb7, 0xd5, 0x5f, 0x57, 0x30, 0x81, 0x9d, 0x7c, 0x39, 0xa6, 0x71, 0x14,
+	0xa6, 0x04, 0xfd, 0x0c, 0x5a, 0x12, 0xe8, 0xd4, 0xae, 0xf0, 0x54, 0xba, 0xc5, 0x54, 0x5c, 0xed,
+	0x81, 0x3e, 0x86, 0x4e, 0x48, 0xde, 0xd3, 0x89, 0x3c, 0x68, 0xb1, 0x0a, 0x30, 0xd5, 0x31, 0xd7,
+	0xe0, 0x23, 0xd8, 0x93, 0x33, 0xcf, 0x09, 0xa5, 0x41, 0x38, 0x33, 0x0b, 0xbf, 0xfc, 0x80, 0xf1,
+	0x18, 0x1e, 0x5f, 0xc4, 0x53, 0x8f, 0x92, 0xff, 0x77, 0x26, 0x72, 0xa0, 0x95, 0x4a, 0x67, 0x99,
+	0x8b, 0x96, 0xf1, 0x29, 0x3c, 0x5a, 0x89, 0x27, 0xf7, 0xfc, 0xdd, 0x02, 0x8e, 0xc0, 0x7e, 0x91,
+	0xcd, 0xaf, 0x65, 0xd0, 0x9e, 0x4f, 0x83, 0x28, 0x54, 0x29, 0xf2, 0x79, 0x73, 0xe2, 0xd3, 0x28,
+	0x91, 0x21, 0xb5, 0xcc, 0x2a, 0xd5, 0xe3, 0xce, 0x32, 0xa2, 0x94, 0xf0, 0x10, 0x1e, 0x95, 0xc4,
+	0x4b, 0xb3, 0x39, 0xbd, 0xff, 0x32, 0x90, 0x24, 0xd1, 0xd0, 0x0b, 0x01, 0x9f, 0xc3, 0x7e, 0x59,
+	0x28, 0xb1, 0xdb, 0x5f, 0x42, 0x33, 0xe1, 0x61, 0xd5, 0x01, 0x8b, 0x5a, 0xbb, 0x63, 0x6d, 0x57,
+	0x39, 0xe3, 0xff, 0x54, 0xa0, 0xd5, 0x3b, 0x1b, 0x8e, 0xa3, 0x6b, 0x12, 0xb2, 0x7b, 0x43, 0xd9,
+	0x60, 0x12, 0x4c, 0x55, 0x4a, 0x5c, 0x1e, 0x4e, 0xcd, 0x64, 0xab, 0xf9, 0x64, 0x11, 0xd4, 0x42,
+	0x4f, 0x77, 0x2d, 0x3e, 0x66, 0x68, 0xa4, 0x7e, 0x14, 0x93, 0xd4, 0xae, 0x3d, 0xb5, 0x18, 0x1a,
+	0x42, 0x62, 0xf7, 0xd6, 0x4f, 0x88, 0x47, 0xc9, 0x74, 0xe2, 0x89, 0xab, 0x69, 0xb9, 0x6d, 0xa9,
+	0xe9, 0x51, 0x66, 0x26, 0xef, 0xe3, 0x20, 0x21, 0xe9, 0xc4, 0x13, 0x57, 0xd3, 0x72, 0xdb, 0x52,
+	0x23, 0xcc, 0x09, 0xb9, 0x89, 0xae, 0xc5, 0xec, 0xa6, 0x30, 0x4b, 0x4d, 0x8f, 0xe2, 0xbf, 0xc0,
+	0xee, 0x31, 0x0f, 0xa5, 0xf6, 0xf3, 0xe1, 0xd2, 0x52, 0xb9, 0x57, 0x4b, 0x73, 0xb7, 0x8a, 0xb9,
+	0xab, 0xe4, 0x82, 0x50, 0xf5, 0x1c, 0xa9, 0x19, 0x86, 0xf8, 0x02, 0xf6, 0x8a, 0xab, 0xcb, 0xa3,
+	0xf9, 0x21, 0xd4, 0x39, 0x8a, 0x7c, 0xf1, 0xce, 0xd1, 0x16, 0x3f, 0x18, 0xed, 0x25, 0x6c, 0x7c,
+	0x55, 0xe2, 0x27, 0x84, 0xaa, 0xfa, 0x11, 0x12, 0x7e, 0x05, 0xbb, 0x2e, 0xdf, 0xe1, 0xfa, 0x9b,
+	0x32, 0x4f, 0xb1, 0x9a, 0x3b, 0x45, 0x6c, 0xc3, 0x5e, 0x31, 0x9a, 0x48, 0x12, 0x7f, 0x2e, 0x3a,
+	0x87, 0xd2, 0xaf, 0x71, 0xa1, 0xff, 0x00, 0xbb, 0x85, 0x19, 0x72, 0xbf, 0x3f, 0x82, 0x06, 0x5f,
+	0x4f, 0x55, 0x62, 0x61, 0xc3, 0xd2, 0x88, 0xbf, 0xd4, 0x9f, 0x86, 0x8b, 0x94, 0x24, 0xec, 0x28,
+	0xb2, 0x94, 0xa8, 0x8b, 0x55, 0xcb, 0xa4, 0x2e, 0x89, 0xe6, 0xfa, 0x78, 0xd8, 0x18, 0x7f, 0x06,
+	0xdb, 0xc6, 0xb4, 0xb5, 0xf2, 0xdc, 0xc9, 0x4f, 0x90, 0x69, 0xfe, 0x18, 0xea, 0x6c, 0x91, 0xd2,
+	0x86, 0xc8, 0x3c, 0x5d, 0x61, 0xc6, 0xaf, 0x01, 0x9d, 0x13, 0xca, 0x35, 0xd1, 0x9c, 0xac, 0x55,
+	0x53, 0x7c, 0x23, 0xd5, 0x92, 0x8d, 0x58, 0xc6, 0x46, 0xbe, 0x85, 0xad, 0x3e, 0x09, 0x97, 0xf3,
+	0x20, 0xa5, 0xa2, 0xd1, 0x33, 0xa7, 0x6c, 0x4e, 0x14, 0x02, 0x6c, 0xcc, 0xca, 0x22, 0x21, 0x5e,
+	0x7a, 0xdb, 0x56, 0x84, 0x54, 0xb8, 0x48, 0x56, 0xe1, 0x22, 0xe1, 0x19, 0xec, 0xf7, 0xa6, 0x53,
+	0x33, 0x7c, 0x40, 0xd6, 0xe8, 0xb4, 0x36, 0x34, 0x89, 0xf0, 0xb5, 0xab, 0xbc, 0xf6, 0x95, 0x68,
+	0xe4, 0x61, 0x99, 0x79, 0x60, 0x17, 0x1e, 0xbb, 0x64, 0x11, 0xdd, 0x90, 0xef, 0x6f, 0x2d, 0x7c,
+	0x08, 0x7b, 0xe2, 0x4b, 0xa1, 0x62, 0xea, 0x23, 0xdb, 0x81, 0xba, 0x1f, 0x65, 0x21, 0xe5, 0xb1,
+	0x2c, 0x57, 0x08, 0xac, 0x22, 0x58, 0x21, 0xde, 0x7a, 0x7f, 0xa8, 0x22, 0xfa, 0xb0, 0x93, 0x9f,
+	0xa0, 0xbf, 0x92, 0x3a, 0x25, 0x51, 0x13, 0x88, 0xd7, 0x44, 0xee, 0x94, 0x6e, 0xd3, 0xfc, 0x6b,
+	0x15, 0x1a, 0xfd, 0x68, 0xe1, 0x05, 0x21, 0x3b, 0xb9, 0xab, 0x28, 0xa5, 0xea, 0xe4, 0xae, 0xa2,
+	0xfc, 0xf2, 0x85, 0x86, 0x79, 0x00, 0xed, 0x24, 0x8a, 0xe8, 0x24, 0xf6, 0xe8, 0x95, 0x84, 0xb3,
+	0xc5, 0x14, 0x67, 0x1e, 0xbd, 0x62, 0xdf, 0x98, 0x1b, 0x92, 0x04, 0x6f, 0x03, 0x32, 0xe5, 0x3d,
+	0xa6, 0xe5, 0x6a, 0x19, 0x7d, 0x0a, 0x5d, 0xff, 0xca, 0x9b, 0xcf, 0x49, 0x38, 0x23, 0x93, 0x84,
+	0xf8, 0x51, 0x32, 0x95, 0xf4, 0xe6, 0x23, 0xad, 0x77, 0xb9, 0x1a, 0x7d, 0x02, 0xb7, 0xaa, 0x89,
+	0xe8, 0x3e, 0x82, 0xe9, 0x3c, 0xd0, 0x6a, 0xd1, 0xf2, 0xf3, 0x85, 0xd4, 0x2c, 0x76, 0xe4, 0x8f,
+	0xa1, 0xa3, 0x96, 0x67, 0xf6, 0x16, 0xb7, 0x83, 0x52, 0xf5, 0x28, 0xfe, 0x13, 0x74, 0x59, 0xa5,
+	0x71, 0x1c, 0xd6, 0xba, 0x1b, 0x1c, 0xa8, 0xaa, 0x01, 0xd4, 0x7d, 0x70, 0xe0, 0x4f, 0x61, 0xfb,
+	0x35, 0x5b, 0x6c, 0x99, 0x5f, 0xa1, 0x04, 0x70, 0x7c, 0x0c, 0xdb, 0xb2, 0x14, 0xbf, 0x7b, 0x32,
+	0x78, 0x0f, 0x76, 0xf2, 0x41, 0x64, 0x7b, 0x3c, 0x04, 0xc4, 0x4b, 0x86, 0x6b, 0xd7, 0x68, 0x3a,
+	0xbf, 0x87, 0xed, 0x9c, 0xbf, 0x6e, 0x8d, 0xcd, 0xa9, 0x50, 0xc9, 0x0a, 0xeb, 0x88, 0x0a, 0x13,
+	0x8b, 0x29, 0x1b, 0xcb, 0xe2, 0x8d, 0x47, 0xfd, 0x2b, 0x97, 0xcc, 0x82, 0x94, 0x15, 0x9d, 0x58,
+	0x0f, 0xff, 0xbd, 0x0a, 0x9b, 0x4a, 0x37, 0x8a, 0xa6, 0xf7, 0x71, 0x9c, 0x3c, 0x43, 0xae, 0x16,
+	0x19, 0x32, 0x86, 0x2d, 0x61, 0x7e, 0x97, 0x0a, 0x0f, 0x01, 0x7c, 0x87, 0x2b, 0xdf, 0xa4, 0x2b,
+	0x2c, 0xba, 0x76, 0x3f, 0x8b, 0xae, 0xaf, 0xb2, 0xe8, 0x2f, 0x35, 0xf7, 0x6d, 0xf0, 0x9d, 0x3e,
+	0xe1, 0x3b, 0x35, 0xb3, 0xff, 0xbe, 0xc9, 0xef, 0x3f, 0xaa, 0xb0, 0xa5, 0xe2, 0x0f, 0x6e, 0x48,
+	0x48, 0xd1, 0x4f, 0xa1, 0x46, 0x97, 0xb1, 0xe8, 0xa8, 0x0f, 0x8e, 0x1e, 0xe5, 0x32, 0xe0, 0x1e,
+	0x87, 0xe3, 0x65, 0x4c, 0x5c, 0xee, 0x84, 0x3e, 0x81, 0x7a, 0x18, 0x4d, 0x65, 0x3b, 0xea, 0x1c,
+	0x3d, 0x5c, 0xc9, 0xd7, 0x15, 0x76, 0x13, 0x74, 0xab, 0xbc, 0xa2, 0x6a, 0x46, 0x45, 0xfd, 0xad,
+	0x02, 0x35, 0xb6, 0x0a, 0xda, 0x84, 0xd6, 0xf9, 0xa8, 0x77, 0x76, 0xfe, 0xf2, 0x74, 0xdc, 0xdd,
+	0x40, 0x5d, 0xd8, 0x1c, 0x9d, 0xf6, 0x07, 0x93, 0x8b, 0xb3, 0x7e, 0x6f, 0x3c, 0xe8, 0x77, 0x2b,
+	0x5a, 0xe3, 0x0e, 0xbe, 0x3e, 0x7d, 0x3d, 0xe8, 0x77, 0xab, 0x68, 0x1b, 0x3e, 0x1a, 0x0d, 0xc6,
+	0x6f, 0x4e, 0xdd, 0xaf, 0x26, 0xc7, 0x2f, 0x7b, 0xa3, 0x93, 0x41, 0xbf, 0x6b, 0xa1, 0x1d, 0xe8,
+	0xf6, 0x07, 0xa3, 0x6f, 0x5e, 0x0d, 0xcf, 0xc7, 0x5a, 0x5b, 0x43, 0x08, 0x1e, 0xf4, 0x4f, 0xbf,
+	0xee, 0x0d, 0x47, 0x5a, 0x57, 0x47, 0x5b, 0xd0, 0x7e, 0x39, 0xe8, 0xb9, 0xe3, 0x17, 0x83, 0xde,
+	0xb8, 0xdb, 0xc0, 0x21, 0x6c, 0x5e, 0xa4, 0xde, 0x8c, 0xac, 0xd5, 0x9a, 0xdf, 0x12, 0x8f, 0x66,
+	0x89, 0x02, 0x59, 0x89, 0xfc, 0x95, 0x16, 0x84, 0xbe, 0x7a, 0x8e, 0x09, 0x81, 0x69, 0xb3, 0x90,
+	0x06, 0x73, 0xbe, 0x6f, 0xcb, 0x15, 0x02, 0xfe, 0x57, 0x05, 0x3a, 0x72, 0x41, 0xde, 0x92, 0x38,
+	0x38, 0x59, 0x22, 0x7b, 0x37, 0x1f, 0xdf, 0xd3, 0x24, 0x8d, 0x1c, 0xac, 0x7c, 0x0e, 0xea, 0xfb,
+	0x5a, 0x33, 0xbe, 0xaf, 0xfb, 0xd0, 0xba, 0x5c, 0x52, 0xc1, 0xcc, 0x04, 0xab, 0x6c, 0x72, 0x79,
+	0x18, 0xb2, 0xf6, 0x22, 0x4c, 0x51, 0xa6, 0x28, 0xa5, 0xf0, 0x3d, 0xcd, 0x28, 0xfe, 0x1d, 0x6c,
+	0xc9, 0x14, 0xe5, 0x05, 0xfd, 0x09, 0xa3, 0xd1, 0x2c, 0xdd, 0x3c, 0x2d, 0x30, 0xf6, 0xe1, 0x2a,
+	0x87, 0xa3, 0x7f, 0x03, 0x34, 0x8f, 0xa3, 0x90, 0x26, 0xd1, 0x1c, 0x0d, 0x60, 0xd3, 0x7c, 0x78,
+	0x21, 0x9b, 0x4f, 0x2b, 0xf9, 0x35, 0xe0, 0xec, 0x97, 0x58, 0x64, 0x93, 0xd9, 0x40, 0x7f, 0x04,
+	0x74, 0x42, 0x68, 0xe1, 0x45, 0x83, 0x0e, 0x4c, 0x6a, 0x52, 0x78, 0x37, 0x39, 0x8f, 0xcb, 0x8d,
+	0x3a, 0xe4, 0xb7, 0xb0, 0x5b, 0xfa, 0xee, 0x42, 0xcf, 0xc4, 0xce, 0xee, 0x79, 0x93, 0x7d, 0x30,
+	0xf6, 0x18, 0x1e, 0xae, 0x3c, 0x30, 0xd0, 0x93, 0xbb, 0x1e, 0x1e, 0x22, 0xe6, 0x0f, 0xee, 0x32,
+	0xeb, 0xa8, 0x5f, 0xc1, 0x83, 0x3c, 0x93, 0x46, 0x0e, 0x9f, 0x53, 0x4a, 0xee, 0x9d, 0x83, 0x52,
+	0x9b, 0x19, 0x2c, 0xcf, 0x78, 0x65, 0xb0, 0x52, 0x52, 0xed, 0x1c, 0x94, 0xda, 0x74, 0xb0, 0x97,
+	0xb0, 0x95, 0xa3, 0xbc, 0xe8, 0xf6, 0x30, 0x8b, 0xc4, 0xd9, 0x71, 0xca, 0x4c, 0x3a, 0xd2, 0x10,
+	0xba, 0x46, 0x09, 0x70, 0x62, 0x2a, 0x6b, 0xa6, 0x84, 0xdc, 0x3a, 0xfb, 0x25, 0x16, 0x1d, 0xea,
+	0x18, 0x3a, 0x06, 0x3f, 0x45, 0xa2, 0xcb, 0xad, 0x32, 0xd6, 0xfb, 0x83, 0x9c, 0x03, 0x5a, 0x25,
+	0x8c, 0x48, 0x9c, 0xd5, 0x9d, 0x4c, 0xd2, 0x39, 0x30, 0x4a, 0xa8, 0xc8, 0xa6, 0xf0, 0x06, 0xfa,
+	0x06, 0x76, 0xe5, 0xc7, 0xb4, 0x10, 0xf7, 0x99, 0x84, 0xf9, 0x6e, 0xe2, 0xf8, 0xa1, 0xd0, 0xf2,
+	0xbe, 0x29, 0x8b, 0x71, 0xdf, 0x0a, 0x34, 0xd0, 0xd9, 0x2f, 0xb1, 0xe8, 0x30, 0x3f, 0x87, 0xb6,
+	0x66, 0x2f, 0x68, 0x57, 0xef, 0xd6, 0x24, 0x10, 0x8e, 0xf9, 0x89, 0xc6, 0x1b, 0xe8, 0x57, 0xb0,
+	0x69, 0x32, 0x12, 0xb9, 0x72, 0x09, 0x49, 0x29, 0x4e, 0x1c, 0xc0, 0xa6, 0xdc, 0xb1, 0x39, 0xb1,
+	0x84, 0xb2, 0x38, 0xfb, 0x25, 0x16, 0x9d, 0xf2, 0x0b, 0xe8, 0x18, 0xcc, 0x42, 0x1e, 0xf7, 0x2a,
+	0x37, 0x71, 0xec, 0x55, 0x83, 0x11, 0x63, 0x2b, 0xc7, 0x2f, 0x64, 0x1d, 0x97, 0x71, 0x0e, 0x07,
+	0xad, 0x7e, 0x35, 0xf1, 0xc6, 0xe7, 0x15, 0xf4, 0x05, 0xb4, 0x4e, 0x58, 0x91, 0x79, 0x33, 0x82,
+	0x1e, 0x9a, 0x4d, 0xd2, 0x9c, 0x96, 0x6b, 0xae, 0x78, 0xe3, 0xb2, 0xc1, 0xff, 0x9d, 0x7e, 0xf1,
+	0xbf, 0x01, 0x00, 0x38, 0x14, 0x42, 0xb8, 0x50, 0x15, 0x00, 0x00,
+}
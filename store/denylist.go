@@ -0,0 +1,174 @@
+package store
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/gorm"
+	cid "github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Denylist provides access to blocked content. Entries that are not associated
+// with a network apply to all networks.
+type Denylist interface {
+	AddDenylistEntries(entries []*DenylistEntry) error
+	RemoveDenylistEntries(network string, rules []string) (int64, error)
+	ListDenylistEntries(network string) ([]*DenylistEntry, error)
+	AllDenylistEntries() ([]*DenylistEntry, error)
+}
+
+// DenylistEntry blocks access to content. Rules are in the compact denylist
+// format, and are one of:
+//
+//	/ipfs/<cid>[/<path>]  blocks a CID, or a path within it
+//	/ipns/<name>[/<path>] blocks an IPNS name, or a path within it
+//	//<hash>              blocks content by double hash, which does not reveal
+//	                      the blocked CID or name
+//
+// Double hashes are either the hex-encoded sha256 of "<cidv1 base32>/<path>",
+// as used by the legacy badbits list, or the base58-encoded sha2-256 multihash
+// of "<base58 multihash>[/<path>]" for CIDs and "<name>[/<path>]" for IPNS
+// names.
+type DenylistEntry struct {
+	ID        uint      `gorm:"primary_key" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Network string `gorm:"type:varchar(255);unique_index:idx_denylist_network_rule" json:"network,omitempty"`
+	Rule    string `gorm:"type:varchar(255);unique_index:idx_denylist_network_rule" json:"rule"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// ParseDenylist reads rules from a denylist in the compact denylist format.
+// Comments, blank lines, and the optional header are ignored.
+func ParseDenylist(list string) ([]string, error) {
+	var lines = strings.Split(strings.Replace(list, "\r\n", "\n", -1), "\n")
+
+	// skip header, which is terminated by a '---' line
+	var start int
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "---" {
+			start = i + 1
+			break
+		}
+	}
+
+	var rules = make([]string, 0, len(lines)-start)
+	for i := start; i < len(lines); i++ {
+		var line = strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := ParseDenylistRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule on line %d: %s", i+1, err.Error())
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseDenylistRule validates and normalizes a single denylist rule. CIDs are
+// converted to CIDv1, so that a rule blocks content regardless of the CID
+// version used to request it. Bare CIDs are treated as /ipfs/ paths.
+func ParseDenylistRule(rule string) (string, error) {
+	switch {
+	case strings.HasPrefix(rule, "!"):
+		return "", errors.New("allow rules are not supported")
+	case strings.HasPrefix(rule, "//"):
+		var hash = strings.TrimPrefix(rule, "//")
+		if b, err := hex.DecodeString(hash); err == nil && len(b) == 32 {
+			return "//" + strings.ToLower(hash), nil
+		}
+		if m, err := mh.FromB58String(hash); err == nil {
+			if decoded, err := mh.Decode(m); err == nil && decoded.Code == mh.SHA2_256 {
+				return "//" + hash, nil
+			}
+		}
+		return "", fmt.Errorf("'%s' is not a sha256 hash", hash)
+	case strings.HasPrefix(rule, "/ipns/"):
+		var name, p = splitContentPath(strings.TrimPrefix(rule, "/ipns/"))
+		if name == "" {
+			return "", errors.New("no IPNS name provided")
+		}
+		return "/ipns/" + name + p, nil
+	default:
+		var c, p = splitContentPath(strings.TrimPrefix(rule, "/ipfs/"))
+		id, err := cid.Decode(c)
+		if err != nil {
+			return "", fmt.Errorf("invalid CID '%s': %s", c, err.Error())
+		}
+		return "/ipfs/" + cid.NewCidV1(id.Type(), id.Hash()).String() + p, nil
+	}
+}
+
+// splitContentPath separates the root of a content path from the cleaned
+// remainder of the path
+func splitContentPath(p string) (string, string) {
+	var parts = strings.SplitN(p, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	var rest = path.Clean("/" + parts[1])
+	if rest == "/" {
+		rest = ""
+	}
+	return parts[0], rest
+}
+
+// DenylistManager manages denylist entries in the database
+type DenylistManager struct {
+	DB *gorm.DB
+}
+
+// NewDenylistManager instantiates a new DenylistManager
+func NewDenylistManager(db *gorm.DB) *DenylistManager {
+	return &DenylistManager{DB: db}
+}
+
+// AddDenylistEntries stores given entries, skipping rules that are already
+// present
+func (m *DenylistManager) AddDenylistEntries(entries []*DenylistEntry) error {
+	var tx = m.DB.Begin()
+	for _, e := range entries {
+		if e.Rule == "" {
+			tx.Rollback()
+			return errors.New("invalid denylist entry")
+		}
+		if err := tx.Where("network = ? AND rule = ?", e.Network, e.Rule).
+			FirstOrCreate(e).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// RemoveDenylistEntries deletes given rules of given network, and returns the
+// number of entries removed
+func (m *DenylistManager) RemoveDenylistEntries(network string, rules []string) (int64, error) {
+	var q = m.DB.Where("network = ? AND rule IN (?)", network, rules).Delete(&DenylistEntry{})
+	return q.RowsAffected, q.Error
+}
+
+// ListDenylistEntries retrieves the entries of given network
+func (m *DenylistManager) ListDenylistEntries(network string) ([]*DenylistEntry, error) {
+	var entries []*DenylistEntry
+	if err := m.DB.Where("network = ?", network).Order("created_at").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// AllDenylistEntries retrieves the entries of all networks
+func (m *DenylistManager) AllDenylistEntries() ([]*DenylistEntry, error) {
+	var entries []*DenylistEntry
+	if err := m.DB.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDenylistRule(t *testing.T) {
+	const cidv1 = "bafybeiccfclkdtucu6y4yc5cpr6y3yuinr67svmii46v5cfcrkp47ihehy"
+	tests := []struct {
+		name    string
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{"bare CIDv0", "QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D", "/ipfs/" + cidv1, false},
+		{"ipfs path", "/ipfs/QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D/a//b/", "/ipfs/" + cidv1 + "/a/b", false},
+		{"CIDv1", "/ipfs/" + cidv1, "/ipfs/" + cidv1, false},
+		{"ipns path", "/ipns/example.com/./a", "/ipns/example.com/a", false},
+		{"hex double hash", "//4FCF8F4836B2F39D00C53164D930CA89A96602D64E59DE93F1DA550F68FF4A70",
+			"//4fcf8f4836b2f39d00c53164d930ca89a96602d64e59de93f1da550f68ff4a70", false},
+		{"multihash double hash", "//QmVUgA7CedKJVWDgupQwu1ZeppTwUebG1WFDYZ14AFpbUk",
+			"//QmVUgA7CedKJVWDgupQwu1ZeppTwUebG1WFDYZ14AFpbUk", false},
+		{"invalid double hash", "//asdf", "", true},
+		{"invalid CID", "/ipfs/asdf", "", true},
+		{"no IPNS name", "/ipns/", "", true},
+		{"allow rule", "!/ipfs/" + cidv1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDenylistRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDenylistRule() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseDenylistRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDenylist(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", "", []string{}, false},
+		{"rules", "# blocked\n/ipns/example.com\r\n\n//QmVUgA7CedKJVWDgupQwu1ZeppTwUebG1WFDYZ14AFpbUk\n",
+			[]string{"/ipns/example.com", "//QmVUgA7CedKJVWDgupQwu1ZeppTwUebG1WFDYZ14AFpbUk"}, false},
+		{"header", "version: 1\nname: test\n---\n/ipns/example.com", []string{"/ipns/example.com"}, false},
+		{"invalid rule", "/ipns/example.com\n/ipfs/asdf", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDenylist(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDenylist() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDenylist() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
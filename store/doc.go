@@ -0,0 +1,3 @@
+// Package store provides models and managers for Nexus-specific data, which
+// is kept in tables alongside Temporal's in the same database
+package store
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/gorm"
+	cid "github.com/ipfs/go-cid"
+)
+
+// DomainChallengePrefix is prepended to a custom domain's host to form the name
+// of the TXT record that proves control of the domain
+const DomainChallengePrefix = "_nexus-challenge."
+
+// Domains provides access to custom domains, which serve content from a
+// network's gateway
+type Domains interface {
+	AddDomain(d *CustomDomain) error
+	GetDomain(host string) (*CustomDomain, error)
+	ListDomains(network string) ([]*CustomDomain, error)
+	AllDomains() ([]*CustomDomain, error)
+	VerifyDomain(host string) error
+	RemoveDomain(network, host string) error
+}
+
+// CustomDomain maps a host to a network's gateway. Content is served from
+// RootPath if one is set, or from the host's DNSLink record otherwise. Domains
+// are only served once control of the host has been verified, by publishing
+// Token in a TXT record at ChallengeRecord.
+type CustomDomain struct {
+	ID        uint      `gorm:"primary_key" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	Host     string `gorm:"type:varchar(255);unique_index" json:"host"`
+	Network  string `gorm:"type:varchar(255);index" json:"network"`
+	RootPath string `json:"root_path,omitempty"`
+	Token    string `gorm:"type:varchar(64)" json:"token"`
+
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+}
+
+// NewCustomDomain validates and normalizes a domain for given network, and
+// generates its verification token
+func NewCustomDomain(network, host, rootPath string) (*CustomDomain, error) {
+	if network == "" {
+		return nil, errors.New("domain must be associated with a network")
+	}
+	host, err := ParseDomainHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if rootPath != "" {
+		if rootPath, err = ParseRootPath(rootPath); err != nil {
+			return nil, err
+		}
+	}
+	token, err := randomString(24)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomDomain{
+		Host:     host,
+		Network:  network,
+		RootPath: rootPath,
+		Token:    token,
+	}, nil
+}
+
+// ChallengeRecord is the name of the TXT record that must contain the domain's
+// token for the domain to be verified
+func (d *CustomDomain) ChallengeRecord() string {
+	return DomainChallengePrefix + d.Host
+}
+
+// Verified checks if control of the domain has been proven
+func (d *CustomDomain) Verified() bool { return d.VerifiedAt != nil }
+
+// ParseDomainHost validates and normalizes a custom domain's host
+func ParseDomainHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" {
+		return "", errors.New("no host provided")
+	}
+	if len(host) > 253 {
+		return "", fmt.Errorf("host '%s' is too long", host)
+	}
+	var labels = strings.Split(host, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("host '%s' is not a fully qualified domain name", host)
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 ||
+			strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", fmt.Errorf("host '%s' has an invalid label '%s'", host, label)
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+				return "", fmt.Errorf("host '%s' has an invalid label '%s'", host, label)
+			}
+		}
+	}
+	return host, nil
+}
+
+// ParseRootPath validates and normalizes a content path that a custom domain's
+// content is served from, of the form "/ipfs/<cid>[/<path>]" or
+// "/ipns/<name>[/<path>]"
+func ParseRootPath(p string) (string, error) {
+	switch {
+	case strings.HasPrefix(p, "/ipfs/"):
+		var c, rest = splitContentPath(strings.TrimPrefix(p, "/ipfs/"))
+		id, err := cid.Decode(c)
+		if err != nil {
+			return "", fmt.Errorf("invalid CID '%s': %s", c, err.Error())
+		}
+		return "/ipfs/" + id.String() + rest, nil
+	case strings.HasPrefix(p, "/ipns/"):
+		var name, rest = splitContentPath(strings.TrimPrefix(p, "/ipns/"))
+		if name == "" {
+			return "", errors.New("no IPNS name provided")
+		}
+		return "/ipns/" + name + rest, nil
+	default:
+		return "", fmt.Errorf("root path '%s' must be an /ipfs/ or /ipns/ path", p)
+	}
+}
+
+// DomainManager manages custom domains in the database
+type DomainManager struct {
+	DB *gorm.DB
+}
+
+// NewDomainManager instantiates a new DomainManager
+func NewDomainManager(db *gorm.DB) *DomainManager {
+	return &DomainManager{DB: db}
+}
+
+// AddDomain stores a new domain. Unverified claims on the same host by any
+// network are replaced, so that a host cannot be held by an unproven claim.
+func (m *DomainManager) AddDomain(d *CustomDomain) error {
+	if d.Host == "" || d.Network == "" || d.Token == "" {
+		return errors.New("invalid domain")
+	}
+	var tx = m.DB.Begin()
+	if err := tx.Where("host = ? AND verified_at IS NULL", d.Host).
+		Delete(&CustomDomain{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	var existing int
+	if err := tx.Model(&CustomDomain{}).Where("host = ?", d.Host).Count(&existing).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if existing > 0 {
+		tx.Rollback()
+		return fmt.Errorf("domain '%s' is already in use", d.Host)
+	}
+	if err := tx.Create(d).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// GetDomain retrieves the domain with given host. If no such domain exists,
+// nil is returned without an error.
+func (m *DomainManager) GetDomain(host string) (*CustomDomain, error) {
+	var d CustomDomain
+	if err := m.DB.Where("host = ?", host).First(&d).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListDomains retrieves the domains of given network
+func (m *DomainManager) ListDomains(network string) ([]*CustomDomain, error) {
+	var domains []*CustomDomain
+	if err := m.DB.Where("network = ?", network).Order("host").Find(&domains).Error; err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// AllDomains retrieves the verified domains of all networks
+func (m *DomainManager) AllDomains() ([]*CustomDomain, error) {
+	var domains []*CustomDomain
+	if err := m.DB.Where("verified_at IS NOT NULL").Find(&domains).Error; err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// VerifyDomain marks the domain with given host as verified
+func (m *DomainManager) VerifyDomain(host string) error {
+	var q = m.DB.Model(&CustomDomain{}).
+		Where("host = ? AND verified_at IS NULL", host).
+		Update("verified_at", time.Now())
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return fmt.Errorf("no unverified domain '%s' found", host)
+	}
+	return nil
+}
+
+// RemoveDomain deletes the domain with given host from given network
+func (m *DomainManager) RemoveDomain(network, host string) error {
+	var q = m.DB.Where("network = ? AND host = ?", network, host).Delete(&CustomDomain{})
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return fmt.Errorf("no domain '%s' found for network '%s'", host, network)
+	}
+	return nil
+}
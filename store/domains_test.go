@@ -0,0 +1,98 @@
+package store
+
+import "testing"
+
+func TestParseDomainHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{"normalized", " Docs.Example.com. ", "docs.example.com", false},
+		{"hyphenated", "my-docs.example.com", "my-docs.example.com", false},
+		{"empty", "", "", true},
+		{"not qualified", "localhost", "", true},
+		{"empty label", "docs..example.com", "", true},
+		{"leading hyphen", "-docs.example.com", "", true},
+		{"invalid character", "docs_1.example.com", "", true},
+		{"port", "docs.example.com:443", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDomainHost(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDomainHost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseDomainHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRootPath(t *testing.T) {
+	const cidv0 = "QmSnuWmxptJZdLJpKRarxBMS2Ju2oANVrgbr2xWbie9b2D"
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"ipfs path", "/ipfs/" + cidv0 + "/docs//", "/ipfs/" + cidv0 + "/docs", false},
+		{"ipns path", "/ipns/example.com", "/ipns/example.com", false},
+		{"invalid CID", "/ipfs/asdf", "", true},
+		{"no IPNS name", "/ipns/", "", true},
+		{"bare CID", cidv0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRootPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRootPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseRootPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCustomDomain(t *testing.T) {
+	type args struct {
+		network  string
+		host     string
+		rootPath string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"ok", args{"test", "docs.example.com", ""}, false},
+		{"ok with root", args{"test", "docs.example.com", "/ipns/example.com"}, false},
+		{"no network", args{"", "docs.example.com", ""}, true},
+		{"invalid host", args{"test", "localhost", ""}, true},
+		{"invalid root", args{"test", "docs.example.com", "/ipfs/asdf"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewCustomDomain(tt.args.network, tt.args.host, tt.args.rootPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCustomDomain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if d.Token == "" || d.Verified() {
+				t.Errorf("expected unverified domain with token, got %+v", d)
+			}
+			if d.ChallengeRecord() != "_nexus-challenge."+tt.args.host {
+				t.Errorf("unexpected challenge record '%s'", d.ChallengeRecord())
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+type FakeDenylist struct {
+	AddDenylistEntriesStub        func([]*store.DenylistEntry) error
+	addDenylistEntriesMutex       sync.RWMutex
+	addDenylistEntriesArgsForCall []struct {
+		arg1 []*store.DenylistEntry
+	}
+	addDenylistEntriesReturns struct {
+		result1 error
+	}
+	addDenylistEntriesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AllDenylistEntriesStub        func() ([]*store.DenylistEntry, error)
+	allDenylistEntriesMutex       sync.RWMutex
+	allDenylistEntriesArgsForCall []struct {
+	}
+	allDenylistEntriesReturns struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}
+	allDenylistEntriesReturnsOnCall map[int]struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}
+	ListDenylistEntriesStub        func(string) ([]*store.DenylistEntry, error)
+	listDenylistEntriesMutex       sync.RWMutex
+	listDenylistEntriesArgsForCall []struct {
+		arg1 string
+	}
+	listDenylistEntriesReturns struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}
+	listDenylistEntriesReturnsOnCall map[int]struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}
+	RemoveDenylistEntriesStub        func(string, []string) (int64, error)
+	removeDenylistEntriesMutex       sync.RWMutex
+	removeDenylistEntriesArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	removeDenylistEntriesReturns struct {
+		result1 int64
+		result2 error
+	}
+	removeDenylistEntriesReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeDenylist) AddDenylistEntries(arg1 []*store.DenylistEntry) error {
+	var arg1Copy []*store.DenylistEntry
+	if arg1 != nil {
+		arg1Copy = make([]*store.DenylistEntry, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.addDenylistEntriesMutex.Lock()
+	ret, specificReturn := fake.addDenylistEntriesReturnsOnCall[len(fake.addDenylistEntriesArgsForCall)]
+	fake.addDenylistEntriesArgsForCall = append(fake.addDenylistEntriesArgsForCall, struct {
+		arg1 []*store.DenylistEntry
+	}{arg1Copy})
+	fake.recordInvocation("AddDenylistEntries", []interface{}{arg1Copy})
+	fake.addDenylistEntriesMutex.Unlock()
+	if fake.AddDenylistEntriesStub != nil {
+		return fake.AddDenylistEntriesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addDenylistEntriesReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeDenylist) AddDenylistEntriesCallCount() int {
+	fake.addDenylistEntriesMutex.RLock()
+	defer fake.addDenylistEntriesMutex.RUnlock()
+	return len(fake.addDenylistEntriesArgsForCall)
+}
+
+func (fake *FakeDenylist) AddDenylistEntriesCalls(stub func([]*store.DenylistEntry) error) {
+	fake.addDenylistEntriesMutex.Lock()
+	defer fake.addDenylistEntriesMutex.Unlock()
+	fake.AddDenylistEntriesStub = stub
+}
+
+func (fake *FakeDenylist) AddDenylistEntriesArgsForCall(i int) []*store.DenylistEntry {
+	fake.addDenylistEntriesMutex.RLock()
+	defer fake.addDenylistEntriesMutex.RUnlock()
+	argsForCall := fake.addDenylistEntriesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDenylist) AddDenylistEntriesReturns(result1 error) {
+	fake.addDenylistEntriesMutex.Lock()
+	defer fake.addDenylistEntriesMutex.Unlock()
+	fake.AddDenylistEntriesStub = nil
+	fake.addDenylistEntriesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDenylist) AddDenylistEntriesReturnsOnCall(i int, result1 error) {
+	fake.addDenylistEntriesMutex.Lock()
+	defer fake.addDenylistEntriesMutex.Unlock()
+	fake.AddDenylistEntriesStub = nil
+	if fake.addDenylistEntriesReturnsOnCall == nil {
+		fake.addDenylistEntriesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addDenylistEntriesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDenylist) AllDenylistEntries() ([]*store.DenylistEntry, error) {
+	fake.allDenylistEntriesMutex.Lock()
+	ret, specificReturn := fake.allDenylistEntriesReturnsOnCall[len(fake.allDenylistEntriesArgsForCall)]
+	fake.allDenylistEntriesArgsForCall = append(fake.allDenylistEntriesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("AllDenylistEntries", []interface{}{})
+	fake.allDenylistEntriesMutex.Unlock()
+	if fake.AllDenylistEntriesStub != nil {
+		return fake.AllDenylistEntriesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.allDenylistEntriesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDenylist) AllDenylistEntriesCallCount() int {
+	fake.allDenylistEntriesMutex.RLock()
+	defer fake.allDenylistEntriesMutex.RUnlock()
+	return len(fake.allDenylistEntriesArgsForCall)
+}
+
+func (fake *FakeDenylist) AllDenylistEntriesCalls(stub func() ([]*store.DenylistEntry, error)) {
+	fake.allDenylistEntriesMutex.Lock()
+	defer fake.allDenylistEntriesMutex.Unlock()
+	fake.AllDenylistEntriesStub = stub
+}
+
+func (fake *FakeDenylist) AllDenylistEntriesReturns(result1 []*store.DenylistEntry, result2 error) {
+	fake.allDenylistEntriesMutex.Lock()
+	defer fake.allDenylistEntriesMutex.Unlock()
+	fake.AllDenylistEntriesStub = nil
+	fake.allDenylistEntriesReturns = struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDenylist) AllDenylistEntriesReturnsOnCall(i int, result1 []*store.DenylistEntry, result2 error) {
+	fake.allDenylistEntriesMutex.Lock()
+	defer fake.allDenylistEntriesMutex.Unlock()
+	fake.AllDenylistEntriesStub = nil
+	if fake.allDenylistEntriesReturnsOnCall == nil {
+		fake.allDenylistEntriesReturnsOnCall = make(map[int]struct {
+			result1 []*store.DenylistEntry
+			result2 error
+		})
+	}
+	fake.allDenylistEntriesReturnsOnCall[i] = struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDenylist) ListDenylistEntries(arg1 string) ([]*store.DenylistEntry, error) {
+	fake.listDenylistEntriesMutex.Lock()
+	ret, specificReturn := fake.listDenylistEntriesReturnsOnCall[len(fake.listDenylistEntriesArgsForCall)]
+	fake.listDenylistEntriesArgsForCall = append(fake.listDenylistEntriesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListDenylistEntries", []interface{}{arg1})
+	fake.listDenylistEntriesMutex.Unlock()
+	if fake.ListDenylistEntriesStub != nil {
+		return fake.ListDenylistEntriesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDenylistEntriesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDenylist) ListDenylistEntriesCallCount() int {
+	fake.listDenylistEntriesMutex.RLock()
+	defer fake.listDenylistEntriesMutex.RUnlock()
+	return len(fake.listDenylistEntriesArgsForCall)
+}
+
+func (fake *FakeDenylist) ListDenylistEntriesCalls(stub func(string) ([]*store.DenylistEntry, error)) {
+	fake.listDenylistEntriesMutex.Lock()
+	defer fake.listDenylistEntriesMutex.Unlock()
+	fake.ListDenylistEntriesStub = stub
+}
+
+func (fake *FakeDenylist) ListDenylistEntriesArgsForCall(i int) string {
+	fake.listDenylistEntriesMutex.RLock()
+	defer fake.listDenylistEntriesMutex.RUnlock()
+	argsForCall := fake.listDenylistEntriesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDenylist) ListDenylistEntriesReturns(result1 []*store.DenylistEntry, result2 error) {
+	fake.listDenylistEntriesMutex.Lock()
+	defer fake.listDenylistEntriesMutex.Unlock()
+	fake.ListDenylistEntriesStub = nil
+	fake.listDenylistEntriesReturns = struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDenylist) ListDenylistEntriesReturnsOnCall(i int, result1 []*store.DenylistEntry, result2 error) {
+	fake.listDenylistEntriesMutex.Lock()
+	defer fake.listDenylistEntriesMutex.Unlock()
+	fake.ListDenylistEntriesStub = nil
+	if fake.listDenylistEntriesReturnsOnCall == nil {
+		fake.listDenylistEntriesReturnsOnCall = make(map[int]struct {
+			result1 []*store.DenylistEntry
+			result2 error
+		})
+	}
+	fake.listDenylistEntriesReturnsOnCall[i] = struct {
+		result1 []*store.DenylistEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDenylist) RemoveDenylistEntries(arg1 string, arg2 []string) (int64, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.removeDenylistEntriesMutex.Lock()
+	ret, specificReturn := fake.removeDenylistEntriesReturnsOnCall[len(fake.removeDenylistEntriesArgsForCall)]
+	fake.removeDenylistEntriesArgsForCall = append(fake.removeDenylistEntriesArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	fake.recordInvocation("RemoveDenylistEntries", []interface{}{arg1, arg2Copy})
+	fake.removeDenylistEntriesMutex.Unlock()
+	if fake.RemoveDenylistEntriesStub != nil {
+		return fake.RemoveDenylistEntriesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.removeDenylistEntriesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDenylist) RemoveDenylistEntriesCallCount() int {
+	fake.removeDenylistEntriesMutex.RLock()
+	defer fake.removeDenylistEntriesMutex.RUnlock()
+	return len(fake.removeDenylistEntriesArgsForCall)
+}
+
+func (fake *FakeDenylist) RemoveDenylistEntriesCalls(stub func(string, []string) (int64, error)) {
+	fake.removeDenylistEntriesMutex.Lock()
+	defer fake.removeDenylistEntriesMutex.Unlock()
+	fake.RemoveDenylistEntriesStub = stub
+}
+
+func (fake *FakeDenylist) RemoveDenylistEntriesArgsForCall(i int) (string, []string) {
+	fake.removeDenylistEntriesMutex.RLock()
+	defer fake.removeDenylistEntriesMutex.RUnlock()
+	argsForCall := fake.removeDenylistEntriesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDenylist) RemoveDenylistEntriesReturns(result1 int64, result2 error) {
+	fake.removeDenylistEntriesMutex.Lock()
+	defer fake.removeDenylistEntriesMutex.Unlock()
+	fake.RemoveDenylistEntriesStub = nil
+	fake.removeDenylistEntriesReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDenylist) RemoveDenylistEntriesReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.removeDenylistEntriesMutex.Lock()
+	defer fake.removeDenylistEntriesMutex.Unlock()
+	fake.RemoveDenylistEntriesStub = nil
+	if fake.removeDenylistEntriesReturnsOnCall == nil {
+		fake.removeDenylistEntriesReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.removeDenylistEntriesReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDenylist) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addDenylistEntriesMutex.RLock()
+	defer fake.addDenylistEntriesMutex.RUnlock()
+	fake.allDenylistEntriesMutex.RLock()
+	defer fake.allDenylistEntriesMutex.RUnlock()
+	fake.listDenylistEntriesMutex.RLock()
+	defer fake.listDenylistEntriesMutex.RUnlock()
+	fake.removeDenylistEntriesMutex.RLock()
+	defer fake.removeDenylistEntriesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeDenylist) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.Denylist = new(FakeDenylist)
@@ -0,0 +1,483 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+type FakeDomains struct {
+	AddDomainStub        func(*store.CustomDomain) error
+	addDomainMutex       sync.RWMutex
+	addDomainArgsForCall []struct {
+		arg1 *store.CustomDomain
+	}
+	addDomainReturns struct {
+		result1 error
+	}
+	addDomainReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AllDomainsStub        func() ([]*store.CustomDomain, error)
+	allDomainsMutex       sync.RWMutex
+	allDomainsArgsForCall []struct {
+	}
+	allDomainsReturns struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}
+	allDomainsReturnsOnCall map[int]struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}
+	GetDomainStub        func(string) (*store.CustomDomain, error)
+	getDomainMutex       sync.RWMutex
+	getDomainArgsForCall []struct {
+		arg1 string
+	}
+	getDomainReturns struct {
+		result1 *store.CustomDomain
+		result2 error
+	}
+	getDomainReturnsOnCall map[int]struct {
+		result1 *store.CustomDomain
+		result2 error
+	}
+	ListDomainsStub        func(string) ([]*store.CustomDomain, error)
+	listDomainsMutex       sync.RWMutex
+	listDomainsArgsForCall []struct {
+		arg1 string
+	}
+	listDomainsReturns struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}
+	listDomainsReturnsOnCall map[int]struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}
+	RemoveDomainStub        func(string, string) error
+	removeDomainMutex       sync.RWMutex
+	removeDomainArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	removeDomainReturns struct {
+		result1 error
+	}
+	removeDomainReturnsOnCall map[int]struct {
+		result1 error
+	}
+	VerifyDomainStub        func(string) error
+	verifyDomainMutex       sync.RWMutex
+	verifyDomainArgsForCall []struct {
+		arg1 string
+	}
+	verifyDomainReturns struct {
+		result1 error
+	}
+	verifyDomainReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeDomains) AddDomain(arg1 *store.CustomDomain) error {
+	fake.addDomainMutex.Lock()
+	ret, specificReturn := fake.addDomainReturnsOnCall[len(fake.addDomainArgsForCall)]
+	fake.addDomainArgsForCall = append(fake.addDomainArgsForCall, struct {
+		arg1 *store.CustomDomain
+	}{arg1})
+	fake.recordInvocation("AddDomain", []interface{}{arg1})
+	fake.addDomainMutex.Unlock()
+	if fake.AddDomainStub != nil {
+		return fake.AddDomainStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addDomainReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeDomains) AddDomainCallCount() int {
+	fake.addDomainMutex.RLock()
+	defer fake.addDomainMutex.RUnlock()
+	return len(fake.addDomainArgsForCall)
+}
+
+func (fake *FakeDomains) AddDomainCalls(stub func(*store.CustomDomain) error) {
+	fake.addDomainMutex.Lock()
+	defer fake.addDomainMutex.Unlock()
+	fake.AddDomainStub = stub
+}
+
+func (fake *FakeDomains) AddDomainArgsForCall(i int) *store.CustomDomain {
+	fake.addDomainMutex.RLock()
+	defer fake.addDomainMutex.RUnlock()
+	argsForCall := fake.addDomainArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDomains) AddDomainReturns(result1 error) {
+	fake.addDomainMutex.Lock()
+	defer fake.addDomainMutex.Unlock()
+	fake.AddDomainStub = nil
+	fake.addDomainReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDomains) AddDomainReturnsOnCall(i int, result1 error) {
+	fake.addDomainMutex.Lock()
+	defer fake.addDomainMutex.Unlock()
+	fake.AddDomainStub = nil
+	if fake.addDomainReturnsOnCall == nil {
+		fake.addDomainReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addDomainReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDomains) AllDomains() ([]*store.CustomDomain, error) {
+	fake.allDomainsMutex.Lock()
+	ret, specificReturn := fake.allDomainsReturnsOnCall[len(fake.allDomainsArgsForCall)]
+	fake.allDomainsArgsForCall = append(fake.allDomainsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("AllDomains", []interface{}{})
+	fake.allDomainsMutex.Unlock()
+	if fake.AllDomainsStub != nil {
+		return fake.AllDomainsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.allDomainsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDomains) AllDomainsCallCount() int {
+	fake.allDomainsMutex.RLock()
+	defer fake.allDomainsMutex.RUnlock()
+	return len(fake.allDomainsArgsForCall)
+}
+
+func (fake *FakeDomains) AllDomainsCalls(stub func() ([]*store.CustomDomain, error)) {
+	fake.allDomainsMutex.Lock()
+	defer fake.allDomainsMutex.Unlock()
+	fake.AllDomainsStub = stub
+}
+
+func (fake *FakeDomains) AllDomainsReturns(result1 []*store.CustomDomain, result2 error) {
+	fake.allDomainsMutex.Lock()
+	defer fake.allDomainsMutex.Unlock()
+	fake.AllDomainsStub = nil
+	fake.allDomainsReturns = struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDomains) AllDomainsReturnsOnCall(i int, result1 []*store.CustomDomain, result2 error) {
+	fake.allDomainsMutex.Lock()
+	defer fake.allDomainsMutex.Unlock()
+	fake.AllDomainsStub = nil
+	if fake.allDomainsReturnsOnCall == nil {
+		fake.allDomainsReturnsOnCall = make(map[int]struct {
+			result1 []*store.CustomDomain
+			result2 error
+		})
+	}
+	fake.allDomainsReturnsOnCall[i] = struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDomains) GetDomain(arg1 string) (*store.CustomDomain, error) {
+	fake.getDomainMutex.Lock()
+	ret, specificReturn := fake.getDomainReturnsOnCall[len(fake.getDomainArgsForCall)]
+	fake.getDomainArgsForCall = append(fake.getDomainArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetDomain", []interface{}{arg1})
+	fake.getDomainMutex.Unlock()
+	if fake.GetDomainStub != nil {
+		return fake.GetDomainStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getDomainReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDomains) GetDomainCallCount() int {
+	fake.getDomainMutex.RLock()
+	defer fake.getDomainMutex.RUnlock()
+	return len(fake.getDomainArgsForCall)
+}
+
+func (fake *FakeDomains) GetDomainCalls(stub func(string) (*store.CustomDomain, error)) {
+	fake.getDomainMutex.Lock()
+	defer fake.getDomainMutex.Unlock()
+	fake.GetDomainStub = stub
+}
+
+func (fake *FakeDomains) GetDomainArgsForCall(i int) string {
+	fake.getDomainMutex.RLock()
+	defer fake.getDomainMutex.RUnlock()
+	argsForCall := fake.getDomainArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDomains) GetDomainReturns(result1 *store.CustomDomain, result2 error) {
+	fake.getDomainMutex.Lock()
+	defer fake.getDomainMutex.Unlock()
+	fake.GetDomainStub = nil
+	fake.getDomainReturns = struct {
+		result1 *store.CustomDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDomains) GetDomainReturnsOnCall(i int, result1 *store.CustomDomain, result2 error) {
+	fake.getDomainMutex.Lock()
+	defer fake.getDomainMutex.Unlock()
+	fake.GetDomainStub = nil
+	if fake.getDomainReturnsOnCall == nil {
+		fake.getDomainReturnsOnCall = make(map[int]struct {
+			result1 *store.CustomDomain
+			result2 error
+		})
+	}
+	fake.getDomainReturnsOnCall[i] = struct {
+		result1 *store.CustomDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDomains) ListDomains(arg1 string) ([]*store.CustomDomain, error) {
+	fake.listDomainsMutex.Lock()
+	ret, specificReturn := fake.listDomainsReturnsOnCall[len(fake.listDomainsArgsForCall)]
+	fake.listDomainsArgsForCall = append(fake.listDomainsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListDomains", []interface{}{arg1})
+	fake.listDomainsMutex.Unlock()
+	if fake.ListDomainsStub != nil {
+		return fake.ListDomainsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDomainsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeDomains) ListDomainsCallCount() int {
+	fake.listDomainsMutex.RLock()
+	defer fake.listDomainsMutex.RUnlock()
+	return len(fake.listDomainsArgsForCall)
+}
+
+func (fake *FakeDomains) ListDomainsCalls(stub func(string) ([]*store.CustomDomain, error)) {
+	fake.listDomainsMutex.Lock()
+	defer fake.listDomainsMutex.Unlock()
+	fake.ListDomainsStub = stub
+}
+
+func (fake *FakeDomains) ListDomainsArgsForCall(i int) string {
+	fake.listDomainsMutex.RLock()
+	defer fake.listDomainsMutex.RUnlock()
+	argsForCall := fake.listDomainsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDomains) ListDomainsReturns(result1 []*store.CustomDomain, result2 error) {
+	fake.listDomainsMutex.Lock()
+	defer fake.listDomainsMutex.Unlock()
+	fake.ListDomainsStub = nil
+	fake.listDomainsReturns = struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDomains) ListDomainsReturnsOnCall(i int, result1 []*store.CustomDomain, result2 error) {
+	fake.listDomainsMutex.Lock()
+	defer fake.listDomainsMutex.Unlock()
+	fake.ListDomainsStub = nil
+	if fake.listDomainsReturnsOnCall == nil {
+		fake.listDomainsReturnsOnCall = make(map[int]struct {
+			result1 []*store.CustomDomain
+			result2 error
+		})
+	}
+	fake.listDomainsReturnsOnCall[i] = struct {
+		result1 []*store.CustomDomain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDomains) RemoveDomain(arg1 string, arg2 string) error {
+	fake.removeDomainMutex.Lock()
+	ret, specificReturn := fake.removeDomainReturnsOnCall[len(fake.removeDomainArgsForCall)]
+	fake.removeDomainArgsForCall = append(fake.removeDomainArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("RemoveDomain", []interface{}{arg1, arg2})
+	fake.removeDomainMutex.Unlock()
+	if fake.RemoveDomainStub != nil {
+		return fake.RemoveDomainStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.removeDomainReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeDomains) RemoveDomainCallCount() int {
+	fake.removeDomainMutex.RLock()
+	defer fake.removeDomainMutex.RUnlock()
+	return len(fake.removeDomainArgsForCall)
+}
+
+func (fake *FakeDomains) RemoveDomainCalls(stub func(string, string) error) {
+	fake.removeDomainMutex.Lock()
+	defer fake.removeDomainMutex.Unlock()
+	fake.RemoveDomainStub = stub
+}
+
+func (fake *FakeDomains) RemoveDomainArgsForCall(i int) (string, string) {
+	fake.removeDomainMutex.RLock()
+	defer fake.removeDomainMutex.RUnlock()
+	argsForCall := fake.removeDomainArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeDomains) RemoveDomainReturns(result1 error) {
+	fake.removeDomainMutex.Lock()
+	defer fake.removeDomainMutex.Unlock()
+	fake.RemoveDomainStub = nil
+	fake.removeDomainReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDomains) RemoveDomainReturnsOnCall(i int, result1 error) {
+	fake.removeDomainMutex.Lock()
+	defer fake.removeDomainMutex.Unlock()
+	fake.RemoveDomainStub = nil
+	if fake.removeDomainReturnsOnCall == nil {
+		fake.removeDomainReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeDomainReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDomains) VerifyDomain(arg1 string) error {
+	fake.verifyDomainMutex.Lock()
+	ret, specificReturn := fake.verifyDomainReturnsOnCall[len(fake.verifyDomainArgsForCall)]
+	fake.verifyDomainArgsForCall = append(fake.verifyDomainArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("VerifyDomain", []interface{}{arg1})
+	fake.verifyDomainMutex.Unlock()
+	if fake.VerifyDomainStub != nil {
+		return fake.VerifyDomainStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.verifyDomainReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeDomains) VerifyDomainCallCount() int {
+	fake.verifyDomainMutex.RLock()
+	defer fake.verifyDomainMutex.RUnlock()
+	return len(fake.verifyDomainArgsForCall)
+}
+
+func (fake *FakeDomains) VerifyDomainCalls(stub func(string) error) {
+	fake.verifyDomainMutex.Lock()
+	defer fake.verifyDomainMutex.Unlock()
+	fake.VerifyDomainStub = stub
+}
+
+func (fake *FakeDomains) VerifyDomainArgsForCall(i int) string {
+	fake.verifyDomainMutex.RLock()
+	defer fake.verifyDomainMutex.RUnlock()
+	argsForCall := fake.verifyDomainArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeDomains) VerifyDomainReturns(result1 error) {
+	fake.verifyDomainMutex.Lock()
+	defer fake.verifyDomainMutex.Unlock()
+	fake.VerifyDomainStub = nil
+	fake.verifyDomainReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDomains) VerifyDomainReturnsOnCall(i int, result1 error) {
+	fake.verifyDomainMutex.Lock()
+	defer fake.verifyDomainMutex.Unlock()
+	fake.VerifyDomainStub = nil
+	if fake.verifyDomainReturnsOnCall == nil {
+		fake.verifyDomainReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.verifyDomainReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeDomains) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addDomainMutex.RLock()
+	defer fake.addDomainMutex.RUnlock()
+	fake.allDomainsMutex.RLock()
+	defer fake.allDomainsMutex.RUnlock()
+	fake.getDomainMutex.RLock()
+	defer fake.getDomainMutex.RUnlock()
+	fake.listDomainsMutex.RLock()
+	defer fake.listDomainsMutex.RUnlock()
+	fake.removeDomainMutex.RLock()
+	defer fake.removeDomainMutex.RUnlock()
+	fake.verifyDomainMutex.RLock()
+	defer fake.verifyDomainMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeDomains) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.Domains = new(FakeDomains)
@@ -0,0 +1,188 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+type FakeSettings struct {
+	GetNetworkSettingsStub        func(string) (*store.NetworkSettings, error)
+	getNetworkSettingsMutex       sync.RWMutex
+	getNetworkSettingsArgsForCall []struct {
+		arg1 string
+	}
+	getNetworkSettingsReturns struct {
+		result1 *store.NetworkSettings
+		result2 error
+	}
+	getNetworkSettingsReturnsOnCall map[int]struct {
+		result1 *store.NetworkSettings
+		result2 error
+	}
+	SaveNetworkSettingsStub        func(*store.NetworkSettings) error
+	saveNetworkSettingsMutex       sync.RWMutex
+	saveNetworkSettingsArgsForCall []struct {
+		arg1 *store.NetworkSettings
+	}
+	saveNetworkSettingsReturns struct {
+		result1 error
+	}
+	saveNetworkSettingsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSettings) GetNetworkSettings(arg1 string) (*store.NetworkSettings, error) {
+	fake.getNetworkSettingsMutex.Lock()
+	ret, specificReturn := fake.getNetworkSettingsReturnsOnCall[len(fake.getNetworkSettingsArgsForCall)]
+	fake.getNetworkSettingsArgsForCall = append(fake.getNetworkSettingsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetNetworkSettings", []interface{}{arg1})
+	fake.getNetworkSettingsMutex.Unlock()
+	if fake.GetNetworkSettingsStub != nil {
+		return fake.GetNetworkSettingsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getNetworkSettingsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSettings) GetNetworkSettingsCallCount() int {
+	fake.getNetworkSettingsMutex.RLock()
+	defer fake.getNetworkSettingsMutex.RUnlock()
+	return len(fake.getNetworkSettingsArgsForCall)
+}
+
+func (fake *FakeSettings) GetNetworkSettingsCalls(stub func(string) (*store.NetworkSettings, error)) {
+	fake.getNetworkSettingsMutex.Lock()
+	defer fake.getNetworkSettingsMutex.Unlock()
+	fake.GetNetworkSettingsStub = stub
+}
+
+func (fake *FakeSettings) GetNetworkSettingsArgsForCall(i int) string {
+	fake.getNetworkSettingsMutex.RLock()
+	defer fake.getNetworkSettingsMutex.RUnlock()
+	argsForCall := fake.getNetworkSettingsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSettings) GetNetworkSettingsReturns(result1 *store.NetworkSettings, result2 error) {
+	fake.getNetworkSettingsMutex.Lock()
+	defer fake.getNetworkSettingsMutex.Unlock()
+	fake.GetNetworkSettingsStub = nil
+	fake.getNetworkSettingsReturns = struct {
+		result1 *store.NetworkSettings
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSettings) GetNetworkSettingsReturnsOnCall(i int, result1 *store.NetworkSettings, result2 error) {
+	fake.getNetworkSettingsMutex.Lock()
+	defer fake.getNetworkSettingsMutex.Unlock()
+	fake.GetNetworkSettingsStub = nil
+	if fake.getNetworkSettingsReturnsOnCall == nil {
+		fake.getNetworkSettingsReturnsOnCall = make(map[int]struct {
+			result1 *store.NetworkSettings
+			result2 error
+		})
+	}
+	fake.getNetworkSettingsReturnsOnCall[i] = struct {
+		result1 *store.NetworkSettings
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSettings) SaveNetworkSettings(arg1 *store.NetworkSettings) error {
+	fake.saveNetworkSettingsMutex.Lock()
+	ret, specificReturn := fake.saveNetworkSettingsReturnsOnCall[len(fake.saveNetworkSettingsArgsForCall)]
+	fake.saveNetworkSettingsArgsForCall = append(fake.saveNetworkSettingsArgsForCall, struct {
+		arg1 *store.NetworkSettings
+	}{arg1})
+	fake.recordInvocation("SaveNetworkSettings", []interface{}{arg1})
+	fake.saveNetworkSettingsMutex.Unlock()
+	if fake.SaveNetworkSettingsStub != nil {
+		return fake.SaveNetworkSettingsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.saveNetworkSettingsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSettings) SaveNetworkSettingsCallCount() int {
+	fake.saveNetworkSettingsMutex.RLock()
+	defer fake.saveNetworkSettingsMutex.RUnlock()
+	return len(fake.saveNetworkSettingsArgsForCall)
+}
+
+func (fake *FakeSettings) SaveNetworkSettingsCalls(stub func(*store.NetworkSettings) error) {
+	fake.saveNetworkSettingsMutex.Lock()
+	defer fake.saveNetworkSettingsMutex.Unlock()
+	fake.SaveNetworkSettingsStub = stub
+}
+
+func (fake *FakeSettings) SaveNetworkSettingsArgsForCall(i int) *store.NetworkSettings {
+	fake.saveNetworkSettingsMutex.RLock()
+	defer fake.saveNetworkSettingsMutex.RUnlock()
+	argsForCall := fake.saveNetworkSettingsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSettings) SaveNetworkSettingsReturns(result1 error) {
+	fake.saveNetworkSettingsMutex.Lock()
+	defer fake.saveNetworkSettingsMutex.Unlock()
+	fake.SaveNetworkSettingsStub = nil
+	fake.saveNetworkSettingsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSettings) SaveNetworkSettingsReturnsOnCall(i int, result1 error) {
+	fake.saveNetworkSettingsMutex.Lock()
+	defer fake.saveNetworkSettingsMutex.Unlock()
+	fake.SaveNetworkSettingsStub = nil
+	if fake.saveNetworkSettingsReturnsOnCall == nil {
+		fake.saveNetworkSettingsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveNetworkSettingsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSettings) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getNetworkSettingsMutex.RLock()
+	defer fake.getNetworkSettingsMutex.RUnlock()
+	fake.saveNetworkSettingsMutex.RLock()
+	defer fake.saveNetworkSettingsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSettings) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.Settings = new(FakeSettings)
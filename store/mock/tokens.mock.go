@@ -0,0 +1,341 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+type FakeTokens struct {
+	CreateTokenStub        func(*store.APIToken) error
+	createTokenMutex       sync.RWMutex
+	createTokenArgsForCall []struct {
+		arg1 *store.APIToken
+	}
+	createTokenReturns struct {
+		result1 error
+	}
+	createTokenReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindTokenStub        func(string) (*store.APIToken, error)
+	findTokenMutex       sync.RWMutex
+	findTokenArgsForCall []struct {
+		arg1 string
+	}
+	findTokenReturns struct {
+		result1 *store.APIToken
+		result2 error
+	}
+	findTokenReturnsOnCall map[int]struct {
+		result1 *store.APIToken
+		result2 error
+	}
+	ListTokensStub        func(string) ([]*store.APIToken, error)
+	listTokensMutex       sync.RWMutex
+	listTokensArgsForCall []struct {
+		arg1 string
+	}
+	listTokensReturns struct {
+		result1 []*store.APIToken
+		result2 error
+	}
+	listTokensReturnsOnCall map[int]struct {
+		result1 []*store.APIToken
+		result2 error
+	}
+	RevokeTokenStub        func(string, string) error
+	revokeTokenMutex       sync.RWMutex
+	revokeTokenArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	revokeTokenReturns struct {
+		result1 error
+	}
+	revokeTokenReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTokens) CreateToken(arg1 *store.APIToken) error {
+	fake.createTokenMutex.Lock()
+	ret, specificReturn := fake.createTokenReturnsOnCall[len(fake.createTokenArgsForCall)]
+	fake.createTokenArgsForCall = append(fake.createTokenArgsForCall, struct {
+		arg1 *store.APIToken
+	}{arg1})
+	fake.recordInvocation("CreateToken", []interface{}{arg1})
+	fake.createTokenMutex.Unlock()
+	if fake.CreateTokenStub != nil {
+		return fake.CreateTokenStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createTokenReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTokens) CreateTokenCallCount() int {
+	fake.createTokenMutex.RLock()
+	defer fake.createTokenMutex.RUnlock()
+	return len(fake.createTokenArgsForCall)
+}
+
+func (fake *FakeTokens) CreateTokenCalls(stub func(*store.APIToken) error) {
+	fake.createTokenMutex.Lock()
+	defer fake.createTokenMutex.Unlock()
+	fake.CreateTokenStub = stub
+}
+
+func (fake *FakeTokens) CreateTokenArgsForCall(i int) *store.APIToken {
+	fake.createTokenMutex.RLock()
+	defer fake.createTokenMutex.RUnlock()
+	argsForCall := fake.createTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTokens) CreateTokenReturns(result1 error) {
+	fake.createTokenMutex.Lock()
+	defer fake.createTokenMutex.Unlock()
+	fake.CreateTokenStub = nil
+	fake.createTokenReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTokens) CreateTokenReturnsOnCall(i int, result1 error) {
+	fake.createTokenMutex.Lock()
+	defer fake.createTokenMutex.Unlock()
+	fake.CreateTokenStub = nil
+	if fake.createTokenReturnsOnCall == nil {
+		fake.createTokenReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createTokenReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTokens) FindToken(arg1 string) (*store.APIToken, error) {
+	fake.findTokenMutex.Lock()
+	ret, specificReturn := fake.findTokenReturnsOnCall[len(fake.findTokenArgsForCall)]
+	fake.findTokenArgsForCall = append(fake.findTokenArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("FindToken", []interface{}{arg1})
+	fake.findTokenMutex.Unlock()
+	if fake.FindTokenStub != nil {
+		return fake.FindTokenStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.findTokenReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTokens) FindTokenCallCount() int {
+	fake.findTokenMutex.RLock()
+	defer fake.findTokenMutex.RUnlock()
+	return len(fake.findTokenArgsForCall)
+}
+
+func (fake *FakeTokens) FindTokenCalls(stub func(string) (*store.APIToken, error)) {
+	fake.findTokenMutex.Lock()
+	defer fake.findTokenMutex.Unlock()
+	fake.FindTokenStub = stub
+}
+
+func (fake *FakeTokens) FindTokenArgsForCall(i int) string {
+	fake.findTokenMutex.RLock()
+	defer fake.findTokenMutex.RUnlock()
+	argsForCall := fake.findTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTokens) FindTokenReturns(result1 *store.APIToken, result2 error) {
+	fake.findTokenMutex.Lock()
+	defer fake.findTokenMutex.Unlock()
+	fake.FindTokenStub = nil
+	fake.findTokenReturns = struct {
+		result1 *store.APIToken
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTokens) FindTokenReturnsOnCall(i int, result1 *store.APIToken, result2 error) {
+	fake.findTokenMutex.Lock()
+	defer fake.findTokenMutex.Unlock()
+	fake.FindTokenStub = nil
+	if fake.findTokenReturnsOnCall == nil {
+		fake.findTokenReturnsOnCall = make(map[int]struct {
+			result1 *store.APIToken
+			result2 error
+		})
+	}
+	fake.findTokenReturnsOnCall[i] = struct {
+		result1 *store.APIToken
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTokens) ListTokens(arg1 string) ([]*store.APIToken, error) {
+	fake.listTokensMutex.Lock()
+	ret, specificReturn := fake.listTokensReturnsOnCall[len(fake.listTokensArgsForCall)]
+	fake.listTokensArgsForCall = append(fake.listTokensArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListTokens", []interface{}{arg1})
+	fake.listTokensMutex.Unlock()
+	if fake.ListTokensStub != nil {
+		return fake.ListTokensStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listTokensReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTokens) ListTokensCallCount() int {
+	fake.listTokensMutex.RLock()
+	defer fake.listTokensMutex.RUnlock()
+	return len(fake.listTokensArgsForCall)
+}
+
+func (fake *FakeTokens) ListTokensCalls(stub func(string) ([]*store.APIToken, error)) {
+	fake.listTokensMutex.Lock()
+	defer fake.listTokensMutex.Unlock()
+	fake.ListTokensStub = stub
+}
+
+func (fake *FakeTokens) ListTokensArgsForCall(i int) string {
+	fake.listTokensMutex.RLock()
+	defer fake.listTokensMutex.RUnlock()
+	argsForCall := fake.listTokensArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTokens) ListTokensReturns(result1 []*store.APIToken, result2 error) {
+	fake.listTokensMutex.Lock()
+	defer fake.listTokensMutex.Unlock()
+	fake.ListTokensStub = nil
+	fake.listTokensReturns = struct {
+		result1 []*store.APIToken
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTokens) ListTokensReturnsOnCall(i int, result1 []*store.APIToken, result2 error) {
+	fake.listTokensMutex.Lock()
+	defer fake.listTokensMutex.Unlock()
+	fake.ListTokensStub = nil
+	if fake.listTokensReturnsOnCall == nil {
+		fake.listTokensReturnsOnCall = make(map[int]struct {
+			result1 []*store.APIToken
+			result2 error
+		})
+	}
+	fake.listTokensReturnsOnCall[i] = struct {
+		result1 []*store.APIToken
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTokens) RevokeToken(arg1 string, arg2 string) error {
+	fake.revokeTokenMutex.Lock()
+	ret, specificReturn := fake.revokeTokenReturnsOnCall[len(fake.revokeTokenArgsForCall)]
+	fake.revokeTokenArgsForCall = append(fake.revokeTokenArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("RevokeToken", []interface{}{arg1, arg2})
+	fake.revokeTokenMutex.Unlock()
+	if fake.RevokeTokenStub != nil {
+		return fake.RevokeTokenStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.revokeTokenReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTokens) RevokeTokenCallCount() int {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	return len(fake.revokeTokenArgsForCall)
+}
+
+func (fake *FakeTokens) RevokeTokenCalls(stub func(string, string) error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = stub
+}
+
+func (fake *FakeTokens) RevokeTokenArgsForCall(i int) (string, string) {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	argsForCall := fake.revokeTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTokens) RevokeTokenReturns(result1 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	fake.revokeTokenReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTokens) RevokeTokenReturnsOnCall(i int, result1 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	if fake.revokeTokenReturnsOnCall == nil {
+		fake.revokeTokenReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeTokenReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTokens) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createTokenMutex.RLock()
+	defer fake.createTokenMutex.RUnlock()
+	fake.findTokenMutex.RLock()
+	defer fake.findTokenMutex.RUnlock()
+	fake.listTokensMutex.RLock()
+	defer fake.listTokensMutex.RUnlock()
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTokens) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.Tokens = new(FakeTokens)
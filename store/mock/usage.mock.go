@@ -0,0 +1,193 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/RTradeLtd/Nexus/store"
+)
+
+type FakeUsage struct {
+	AddUsageStub        func([]*store.UsageRecord) error
+	addUsageMutex       sync.RWMutex
+	addUsageArgsForCall []struct {
+		arg1 []*store.UsageRecord
+	}
+	addUsageReturns struct {
+		result1 error
+	}
+	addUsageReturnsOnCall map[int]struct {
+		result1 error
+	}
+	QueryUsageStub        func(store.UsageQuery) ([]*store.UsageRecord, error)
+	queryUsageMutex       sync.RWMutex
+	queryUsageArgsForCall []struct {
+		arg1 store.UsageQuery
+	}
+	queryUsageReturns struct {
+		result1 []*store.UsageRecord
+		result2 error
+	}
+	queryUsageReturnsOnCall map[int]struct {
+		result1 []*store.UsageRecord
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeUsage) AddUsage(arg1 []*store.UsageRecord) error {
+	var arg1Copy []*store.UsageRecord
+	if arg1 != nil {
+		arg1Copy = make([]*store.UsageRecord, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.addUsageMutex.Lock()
+	ret, specificReturn := fake.addUsageReturnsOnCall[len(fake.addUsageArgsForCall)]
+	fake.addUsageArgsForCall = append(fake.addUsageArgsForCall, struct {
+		arg1 []*store.UsageRecord
+	}{arg1Copy})
+	fake.recordInvocation("AddUsage", []interface{}{arg1Copy})
+	fake.addUsageMutex.Unlock()
+	if fake.AddUsageStub != nil {
+		return fake.AddUsageStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addUsageReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeUsage) AddUsageCallCount() int {
+	fake.addUsageMutex.RLock()
+	defer fake.addUsageMutex.RUnlock()
+	return len(fake.addUsageArgsForCall)
+}
+
+func (fake *FakeUsage) AddUsageCalls(stub func([]*store.UsageRecord) error) {
+	fake.addUsageMutex.Lock()
+	defer fake.addUsageMutex.Unlock()
+	fake.AddUsageStub = stub
+}
+
+func (fake *FakeUsage) AddUsageArgsForCall(i int) []*store.UsageRecord {
+	fake.addUsageMutex.RLock()
+	defer fake.addUsageMutex.RUnlock()
+	argsForCall := fake.addUsageArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeUsage) AddUsageReturns(result1 error) {
+	fake.addUsageMutex.Lock()
+	defer fake.addUsageMutex.Unlock()
+	fake.AddUsageStub = nil
+	fake.addUsageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsage) AddUsageReturnsOnCall(i int, result1 error) {
+	fake.addUsageMutex.Lock()
+	defer fake.addUsageMutex.Unlock()
+	fake.AddUsageStub = nil
+	if fake.addUsageReturnsOnCall == nil {
+		fake.addUsageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addUsageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeUsage) QueryUsage(arg1 store.UsageQuery) ([]*store.UsageRecord, error) {
+	fake.queryUsageMutex.Lock()
+	ret, specificReturn := fake.queryUsageReturnsOnCall[len(fake.queryUsageArgsForCall)]
+	fake.queryUsageArgsForCall = append(fake.queryUsageArgsForCall, struct {
+		arg1 store.UsageQuery
+	}{arg1})
+	fake.recordInvocation("QueryUsage", []interface{}{arg1})
+	fake.queryUsageMutex.Unlock()
+	if fake.QueryUsageStub != nil {
+		return fake.QueryUsageStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.queryUsageReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeUsage) QueryUsageCallCount() int {
+	fake.queryUsageMutex.RLock()
+	defer fake.queryUsageMutex.RUnlock()
+	return len(fake.queryUsageArgsForCall)
+}
+
+func (fake *FakeUsage) QueryUsageCalls(stub func(store.UsageQuery) ([]*store.UsageRecord, error)) {
+	fake.queryUsageMutex.Lock()
+	defer fake.queryUsageMutex.Unlock()
+	fake.QueryUsageStub = stub
+}
+
+func (fake *FakeUsage) QueryUsageArgsForCall(i int) store.UsageQuery {
+	fake.queryUsageMutex.RLock()
+	defer fake.queryUsageMutex.RUnlock()
+	argsForCall := fake.queryUsageArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeUsage) QueryUsageReturns(result1 []*store.UsageRecord, result2 error) {
+	fake.queryUsageMutex.Lock()
+	defer fake.queryUsageMutex.Unlock()
+	fake.QueryUsageStub = nil
+	fake.queryUsageReturns = struct {
+		result1 []*store.UsageRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUsage) QueryUsageReturnsOnCall(i int, result1 []*store.UsageRecord, result2 error) {
+	fake.queryUsageMutex.Lock()
+	defer fake.queryUsageMutex.Unlock()
+	fake.QueryUsageStub = nil
+	if fake.queryUsageReturnsOnCall == nil {
+		fake.queryUsageReturnsOnCall = make(map[int]struct {
+			result1 []*store.UsageRecord
+			result2 error
+		})
+	}
+	fake.queryUsageReturnsOnCall[i] = struct {
+		result1 []*store.UsageRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUsage) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addUsageMutex.RLock()
+	defer fake.addUsageMutex.RUnlock()
+	fake.queryUsageMutex.RLock()
+	defer fake.queryUsageMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeUsage) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ store.Usage = new(FakeUsage)
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Roles available to network users
+const (
+	// RoleViewer allows read-only API commands and network status
+	RoleViewer = "viewer"
+	// RoleWriter allows all API commands, network status, and diagnostics
+	RoleWriter = "writer"
+	// RoleAdmin allows everything a writer can, as well as access to the
+	// network's swarm key
+	RoleAdmin = "admin"
+)
+
+// ValidRole checks if given role is known
+func ValidRole(role string) bool {
+	switch role {
+	case RoleViewer, RoleWriter, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Roles maps network users to their role. Users with a role are members of
+// the network, in addition to those listed by Temporal.
+type Roles map[string]string
+
+// Validate checks that all assigned roles are known
+func (r Roles) Validate() error {
+	for user, role := range r {
+		if user == "" {
+			return fmt.Errorf("role '%s' assigned to empty user", role)
+		}
+		if !ValidRole(role) {
+			return fmt.Errorf("unknown role '%s' for user '%s'", role, user)
+		}
+	}
+	return nil
+}
+
+// Role retrieves the role of given user, falling back to given default if the
+// user has no assigned role
+func (r Roles) Role(user, defaultRole string) string {
+	if role, found := r[user]; found {
+		return role
+	}
+	return defaultRole
+}
+
+// Value implements driver.Valuer
+func (r Roles) Value() (driver.Value, error) { return valueJSON(r) }
+
+// Scan implements sql.Scanner
+func (r *Roles) Scan(src interface{}) error { return scanJSON(src, r) }
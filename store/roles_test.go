@@ -0,0 +1,33 @@
+package store
+
+import "testing"
+
+func TestRoles_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   Roles
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", Roles{"a": RoleViewer, "b": RoleWriter, "c": RoleAdmin}, false},
+		{"empty user", Roles{"": RoleViewer}, true},
+		{"unknown role", Roles{"a": "owner"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.roles.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Roles.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRoles_Role(t *testing.T) {
+	var roles = Roles{"bobheadxi": RoleAdmin}
+	if got := roles.Role("bobheadxi", RoleWriter); got != RoleAdmin {
+		t.Errorf("Roles.Role() = %v, want %v", got, RoleAdmin)
+	}
+	if got := roles.Role("postables", RoleWriter); got != RoleWriter {
+		t.Errorf("Roles.Role() = %v, want default %v", got, RoleWriter)
+	}
+}
@@ -0,0 +1,502 @@
+package store
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/gorm"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+// Settings provides access to Nexus-specific network configuration
+type Settings interface {
+	GetNetworkSettings(network string) (*NetworkSettings, error)
+	SaveNetworkSettings(s *NetworkSettings) error
+}
+
+// NetworkSettings declares configuration for a network that is not covered by
+// Temporal's HostedNetwork model
+type NetworkSettings struct {
+	ID        uint      `gorm:"primary_key" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	Network string `gorm:"type:varchar(255);unique_index" json:"network"`
+
+	// Labels are user-defined tags for this network
+	Labels Labels `gorm:"type:text" json:"labels,omitempty"`
+
+	// RateLimits override the default rate limits of individual features
+	RateLimits RateLimits `gorm:"type:text" json:"rate_limits,omitempty"`
+
+	// Commands overrides the default IPFS API command policy
+	Commands *CommandPolicy `gorm:"type:text" json:"commands,omitempty"`
+
+	// BodyLimits override the default request body size limits of individual
+	// features
+	BodyLimits BodyLimits `gorm:"type:text" json:"body_limits,omitempty"`
+
+	// Access restricts the addresses individual features may be accessed from
+	Access AccessRules `gorm:"type:text" json:"access,omitempty"`
+
+	// CORS overrides the default CORS policies of individual features
+	CORS CORSPolicies `gorm:"type:text" json:"cors,omitempty"`
+
+	// Egress overrides the default gateway egress limits
+	Egress *EgressLimits `gorm:"type:text" json:"egress,omitempty"`
+
+	// Roles assigns roles to network users
+	Roles Roles `gorm:"type:text" json:"roles,omitempty"`
+}
+
+// Apply updates settings with the top-level fields present in given JSON
+// object. Fields set to null are reset.
+func (s *NetworkSettings) Apply(patch []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return fmt.Errorf("invalid settings: %s", err.Error())
+	}
+	if _, found := fields["network"]; found {
+		return errors.New("network of settings cannot be changed")
+	}
+
+	// merge patch onto current settings
+	var merged map[string]json.RawMessage
+	current, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(current, &merged); err != nil {
+		return err
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+
+	// decode into fresh settings so that omitted fields are reset
+	var updated NetworkSettings
+	var dec = json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&updated); err != nil {
+		return fmt.Errorf("invalid settings: %s", err.Error())
+	}
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+	updated.ID, updated.CreatedAt, updated.UpdatedAt = s.ID, s.CreatedAt, s.UpdatedAt
+	*s = updated
+	return nil
+}
+
+// Validate checks that settings are well-formed
+func (s *NetworkSettings) Validate() error {
+	if err := s.Labels.Validate(); err != nil {
+		return err
+	}
+	if err := s.RateLimits.Validate(); err != nil {
+		return err
+	}
+	if err := s.BodyLimits.Validate(); err != nil {
+		return err
+	}
+	if err := s.Access.Validate(); err != nil {
+		return err
+	}
+	if err := s.CORS.Validate(); err != nil {
+		return err
+	}
+	if err := s.Roles.Validate(); err != nil {
+		return err
+	}
+	if err := s.Egress.Validate(); err != nil {
+		return err
+	}
+	return s.Commands.Validate()
+}
+
+// CommandPolicy retrieves the IPFS API command policy for the network,
+// falling back to given defaults if no override is set
+func (s *NetworkSettings) CommandPolicy(defaults config.CommandPolicy) config.CommandPolicy {
+	if s.Commands == nil {
+		return defaults
+	}
+	return config.CommandPolicy(*s.Commands)
+}
+
+// EgressLimits retrieves the gateway egress limits for the network, falling
+// back to given defaults if no override is set
+func (s *NetworkSettings) EgressLimits(defaults config.Egress) config.Egress {
+	if s.Egress == nil {
+		return defaults
+	}
+	return config.Egress(*s.Egress)
+}
+
+var (
+	labelKeyFormat   = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_./-]{0,61}[a-zA-Z0-9])?$`)
+	labelValueFormat = regexp.MustCompile(`^[a-zA-Z0-9_.-]{0,63}$`)
+)
+
+// Labels are user-defined key-value tags
+type Labels map[string]string
+
+// Validate checks that label keys and values are of a format that can be
+// safely used in selectors and container labels
+func (l Labels) Validate() error {
+	for k, v := range l {
+		if !labelKeyFormat.MatchString(k) {
+			return fmt.Errorf("invalid label key '%s'", k)
+		}
+		if !labelValueFormat.MatchString(v) {
+			return fmt.Errorf("invalid value '%s' for label '%s'", v, k)
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer
+func (l Labels) Value() (driver.Value, error) { return valueJSON(l) }
+
+// Scan implements sql.Scanner
+func (l *Labels) Scan(src interface{}) error { return scanJSON(src, l) }
+
+// RateLimits maps features to the rate limits that should be used instead of
+// the configured defaults
+type RateLimits map[string]config.FeatureRateLimits
+
+// Validate checks that overrides are for known features and are not negative
+func (r RateLimits) Validate() error {
+	for feature, limits := range r {
+		if feature != "api" && feature != "gateway" {
+			return fmt.Errorf("rate limits cannot be set for feature '%s'", feature)
+		}
+		for _, l := range []config.RateLimit{limits.Network, limits.User} {
+			if l.Rate < 0 || l.Burst < 0 {
+				return fmt.Errorf("invalid rate limit for feature '%s'", feature)
+			}
+		}
+	}
+	return nil
+}
+
+// Feature retrieves the rate limits for given feature, falling back to given
+// defaults if no override is set
+func (r RateLimits) Feature(feature string, defaults config.RateLimits) config.FeatureRateLimits {
+	if limits, found := r[feature]; found {
+		return limits
+	}
+	return defaults.Feature(feature)
+}
+
+// Value implements driver.Valuer
+func (r RateLimits) Value() (driver.Value, error) { return valueJSON(r) }
+
+// Scan implements sql.Scanner
+func (r *RateLimits) Scan(src interface{}) error { return scanJSON(src, r) }
+
+// BodyLimits maps features to the request body size limits, in megabytes,
+// that should be used instead of the configured defaults
+type BodyLimits map[string]int64
+
+// Validate checks that overrides are for known features and are positive
+func (b BodyLimits) Validate() error {
+	for feature, limit := range b {
+		if feature != "api" && feature != "gateway" {
+			return fmt.Errorf("body limits cannot be set for feature '%s'", feature)
+		}
+		if limit < 1 {
+			return fmt.Errorf("invalid body limit for feature '%s'", feature)
+		}
+	}
+	return nil
+}
+
+// Feature retrieves the body size limit for given feature in megabytes,
+// falling back to given defaults if no override is set
+func (b BodyLimits) Feature(feature string, defaults config.BodyLimits) int64 {
+	if limit, found := b[feature]; found {
+		return limit
+	}
+	return defaults.Feature(feature)
+}
+
+// Value implements driver.Valuer
+func (b BodyLimits) Value() (driver.Value, error) { return valueJSON(b) }
+
+// Scan implements sql.Scanner
+func (b *BodyLimits) Scan(src interface{}) error { return scanJSON(src, b) }
+
+// AccessRules maps features to the addresses they may be accessed from
+type AccessRules map[string]AccessRule
+
+// AccessRule declares the addresses a feature may be accessed from, as IPs or
+// CIDR ranges. Denied addresses take precedence, and if no addresses are
+// allowed, all addresses that are not denied are permitted.
+type AccessRule struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// Validate checks that rules are for known features and are made up of IPs or
+// CIDR ranges
+func (a AccessRules) Validate() error {
+	for feature, rule := range a {
+		if feature != "api" && feature != "gateway" && feature != "swarm" {
+			return fmt.Errorf("access rules cannot be set for feature '%s'", feature)
+		}
+		for _, ranges := range [][]string{rule.Allow, rule.Deny} {
+			for _, r := range ranges {
+				if _, err := ParseCIDR(r); err != nil {
+					return fmt.Errorf("invalid access rule for feature '%s': %s", feature, err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Permits checks if given address may access the feature, and returns the
+// range that decided the outcome, if any
+func (r AccessRule) Permits(ip net.IP) (bool, string) {
+	if match, ok := matchCIDRs(r.Deny, ip); ok {
+		return false, match
+	}
+	if len(r.Allow) == 0 {
+		return true, ""
+	}
+	if match, ok := matchCIDRs(r.Allow, ip); ok {
+		return true, match
+	}
+	return false, ""
+}
+
+// matchCIDRs returns the first range that contains given address
+func matchCIDRs(ranges []string, ip net.IP) (string, bool) {
+	for _, r := range ranges {
+		if n, err := ParseCIDR(r); err == nil && n.Contains(ip) {
+			return r, true
+		}
+	}
+	return "", false
+}
+
+// ParseCIDR parses a CIDR range, such as "10.0.0.0/8", or a single address,
+// which is treated as a range containing only that address
+func ParseCIDR(s string) (*net.IPNet, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		var bits = 8 * net.IPv6len
+		if v4 := ip.To4(); v4 != nil {
+			ip, bits = v4, 8*net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not an IP or CIDR range", s)
+	}
+	return n, nil
+}
+
+// Value implements driver.Valuer
+func (a AccessRules) Value() (driver.Value, error) { return valueJSON(a) }
+
+// Scan implements sql.Scanner
+func (a *AccessRules) Scan(src interface{}) error { return scanJSON(src, a) }
+
+// CORSPolicies maps features to the CORS policies that should be used
+// instead of the configured defaults. Fields of a policy that are not set,
+// other than AllowCredentials, are inherited from the default policy.
+type CORSPolicies map[string]config.CORSPolicy
+
+var tokenFormat = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+// Validate checks that policies are for known features, and that origins,
+// methods, and headers are well-formed
+func (c CORSPolicies) Validate() error {
+	for feature, policy := range c {
+		if feature != "api" && feature != "gateway" && feature != "status" {
+			return fmt.Errorf("CORS policies cannot be set for feature '%s'", feature)
+		}
+		for _, origin := range policy.AllowedOrigins {
+			if err := ValidateOrigin(origin); err != nil {
+				return fmt.Errorf("invalid CORS policy for feature '%s': %s", feature, err.Error())
+			}
+			if origin == "*" && policy.AllowCredentials {
+				return fmt.Errorf("invalid CORS policy for feature '%s': credentials cannot be allowed for all origins",
+					feature)
+			}
+		}
+		for _, tokens := range [][]string{policy.AllowedMethods, policy.AllowedHeaders, policy.ExposedHeaders} {
+			for _, t := range tokens {
+				if !tokenFormat.MatchString(t) {
+					return fmt.Errorf("invalid CORS policy for feature '%s': invalid method or header '%s'",
+						feature, t)
+				}
+			}
+		}
+		if policy.MaxAgeSeconds < 0 {
+			return fmt.Errorf("invalid CORS policy for feature '%s': max age cannot be negative", feature)
+		}
+	}
+	return nil
+}
+
+// Feature retrieves the CORS policy for given feature, falling back to given
+// defaults if no override is set
+func (c CORSPolicies) Feature(feature string, defaults config.CORS) config.CORSPolicy {
+	var fallback = defaults.Feature(feature)
+	policy, found := c[feature]
+	if !found {
+		return fallback
+	}
+	if policy.AllowedOrigins == nil {
+		policy.AllowedOrigins = fallback.AllowedOrigins
+	}
+	if policy.AllowedMethods == nil {
+		policy.AllowedMethods = fallback.AllowedMethods
+	}
+	if policy.AllowedHeaders == nil {
+		policy.AllowedHeaders = fallback.AllowedHeaders
+	}
+	if policy.ExposedHeaders == nil {
+		policy.ExposedHeaders = fallback.ExposedHeaders
+	}
+	if policy.MaxAgeSeconds == 0 {
+		policy.MaxAgeSeconds = fallback.MaxAgeSeconds
+	}
+	return policy
+}
+
+// ValidateOrigin checks that an allowed origin is "*", or of the form
+// "<scheme>://<host>[:<port>]", where the host may begin with "*." to match
+// all of its subdomains
+func ValidateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.User != nil ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" ||
+		strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+		return fmt.Errorf("'%s' is not a valid origin", origin)
+	}
+	return nil
+}
+
+// MatchOrigin checks if an origin, as provided in a request's Origin header,
+// matches an allowed origin
+func MatchOrigin(allowed, origin string) bool {
+	if allowed == "*" {
+		return true
+	}
+	allowed, origin = strings.ToLower(allowed), strings.ToLower(origin)
+	var i = strings.Index(allowed, "://*.")
+	if i < 0 {
+		return allowed == origin
+	}
+
+	// match any subdomain of the host, on the same scheme and port
+	var scheme, suffix = allowed[:i+3], allowed[i+4:]
+	if !strings.HasPrefix(origin, scheme) || !strings.HasSuffix(origin, suffix) ||
+		len(origin) <= len(scheme)+len(suffix) {
+		return false
+	}
+	return !strings.ContainsAny(origin[len(scheme):len(origin)-len(suffix)], ":/@")
+}
+
+// Value implements driver.Valuer
+func (c CORSPolicies) Value() (driver.Value, error) { return valueJSON(c) }
+
+// Scan implements sql.Scanner
+func (c *CORSPolicies) Scan(src interface{}) error { return scanJSON(src, c) }
+
+var commandFormat = regexp.MustCompile(`^[a-z0-9-]+(/[a-z0-9-]+)*$`)
+
+// CommandPolicy declares which IPFS API commands may be called on a network
+type CommandPolicy config.CommandPolicy
+
+// Validate checks that entries are command paths, such as "pin/add"
+func (p *CommandPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, commands := range [][]string{p.Allow, p.Deny} {
+		for _, c := range commands {
+			if !commandFormat.MatchString(c) {
+				return fmt.Errorf("invalid command '%s'", c)
+			}
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer
+func (p CommandPolicy) Value() (driver.Value, error) { return valueJSON(p) }
+
+// Scan implements sql.Scanner
+func (p *CommandPolicy) Scan(src interface{}) error { return scanJSON(src, p) }
+
+// EgressLimits declares limits on the bytes served by a network's gateway -
+// limits set to 0 are disabled
+type EgressLimits config.Egress
+
+// Validate checks that limits are not negative
+func (e *EgressLimits) Validate() error {
+	if e == nil {
+		return nil
+	}
+	if e.BytesPerSecond < 0 || e.BurstBytes < 0 || e.MonthlyCapGB < 0 {
+		return errors.New("egress limits cannot be negative")
+	}
+	return nil
+}
+
+// Value implements driver.Valuer
+func (e EgressLimits) Value() (driver.Value, error) { return valueJSON(e) }
+
+// Scan implements sql.Scanner
+func (e *EgressLimits) Scan(src interface{}) error { return scanJSON(src, e) }
+
+// SettingsManager manages network settings in the database
+type SettingsManager struct {
+	DB *gorm.DB
+}
+
+// NewSettingsManager instantiates a new SettingsManager
+func NewSettingsManager(db *gorm.DB) *SettingsManager {
+	return &SettingsManager{DB: db}
+}
+
+// GetNetworkSettings retrieves settings for given network. Networks without
+// stored settings receive empty settings.
+func (m *SettingsManager) GetNetworkSettings(network string) (*NetworkSettings, error) {
+	var s NetworkSettings
+	if err := m.DB.Where("network = ?", network).First(&s).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return &NetworkSettings{Network: network}, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SaveNetworkSettings creates or updates given settings
+func (m *SettingsManager) SaveNetworkSettings(s *NetworkSettings) error {
+	if s.Network == "" {
+		return errors.New("settings must be associated with a network")
+	}
+	return m.DB.Save(s).Error
+}
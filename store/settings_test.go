@@ -0,0 +1,378 @@
+package store
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	tcfg "github.com/RTradeLtd/config/v2"
+	"github.com/RTradeLtd/database/v2"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+func newTestDB() (*database.Manager, error) {
+	return database.New(&tcfg.TemporalConfig{
+		Database: tcfg.Database{
+			Name:     "temporal",
+			URL:      "127.0.0.1",
+			Port:     "5433",
+			Username: "postgres",
+			Password: "password123",
+		},
+	}, database.Options{
+		SSLModeDisable: true,
+	})
+}
+
+func TestNetworkSettings_Apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		current NetworkSettings
+		patch   string
+		want    NetworkSettings
+		wantErr bool
+	}{
+		{"invalid json", NetworkSettings{}, `{`, NetworkSettings{}, true},
+		{"unknown field", NetworkSettings{}, `{"color":"blue"}`, NetworkSettings{}, true},
+		{"change network", NetworkSettings{Network: "a"}, `{"network":"b"}`, NetworkSettings{}, true},
+		{"invalid label", NetworkSettings{}, `{"labels":{"bad key":"a"}}`, NetworkSettings{}, true},
+		{"empty patch",
+			NetworkSettings{ID: 1, Network: "a", Labels: Labels{"tier": "trial"}}, `{}`,
+			NetworkSettings{ID: 1, Network: "a", Labels: Labels{"tier": "trial"}}, false},
+		{"replace labels",
+			NetworkSettings{ID: 1, Network: "a", Labels: Labels{"tier": "trial"}}, `{"labels":{"region":"us"}}`,
+			NetworkSettings{ID: 1, Network: "a", Labels: Labels{"region": "us"}}, false},
+		{"reset labels",
+			NetworkSettings{ID: 1, Network: "a", Labels: Labels{"tier": "trial"}}, `{"labels":null}`,
+			NetworkSettings{ID: 1, Network: "a"}, false},
+		{"invalid command", NetworkSettings{}, `{"commands":{"deny":["/api/v0/shutdown"]}}`,
+			NetworkSettings{}, true},
+		{"invalid body limit", NetworkSettings{}, `{"body_limits":{"api":0}}`, NetworkSettings{}, true},
+		{"set body limits",
+			NetworkSettings{ID: 1, Network: "a"}, `{"body_limits":{"api":4096}}`,
+			NetworkSettings{ID: 1, Network: "a", BodyLimits: BodyLimits{"api": 4096}}, false},
+		{"invalid egress", NetworkSettings{}, `{"egress":{"bytes_per_second":-1}}`, NetworkSettings{}, true},
+		{"set egress",
+			NetworkSettings{ID: 1, Network: "a"}, `{"egress":{"bytes_per_second":1048576,"monthly_cap_gb":100}}`,
+			NetworkSettings{ID: 1, Network: "a", Egress: &EgressLimits{
+				BytesPerSecond: 1048576, MonthlyCapGB: 100}}, false},
+		{"set commands",
+			NetworkSettings{ID: 1, Network: "a"}, `{"commands":{"allow":["pin","cat"],"deny":[]}}`,
+			NetworkSettings{ID: 1, Network: "a", Commands: &CommandPolicy{
+				Allow: []string{"pin", "cat"}, Deny: []string{}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s = tt.current
+			if err := s.Apply([]byte(tt.patch)); (err != nil) != tt.wantErr {
+				t.Errorf("NetworkSettings.Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(s, tt.want) {
+				t.Errorf("NetworkSettings.Apply() = %+v, want %+v", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabels_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  Labels
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", Labels{"tier": "trial", "rtrade.io/plan": "v1.2", "empty": ""}, false},
+		{"empty key", Labels{"": "trial"}, true},
+		{"key with spaces", Labels{"my tier": "trial"}, true},
+		{"key with trailing separator", Labels{"tier-": "trial"}, true},
+		{"value with comma", Labels{"tier": "trial,paid"}, true},
+		{"value with parentheses", Labels{"tier": "(trial)"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.labels.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Labels.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRateLimits_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  RateLimits
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", RateLimits{"api": {Network: config.RateLimit{Rate: 10, Burst: 20}}}, false},
+		{"unknown feature", RateLimits{"swarm": {}}, true},
+		{"negative rate", RateLimits{"gateway": {User: config.RateLimit{Rate: -1}}}, true},
+		{"negative burst", RateLimits{"gateway": {Network: config.RateLimit{Burst: -1}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limits.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("RateLimits.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRateLimits_Feature(t *testing.T) {
+	var (
+		defaults = config.RateLimits{
+			API:     config.FeatureRateLimits{Network: config.RateLimit{Rate: 1}},
+			Gateway: config.FeatureRateLimits{Network: config.RateLimit{Rate: 2}},
+		}
+		override  = config.FeatureRateLimits{Network: config.RateLimit{Rate: 3}}
+		overrides = RateLimits{"api": override}
+	)
+	if got := overrides.Feature("api", defaults); got != override {
+		t.Errorf("RateLimits.Feature() = %v, want override %v", got, override)
+	}
+	if got := overrides.Feature("gateway", defaults); got != defaults.Gateway {
+		t.Errorf("RateLimits.Feature() = %v, want default %v", got, defaults.Gateway)
+	}
+}
+
+func TestBodyLimits_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  BodyLimits
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", BodyLimits{"api": 4096, "gateway": 1}, false},
+		{"unknown feature", BodyLimits{"swarm": 1}, true},
+		{"zero limit", BodyLimits{"api": 0}, true},
+		{"negative limit", BodyLimits{"gateway": -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limits.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("BodyLimits.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBodyLimits_Feature(t *testing.T) {
+	var (
+		defaults  = config.BodyLimits{API: 1024, Gateway: 1}
+		overrides = BodyLimits{"api": 4096}
+	)
+	if got := overrides.Feature("api", defaults); got != 4096 {
+		t.Errorf("BodyLimits.Feature() = %v, want override %v", got, 4096)
+	}
+	if got := overrides.Feature("gateway", defaults); got != defaults.Gateway {
+		t.Errorf("BodyLimits.Feature() = %v, want default %v", got, defaults.Gateway)
+	}
+	if got := overrides.Feature("swarm", defaults); got != 0 {
+		t.Errorf("BodyLimits.Feature() = %v, want no limit", got)
+	}
+}
+
+func TestAccessRules_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   AccessRules
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", AccessRules{
+			"api":     {Allow: []string{"203.0.113.0/24", "2001:db8::/32"}},
+			"gateway": {Deny: []string{"198.51.100.7"}},
+		}, false},
+		{"unknown feature", AccessRules{"status": {Allow: []string{"10.0.0.0/8"}}}, true},
+		{"invalid range", AccessRules{"api": {Allow: []string{"10.0.0.0/33"}}}, true},
+		{"hostname", AccessRules{"api": {Deny: []string{"example.com"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rules.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AccessRules.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessRule_Permits(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      AccessRule
+		ip        string
+		want      bool
+		wantMatch string
+	}{
+		{"no rules", AccessRule{}, "198.51.100.7", true, ""},
+		{"allowed", AccessRule{Allow: []string{"198.51.100.0/24"}}, "198.51.100.7", true, "198.51.100.0/24"},
+		{"not allowed", AccessRule{Allow: []string{"198.51.100.0/24"}}, "203.0.113.1", false, ""},
+		{"denied", AccessRule{Deny: []string{"198.51.100.7"}}, "198.51.100.7", false, "198.51.100.7"},
+		{"not denied", AccessRule{Deny: []string{"198.51.100.7"}}, "198.51.100.8", true, ""},
+		{"deny takes precedence",
+			AccessRule{Allow: []string{"198.51.100.0/24"}, Deny: []string{"198.51.100.7"}},
+			"198.51.100.7", false, "198.51.100.7"},
+		{"ipv6", AccessRule{Allow: []string{"2001:db8::/32"}}, "2001:db8::1", true, "2001:db8::/32"},
+		{"ipv4-mapped ipv6", AccessRule{Allow: []string{"198.51.100.0/24"}}, "::ffff:198.51.100.7", true, "198.51.100.0/24"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, match := tt.rule.Permits(net.ParseIP(tt.ip))
+			if got != tt.want || match != tt.wantMatch {
+				t.Errorf("AccessRule.Permits() = (%v, %s), want (%v, %s)", got, match, tt.want, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestCORSPolicies_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies CORSPolicies
+		wantErr  bool
+	}{
+		{"nil", nil, false},
+		{"valid", CORSPolicies{
+			"api": {
+				AllowedOrigins:   []string{"https://example.com", "https://*.example.com:8443"},
+				AllowedMethods:   []string{"POST"},
+				AllowedHeaders:   []string{"Authorization", "Content-Type"},
+				ExposedHeaders:   []string{"X-Stream-Output"},
+				MaxAgeSeconds:    600,
+				AllowCredentials: true,
+			},
+			"gateway": {AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}},
+		}, false},
+		{"unknown feature", CORSPolicies{"swarm": {AllowedOrigins: []string{"*"}}}, true},
+		{"status", CORSPolicies{"status": {AllowedOrigins: []string{"https://dashboard.example.com"}}}, false},
+		{"origin without scheme", CORSPolicies{"api": {AllowedOrigins: []string{"example.com"}}}, true},
+		{"origin with path", CORSPolicies{"api": {AllowedOrigins: []string{"https://example.com/"}}}, true},
+		{"inner wildcard", CORSPolicies{"api": {AllowedOrigins: []string{"https://a.*.com"}}}, true},
+		{"credentials for all origins", CORSPolicies{"api": {AllowedOrigins: []string{"*"}, AllowCredentials: true}}, true},
+		{"invalid method", CORSPolicies{"api": {AllowedMethods: []string{"GET POST"}}}, true},
+		{"invalid header", CORSPolicies{"gateway": {ExposedHeaders: []string{"X-Ipfs-Path:"}}}, true},
+		{"negative max age", CORSPolicies{"gateway": {MaxAgeSeconds: -1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policies.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("CORSPolicies.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCORSPolicies_Feature(t *testing.T) {
+	var (
+		defaults = config.CORS{
+			API:     config.CORSPolicy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"POST"}, MaxAgeSeconds: 600},
+			Gateway: config.CORSPolicy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}},
+		}
+		overrides = CORSPolicies{"api": {AllowedOrigins: []string{"https://example.com"}, AllowCredentials: true}}
+	)
+	var api = overrides.Feature("api", defaults)
+	if !reflect.DeepEqual(api.AllowedOrigins, []string{"https://example.com"}) || !api.AllowCredentials {
+		t.Errorf("CORSPolicies.Feature() = %+v, want override", api)
+	}
+	if !reflect.DeepEqual(api.AllowedMethods, []string{"POST"}) || api.MaxAgeSeconds != 600 {
+		t.Errorf("CORSPolicies.Feature() = %+v, want unset fields inherited", api)
+	}
+	if got := overrides.Feature("gateway", defaults); !reflect.DeepEqual(got, defaults.Gateway) {
+		t.Errorf("CORSPolicies.Feature() = %+v, want default %+v", got, defaults.Gateway)
+	}
+	if got := overrides.Feature("swarm", defaults); got.AllowedOrigins != nil {
+		t.Errorf("CORSPolicies.Feature() = %+v, want empty policy", got)
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		allowed string
+		origin  string
+		want    bool
+	}{
+		{"*", "https://example.com", true},
+		{"https://example.com", "https://example.com", true},
+		{"https://example.com", "HTTPS://Example.com", true},
+		{"https://example.com", "http://example.com", false},
+		{"https://example.com", "https://example.com:8443", false},
+		{"https://*.example.com", "https://app.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evilexample.com", false},
+		{"https://*.example.com", "http://app.example.com", false},
+		{"https://*.example.com", "https://app.example.com:8443", false},
+		{"https://*.example.com:8443", "https://app.example.com:8443", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.allowed+" "+tt.origin, func(t *testing.T) {
+			if got := MatchOrigin(tt.allowed, tt.origin); got != tt.want {
+				t.Errorf("MatchOrigin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetworkSettings_CommandPolicy(t *testing.T) {
+	var defaults = config.CommandPolicy{Deny: []string{"shutdown"}}
+	if got := (&NetworkSettings{}).CommandPolicy(defaults); !reflect.DeepEqual(got, defaults) {
+		t.Errorf("NetworkSettings.CommandPolicy() = %v, want default %v", got, defaults)
+	}
+	var override = &NetworkSettings{Commands: &CommandPolicy{Allow: []string{"cat"}}}
+	if got := override.CommandPolicy(defaults); !reflect.DeepEqual(got, config.CommandPolicy{Allow: []string{"cat"}}) {
+		t.Errorf("NetworkSettings.CommandPolicy() = %v, want override", got)
+	}
+}
+
+func TestNetworkSettings_EgressLimits(t *testing.T) {
+	var defaults = config.Egress{BytesPerSecond: 1 << 20, MonthlyCapGB: 100}
+	if got := (&NetworkSettings{}).EgressLimits(defaults); got != defaults {
+		t.Errorf("NetworkSettings.EgressLimits() = %v, want default %v", got, defaults)
+	}
+	var override = &NetworkSettings{Egress: &EgressLimits{MonthlyCapGB: 500}}
+	if got := override.EgressLimits(defaults); got != (config.Egress{MonthlyCapGB: 500}) {
+		t.Errorf("NetworkSettings.EgressLimits() = %v, want override", got)
+	}
+}
+
+func TestSettingsManager(t *testing.T) {
+	dbm, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbm.DB.Close()
+	if err := Migrate(dbm.DB); err != nil {
+		t.Fatal(err)
+	}
+	var m = NewSettingsManager(dbm.DB)
+	defer m.DB.Unscoped().Where("network = ?", "test-settings").Delete(&NetworkSettings{})
+
+	// unknown networks should have empty settings
+	s, err := m.GetNetworkSettings("test-settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Network != "test-settings" || len(s.Labels) != 0 {
+		t.Errorf("expected empty settings, got %+v", s)
+	}
+
+	// settings should be retrievable after save
+	s.Labels = Labels{"tier": "trial"}
+	if err := m.SaveNetworkSettings(s); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := m.GetNetworkSettings("test-settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(saved.Labels, s.Labels) {
+		t.Errorf("GetNetworkSettings() labels = %v, want %v", saved.Labels, s.Labels)
+	}
+
+	// settings must belong to a network
+	if err := m.SaveNetworkSettings(&NetworkSettings{}); err == nil {
+		t.Error("expected error saving settings without network")
+	}
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/RTradeLtd/gorm"
+)
+
+// Migrate creates or updates all tables owned by Nexus. Existing columns and
+// data are left untouched.
+func Migrate(db *gorm.DB) error {
+	for _, t := range []interface{}{
+		&NetworkSettings{},
+		&APIToken{},
+		&DenylistEntry{},
+		&CustomDomain{},
+		&UsageRecord{},
+	} {
+		if err := db.AutoMigrate(t).Error; err != nil {
+			return fmt.Errorf("failed to migrate table for %T: %s", t, err.Error())
+		}
+	}
+	return nil
+}
+
+// valueJSON encodes given value for storage in a text column
+func valueJSON(v interface{}) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// scanJSON decodes a text column into given value
+func scanJSON(src interface{}, v interface{}) error {
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(s), v)
+	case []byte:
+		return json.Unmarshal(s, v)
+	default:
+		return fmt.Errorf("unexpected column type %T", src)
+	}
+}
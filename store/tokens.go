@@ -0,0 +1,216 @@
+package store
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RTradeLtd/gorm"
+)
+
+// TokenPrefix denotes a Nexus-issued API token, as opposed to a Temporal JWT
+const TokenPrefix = "nxt_"
+
+// Scopes available for API tokens
+const (
+	// ScopeReadOnly allows commands that do not modify a node
+	ScopeReadOnly = "read-only"
+	// ScopePinOnly allows pin management commands
+	ScopePinOnly = "pin-only"
+	// ScopeAdmin allows all commands
+	ScopeAdmin = "admin"
+)
+
+// Tokens provides access to API tokens
+type Tokens interface {
+	CreateToken(t *APIToken) error
+	FindToken(hash string) (*APIToken, error)
+	ListTokens(network string) ([]*APIToken, error)
+	RevokeToken(network, tokenID string) error
+}
+
+// APIToken is a Nexus-issued credential that grants access to a single
+// network's API. Only a hash of the token secret is stored.
+type APIToken struct {
+	ID        uint      `gorm:"primary_key" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	TokenID string `gorm:"type:varchar(32);unique_index" json:"token_id"`
+	Network string `gorm:"type:varchar(255);index" json:"network"`
+	Name    string `json:"name"`
+	Hash    string `gorm:"type:varchar(64);unique_index" json:"-"`
+	Scopes  Scopes `gorm:"type:text" json:"scopes"`
+
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// NewAPIToken generates a token for given network. The returned secret should
+// be provided to the user, and cannot be recovered once discarded. A zero
+// expiry creates a token that never expires.
+func NewAPIToken(network, name string, scopes Scopes, expiry time.Duration) (string, *APIToken, error) {
+	if network == "" {
+		return "", nil, errors.New("token must be associated with a network")
+	}
+	if len(scopes) == 0 {
+		return "", nil, errors.New("at least one scope is required")
+	}
+	if err := scopes.Validate(); err != nil {
+		return "", nil, err
+	}
+	if expiry < 0 {
+		return "", nil, errors.New("expiry must not be negative")
+	}
+
+	id, err := randomString(9)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return "", nil, err
+	}
+	secret = TokenPrefix + secret
+
+	var t = &APIToken{
+		TokenID: id,
+		Network: network,
+		Name:    name,
+		Hash:    HashToken(secret),
+		Scopes:  scopes,
+	}
+	if expiry > 0 {
+		var expires = time.Now().Add(expiry)
+		t.ExpiresAt = &expires
+	}
+	return secret, t, nil
+}
+
+// HashToken generates the stored representation of a token secret
+func HashToken(secret string) string {
+	var sum = sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	// ErrTokenRevoked indicates that a token has been revoked
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrTokenExpired indicates that a token has expired
+	ErrTokenExpired = errors.New("token is expired")
+)
+
+// IsAPIToken checks if given credential is a Nexus-issued API token
+func IsAPIToken(credential string) bool {
+	return strings.HasPrefix(credential, TokenPrefix)
+}
+
+// Valid checks if the token can be used at given time
+func (t *APIToken) Valid(now time.Time) error {
+	if t.RevokedAt != nil {
+		return ErrTokenRevoked
+	}
+	if t.ExpiresAt != nil && now.After(*t.ExpiresAt) {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+// Scopes is a set of permissions granted to a token
+type Scopes []string
+
+// Validate checks that all scopes are known
+func (s Scopes) Validate() error {
+	for _, scope := range s {
+		switch scope {
+		case ScopeReadOnly, ScopePinOnly, ScopeAdmin:
+		default:
+			return fmt.Errorf("unknown scope '%s'", scope)
+		}
+	}
+	return nil
+}
+
+// Has checks if given scope is granted
+func (s Scopes) Has(scope string) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer
+func (s Scopes) Value() (driver.Value, error) { return valueJSON(s) }
+
+// Scan implements sql.Scanner
+func (s *Scopes) Scan(src interface{}) error { return scanJSON(src, s) }
+
+// TokenManager manages API tokens in the database
+type TokenManager struct {
+	DB *gorm.DB
+}
+
+// NewTokenManager instantiates a new TokenManager
+func NewTokenManager(db *gorm.DB) *TokenManager {
+	return &TokenManager{DB: db}
+}
+
+// CreateToken stores a new token
+func (m *TokenManager) CreateToken(t *APIToken) error {
+	if t.Network == "" || t.Hash == "" || t.TokenID == "" {
+		return errors.New("invalid token")
+	}
+	return m.DB.Create(t).Error
+}
+
+// FindToken retrieves the token with given hash. If no such token exists, nil
+// is returned without an error.
+func (m *TokenManager) FindToken(hash string) (*APIToken, error) {
+	var t APIToken
+	if err := m.DB.Where("hash = ?", hash).First(&t).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListTokens retrieves all tokens of given network
+func (m *TokenManager) ListTokens(network string) ([]*APIToken, error) {
+	var tokens []*APIToken
+	if err := m.DB.Where("network = ?", network).Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeToken prevents further use of the token with given ID
+func (m *TokenManager) RevokeToken(network, tokenID string) error {
+	var q = m.DB.Model(&APIToken{}).
+		Where("network = ? AND token_id = ? AND revoked_at IS NULL", network, tokenID).
+		Update("revoked_at", time.Now())
+	if q.Error != nil {
+		return q.Error
+	}
+	if q.RowsAffected == 0 {
+		return fmt.Errorf("no active token '%s' found for network '%s'", tokenID, network)
+	}
+	return nil
+}
+
+func randomString(n int) (string, error) {
+	var b = make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %s", err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
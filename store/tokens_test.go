@@ -0,0 +1,124 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewAPIToken(t *testing.T) {
+	type args struct {
+		network string
+		scopes  Scopes
+		expiry  time.Duration
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantExpiry bool
+		wantErr    bool
+	}{
+		{"no network", args{"", Scopes{ScopeAdmin}, 0}, false, true},
+		{"no scopes", args{"bobheadxi", nil, 0}, false, true},
+		{"unknown scope", args{"bobheadxi", Scopes{"superuser"}, 0}, false, true},
+		{"negative expiry", args{"bobheadxi", Scopes{ScopeAdmin}, -time.Hour}, false, true},
+		{"no expiry", args{"bobheadxi", Scopes{ScopeReadOnly}, 0}, false, false},
+		{"with expiry", args{"bobheadxi", Scopes{ScopeReadOnly, ScopePinOnly}, time.Hour}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, token, err := NewAPIToken(tt.args.network, "ci", tt.args.scopes, tt.args.expiry)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewAPIToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !IsAPIToken(secret) {
+				t.Errorf("secret '%s' not recognized as API token", secret)
+			}
+			if token.Hash != HashToken(secret) || token.Hash == secret {
+				t.Error("token should only store secret hash")
+			}
+			if token.TokenID == "" || token.Network != tt.args.network {
+				t.Errorf("unexpected token %+v", token)
+			}
+			if (token.ExpiresAt != nil) != tt.wantExpiry {
+				t.Errorf("NewAPIToken() expiry = %v, wantExpiry %v", token.ExpiresAt, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestAPIToken_Valid(t *testing.T) {
+	var (
+		now  = time.Now()
+		past = now.Add(-time.Hour)
+		next = now.Add(time.Hour)
+	)
+	tests := []struct {
+		name    string
+		token   APIToken
+		wantErr bool
+	}{
+		{"no expiry", APIToken{}, false},
+		{"not expired", APIToken{ExpiresAt: &next}, false},
+		{"expired", APIToken{ExpiresAt: &past}, true},
+		{"revoked", APIToken{RevokedAt: &past}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.token.Valid(now); (err != nil) != tt.wantErr {
+				t.Errorf("APIToken.Valid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenManager(t *testing.T) {
+	dbm, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbm.DB.Close()
+	if err := Migrate(dbm.DB); err != nil {
+		t.Fatal(err)
+	}
+	var m = NewTokenManager(dbm.DB)
+	defer m.DB.Unscoped().Where("network = ?", "test-tokens").Delete(&APIToken{})
+
+	secret, token, err := NewAPIToken("test-tokens", "ci", Scopes{ScopeReadOnly}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CreateToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	// token should be found by hash of secret
+	found, err := m.FindToken(HashToken(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.TokenID != token.TokenID || !found.Scopes.Has(ScopeReadOnly) {
+		t.Errorf("FindToken() = %+v, want %+v", found, token)
+	}
+	if missing, err := m.FindToken(HashToken("nxt_missing")); err != nil || missing != nil {
+		t.Errorf("FindToken() = %v, %v, want nil", missing, err)
+	}
+	if tokens, err := m.ListTokens("test-tokens"); err != nil || len(tokens) != 1 {
+		t.Errorf("ListTokens() = %v, %v", tokens, err)
+	}
+
+	// revoked tokens should no longer be valid
+	if err := m.RevokeToken("test-tokens", token.TokenID); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RevokeToken("test-tokens", token.TokenID); err == nil {
+		t.Error("expected error revoking token twice")
+	}
+	found, _ = m.FindToken(HashToken(secret))
+	if found.Valid(time.Now()) == nil {
+		t.Error("expected revoked token to be invalid")
+	}
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/RTradeLtd/gorm"
+)
+
+// Usage provides access to hourly aggregates of the bandwidth used by networks
+type Usage interface {
+	AddUsage(records []*UsageRecord) error
+	QueryUsage(q UsageQuery) ([]*UsageRecord, error)
+}
+
+// UsageRecord aggregates the bytes transferred by a network feature for a user
+// within an hour. BytesIn are bytes sent to the network, such as request
+// bodies and incoming swarm traffic, and BytesOut are bytes sent by the
+// network, such as response bodies and outgoing swarm traffic. Swarm traffic
+// and anonymous requests are recorded without a user.
+type UsageRecord struct {
+	ID        uint      `gorm:"primary_key" json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	Hour    time.Time `gorm:"unique_index:idx_usage_hour_network_feature_user" json:"hour"`
+	Network string    `gorm:"type:varchar(255);unique_index:idx_usage_hour_network_feature_user" json:"network"`
+	Feature string    `gorm:"type:varchar(16);unique_index:idx_usage_hour_network_feature_user" json:"feature"`
+	User    string    `gorm:"column:username;type:varchar(255);unique_index:idx_usage_hour_network_feature_user" json:"user,omitempty"`
+
+	BytesIn  int64 `json:"bytes_in"`
+	BytesOut int64 `json:"bytes_out"`
+}
+
+// UsageHour returns the hour that usage at given time is aggregated into
+func UsageHour(t time.Time) time.Time { return t.UTC().Truncate(time.Hour) }
+
+// UsageQuery filters usage records. Empty fields match all records.
+type UsageQuery struct {
+	Network string
+	Feature string
+	// Since and Until bound the hours records are aggregated into - records
+	// for hours starting at or after Since, and before Until, are included
+	Since time.Time
+	Until time.Time
+}
+
+// UsageManager manages usage records in the database
+type UsageManager struct {
+	DB *gorm.DB
+}
+
+// NewUsageManager instantiates a new UsageManager
+func NewUsageManager(db *gorm.DB) *UsageManager {
+	return &UsageManager{DB: db}
+}
+
+// upsertUsage adds bytes to a record, creating it if it does not exist yet
+const upsertUsage = `INSERT INTO usage_records
+	(updated_at, hour, network, feature, username, bytes_in, bytes_out)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (hour, network, feature, username) DO UPDATE SET
+		updated_at = EXCLUDED.updated_at,
+		bytes_in = usage_records.bytes_in + EXCLUDED.bytes_in,
+		bytes_out = usage_records.bytes_out + EXCLUDED.bytes_out`
+
+// AddUsage adds the bytes of given records to the stored aggregates of their
+// hour, network, feature, and user. Records are aligned to their hour.
+func (m *UsageManager) AddUsage(records []*UsageRecord) error {
+	var (
+		tx  = m.DB.Begin()
+		now = time.Now()
+	)
+	for _, r := range records {
+		if r.Network == "" || r.Feature == "" {
+			tx.Rollback()
+			return errors.New("usage must be associated with a network feature")
+		}
+		if err := tx.Exec(upsertUsage, now, UsageHour(r.Hour), r.Network, r.Feature, r.User,
+			r.BytesIn, r.BytesOut).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// QueryUsage retrieves records matching given query, ordered by hour, network,
+// feature, and user
+func (m *UsageManager) QueryUsage(q UsageQuery) ([]*UsageRecord, error) {
+	var db = m.DB
+	if q.Network != "" {
+		db = db.Where("network = ?", q.Network)
+	}
+	if q.Feature != "" {
+		db = db.Where("feature = ?", q.Feature)
+	}
+	if !q.Since.IsZero() {
+		db = db.Where("hour >= ?", q.Since.UTC())
+	}
+	if !q.Until.IsZero() {
+		db = db.Where("hour < ?", q.Until.UTC())
+	}
+	var records []*UsageRecord
+	if err := db.Order("hour, network, feature, username").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
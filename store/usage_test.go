@@ -0,0 +1,79 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageHour(t *testing.T) {
+	var est = time.FixedZone("EST", -5*60*60)
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{"on the hour",
+			time.Date(2019, 4, 1, 10, 0, 0, 0, time.UTC),
+			time.Date(2019, 4, 1, 10, 0, 0, 0, time.UTC)},
+		{"within the hour",
+			time.Date(2019, 4, 1, 10, 59, 59, 999, time.UTC),
+			time.Date(2019, 4, 1, 10, 0, 0, 0, time.UTC)},
+		{"other time zone",
+			time.Date(2019, 4, 1, 22, 30, 0, 0, est),
+			time.Date(2019, 4, 2, 3, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UsageHour(tt.t); !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("UsageHour() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageManager(t *testing.T) {
+	dbm, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbm.DB.Close()
+	if err := Migrate(dbm.DB); err != nil {
+		t.Fatal(err)
+	}
+	var m = NewUsageManager(dbm.DB)
+	defer m.DB.Unscoped().Where("network = ?", "test-usage").Delete(&UsageRecord{})
+
+	// usage within the same hour should be aggregated
+	var hour = UsageHour(time.Now())
+	if err := m.AddUsage([]*UsageRecord{
+		{Hour: hour, Network: "test-usage", Feature: "api", User: "bobheadxi", BytesIn: 10, BytesOut: 20},
+		{Hour: hour.Add(time.Minute), Network: "test-usage", Feature: "api", User: "bobheadxi", BytesIn: 1, BytesOut: 2},
+		{Hour: hour.Add(-time.Hour), Network: "test-usage", Feature: "swarm", BytesIn: 100, BytesOut: 200},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := m.QueryUsage(UsageQuery{Network: "test-usage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if r := records[1]; r.Feature != "api" || r.BytesIn != 11 || r.BytesOut != 22 {
+		t.Errorf("expected aggregated api usage, got %+v", r)
+	}
+
+	// queries should be bounded by hour
+	records, err = m.QueryUsage(UsageQuery{Network: "test-usage", Since: hour, Until: hour.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Feature != "api" {
+		t.Errorf("expected only api usage, got %+v", records)
+	}
+
+	// usage must belong to a network feature
+	if err := m.AddUsage([]*UsageRecord{{Network: "test-usage"}}); err == nil {
+		t.Error("expected error adding usage without feature")
+	}
+}
@@ -0,0 +1,3 @@
+// Package tracing provides utilities for recording OpenTelemetry traces and
+// propagating trace context over HTTP and gRPC
+package tracing
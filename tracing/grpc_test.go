@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestInterceptors(t *testing.T) {
+	var (
+		recorder = newTestRecorder(t)
+		client   = UnaryClientInterceptor()
+		server   = UnaryServerInterceptor()
+		info     = &grpc.UnaryServerInfo{FullMethod: "/rpc.Control/ListNetworks"}
+	)
+	var ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer hi")
+
+	err := client(ctx, info.FullMethod, nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			// pass outgoing metadata to the server, as a connection would
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if md.Get("authorization")[0] != "bearer hi" {
+				t.Error("expected existing metadata to be retained")
+			}
+			_, err := server(metadata.NewIncomingContext(context.Background(), md), nil, info,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+						t.Error("expected span in handler context")
+					}
+					return nil, status.Error(grpccodes.NotFound, "oh no")
+				})
+			return err
+		})
+	if status.Code(err) != grpccodes.NotFound {
+		t.Errorf("expected handler error, got %v", err)
+	}
+
+	var spans = recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	var serverSpan, clientSpan = spans[0], spans[1]
+	if serverSpan.SpanKind() != trace.SpanKindServer || clientSpan.SpanKind() != trace.SpanKindClient {
+		t.Error("unexpected span kinds")
+	}
+	if serverSpan.Parent().SpanID() != clientSpan.SpanContext().SpanID() ||
+		serverSpan.SpanContext().TraceID() != clientSpan.SpanContext().TraceID() {
+		t.Error("expected server span to continue client trace")
+	}
+	if serverSpan.Status().Code != codes.Error || clientSpan.Status().Code != codes.Error {
+		t.Error("expected failed call to be recorded")
+	}
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewMiddleware creates a handler that records each request to given server in
+// a span with given name, continuing the caller's trace if a "traceparent"
+// header is provided
+func NewMiddleware(server, name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ctx = otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := otel.Tracer(tracerName).Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(server, "", r)...))
+			defer span.End()
+
+			var ww = middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			var status = ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+			// client errors are not failures of the server
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(status))
+			}
+		})
+	}
+}
+
+// Transport records each round trip of the wrapped transport in a span, and
+// propagates trace context to the upstream server with a "traceparent"
+// header. Spans end once response headers are received.
+type Transport struct {
+	Name string
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var base = t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), t.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPClientAttributesFromHTTPRequest(req)...))
+
+	// requests must not be modified by transports
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		Finish(span, err)
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(resp.StatusCode)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(resp.StatusCode))
+	span.End()
+	return resp, nil
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestNewMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		status      int
+		wantFailed  bool
+	}{
+		{"new trace", "", http.StatusOK, false},
+		{"continued trace", testTraceParent, http.StatusOK, false},
+		{"client error", "", http.StatusNotFound, false},
+		{"server error", "", http.StatusBadGateway, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				recorder = newTestRecorder(t)
+				req      = httptest.NewRequest("GET", "/", nil)
+				rec      = httptest.NewRecorder()
+			)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			NewMiddleware("test", "test.request")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !trace.SpanFromContext(r.Context()).SpanContext().IsValid() {
+					t.Error("expected span in request context")
+				}
+				w.WriteHeader(tt.status)
+			})).ServeHTTP(rec, req)
+
+			var spans = recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+			var traceID = spans[0].SpanContext().TraceID().String()
+			if tt.traceparent != "" && traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("expected trace to be continued, got trace %s", traceID)
+			}
+			if (spans[0].Status().Code == codes.Error) != tt.wantFailed {
+				t.Errorf("unexpected span status %v", spans[0].Status())
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var (
+		recorder    = newTestRecorder(t)
+		traceparent string
+		upstream    = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+		}))
+	)
+	defer upstream.Close()
+
+	var req = httptest.NewRequest("GET", upstream.URL, nil)
+	req.RequestURI = ""
+	resp, err := (&Transport{Name: "test.proxy"}).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	var spans = recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if want := "00-" + spans[0].SpanContext().TraceID().String() + "-" +
+		spans[0].SpanContext().SpanID().String() + "-01"; traceparent != want {
+		t.Errorf("expected upstream to receive traceparent '%s', got '%s'", want, traceparent)
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Error("expected original request not to be modified")
+	}
+
+	// failed round trips should be recorded
+	req = httptest.NewRequest("GET", "http://127.0.0.1:0", nil)
+	req.RequestURI = ""
+	if _, err := (&Transport{Name: "test.proxy"}).RoundTrip(req); err == nil {
+		t.Error("expected error")
+	}
+	if spans = recorder.Ended(); len(spans) != 2 || spans[1].Status().Code != codes.Error {
+		t.Error("expected failed span to be recorded")
+	}
+}
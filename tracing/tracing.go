@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+// tracerName identifies spans recorded by Nexus
+const tracerName = "github.com/RTradeLtd/Nexus"
+
+// New sets up the global tracer provider for given service, and W3C trace
+// context propagation. The returned function flushes and stops export, and
+// should be called on shutdown. If tracing is disabled, spans are not
+// recorded.
+func New(l *zap.SugaredLogger, cfg config.Tracing, service, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if !cfg.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		cleanup  = func() error { return nil }
+	)
+	switch cfg.Exporter {
+	case config.ExporterOTLP:
+		var opts = []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		var err error
+		if exporter, err = otlptracehttp.New(context.Background(), opts...); err != nil {
+			return nil, fmt.Errorf("failed to set up OTLP exporter: %s", err.Error())
+		}
+	case config.ExporterFile:
+		if cfg.Path == "" {
+			return nil, errors.New("no path provided for trace file")
+		}
+		if err := os.MkdirAll(filepath.Dir(cfg.Path), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("failed to create directories for trace file '%s': %s",
+				cfg.Path, err.Error())
+		}
+		f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %s", err.Error())
+		}
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(f)); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to set up file exporter: %s", err.Error())
+		}
+		cleanup = f.Close
+	default:
+		return nil, fmt.Errorf("unknown trace exporter '%s'", cfg.Exporter)
+	}
+
+	var provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(service),
+			semconv.ServiceVersionKey.String(version))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		l.Warnw("failed to export traces", "error", err)
+	}))
+	l.Infow("exporting traces",
+		"exporter", cfg.Exporter,
+		"sample_ratio", cfg.SampleRatio)
+
+	return func(ctx context.Context) error {
+		if err := provider.Shutdown(ctx); err != nil {
+			cleanup()
+			return err
+		}
+		return cleanup()
+	}, nil
+}
+
+// Start creates a span with given name as a child of any span in the context
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Finish ends the span, marking it as failed if an error is provided
+func Finish(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
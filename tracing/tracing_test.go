@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/RTradeLtd/Nexus/config"
+)
+
+// newTestRecorder records spans of the global tracer provider
+func newTestRecorder(t *testing.T) *tracetest.SpanRecorder {
+	var recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	if _, err := New(zaptest.NewLogger(t).Sugar(), config.Tracing{}, "test", "test"); err != nil {
+		t.Fatal(err)
+	}
+	return recorder
+}
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nexus-tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		cfg     config.Tracing
+		wantErr bool
+	}{
+		{"disabled", config.Tracing{}, false},
+		{"unknown exporter", config.Tracing{Exporter: "zipkin"}, true},
+		{"no file path", config.Tracing{Exporter: config.ExporterFile}, true},
+		{"otlp", config.Tracing{Exporter: config.ExporterOTLP, Endpoint: "127.0.0.1:4318", SampleRatio: 1}, false},
+		{"file", config.Tracing{Exporter: config.ExporterFile, Path: filepath.Join(dir, "traces", "traces.json"), SampleRatio: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := New(zaptest.NewLogger(t).Sugar(), tt.cfg, "nexus", "test")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr || tt.cfg.Exporter == config.ExporterOTLP {
+				return
+			}
+			_, span := Start(context.Background(), "test.span")
+			Finish(span, nil)
+			if err := shutdown(context.Background()); err != nil {
+				t.Errorf("shutdown error = %v", err)
+			}
+			if tt.cfg.Exporter == config.ExporterFile {
+				b, err := ioutil.ReadFile(tt.cfg.Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(b), "test.span") {
+					t.Errorf("expected span to be written to file, got '%s'", b)
+				}
+			}
+		})
+	}
+}
+
+func TestFinish(t *testing.T) {
+	var recorder = newTestRecorder(t)
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	Finish(child, errors.New("oh no"))
+	Finish(parent, nil)
+
+	var spans = recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Error("expected child span to have parent")
+	}
+	if spans[0].Status().Code != codes.Error || len(spans[0].Events()) != 1 {
+		t.Error("expected child span to record error")
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Error("expected parent span not to be failed")
+	}
+}